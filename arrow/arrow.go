@@ -0,0 +1,522 @@
+// Package arrow implements conversions between parquet row groups and the
+// Arrow IPC streaming format.
+//
+// The package has no dependency on the Arrow libraries: it contains its own
+// encoder and decoder of the flatbuffers metadata of Arrow IPC messages, and
+// converts columns of parquet values directly to and from the memory layout
+// of Arrow arrays, using the repetition and definition levels to produce the
+// validity bitmaps and list offsets of nested types.
+//
+// https://arrow.apache.org/docs/format/Columnar.html#serialization-and-interprocess-communication-ipc
+package arrow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// Schema represents the schema of an Arrow IPC stream.
+type Schema struct {
+	Fields []Field
+}
+
+// Field represents a field of an Arrow schema.
+//
+// The children of fields of type List and Map are the fields of their elements,
+// the children of fields of type Struct are the fields of the struct.
+type Field struct {
+	Name     string
+	Type     Type
+	Nullable bool
+	Children []Field
+}
+
+// String returns a human-readable representation of the schema.
+func (s *Schema) String() string {
+	b := new(strings.Builder)
+	b.WriteString("schema {\n")
+	for i := range s.Fields {
+		s.Fields[i].format(b, "\t")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func (f *Field) format(b *strings.Builder, indent string) {
+	b.WriteString(indent)
+	b.WriteString(f.Name)
+	b.WriteString(": ")
+	b.WriteString(f.Type.String())
+	if !f.Nullable {
+		b.WriteString(" not null")
+	}
+	if len(f.Children) == 0 {
+		b.WriteString("\n")
+		return
+	}
+	b.WriteString(" {\n")
+	for i := range f.Children {
+		f.Children[i].format(b, indent+"\t")
+	}
+	b.WriteString(indent)
+	b.WriteString("}\n")
+}
+
+// Type is an interface implemented by the Arrow data types supported by this
+// package.
+type Type interface {
+	String() string
+
+	typeID() typeID
+}
+
+// Values of the Type union of the Arrow schema.
+type typeID uint8
+
+const (
+	typeInt             typeID = 2
+	typeFloatingPoint   typeID = 3
+	typeBinary          typeID = 4
+	typeUtf8            typeID = 5
+	typeBool            typeID = 6
+	typeDate            typeID = 8
+	typeTime            typeID = 9
+	typeTimestamp       typeID = 10
+	typeList            typeID = 12
+	typeStruct          typeID = 13
+	typeFixedSizeBinary typeID = 15
+	typeMap             typeID = 17
+)
+
+// Bool is the Arrow type of boolean values.
+type Bool struct{}
+
+// Int is the Arrow type of integer values.
+type Int struct {
+	BitWidth int
+	Signed   bool
+}
+
+// FloatingPoint is the Arrow type of floating point values.
+type FloatingPoint struct {
+	Precision Precision
+}
+
+// Binary is the Arrow type of variable length byte sequences.
+type Binary struct{}
+
+// Utf8 is the Arrow type of variable length UTF-8 strings.
+type Utf8 struct{}
+
+// FixedSizeBinary is the Arrow type of fixed length byte sequences.
+type FixedSizeBinary struct {
+	ByteWidth int
+}
+
+// Date is the Arrow type of dates. Only the DAY unit is supported by this
+// package, which represents dates as 32 bits integers counting the days since
+// the Unix epoch.
+type Date struct {
+	Unit DateUnit
+}
+
+// Time is the Arrow type of times of the day.
+type Time struct {
+	Unit     TimeUnit
+	BitWidth int
+}
+
+// Timestamp is the Arrow type of timestamps, represented as 64 bits integers.
+type Timestamp struct {
+	Unit     TimeUnit
+	Timezone string
+}
+
+// List is the Arrow type of variable length lists, the field of the elements is
+// the only child of fields of this type.
+type List struct{}
+
+// Struct is the Arrow type of structs, the fields of the struct are the
+// children of fields of this type.
+type Struct struct{}
+
+// Map is the Arrow type of maps. Fields of this type have a single non-nullable
+// child of type Struct named "entries", which has two children named "key"
+// and "value".
+type Map struct {
+	KeysSorted bool
+}
+
+// Precision is an enumeration of the precisions of the FloatingPoint type.
+type Precision int16
+
+const (
+	Half Precision = iota
+	Single
+	Double
+)
+
+// DateUnit is an enumeration of the units of the Date type.
+type DateUnit int16
+
+const (
+	Day DateUnit = iota
+	DateMillisecond
+)
+
+// TimeUnit is an enumeration of the units of the Time and Timestamp types.
+type TimeUnit int16
+
+const (
+	Second TimeUnit = iota
+	Millisecond
+	Microsecond
+	Nanosecond
+)
+
+func (Bool) typeID() typeID            { return typeBool }
+func (Int) typeID() typeID             { return typeInt }
+func (FloatingPoint) typeID() typeID   { return typeFloatingPoint }
+func (Binary) typeID() typeID          { return typeBinary }
+func (Utf8) typeID() typeID            { return typeUtf8 }
+func (FixedSizeBinary) typeID() typeID { return typeFixedSizeBinary }
+func (Date) typeID() typeID            { return typeDate }
+func (Time) typeID() typeID            { return typeTime }
+func (Timestamp) typeID() typeID       { return typeTimestamp }
+func (List) typeID() typeID            { return typeList }
+func (Struct) typeID() typeID          { return typeStruct }
+func (Map) typeID() typeID             { return typeMap }
+
+func (Bool) String() string   { return "bool" }
+func (Binary) String() string { return "binary" }
+func (Utf8) String() string   { return "utf8" }
+func (List) String() string   { return "list" }
+func (Struct) String() string { return "struct" }
+
+func (t Int) String() string {
+	if t.Signed {
+		return fmt.Sprintf("int%d", t.BitWidth)
+	}
+	return fmt.Sprintf("uint%d", t.BitWidth)
+}
+
+func (t FloatingPoint) String() string {
+	switch t.Precision {
+	case Half:
+		return "float16"
+	case Single:
+		return "float32"
+	case Double:
+		return "float64"
+	default:
+		return fmt.Sprintf("float(%d)", t.Precision)
+	}
+}
+
+func (t FixedSizeBinary) String() string { return fmt.Sprintf("fixed_size_binary(%d)", t.ByteWidth) }
+
+func (t Date) String() string {
+	if t.Unit == Day {
+		return "date32"
+	}
+	return "date64"
+}
+
+func (t Time) String() string { return fmt.Sprintf("time%d(%s)", t.BitWidth, t.Unit) }
+
+func (t Timestamp) String() string {
+	if t.Timezone == "" {
+		return fmt.Sprintf("timestamp(%s)", t.Unit)
+	}
+	return fmt.Sprintf("timestamp(%s, %s)", t.Unit, t.Timezone)
+}
+
+func (t Map) String() string {
+	if t.KeysSorted {
+		return "map(sorted)"
+	}
+	return "map"
+}
+
+func (u TimeUnit) String() string {
+	switch u {
+	case Second:
+		return "s"
+	case Millisecond:
+		return "ms"
+	case Microsecond:
+		return "us"
+	case Nanosecond:
+		return "ns"
+	default:
+		return fmt.Sprintf("TimeUnit(%d)", int16(u))
+	}
+}
+
+// SchemaOf returns the Arrow schema equivalent to the parquet schema passed as
+// argument.
+//
+// Parquet groups are converted to Arrow structs, except the groups of LIST and
+// MAP logical types which are converted to Arrow lists and maps. Repeated fields
+// which are not part of a LIST or MAP are converted to non-nullable lists of
+// non-nullable elements.
+//
+// INT96 values are represented as FixedSizeBinary(12) in Arrow, and decimals
+// as their underlying physical type.
+func SchemaOf(schema *parquet.Schema) (*Schema, error) {
+	fields := schema.Fields()
+	s := &Schema{Fields: make([]Field, len(fields))}
+	for i, f := range fields {
+		field, err := fieldOf(f.Name(), f)
+		if err != nil {
+			return nil, err
+		}
+		s.Fields[i] = field
+	}
+	return s, nil
+}
+
+func fieldOf(name string, node parquet.Node) (Field, error) {
+	if node.Repeated() {
+		elem, err := requiredFieldOf("element", node)
+		if err != nil {
+			return Field{}, err
+		}
+		return Field{Name: name, Type: List{}, Children: []Field{elem}}, nil
+	}
+	field, err := requiredFieldOf(name, node)
+	field.Nullable = node.Optional()
+	return field, err
+}
+
+func requiredFieldOf(name string, node parquet.Node) (Field, error) {
+	field := Field{Name: name}
+
+	if node.Leaf() {
+		t, err := typeOf(node.Type())
+		if err != nil {
+			return field, fmt.Errorf("%s: %w", name, err)
+		}
+		field.Type = t
+		return field, nil
+	}
+
+	if elem := parquet.ListElementOf(node); elem != nil {
+		child, err := fieldOf("element", elem)
+		if err != nil {
+			return field, fmt.Errorf("%s: %w", name, err)
+		}
+		field.Type = List{}
+		field.Children = []Field{child}
+		return field, nil
+	}
+
+	if keyValue := parquet.MapKeyValueOf(node); keyValue != nil {
+		// Arrow matches the key and value fields by position, the key comes
+		// first regardless of the order of the fields in the parquet schema.
+		entries := make([]Field, 2)
+		for _, f := range keyValue.Fields() {
+			i := 0
+			if f.Name() == "value" {
+				i = 1
+			}
+			child, err := fieldOf(f.Name(), f)
+			if err != nil {
+				return field, fmt.Errorf("%s: %w", name, err)
+			}
+			entries[i] = child
+		}
+		field.Type = Map{}
+		field.Children = []Field{{
+			Name:     "entries",
+			Type:     Struct{},
+			Children: entries,
+		}}
+		return field, nil
+	}
+
+	fields := node.Fields()
+	field.Type = Struct{}
+	field.Children = make([]Field, len(fields))
+	for i, f := range fields {
+		child, err := fieldOf(f.Name(), f)
+		if err != nil {
+			return field, fmt.Errorf("%s: %w", name, err)
+		}
+		field.Children[i] = child
+	}
+	return field, nil
+}
+
+func typeOf(t parquet.Type) (Type, error) {
+	if lt := t.LogicalType(); lt != nil {
+		switch {
+		case lt.UTF8 != nil, lt.Enum != nil, lt.Json != nil:
+			return Utf8{}, nil
+		case lt.Integer != nil:
+			return Int{BitWidth: int(lt.Integer.BitWidth), Signed: lt.Integer.IsSigned}, nil
+		case lt.Date != nil:
+			return Date{Unit: Day}, nil
+		case lt.Time != nil:
+			unit := timeUnitOf(lt.Time.Unit)
+			if t.Kind() == parquet.Int32 {
+				return Time{Unit: unit, BitWidth: 32}, nil
+			}
+			return Time{Unit: unit, BitWidth: 64}, nil
+		case lt.Timestamp != nil:
+			timestamp := Timestamp{Unit: timeUnitOf(lt.Timestamp.Unit)}
+			if lt.Timestamp.IsAdjustedToUTC {
+				timestamp.Timezone = "UTC"
+			}
+			return timestamp, nil
+		}
+	}
+
+	switch t.Kind() {
+	case parquet.Boolean:
+		return Bool{}, nil
+	case parquet.Int32:
+		return Int{BitWidth: 32, Signed: true}, nil
+	case parquet.Int64:
+		return Int{BitWidth: 64, Signed: true}, nil
+	case parquet.Int96:
+		return FixedSizeBinary{ByteWidth: 12}, nil
+	case parquet.Float:
+		return FloatingPoint{Precision: Single}, nil
+	case parquet.Double:
+		return FloatingPoint{Precision: Double}, nil
+	case parquet.ByteArray:
+		return Binary{}, nil
+	case parquet.FixedLenByteArray:
+		return FixedSizeBinary{ByteWidth: t.Length()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported parquet type: %s", t)
+	}
+}
+
+func timeUnitOf(unit format.TimeUnit) TimeUnit {
+	switch {
+	case unit.Millis != nil:
+		return Millisecond
+	case unit.Micros != nil:
+		return Microsecond
+	default:
+		return Nanosecond
+	}
+}
+
+// ParquetSchemaOf returns a parquet schema equivalent to the Arrow schema passed
+// as argument, using name as the name of the root node.
+//
+// Arrow structs are converted to parquet groups, which order their fields by
+// name, lists and maps are converted to groups of LIST and MAP logical types.
+func ParquetSchemaOf(name string, schema *Schema) (*parquet.Schema, error) {
+	root := make(parquet.Group, len(schema.Fields))
+	for i := range schema.Fields {
+		node, err := nodeOf(&schema.Fields[i])
+		if err != nil {
+			return nil, err
+		}
+		root[schema.Fields[i].Name] = node
+	}
+	return parquet.NewSchema(name, root), nil
+}
+
+func nodeOf(field *Field) (parquet.Node, error) {
+	node, err := requiredNodeOf(field)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field.Name, err)
+	}
+	if field.Nullable {
+		return parquet.Optional(node), nil
+	}
+	return parquet.Required(node), nil
+}
+
+func requiredNodeOf(field *Field) (parquet.Node, error) {
+	switch t := field.Type.(type) {
+	case Bool:
+		return parquet.Leaf(parquet.BooleanType), nil
+	case Int:
+		switch {
+		case t.BitWidth != 8 && t.BitWidth != 16 && t.BitWidth != 32 && t.BitWidth != 64:
+			return nil, fmt.Errorf("unsupported arrow type: %s", t)
+		case t.Signed:
+			return parquet.Int(t.BitWidth), nil
+		default:
+			return parquet.Uint(t.BitWidth), nil
+		}
+	case FloatingPoint:
+		switch t.Precision {
+		case Single:
+			return parquet.Leaf(parquet.FloatType), nil
+		case Double:
+			return parquet.Leaf(parquet.DoubleType), nil
+		}
+	case Binary:
+		return parquet.Leaf(parquet.ByteArrayType), nil
+	case Utf8:
+		return parquet.String(), nil
+	case FixedSizeBinary:
+		return parquet.Leaf(parquet.FixedLenByteArrayType(t.ByteWidth)), nil
+	case Date:
+		if t.Unit == Day {
+			return parquet.Date(), nil
+		}
+	case Time:
+		switch {
+		case t.Unit == Millisecond && t.BitWidth == 32:
+			return parquet.Time(parquet.Millisecond), nil
+		case t.Unit == Microsecond && t.BitWidth == 64:
+			return parquet.Time(parquet.Microsecond), nil
+		case t.Unit == Nanosecond && t.BitWidth == 64:
+			return parquet.Time(parquet.Nanosecond), nil
+		}
+	case Timestamp:
+		switch t.Unit {
+		case Millisecond:
+			return parquet.Timestamp(parquet.Millisecond), nil
+		case Microsecond:
+			return parquet.Timestamp(parquet.Microsecond), nil
+		case Nanosecond:
+			return parquet.Timestamp(parquet.Nanosecond), nil
+		}
+	case List:
+		if len(field.Children) != 1 {
+			return nil, fmt.Errorf("arrow list must have exactly one child but %d were found", len(field.Children))
+		}
+		elem, err := nodeOf(&field.Children[0])
+		if err != nil {
+			return nil, err
+		}
+		return parquet.List(elem), nil
+	case Map:
+		if len(field.Children) != 1 || len(field.Children[0].Children) != 2 {
+			return nil, fmt.Errorf("arrow map must have a single entries child with a key and value")
+		}
+		entries := field.Children[0].Children
+		key, err := requiredNodeOf(&entries[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entries[0].Name, err)
+		}
+		value, err := nodeOf(&entries[1])
+		if err != nil {
+			return nil, err
+		}
+		return parquet.Map(key, value), nil
+	case Struct:
+		group := make(parquet.Group, len(field.Children))
+		for i := range field.Children {
+			node, err := nodeOf(&field.Children[i])
+			if err != nil {
+				return nil, err
+			}
+			group[field.Children[i].Name] = node
+		}
+		return group, nil
+	}
+	return nil, fmt.Errorf("unsupported arrow type: %s", field.Type)
+}
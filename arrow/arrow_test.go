@@ -0,0 +1,598 @@
+package arrow_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/arrow"
+)
+
+type point struct {
+	X int32  `parquet:"x"`
+	Y string `parquet:"y,optional"`
+}
+
+type group struct {
+	Names []string `parquet:"names,list"`
+}
+
+type record struct {
+	Bool    bool             `parquet:"bool"`
+	Int8    int8             `parquet:"int8"`
+	Int16   int16            `parquet:"int16"`
+	Int32   int32            `parquet:"int32"`
+	Int64   int64            `parquet:"int64"`
+	Uint32  uint32           `parquet:"uint32"`
+	Float   float32          `parquet:"float"`
+	Double  float64          `parquet:"double"`
+	String  string           `parquet:"string"`
+	Bytes   []byte           `parquet:"bytes"`
+	UUID    [16]byte         `parquet:"uuid,uuid"`
+	Date    int32            `parquet:"date,date"`
+	Time    int64            `parquet:"time,timestamp"`
+	Maybe   string           `parquet:"maybe,optional"`
+	List    []int64          `parquet:"list,list"`
+	Tags    []string         `parquet:"tags"`
+	Labels  map[string]int32 `parquet:"labels"`
+	Point   point            `parquet:"point"`
+	Points  []point          `parquet:"points"`
+	Groups  []group          `parquet:"groups,list"`
+	Options *point           `parquet:"options,optional"`
+}
+
+func records() []record {
+	return []record{
+		{
+			Bool:   true,
+			Int8:   -1,
+			Int16:  -2,
+			Int32:  -3,
+			Int64:  -4,
+			Uint32: 1<<32 - 1,
+			Float:  1.5,
+			Double: 2.5,
+			String: "hello",
+			Bytes:  []byte{1, 2, 3},
+			UUID:   [16]byte{15: 1},
+			Date:   19000,
+			Time:   1666000000000,
+			Maybe:  "world",
+			List:   []int64{1, 2, 3},
+			Tags:   []string{"a", "b"},
+			Labels: map[string]int32{"answer": 42},
+			Point:  point{X: 1, Y: "one"},
+			Points: []point{{X: 2}, {X: 3, Y: "three"}},
+			Groups: []group{{Names: []string{"x"}}, {}, {Names: []string{"y", "z"}}},
+			Options: &point{
+				X: 4,
+				Y: "four",
+			},
+		},
+		{
+			String: "",
+			Bytes:  []byte{},
+		},
+		{
+			Int64:  42,
+			List:   []int64{},
+			Labels: map[string]int32{"a": 1, "b": 2},
+			Points: []point{{X: 5}},
+			Groups: []group{{Names: []string{}}},
+		},
+	}
+}
+
+func TestSchemaOf(t *testing.T) {
+	schema, err := arrow.SchemaOf(parquet.SchemaOf(record{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `schema {
+	bool: bool not null
+	int8: int8 not null
+	int16: int16 not null
+	int32: int32 not null
+	int64: int64 not null
+	uint32: uint32 not null
+	float: float32 not null
+	double: float64 not null
+	string: utf8 not null
+	bytes: binary not null
+	uuid: fixed_size_binary(16) not null
+	date: date32 not null
+	time: timestamp(ms, UTC) not null
+	maybe: utf8
+	list: list not null {
+		element: int64 not null
+	}
+	tags: list not null {
+		element: utf8 not null
+	}
+	labels: map not null {
+		entries: struct not null {
+			key: utf8 not null
+			value: int32 not null
+		}
+	}
+	point: struct not null {
+		x: int32 not null
+		y: utf8
+	}
+	points: list not null {
+		element: struct not null {
+			x: int32 not null
+			y: utf8
+		}
+	}
+	groups: list not null {
+		element: struct not null {
+			names: list not null {
+				element: utf8 not null
+			}
+		}
+	}
+	options: struct {
+		x: int32 not null
+		y: utf8
+	}
+}`
+
+	if got := schema.String(); got != want {
+		t.Errorf("schema mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestParquetSchemaOf(t *testing.T) {
+	schema := parquet.SchemaOf(point{})
+
+	arrowSchema, err := arrow.SchemaOf(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parquetSchema, err := arrow.ParquetSchemaOf(schema.Name(), arrowSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := parquetSchema.String(), schema.String(); got != want {
+		t.Errorf("schema mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestRoundTripBuffer(t *testing.T) {
+	schema := parquet.SchemaOf(record{})
+	buffer := parquet.NewBuffer(schema)
+	for _, r := range records() {
+		if err := buffer.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stream := new(bytes.Buffer)
+	w, err := arrow.NewWriter(stream, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if n, err := w.WriteRowGroup(buffer); err != nil {
+			t.Fatal(err)
+		} else if n != int64(buffer.NumRows()) {
+			t.Fatalf("wrong number of rows written: want=%d got=%d", buffer.NumRows(), n)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := arrow.NewReader(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := readRows(t, buffer)
+
+	for i := 0; i < 2; i++ {
+		output := parquet.NewBuffer(schema)
+		if n, err := r.ReadRecordBatch(output); err != nil {
+			t.Fatal(err)
+		} else if n != buffer.NumRows() {
+			t.Fatalf("wrong number of rows read: want=%d got=%d", buffer.NumRows(), n)
+		}
+		assertRowsEqual(t, want, readRows(t, output))
+	}
+
+	if _, err := r.ReadRecordBatch(parquet.NewBuffer(schema)); err != io.EOF {
+		t.Errorf("expected io.EOF at the end of the stream, got %v", err)
+	}
+}
+
+func TestRoundTripFile(t *testing.T) {
+	type row struct {
+		ID    int64   `parquet:"id"`
+		Name  string  `parquet:"name,dict"`
+		Score float64 `parquet:"score,optional"`
+		Tags  []int32 `parquet:"tags,list"`
+	}
+
+	rows := make([]row, 100)
+	for i := range rows {
+		rows[i] = row{
+			ID:    int64(i),
+			Name:  []string{"a", "b", "c"}[i%3],
+			Score: float64(i) / 2,
+			Tags:  make([]int32, i%4),
+		}
+	}
+
+	schema := parquet.SchemaOf(row{})
+	file := new(bytes.Buffer)
+	writer := parquet.NewWriter(file, schema, parquet.PageBufferSize(256))
+	for _, r := range rows {
+		if err := writer.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(file.Bytes()), int64(file.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream := new(bytes.Buffer)
+	w, err := arrow.NewWriter(stream, f.Schema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rowGroup := range f.RowGroups() {
+		if _, err := w.WriteRowGroup(rowGroup); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := arrow.NewReader(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := new(bytes.Buffer)
+	writer = parquet.NewWriter(output, schema)
+	if n, err := arrow.Copy(writer, r, schema); err != nil {
+		t.Fatal(err)
+	} else if n != int64(len(rows)) {
+		t.Fatalf("wrong number of rows copied: want=%d got=%d", len(rows), n)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(output.Bytes()))
+	for i, want := range rows {
+		got := row{}
+		if err := reader.Read(&got); err != nil {
+			t.Fatalf("reading row %d: %v", i, err)
+		}
+		if got.ID != want.ID || got.Name != want.Name || got.Score != want.Score || len(got.Tags) != len(want.Tags) {
+			t.Fatalf("row %d mismatch:\nwant: %+v\ngot:  %+v", i, want, got)
+		}
+	}
+}
+
+func TestRoundTripTestdata(t *testing.T) {
+	for _, name := range []string{
+		"alltypes_plain.parquet",
+		"data_index_bloom_encoding_stats.parquet",
+		"list_columns.parquet",
+		"nested_lists.snappy.parquet",
+		"nested_maps.snappy.parquet",
+		"nullable.impala.parquet",
+		"repeated_no_annotation.parquet",
+		"small.parquet",
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("..", "testdata", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stream := new(bytes.Buffer)
+			w, err := arrow.NewWriter(stream, f.Schema())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []parquet.Row
+			for _, rowGroup := range f.RowGroups() {
+				if _, err := w.WriteRowGroup(rowGroup); err != nil {
+					t.Fatal(err)
+				}
+				want = append(want, readRows(t, rowGroup)...)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := arrow.NewReader(stream)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []parquet.Row
+			for {
+				buffer := parquet.NewBuffer(f.Schema())
+				if _, err := r.ReadRecordBatch(buffer); err != nil {
+					if err != io.EOF {
+						t.Fatal(err)
+					}
+					break
+				}
+				got = append(got, readRows(t, buffer)...)
+			}
+			assertRowsEqual(t, want, got)
+		})
+	}
+}
+
+func TestReaderSchemaMismatch(t *testing.T) {
+	stream := new(bytes.Buffer)
+	w, err := arrow.NewWriter(stream, parquet.SchemaOf(point{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := arrow.NewReader(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type other struct {
+		X string `parquet:"x"`
+		Y string `parquet:"y,optional"`
+	}
+	if _, err := r.ReadRecordBatch(parquet.NewBuffer(parquet.SchemaOf(other{}))); err == nil {
+		t.Error("expected an error when reading arrow values into columns of a different type")
+	}
+}
+
+func TestReaderInvalidInput(t *testing.T) {
+	schema := parquet.SchemaOf(record{})
+	buffer := parquet.NewBuffer(schema)
+	for _, r := range records() {
+		if err := buffer.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stream := new(bytes.Buffer)
+	w, err := arrow.NewWriter(stream, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteRowGroup(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := stream.Bytes()
+	output := parquet.NewBuffer(schema)
+
+	for i := 0; i < len(data)-8; i++ {
+		output.Reset()
+		r, err := arrow.NewReader(bytes.NewReader(data[:i]))
+		if err == nil {
+			_, err = r.ReadRecordBatch(output)
+		}
+		if err == nil {
+			t.Fatalf("reading stream truncated at %d bytes did not return an error", i)
+		}
+	}
+
+	// Corrupting bytes of the stream must never trigger panics.
+	for i := 0; i < len(data); i++ {
+		corrupted := append([]byte{}, data...)
+		corrupted[i] ^= 0xFF
+		output.Reset()
+		r, err := arrow.NewReader(bytes.NewReader(corrupted))
+		if err == nil {
+			r.ReadRecordBatch(output)
+		}
+	}
+
+	if _, err := arrow.NewReader(bytes.NewReader([]byte("not an arrow stream"))); err == nil {
+		t.Error("expected an error when reading an invalid stream")
+	} else if errors.Is(err, io.EOF) {
+		t.Errorf("unexpected io.EOF error: %v", err)
+	}
+}
+
+// goldenSchema and goldenRows describe the content of testdata/golden.arrows,
+// which is generated with the Go implementation of Apache Arrow by
+// testdata/golden/main.go (testdata/interop.py generates the same stream with
+// pyarrow).
+var goldenSchema = &arrow.Schema{
+	Fields: []arrow.Field{
+		{Name: "bool", Type: arrow.Bool{}, Nullable: true},
+		{Name: "double", Type: arrow.FloatingPoint{Precision: arrow.Double}, Nullable: true},
+		{Name: "int32", Type: arrow.Int{BitWidth: 32, Signed: true}, Nullable: true},
+		{Name: "int64", Type: arrow.Int{BitWidth: 64, Signed: true}},
+		{Name: "list", Type: arrow.List{}, Nullable: true, Children: []arrow.Field{
+			{Name: "item", Type: arrow.Int{BitWidth: 64, Signed: true}, Nullable: true},
+		}},
+		{Name: "map", Type: arrow.Map{}, Nullable: true, Children: []arrow.Field{
+			{Name: "entries", Type: arrow.Struct{}, Children: []arrow.Field{
+				{Name: "key", Type: arrow.Utf8{}},
+				{Name: "value", Type: arrow.Int{BitWidth: 32, Signed: true}, Nullable: true},
+			}},
+		}},
+		{Name: "string", Type: arrow.Utf8{}, Nullable: true},
+		{Name: "struct", Type: arrow.Struct{}, Nullable: true, Children: []arrow.Field{
+			{Name: "x", Type: arrow.Int{BitWidth: 32, Signed: true}},
+			{Name: "y", Type: arrow.Utf8{}, Nullable: true},
+		}},
+	},
+}
+
+var goldenRows = []map[string]interface{}{
+	{
+		"bool":   true,
+		"double": 1.5,
+		"int32":  int32(1),
+		"int64":  int64(-1),
+		"list":   []interface{}{int64(1), int64(2)},
+		"map":    map[string]interface{}{"a": int32(1)},
+		"string": "a",
+		"struct": map[string]interface{}{"x": int32(1), "y": "one"},
+	},
+	{
+		"bool":   nil,
+		"double": 2.5,
+		"int32":  nil,
+		"int64":  int64(0),
+		"list":   nil,
+		"map":    map[string]interface{}{},
+		"string": nil,
+		"struct": map[string]interface{}{"x": int32(2), "y": nil},
+	},
+	{
+		"bool":   false,
+		"double": nil,
+		"int32":  int32(3),
+		"int64":  int64(1),
+		"list":   []interface{}{},
+		"map":    nil,
+		"string": "ccc",
+		"struct": nil,
+	},
+}
+
+func goldenBuffer(t *testing.T) *parquet.Buffer {
+	t.Helper()
+	schema, err := arrow.ParquetSchemaOf("golden", goldenSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer := parquet.NewBuffer(schema)
+	for _, row := range goldenRows {
+		if err := buffer.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buffer
+}
+
+func TestGoldenStreamDecoding(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "golden.arrows"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r, err := arrow.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Schema(), goldenSchema) {
+		t.Fatalf("schema mismatch:\nwant:\n%s\ngot:\n%s", goldenSchema, r.Schema())
+	}
+
+	buffer := goldenBuffer(t)
+	var got []parquet.Row
+	var numBatches int
+	for {
+		output := parquet.NewBuffer(buffer.Schema())
+		if _, err := r.ReadRecordBatch(output); err != nil {
+			if err != io.EOF {
+				t.Fatal(err)
+			}
+			break
+		}
+		got = append(got, readRows(t, output)...)
+		numBatches++
+	}
+	if numBatches != 2 {
+		t.Errorf("wrong number of record batches: want=2 got=%d", numBatches)
+	}
+	assertRowsEqual(t, readRows(t, buffer), got)
+}
+
+func TestGoldenStreamEncoding(t *testing.T) {
+	buffer := goldenBuffer(t)
+	path := filepath.Join(t.TempDir(), "golden.arrows")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w, err := arrow.NewWriter(f, buffer.Schema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteRowGroup(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The stream is verified by pyarrow when it is installed, the test is
+	// otherwise limited to the checks of the round trip tests.
+	if err := exec.Command("python3", "-c", "import pyarrow").Run(); err != nil {
+		t.Skip("pyarrow is not installed")
+	}
+	output, err := exec.Command("python3", filepath.Join("testdata", "interop.py"), "check", path).CombinedOutput()
+	if err != nil {
+		t.Errorf("pyarrow could not read the stream: %v\n%s", err, output)
+	}
+}
+
+func readRows(t *testing.T, rowGroup parquet.RowGroup) []parquet.Row {
+	t.Helper()
+	rows := rowGroup.Rows()
+
+	var all []parquet.Row
+	for {
+		row, err := rows.ReadRow(nil)
+		if err != nil {
+			if err == io.EOF {
+				return all
+			}
+			t.Fatal(err)
+		}
+		// Values may reference buffers reused by the row group.
+		for i, v := range row {
+			row[i] = v.Clone()
+		}
+		all = append(all, row)
+	}
+}
+
+func assertRowsEqual(t *testing.T, want, got []parquet.Row) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("number of rows mismatch: want=%d got=%d", len(want), len(got))
+	}
+	for i := range want {
+		if !want[i].Equal(got[i]) {
+			t.Errorf("row %d mismatch:\nwant: %+v\ngot:  %+v", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// This file contains a minimal implementation of the flatbuffers binary format,
+// which is used to encode the metadata of Arrow IPC messages. Only the features
+// needed to represent the Arrow schema and record batch messages are supported.
+//
+// https://flatbuffers.dev/md__internals.html
+
+// fbTable is the representation of a flatbuffers table to encode. The index of
+// each field in the table is its identifier in the flatbuffers schema.
+type fbTable struct {
+	fields []fbField
+}
+
+type fbField struct {
+	size  int      // size of the inline field, zero if the field is absent
+	value uint64   // value of scalar fields
+	ref   fbObject // referenced object of offset fields
+}
+
+// fbObject is implemented by the types representing out-of-line flatbuffers
+// objects, which are referenced by offsets from table fields and vectors.
+type fbObject interface {
+	writeTo(b *fbBuilder) int
+}
+
+func (t *fbTable) set(id int, f fbField) *fbTable {
+	for len(t.fields) <= id {
+		t.fields = append(t.fields, fbField{})
+	}
+	t.fields[id] = f
+	return t
+}
+
+func (t *fbTable) bool(id int, v bool) *fbTable {
+	if v {
+		return t.set(id, fbField{size: 1, value: 1})
+	}
+	return t.set(id, fbField{size: 1})
+}
+
+func (t *fbTable) uint8(id int, v uint8) *fbTable {
+	return t.set(id, fbField{size: 1, value: uint64(v)})
+}
+
+func (t *fbTable) int16(id int, v int16) *fbTable {
+	return t.set(id, fbField{size: 2, value: uint64(uint16(v))})
+}
+
+func (t *fbTable) int32(id int, v int32) *fbTable {
+	return t.set(id, fbField{size: 4, value: uint64(uint32(v))})
+}
+
+func (t *fbTable) int64(id int, v int64) *fbTable {
+	return t.set(id, fbField{size: 8, value: uint64(v)})
+}
+
+func (t *fbTable) ref(id int, obj fbObject) *fbTable {
+	return t.set(id, fbField{size: 4, ref: obj})
+}
+
+// fbString is a flatbuffers string, prefixed with its length and terminated by
+// a zero byte.
+type fbString string
+
+func (s fbString) writeTo(b *fbBuilder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.buf = appendUint32(b.buf, uint32(len(s)))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return pos
+}
+
+// fbTables is a flatbuffers vector of tables.
+type fbTables []*fbTable
+
+func (v fbTables) writeTo(b *fbBuilder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.buf = appendUint32(b.buf, uint32(len(v)))
+	b.buf = append(b.buf, make([]byte, 4*len(v))...)
+	for i, t := range v {
+		slot := pos + 4 + 4*i
+		b.patch(slot, t.writeTo(b))
+	}
+	return pos
+}
+
+// fbStructs is a flatbuffers vector of structs. Arrow metadata only uses structs
+// made of two 64 bits integers, which are the only ones supported here.
+type fbStructs [][2]int64
+
+func (v fbStructs) writeTo(b *fbBuilder) int {
+	// The elements of the vector must be aligned on 8 bytes boundaries, which
+	// means that the length prefix must be positioned 4 bytes before.
+	for len(b.buf)%8 != 4 {
+		b.buf = append(b.buf, 0)
+	}
+	pos := len(b.buf)
+	b.buf = appendUint32(b.buf, uint32(len(v)))
+	for _, s := range v {
+		b.buf = appendUint64(b.buf, uint64(s[0]))
+		b.buf = appendUint64(b.buf, uint64(s[1]))
+	}
+	return pos
+}
+
+func (t *fbTable) writeTo(b *fbBuilder) int {
+	// Lay out the inline fields of the table by decreasing size so they are
+	// all aligned to their natural boundary, the table itself always starts on
+	// an 8 bytes boundary.
+	offsets := make([]int, len(t.fields))
+	tableSize := 4 // soffset to the vtable
+	for _, size := range [...]int{8, 4, 2, 1} {
+		for i, f := range t.fields {
+			if f.size == size {
+				tableSize = alignUp(tableSize, size)
+				offsets[i] = tableSize
+				tableSize += size
+			}
+		}
+	}
+
+	b.align(2)
+	vtable := len(b.buf)
+	b.buf = appendUint16(b.buf, uint16(4+2*len(t.fields)))
+	b.buf = appendUint16(b.buf, uint16(tableSize))
+	for _, offset := range offsets {
+		b.buf = appendUint16(b.buf, uint16(offset))
+	}
+
+	b.align(8)
+	table := len(b.buf)
+	b.buf = append(b.buf, make([]byte, tableSize)...)
+	binary.LittleEndian.PutUint32(b.buf[table:], uint32(table-vtable))
+
+	for i, f := range t.fields {
+		field := b.buf[table+offsets[i]:]
+		switch {
+		case f.size == 0:
+		case f.ref != nil:
+			// Offsets are resolved after writing the referenced objects.
+		case f.size == 1:
+			field[0] = byte(f.value)
+		case f.size == 2:
+			binary.LittleEndian.PutUint16(field, uint16(f.value))
+		case f.size == 4:
+			binary.LittleEndian.PutUint32(field, uint32(f.value))
+		case f.size == 8:
+			binary.LittleEndian.PutUint64(field, f.value)
+		}
+	}
+
+	for i, f := range t.fields {
+		if f.ref != nil {
+			b.patch(table+offsets[i], f.ref.writeTo(b))
+		}
+	}
+	return table
+}
+
+// fbBuilder serializes flatbuffers from front to back: contrary to the builders
+// of the flatbuffers library, objects are written after the tables that
+// reference them, which is valid since offsets are always positive.
+type fbBuilder struct {
+	buf []byte
+}
+
+func (b *fbBuilder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// patch writes at pos the offset to the object written at target.
+func (b *fbBuilder) patch(pos, target int) {
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(target-pos))
+}
+
+// finish serializes the root table t and returns the flatbuffer, padded to a
+// multiple of 8 bytes as required by the Arrow IPC format.
+func (b *fbBuilder) finish(t *fbTable) []byte {
+	b.buf = append(b.buf[:0], 0, 0, 0, 0)
+	b.patch(0, t.writeTo(b))
+	b.align(8)
+	return b.buf
+}
+
+func alignUp(n, a int) int {
+	return (n + a - 1) &^ (a - 1)
+}
+
+// errInvalidFlatbuffer is the panic value used when decoding flatbuffers which
+// are out of bounds, the panics are recovered and converted to errors when
+// decoding IPC messages.
+var errInvalidFlatbuffer = errors.New("invalid flatbuffer")
+
+// fbRef is a reference to a table in a flatbuffer being decoded.
+type fbRef struct {
+	buf []byte
+	pos int
+}
+
+func fbRoot(buf []byte) fbRef {
+	return fbRef{buf: buf, pos: fbDeref(buf, 0)}
+}
+
+func fbDeref(buf []byte, pos int) int {
+	return pos + int(fbUint32(buf, pos))
+}
+
+func fbUint16(buf []byte, pos int) uint16 {
+	if pos < 0 || pos+2 > len(buf) {
+		panic(errInvalidFlatbuffer)
+	}
+	return binary.LittleEndian.Uint16(buf[pos:])
+}
+
+func fbUint32(buf []byte, pos int) uint32 {
+	if pos < 0 || pos+4 > len(buf) {
+		panic(errInvalidFlatbuffer)
+	}
+	return binary.LittleEndian.Uint32(buf[pos:])
+}
+
+func fbUint64(buf []byte, pos int) uint64 {
+	if pos < 0 || pos+8 > len(buf) {
+		panic(errInvalidFlatbuffer)
+	}
+	return binary.LittleEndian.Uint64(buf[pos:])
+}
+
+// field returns the position of the field with the given id, or zero if the
+// field is absent from the table.
+func (r fbRef) field(id int) int {
+	vtable := r.pos - int(int32(fbUint32(r.buf, r.pos)))
+	vtableSize := int(fbUint16(r.buf, vtable))
+	if offset := 4 + 2*id; offset+2 <= vtableSize {
+		if fieldOffset := int(fbUint16(r.buf, vtable+offset)); fieldOffset != 0 {
+			return r.pos + fieldOffset
+		}
+	}
+	return 0
+}
+
+func (r fbRef) bool(id int) bool { return r.uint8(id, 0) != 0 }
+
+func (r fbRef) uint8(id int, defaultValue uint8) uint8 {
+	if pos := r.field(id); pos != 0 {
+		if pos >= len(r.buf) {
+			panic(errInvalidFlatbuffer)
+		}
+		return r.buf[pos]
+	}
+	return defaultValue
+}
+
+func (r fbRef) int16(id int, defaultValue int16) int16 {
+	if pos := r.field(id); pos != 0 {
+		return int16(fbUint16(r.buf, pos))
+	}
+	return defaultValue
+}
+
+func (r fbRef) int32(id int, defaultValue int32) int32 {
+	if pos := r.field(id); pos != 0 {
+		return int32(fbUint32(r.buf, pos))
+	}
+	return defaultValue
+}
+
+func (r fbRef) int64(id int, defaultValue int64) int64 {
+	if pos := r.field(id); pos != 0 {
+		return int64(fbUint64(r.buf, pos))
+	}
+	return defaultValue
+}
+
+func (r fbRef) table(id int) (fbRef, bool) {
+	if pos := r.field(id); pos != 0 {
+		return fbRef{buf: r.buf, pos: fbDeref(r.buf, pos)}, true
+	}
+	return fbRef{}, false
+}
+
+func (r fbRef) string(id int) string {
+	if pos := r.field(id); pos != 0 {
+		pos = fbDeref(r.buf, pos)
+		n := int(fbUint32(r.buf, pos))
+		if n < 0 || pos+4+n > len(r.buf) {
+			panic(errInvalidFlatbuffer)
+		}
+		return string(r.buf[pos+4 : pos+4+n])
+	}
+	return ""
+}
+
+// vector returns the position of the first element and the length of the
+// vector field with the given id.
+func (r fbRef) vector(id int) (pos, length int) {
+	if pos = r.field(id); pos != 0 {
+		pos = fbDeref(r.buf, pos)
+		length = int(fbUint32(r.buf, pos))
+		if length < 0 || length > len(r.buf) {
+			panic(errInvalidFlatbuffer)
+		}
+		return pos + 4, length
+	}
+	return 0, 0
+}
+
+func (r fbRef) tables(id int) []fbRef {
+	pos, length := r.vector(id)
+	tables := make([]fbRef, length)
+	for i := range tables {
+		tables[i] = fbRef{buf: r.buf, pos: fbDeref(r.buf, pos+4*i)}
+	}
+	return tables
+}
+
+func (r fbRef) structs(id int) [][2]int64 {
+	pos, length := r.vector(id)
+	structs := make([][2]int64, length)
+	for i := range structs {
+		structs[i][0] = int64(fbUint64(r.buf, pos+16*i))
+		structs[i][1] = int64(fbUint64(r.buf, pos+16*i+8))
+	}
+	return structs
+}
+
+// fbDecode calls decode and converts the panics triggered by out of bounds
+// accesses to the flatbuffer into errors.
+func fbDecode(decode func() error) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			if e != errInvalidFlatbuffer {
+				panic(e)
+			}
+			err = fmt.Errorf("decoding arrow message metadata: %w", errInvalidFlatbuffer)
+		}
+	}()
+	return decode()
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v)), uint32(v>>32))
+}
@@ -0,0 +1,375 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// Version of the Arrow metadata written by this package (V5).
+	metadataVersion = 4
+
+	// Arrow IPC messages are prefixed with this marker, followed by the length
+	// of the flatbuffer holding the message metadata.
+	continuationMarker = 0xFFFFFFFF
+)
+
+// Values of the MessageHeader union of Arrow messages.
+type messageType uint8
+
+const (
+	schemaMessage          messageType = 1
+	dictionaryBatchMessage messageType = 2
+	recordBatchMessage     messageType = 3
+)
+
+func (t messageType) String() string {
+	switch t {
+	case schemaMessage:
+		return "Schema"
+	case dictionaryBatchMessage:
+		return "DictionaryBatch"
+	case recordBatchMessage:
+		return "RecordBatch"
+	default:
+		return fmt.Sprintf("MessageHeader(%d)", uint8(t))
+	}
+}
+
+// message is the in-memory representation of an Arrow IPC message.
+type message struct {
+	typ    messageType
+	header fbRef
+	body   []byte
+}
+
+// writeMessage writes an encapsulated Arrow IPC message to w. The body is
+// expected to be padded to a multiple of 8 bytes already.
+func writeMessage(w io.Writer, typ messageType, header *fbTable, body []byte) error {
+	msg := new(fbTable).
+		int16(0, metadataVersion).
+		uint8(1, uint8(typ)).
+		ref(2, header).
+		int64(3, int64(len(body)))
+
+	b := new(fbBuilder)
+	metadata := b.finish(msg)
+
+	prefix := [8]byte{}
+	binary.LittleEndian.PutUint32(prefix[:4], continuationMarker)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(metadata)))
+
+	for _, data := range [...][]byte{prefix[:], metadata, body} {
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeEndOfStream writes the marker indicating the end of an Arrow IPC stream.
+func writeEndOfStream(w io.Writer) error {
+	eos := [8]byte{}
+	binary.LittleEndian.PutUint32(eos[:4], continuationMarker)
+	_, err := w.Write(eos[:])
+	return err
+}
+
+// readMessage reads the next Arrow IPC message from r, returning io.EOF when
+// the end of the stream is reached.
+//
+// Messages written in the format used prior to Arrow 0.15, which were not
+// prefixed with the continuation marker, are also supported.
+func readMessage(r io.Reader) (*message, error) {
+	prefix := [4]byte{}
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint32(prefix[:])
+	if length == continuationMarker {
+		if _, err := io.ReadFull(r, prefix[:]); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		length = binary.LittleEndian.Uint32(prefix[:])
+	}
+	if length == 0 {
+		return nil, io.EOF
+	}
+	if length > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid arrow message metadata length: %d", length)
+	}
+
+	metadata, err := readFull(r, int64(length))
+	if err != nil {
+		return nil, fmt.Errorf("reading arrow message metadata: %w", err)
+	}
+
+	msg := new(message)
+	var bodyLength int64
+	err = fbDecode(func() error {
+		root := fbRoot(metadata)
+		if version := root.int16(0, 0); version < metadataVersion {
+			return fmt.Errorf("unsupported arrow metadata version: V%d", version+1)
+		}
+		header, ok := root.table(2)
+		if !ok {
+			return fmt.Errorf("arrow message has no header")
+		}
+		msg.typ = messageType(root.uint8(1, 0))
+		msg.header = header
+		bodyLength = root.int64(3, 0)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bodyLength < 0 {
+		return nil, fmt.Errorf("invalid arrow message body length: %d", bodyLength)
+	}
+
+	if msg.body, err = readFull(r, bodyLength); err != nil {
+		return nil, fmt.Errorf("reading arrow message body: %w", err)
+	}
+	return msg, nil
+}
+
+// readFull reads exactly n bytes from r. The data is read incrementally instead
+// of being allocated upfront so corrupted lengths do not trigger large memory
+// allocations.
+func readFull(r io.Reader, n int64) ([]byte, error) {
+	b := new(bytes.Buffer)
+	if _, err := io.CopyN(b, r, n); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return b.Bytes(), nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func encodeSchema(schema *Schema) *fbTable {
+	return new(fbTable).
+		int16(0, 0). // little endian
+		ref(1, encodeFields(schema.Fields))
+}
+
+func encodeFields(fields []Field) fbTables {
+	tables := make(fbTables, len(fields))
+	for i := range fields {
+		tables[i] = encodeField(&fields[i])
+	}
+	return tables
+}
+
+func encodeField(field *Field) *fbTable {
+	t := new(fbTable)
+	if field.Name != "" {
+		t.ref(0, fbString(field.Name))
+	}
+	return t.
+		bool(1, field.Nullable).
+		uint8(2, uint8(field.Type.typeID())).
+		ref(3, encodeType(field.Type)).
+		ref(5, encodeFields(field.Children))
+}
+
+func encodeType(typ Type) *fbTable {
+	t := new(fbTable)
+	switch typ := typ.(type) {
+	case Int:
+		t.int32(0, int32(typ.BitWidth)).bool(1, typ.Signed)
+	case FloatingPoint:
+		t.int16(0, int16(typ.Precision))
+	case FixedSizeBinary:
+		t.int32(0, int32(typ.ByteWidth))
+	case Date:
+		t.int16(0, int16(typ.Unit))
+	case Time:
+		t.int16(0, int16(typ.Unit)).int32(1, int32(typ.BitWidth))
+	case Timestamp:
+		t.int16(0, int16(typ.Unit))
+		if typ.Timezone != "" {
+			t.ref(1, fbString(typ.Timezone))
+		}
+	case Map:
+		t.bool(0, typ.KeysSorted)
+	}
+	return t
+}
+
+func decodeSchema(msg *message) (*Schema, error) {
+	if msg.typ != schemaMessage {
+		return nil, fmt.Errorf("expected arrow Schema message but got %s", msg.typ)
+	}
+	schema := new(Schema)
+	err := fbDecode(func() error {
+		if endianness := msg.header.int16(0, 0); endianness != 0 {
+			return fmt.Errorf("big endian arrow streams are not supported")
+		}
+		fields, err := decodeFields(msg.header.tables(1))
+		schema.Fields = fields
+		return err
+	})
+	return schema, err
+}
+
+func decodeFields(tables []fbRef) ([]Field, error) {
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	fields := make([]Field, len(tables))
+	for i, t := range tables {
+		f := &fields[i]
+		f.Name = t.string(0)
+		f.Nullable = t.bool(1)
+		if _, ok := t.table(4); ok {
+			return nil, fmt.Errorf("%s: dictionary encoded arrow fields are not supported", f.Name)
+		}
+		typ, err := decodeType(typeID(t.uint8(2, 0)), t)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		children, err := decodeFields(t.tables(5))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		f.Type = typ
+		f.Children = children
+	}
+	return fields, nil
+}
+
+func decodeType(id typeID, field fbRef) (Type, error) {
+	t, _ := field.table(3)
+	if t.buf == nil {
+		switch id {
+		case typeBinary, typeUtf8, typeBool, typeList, typeStruct:
+			// Tables of these types have no fields, it is valid for encoders
+			// to omit them.
+		default:
+			return nil, fmt.Errorf("missing arrow type information")
+		}
+	}
+	switch id {
+	case typeInt:
+		typ := Int{BitWidth: int(t.int32(0, 0)), Signed: t.bool(1)}
+		switch typ.BitWidth {
+		case 8, 16, 32, 64:
+			return typ, nil
+		}
+		return nil, fmt.Errorf("unsupported arrow type: %s", typ)
+	case typeFloatingPoint:
+		typ := FloatingPoint{Precision: Precision(t.int16(0, 0))}
+		if typ.Precision != Single && typ.Precision != Double {
+			return nil, fmt.Errorf("unsupported arrow type: %s", typ)
+		}
+		return typ, nil
+	case typeBinary:
+		return Binary{}, nil
+	case typeUtf8:
+		return Utf8{}, nil
+	case typeBool:
+		return Bool{}, nil
+	case typeFixedSizeBinary:
+		typ := FixedSizeBinary{ByteWidth: int(t.int32(0, 0))}
+		if typ.ByteWidth <= 0 {
+			return nil, fmt.Errorf("unsupported arrow type: %s", typ)
+		}
+		return typ, nil
+	case typeDate:
+		typ := Date{Unit: DateUnit(t.int16(0, int16(DateMillisecond)))}
+		if typ.Unit != Day && typ.Unit != DateMillisecond {
+			return nil, fmt.Errorf("unsupported arrow date unit: %d", typ.Unit)
+		}
+		return typ, nil
+	case typeTime:
+		typ := Time{Unit: TimeUnit(t.int16(0, int16(Millisecond))), BitWidth: int(t.int32(1, 32))}
+		if !isValidTimeUnit(typ.Unit) || (typ.BitWidth != 32 && typ.BitWidth != 64) {
+			return nil, fmt.Errorf("unsupported arrow type: %s", typ)
+		}
+		return typ, nil
+	case typeTimestamp:
+		typ := Timestamp{Unit: TimeUnit(t.int16(0, 0)), Timezone: t.string(1)}
+		if !isValidTimeUnit(typ.Unit) {
+			return nil, fmt.Errorf("unsupported arrow type: %s", typ)
+		}
+		return typ, nil
+	case typeList:
+		return List{}, nil
+	case typeStruct:
+		return Struct{}, nil
+	case typeMap:
+		return Map{KeysSorted: t.bool(0)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported arrow type: Type(%d)", id)
+	}
+}
+
+func isValidTimeUnit(unit TimeUnit) bool {
+	return unit >= Second && unit <= Nanosecond
+}
+
+// recordBatch is the representation of the metadata and body of an Arrow
+// record batch.
+//
+// Each element of nodes holds the length and null count of an array, each
+// element of buffers holds the offset and length of a buffer in the body.
+type recordBatch struct {
+	length  int64
+	nodes   [][2]int64
+	buffers [][2]int64
+	body    []byte
+}
+
+func (b *recordBatch) appendNode(length, nullCount int) {
+	b.nodes = append(b.nodes, [2]int64{int64(length), int64(nullCount)})
+}
+
+// appendBuffer appends data to the body of the record batch, padding it to a
+// multiple of 8 bytes as required by the IPC format.
+func (b *recordBatch) appendBuffer(data []byte) {
+	offset := len(b.body)
+	b.body = append(b.body, data...)
+	for len(b.body)%8 != 0 {
+		b.body = append(b.body, 0)
+	}
+	b.buffers = append(b.buffers, [2]int64{int64(offset), int64(len(data))})
+}
+
+func (b *recordBatch) reset() {
+	b.length = 0
+	b.nodes = b.nodes[:0]
+	b.buffers = b.buffers[:0]
+	b.body = b.body[:0]
+}
+
+func encodeRecordBatch(b *recordBatch) *fbTable {
+	return new(fbTable).
+		int64(0, b.length).
+		ref(1, fbStructs(b.nodes)).
+		ref(2, fbStructs(b.buffers))
+}
+
+func decodeRecordBatch(msg *message) (*recordBatch, error) {
+	if msg.typ != recordBatchMessage {
+		return nil, fmt.Errorf("expected arrow RecordBatch message but got %s", msg.typ)
+	}
+	b := &recordBatch{body: msg.body}
+	err := fbDecode(func() error {
+		if _, ok := msg.header.table(3); ok {
+			return fmt.Errorf("compressed arrow record batches are not supported")
+		}
+		b.length = msg.header.int64(0, 0)
+		b.nodes = msg.header.structs(1)
+		b.buffers = msg.header.structs(2)
+		return nil
+	})
+	return b, err
+}
@@ -0,0 +1,559 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/segmentio/parquet-go"
+)
+
+// Reader decodes record batches from an Arrow IPC stream into parquet
+// buffers.
+type Reader struct {
+	input  io.Reader
+	schema *Schema
+
+	target  *parquet.Schema
+	fields  []*importNode
+	columns [][]parquet.Value
+}
+
+// NewReader constructs a reader of the Arrow IPC stream read from input.
+//
+// The function reads the schema message at the beginning of the stream, and
+// returns an error if it could not be decoded.
+func NewReader(input io.Reader) (*Reader, error) {
+	msg, err := readMessage(input)
+	if err != nil {
+		return nil, fmt.Errorf("reading arrow schema: %w", unexpectedEOF(err))
+	}
+	schema, err := decodeSchema(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{input: input, schema: schema}, nil
+}
+
+// Schema returns the Arrow schema of the stream.
+func (r *Reader) Schema() *Schema { return r.schema }
+
+// ReadRecordBatch reads the next record batch of the stream and writes its
+// rows to buffer, column by column. The method returns the number of rows
+// written, or io.EOF when the end of the stream was reached.
+//
+// The Arrow fields are matched with the parquet fields of the buffer by name,
+// the parquet schema may use either the standard LIST and MAP groups or
+// repeated fields to represent Arrow lists. The conversion fails if the Arrow
+// and parquet schemas do not have the same set of fields, or if a null value
+// is found for a field which is required in the parquet schema.
+func (r *Reader) ReadRecordBatch(buffer *parquet.Buffer) (int64, error) {
+	schema := buffer.Schema()
+	if schema == nil {
+		return 0, parquet.ErrRowGroupSchemaMissing
+	}
+	if schema != r.target {
+		if err := r.configure(schema); err != nil {
+			return 0, err
+		}
+	}
+
+	msg, err := readMessage(r.input)
+	if err != nil {
+		return 0, err
+	}
+	if msg.typ == dictionaryBatchMessage {
+		return 0, fmt.Errorf("dictionary encoded arrow record batches are not supported")
+	}
+	batch, err := decodeRecordBatch(msg)
+	if err != nil {
+		return 0, err
+	}
+
+	arrays := make([]*array, len(r.schema.Fields))
+	d := &arrayDecoder{batch: batch}
+	for i := range r.schema.Fields {
+		a, err := d.decode(&r.schema.Fields[i])
+		if err != nil {
+			return 0, fmt.Errorf("decoding arrow record batch: %s: %w", r.schema.Fields[i].Name, err)
+		}
+		if int64(a.length) < batch.length {
+			return 0, fmt.Errorf("decoding arrow record batch: %s: array has %d values but the record batch has %d rows", r.schema.Fields[i].Name, a.length, batch.length)
+		}
+		arrays[i] = a
+	}
+
+	for i, values := range r.columns {
+		r.columns[i] = values[:0]
+	}
+	for row := 0; row < int(batch.length); row++ {
+		for i, n := range r.fields {
+			if err := r.readValues(n, arrays[i], row, 0, 0); err != nil {
+				return 0, fmt.Errorf("%s: row %d: %w", r.schema.Fields[i].Name, row, err)
+			}
+		}
+	}
+
+	columns := buffer.ColumnBuffers()
+	for i, values := range r.columns {
+		if _, err := columns[i].WriteValues(values); err != nil {
+			return 0, err
+		}
+	}
+	return batch.length, nil
+}
+
+// Copy reads the record batches of the Arrow IPC stream from src and writes
+// them as row groups of the given parquet schema to dst.
+//
+// The function returns the number of rows written.
+func Copy(dst parquet.RowGroupWriter, src *Reader, schema *parquet.Schema) (int64, error) {
+	buffer := parquet.NewBuffer(schema)
+	numRows := int64(0)
+	for {
+		buffer.Reset()
+		if _, err := src.ReadRecordBatch(buffer); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return numRows, err
+		}
+		n, err := dst.WriteRowGroup(buffer)
+		numRows += n
+		if err != nil {
+			return numRows, err
+		}
+	}
+}
+
+func (r *Reader) configure(schema *parquet.Schema) error {
+	parquetFields := schema.Fields()
+	if len(parquetFields) != len(r.schema.Fields) {
+		return fmt.Errorf("arrow schema has %d fields but parquet schema has %d", len(r.schema.Fields), len(parquetFields))
+	}
+
+	fields := make([]*importNode, len(r.schema.Fields))
+	columnIndex := 0
+	for _, f := range parquetFields {
+		i := indexOfField(r.schema.Fields, f.Name())
+		if i < 0 {
+			return fmt.Errorf("%s: field of parquet schema is missing from the arrow schema", f.Name())
+		}
+		n, err := newImportNode(&r.schema.Fields[i], f, &columnIndex, 0)
+		if err != nil {
+			return err
+		}
+		fields[i] = n
+	}
+
+	r.target = schema
+	r.fields = fields
+	r.columns = make([][]parquet.Value, columnIndex)
+	return nil
+}
+
+func indexOfField(fields []Field, name string) int {
+	for i := range fields {
+		if fields[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+type importKind int
+
+const (
+	importLeaf importKind = iota
+	importGroup
+	importList
+)
+
+// importNode associates an Arrow field with the parquet node that its values
+// are written to.
+//
+// The children of group nodes are ordered like the parquet fields, indexes
+// holds the index of the corresponding children of the Arrow field.
+type importNode struct {
+	kind     importKind
+	field    *Field
+	optional bool
+
+	// leaf
+	column int
+	typ    parquet.Kind
+
+	// list
+	repetitionLevel int
+
+	children []*importNode
+	indexes  []int
+	columns  []int
+}
+
+func newImportNode(field *Field, node parquet.Node, columnIndex *int, repetitionLevel int) (*importNode, error) {
+	n, err := newImportNodeOf(field, node, columnIndex, repetitionLevel)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field.Name, err)
+	}
+	return n, nil
+}
+
+func newImportNodeOf(field *Field, node parquet.Node, columnIndex *int, repetitionLevel int) (*importNode, error) {
+	n := &importNode{field: field}
+	firstColumn := *columnIndex
+	defer func() {
+		for i := firstColumn; i < *columnIndex; i++ {
+			n.columns = append(n.columns, i)
+		}
+	}()
+
+	if node.Repeated() {
+		if _, ok := field.Type.(List); !ok || len(field.Children) != 1 {
+			return nil, fmt.Errorf("repeated parquet field cannot be written from arrow values of type %s", field.Type)
+		}
+		elem, err := newImportNode(&field.Children[0], parquet.Required(node), columnIndex, repetitionLevel+1)
+		if err != nil {
+			return nil, err
+		}
+		n.kind = importList
+		n.repetitionLevel = repetitionLevel + 1
+		n.children = []*importNode{elem}
+		return n, nil
+	}
+
+	n.optional = node.Optional()
+
+	switch field.Type.(type) {
+	case List:
+		elem := parquet.ListElementOf(node)
+		if elem == nil || len(field.Children) != 1 {
+			return nil, fmt.Errorf("arrow list cannot be written to parquet field of type %s", node.Type())
+		}
+		child, err := newImportNode(&field.Children[0], elem, columnIndex, repetitionLevel+1)
+		if err != nil {
+			return nil, err
+		}
+		n.kind = importList
+		n.repetitionLevel = repetitionLevel + 1
+		n.children = []*importNode{child}
+
+	case Map:
+		keyValue := parquet.MapKeyValueOf(node)
+		if keyValue == nil || len(field.Children) != 1 || len(field.Children[0].Children) != 2 {
+			return nil, fmt.Errorf("arrow map cannot be written to parquet field of type %s", node.Type())
+		}
+		// The names of the key and value fields are not significant in Arrow,
+		// they are matched by position.
+		entries := &field.Children[0]
+		child := &importNode{kind: importGroup, field: entries}
+		for _, f := range keyValue.Fields() {
+			i := 0
+			if f.Name() == "value" {
+				i = 1
+			}
+			c, err := newImportNode(&entries.Children[i], f, columnIndex, repetitionLevel+1)
+			if err != nil {
+				return nil, err
+			}
+			child.children = append(child.children, c)
+			child.indexes = append(child.indexes, i)
+			child.columns = append(child.columns, c.columns...)
+		}
+		n.kind = importList
+		n.repetitionLevel = repetitionLevel + 1
+		n.children = []*importNode{child}
+
+	case Struct:
+		fields := node.Fields()
+		if node.Leaf() || len(fields) != len(field.Children) {
+			return nil, fmt.Errorf("arrow struct cannot be written to parquet field of type %s", node.Type())
+		}
+		n.kind = importGroup
+		for _, f := range fields {
+			i := indexOfField(field.Children, f.Name())
+			if i < 0 {
+				return nil, fmt.Errorf("%s: field of parquet schema is missing from the arrow schema", f.Name())
+			}
+			c, err := newImportNode(&field.Children[i], f, columnIndex, repetitionLevel)
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, c)
+			n.indexes = append(n.indexes, i)
+		}
+
+	default:
+		if !node.Leaf() {
+			return nil, fmt.Errorf("arrow %s values cannot be written to parquet group", field.Type)
+		}
+		typ := node.Type().Kind()
+		if !canConvert(field.Type, typ) {
+			return nil, fmt.Errorf("arrow %s values cannot be written to parquet column of type %s", field.Type, node.Type())
+		}
+		n.kind = importLeaf
+		n.column = *columnIndex
+		n.typ = typ
+		*columnIndex++
+	}
+
+	return n, nil
+}
+
+// canConvert returns true if values of the Arrow type t can be written to
+// parquet columns of the given kind.
+func canConvert(t Type, kind parquet.Kind) bool {
+	switch t := t.(type) {
+	case Bool:
+		return kind == parquet.Boolean
+	case Int, Date, Time, Timestamp:
+		return kind == parquet.Int32 || kind == parquet.Int64
+	case FloatingPoint:
+		return (t.Precision == Single && kind == parquet.Float) || (t.Precision == Double && kind == parquet.Double)
+	case Binary, Utf8:
+		return kind == parquet.ByteArray
+	case FixedSizeBinary:
+		return kind == parquet.FixedLenByteArray || (kind == parquet.Int96 && t.ByteWidth == 12)
+	default:
+		return false
+	}
+}
+
+// readValues appends to the columns of the reader the parquet values of the
+// element at index i of the array a.
+func (r *Reader) readValues(n *importNode, a *array, i, repetitionLevel, definitionLevel int) error {
+	if a.isNull(i) {
+		if !n.optional {
+			return fmt.Errorf("%s: null value found for a required parquet field", n.field.Name)
+		}
+		r.readNulls(n, repetitionLevel, definitionLevel)
+		return nil
+	}
+	if n.optional {
+		definitionLevel++
+	}
+
+	switch n.kind {
+	case importGroup:
+		for k, child := range n.children {
+			if err := r.readValues(child, a.children[n.indexes[k]], i, repetitionLevel, definitionLevel); err != nil {
+				return err
+			}
+		}
+
+	case importList:
+		start, end := a.offset(i), a.offset(i+1)
+		if start == end {
+			r.readNulls(n, repetitionLevel, definitionLevel)
+			return nil
+		}
+		for j := start; j < end; j++ {
+			if err := r.readValues(n.children[0], a.children[0], j, repetitionLevel, definitionLevel+1); err != nil {
+				return err
+			}
+			repetitionLevel = n.repetitionLevel
+		}
+
+	default:
+		v := a.value(i, n.typ)
+		r.columns[n.column] = append(r.columns[n.column], v.Level(repetitionLevel, definitionLevel, n.column))
+	}
+	return nil
+}
+
+func (r *Reader) readNulls(n *importNode, repetitionLevel, definitionLevel int) {
+	for _, columnIndex := range n.columns {
+		r.columns[columnIndex] = append(r.columns[columnIndex], parquet.Value{}.Level(repetitionLevel, definitionLevel, columnIndex))
+	}
+}
+
+// array is the representation of an Arrow array decoded from the body of a
+// record batch.
+type array struct {
+	field     *Field
+	length    int
+	nullCount int
+	validity  []byte
+	offsets   []byte
+	values    []byte
+	children  []*array
+}
+
+func (a *array) isNull(i int) bool {
+	return a.nullCount != 0 && (a.validity[i/8]&(1<<(i%8))) == 0
+}
+
+func (a *array) offset(i int) int {
+	return int(int32(binary.LittleEndian.Uint32(a.offsets[4*i:])))
+}
+
+// value returns the element at index i of the array, converted to a parquet
+// value of the given kind.
+func (a *array) value(i int, kind parquet.Kind) parquet.Value {
+	switch t := a.field.Type.(type) {
+	case Bool:
+		return parquet.ValueOf(a.values[i/8]&(1<<(i%8)) != 0)
+
+	case Binary, Utf8:
+		return kind.Value(a.values[a.offset(i):a.offset(i+1)])
+
+	case FixedSizeBinary:
+		return kind.Value(a.values[i*t.ByteWidth : (i+1)*t.ByteWidth])
+
+	case FloatingPoint:
+		if t.Precision == Single {
+			return parquet.ValueOf(math.Float32frombits(binary.LittleEndian.Uint32(a.values[4*i:])))
+		}
+		return parquet.ValueOf(math.Float64frombits(binary.LittleEndian.Uint64(a.values[8*i:])))
+
+	default:
+		var v int64
+		signed := true
+		if t, ok := t.(Int); ok {
+			signed = t.Signed
+		}
+		switch size := byteWidthOf(t); {
+		case size == 1 && signed:
+			v = int64(int8(a.values[i]))
+		case size == 1:
+			v = int64(a.values[i])
+		case size == 2 && signed:
+			v = int64(int16(binary.LittleEndian.Uint16(a.values[2*i:])))
+		case size == 2:
+			v = int64(binary.LittleEndian.Uint16(a.values[2*i:]))
+		case size == 4 && signed:
+			v = int64(int32(binary.LittleEndian.Uint32(a.values[4*i:])))
+		case size == 4:
+			v = int64(binary.LittleEndian.Uint32(a.values[4*i:]))
+		default:
+			v = int64(binary.LittleEndian.Uint64(a.values[8*i:]))
+		}
+		if kind == parquet.Int32 {
+			return parquet.ValueOf(int32(v))
+		}
+		return parquet.ValueOf(v)
+	}
+}
+
+// arrayDecoder decodes the arrays of a record batch, consuming the field nodes
+// and buffers in the depth-first order of the schema fields.
+type arrayDecoder struct {
+	batch  *recordBatch
+	node   int
+	buffer int
+}
+
+func (d *arrayDecoder) nextNode() (length, nullCount int, err error) {
+	if d.node >= len(d.batch.nodes) {
+		return 0, 0, fmt.Errorf("missing field node")
+	}
+	n := d.batch.nodes[d.node]
+	d.node++
+	if n[0] < 0 || n[0] > math.MaxInt32 || n[1] < 0 || n[1] > n[0] {
+		return 0, 0, fmt.Errorf("invalid field node: length=%d null_count=%d", n[0], n[1])
+	}
+	return int(n[0]), int(n[1]), nil
+}
+
+func (d *arrayDecoder) nextBuffer(minSize int) ([]byte, error) {
+	if d.buffer >= len(d.batch.buffers) {
+		return nil, fmt.Errorf("missing buffer")
+	}
+	b := d.batch.buffers[d.buffer]
+	d.buffer++
+	offset, length := b[0], b[1]
+	if offset < 0 || length < 0 || offset > int64(len(d.batch.body)) || length > int64(len(d.batch.body))-offset {
+		return nil, fmt.Errorf("buffer out of bounds: offset=%d length=%d body=%d", offset, length, len(d.batch.body))
+	}
+	if length < int64(minSize) {
+		return nil, fmt.Errorf("buffer too short: expected at least %d bytes but got %d", minSize, length)
+	}
+	return d.batch.body[offset : offset+length], nil
+}
+
+func (d *arrayDecoder) decode(field *Field) (*array, error) {
+	length, nullCount, err := d.nextNode()
+	if err != nil {
+		return nil, err
+	}
+	a := &array{field: field, length: length, nullCount: nullCount}
+
+	validitySize := 0
+	if nullCount > 0 {
+		validitySize = (length + 7) / 8
+	}
+	if a.validity, err = d.nextBuffer(validitySize); err != nil {
+		return nil, err
+	}
+
+	switch t := field.Type.(type) {
+	case Bool:
+		a.values, err = d.nextBuffer((length + 7) / 8)
+
+	case Binary, Utf8:
+		if a.offsets, err = d.decodeOffsets(length); err != nil {
+			return nil, err
+		}
+		if a.values, err = d.nextBuffer(0); err != nil {
+			return nil, err
+		}
+		err = checkOffsets(a, len(a.values))
+
+	case List, Map:
+		if len(field.Children) != 1 {
+			return nil, fmt.Errorf("%s must have exactly one child but %d were found", field.Type, len(field.Children))
+		}
+		if a.offsets, err = d.decodeOffsets(length); err != nil {
+			return nil, err
+		}
+		child, err := d.decode(&field.Children[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Children[0].Name, err)
+		}
+		a.children = []*array{child}
+		if err := checkOffsets(a, child.length); err != nil {
+			return nil, err
+		}
+
+	case Struct:
+		a.children = make([]*array, len(field.Children))
+		for i := range field.Children {
+			child, err := d.decode(&field.Children[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.Children[i].Name, err)
+			}
+			if child.length < length {
+				return nil, fmt.Errorf("%s: struct child has %d values but the struct has %d", field.Children[i].Name, child.length, length)
+			}
+			a.children[i] = child
+		}
+
+	default:
+		size := byteWidthOf(t)
+		if size == 0 {
+			return nil, fmt.Errorf("unsupported arrow type: %s", t)
+		}
+		a.values, err = d.nextBuffer(length * size)
+	}
+
+	return a, err
+}
+
+func (d *arrayDecoder) decodeOffsets(length int) ([]byte, error) {
+	return d.nextBuffer(4 * (length + 1))
+}
+
+// checkOffsets validates that the offsets of a are increasing and within the
+// bounds of the values or child array that they index.
+func checkOffsets(a *array, limit int) error {
+	prev := 0
+	for i := 0; i <= a.length; i++ {
+		offset := a.offset(i)
+		if offset < prev || offset > limit {
+			return fmt.Errorf("invalid offset at index %d: %d", i, offset)
+		}
+		prev = offset
+	}
+	return nil
+}
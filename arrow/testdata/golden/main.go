@@ -0,0 +1,124 @@
+// Command golden writes testdata/golden.arrows, the Arrow IPC stream decoded
+// by TestGoldenStreamDecoding, with the Go implementation of Apache Arrow.
+//
+// The program depends on github.com/apache/arrow/go/arrow, which is not a
+// dependency of this module; it is run from a module created for it:
+//
+//	cp main.go /tmp/golden && cd /tmp/golden
+//	go mod init golden
+//	go get github.com/apache/arrow/go/arrow@v0.0.0-20211112161151-bc219186db40
+//	go run . golden.arrows
+//
+// The schema and rows must be kept in sync with goldenSchema and goldenRows in
+// arrow_test.go.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+var schema = arrow.NewSchema([]arrow.Field{
+	{Name: "bool", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+	{Name: "double", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	{Name: "int32", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+	{Name: "int64", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "list", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64), Nullable: true},
+	{Name: "map", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32), Nullable: true},
+	{Name: "string", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "struct", Type: arrow.StructOf(
+		arrow.Field{Name: "x", Type: arrow.PrimitiveTypes.Int32},
+		arrow.Field{Name: "y", Type: arrow.BinaryTypes.String, Nullable: true},
+	), Nullable: true},
+}, nil)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: golden <path>")
+	}
+	f, err := os.Create(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	mem := memory.NewGoAllocator()
+	w := ipc.NewWriter(f, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	boolean := b.Field(0).(*array.BooleanBuilder)
+	double := b.Field(1).(*array.Float64Builder)
+	int32s := b.Field(2).(*array.Int32Builder)
+	int64s := b.Field(3).(*array.Int64Builder)
+	list := b.Field(4).(*array.ListBuilder)
+	items := list.ValueBuilder().(*array.Int64Builder)
+	maps := b.Field(5).(*array.MapBuilder)
+	keys := maps.KeyBuilder().(*array.StringBuilder)
+	values := maps.ItemBuilder().(*array.Int32Builder)
+	str := b.Field(6).(*array.StringBuilder)
+	st := b.Field(7).(*array.StructBuilder)
+	x := st.FieldBuilder(0).(*array.Int32Builder)
+	y := st.FieldBuilder(1).(*array.StringBuilder)
+
+	write := func() {
+		rec := b.NewRecord()
+		defer rec.Release()
+		if err := w.Write(rec); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Row 0
+	boolean.Append(true)
+	double.Append(1.5)
+	int32s.Append(1)
+	int64s.Append(-1)
+	list.Append(true)
+	items.AppendValues([]int64{1, 2}, nil)
+	maps.Append(true)
+	keys.Append("a")
+	values.Append(1)
+	str.Append("a")
+	st.Append(true)
+	x.Append(1)
+	y.Append("one")
+
+	// Row 1
+	boolean.AppendNull()
+	double.Append(2.5)
+	int32s.AppendNull()
+	int64s.Append(0)
+	list.AppendNull()
+	maps.Append(true)
+	str.AppendNull()
+	st.Append(true)
+	x.Append(2)
+	y.AppendNull()
+
+	// Two record batches, to exercise reading more than one.
+	write()
+
+	// Row 2
+	boolean.Append(false)
+	double.AppendNull()
+	int32s.Append(3)
+	int64s.Append(1)
+	list.Append(true)
+	maps.AppendNull()
+	str.Append("ccc")
+	st.AppendNull()
+	x.Append(0)
+	y.AppendNull()
+
+	write()
+
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,113 @@
+"""Interoperability checks of the arrow package with pyarrow.
+
+The script has two modes:
+
+    python3 interop.py generate golden.arrows
+        Writes the golden Arrow IPC stream decoded by TestGoldenStreamDecoding.
+
+    python3 interop.py check <stream>
+        Reads a stream produced by the arrow package, which TestGoldenStreamEncoding
+        does when pyarrow is installed, and verifies its schema and values.
+
+The schema and rows must be kept in sync with goldenSchema and goldenRows in
+arrow_test.go.
+"""
+
+import sys
+
+import pyarrow as pa
+
+SCHEMA = pa.schema([
+    pa.field("bool", pa.bool_()),
+    pa.field("double", pa.float64()),
+    pa.field("int32", pa.int32()),
+    pa.field("int64", pa.int64(), nullable=False),
+    pa.field("list", pa.list_(pa.int64())),
+    pa.field("map", pa.map_(pa.string(), pa.int32())),
+    pa.field("string", pa.string()),
+    pa.field("struct", pa.struct([
+        pa.field("x", pa.int32(), nullable=False),
+        pa.field("y", pa.string()),
+    ])),
+])
+
+ROWS = [
+    {
+        "bool": True,
+        "double": 1.5,
+        "int32": 1,
+        "int64": -1,
+        "list": [1, 2],
+        "map": [("a", 1)],
+        "string": "a",
+        "struct": {"x": 1, "y": "one"},
+    },
+    {
+        "bool": None,
+        "double": 2.5,
+        "int32": None,
+        "int64": 0,
+        "list": None,
+        "map": [],
+        "string": None,
+        "struct": {"x": 2, "y": None},
+    },
+    {
+        "bool": False,
+        "double": None,
+        "int32": 3,
+        "int64": 1,
+        "list": [],
+        "map": None,
+        "string": "ccc",
+        "struct": None,
+    },
+]
+
+
+def generate(path):
+    table = pa.Table.from_pylist(ROWS, schema=SCHEMA)
+    with pa.OSFile(path, "wb") as f:
+        with pa.ipc.new_stream(f, SCHEMA) as writer:
+            # Two record batches, to exercise reading more than one.
+            writer.write_table(table, max_chunksize=2)
+
+
+def same_type(a, b):
+    # The names of the children of lists and maps differ between
+    # implementations, only their types and nullability are compared.
+    if pa.types.is_list(a) and pa.types.is_list(b):
+        return (a.value_field.nullable == b.value_field.nullable and
+                same_type(a.value_type, b.value_type))
+    if pa.types.is_map(a) and pa.types.is_map(b):
+        return (same_type(a.key_type, b.key_type) and
+                a.item_field.nullable == b.item_field.nullable and
+                same_type(a.item_type, b.item_type))
+    if pa.types.is_struct(a) and pa.types.is_struct(b):
+        return same_fields(list(a), list(b))
+    return a == b
+
+
+def same_fields(a, b):
+    a = {f.name: f for f in a}
+    b = {f.name: f for f in b}
+    return a.keys() == b.keys() and all(
+        a[k].nullable == b[k].nullable and same_type(a[k].type, b[k].type)
+        for k in a)
+
+
+def check(path):
+    with pa.OSFile(path, "rb") as f:
+        table = pa.ipc.open_stream(f).read_all()
+    if not same_fields(list(table.schema), list(SCHEMA)):
+        sys.exit("schema mismatch:\nwant:\n%s\ngot:\n%s" % (SCHEMA, table.schema))
+    table = table.select(SCHEMA.names)
+    rows = table.to_pylist()
+    if rows != ROWS:
+        sys.exit("rows mismatch:\nwant: %s\ngot:  %s" % (ROWS, rows))
+
+
+if __name__ == "__main__":
+    if len(sys.argv) != 3 or sys.argv[1] not in ("generate", "check"):
+        sys.exit(__doc__)
+    {"generate": generate, "check": check}[sys.argv[1]](sys.argv[2])
@@ -0,0 +1,495 @@
+package arrow
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/segmentio/parquet-go"
+)
+
+// Writer encodes parquet row groups to an Arrow IPC stream.
+//
+// Each row group written to the stream is converted to a single Arrow record
+// batch. The schema message is written before the first record batch, and the
+// end of stream marker is written when the writer is closed.
+type Writer struct {
+	output io.Writer
+	schema *parquet.Schema
+	arrow  *Schema
+	fields []*exportNode
+	leaves []*exportNode
+
+	columns []columnData
+	batch   recordBatch
+	values  []parquet.Value
+	scratch []byte
+
+	wroteSchema bool
+	closed      bool
+}
+
+// NewWriter constructs a writer of Arrow IPC streams to output, for row groups
+// of the given parquet schema.
+//
+// The function returns an error if the schema contains parquet types that have
+// no equivalent in Arrow.
+func NewWriter(output io.Writer, schema *parquet.Schema) (*Writer, error) {
+	arrowSchema, err := SchemaOf(schema)
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{
+		output: output,
+		schema: schema,
+		arrow:  arrowSchema,
+		fields: make([]*exportNode, len(arrowSchema.Fields)),
+	}
+	columnIndex := 0
+	for i, f := range schema.Fields() {
+		w.fields[i] = w.newExportNode(&arrowSchema.Fields[i], f, &columnIndex, 0, 0, 0)
+	}
+	w.columns = make([]columnData, len(w.leaves))
+	return w, nil
+}
+
+// Schema returns the Arrow schema of the stream.
+func (w *Writer) Schema() *Schema { return w.arrow }
+
+// WriteRowGroup writes the rows of rowGroup to the stream as an Arrow record
+// batch. The row group must have the schema that the writer was created with.
+//
+// The method returns the number of rows written.
+func (w *Writer) WriteRowGroup(rowGroup parquet.RowGroup) (int64, error) {
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if rowGroup.Schema().String() != w.schema.String() {
+		return 0, parquet.ErrRowGroupSchemaMismatch
+	}
+	if err := w.writeSchema(); err != nil {
+		return 0, err
+	}
+
+	for i, chunk := range rowGroup.ColumnChunks() {
+		if err := w.readColumnChunk(&w.columns[i], w.leaves[i], chunk); err != nil {
+			return 0, fmt.Errorf("reading column %d: %w", i, err)
+		}
+	}
+
+	w.batch.reset()
+	w.batch.length = rowGroup.NumRows()
+	for _, f := range w.fields {
+		w.writeArray(f)
+	}
+
+	err := writeMessage(w.output, recordBatchMessage, encodeRecordBatch(&w.batch), w.batch.body)
+	if err != nil {
+		return 0, err
+	}
+	return w.batch.length, nil
+}
+
+// Close writes the end of stream marker. It does not close the underlying
+// output.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.writeSchema(); err != nil {
+		return err
+	}
+	w.closed = true
+	return writeEndOfStream(w.output)
+}
+
+func (w *Writer) writeSchema() error {
+	if w.wroteSchema {
+		return nil
+	}
+	w.wroteSchema = true
+	return writeMessage(w.output, schemaMessage, encodeSchema(w.arrow), nil)
+}
+
+// exportNode associates an Arrow field with the levels of the parquet columns
+// it is represented by.
+//
+// An entry of the parquet column at index column starts a new slot of the
+// Arrow array if its repetition level is less or equal to repetitionLevel and
+// its definition level is greater or equal to slotLevel; slots for which the
+// definition level is less than validLevel are null.
+//
+// The elements of lists are delimited by the same rules, using elemRepetition
+// and elemDefinition as levels.
+type exportNode struct {
+	field    *Field
+	column   int
+	maxLevel int // max definition level of leaf columns
+
+	repetitionLevel int
+	slotLevel       int
+	validLevel      int
+	elemRepetition  int
+	elemDefinition  int
+
+	children []*exportNode
+}
+
+func (w *Writer) newExportNode(field *Field, node parquet.Node, columnIndex *int, repetitionLevel, definitionLevel, slotLevel int) *exportNode {
+	n := &exportNode{
+		field:           field,
+		column:          *columnIndex,
+		repetitionLevel: repetitionLevel,
+		slotLevel:       slotLevel,
+		validLevel:      slotLevel,
+	}
+
+	if node.Repeated() {
+		// Repeated fields which are not part of a LIST or MAP group are
+		// represented as non-nullable lists of non-nullable elements.
+		n.elemRepetition = repetitionLevel + 1
+		n.elemDefinition = definitionLevel + 1
+		n.children = []*exportNode{
+			w.newExportNode(&field.Children[0], parquet.Required(node), columnIndex, n.elemRepetition, n.elemDefinition, n.elemDefinition),
+		}
+		return n
+	}
+
+	if node.Optional() {
+		definitionLevel++
+		n.validLevel = definitionLevel
+	}
+
+	switch field.Type.(type) {
+	case List:
+		n.elemRepetition = repetitionLevel + 1
+		n.elemDefinition = definitionLevel + 1
+		n.children = []*exportNode{
+			w.newExportNode(&field.Children[0], parquet.ListElementOf(node), columnIndex, n.elemRepetition, n.elemDefinition, n.elemDefinition),
+		}
+
+	case Map:
+		n.elemRepetition = repetitionLevel + 1
+		n.elemDefinition = definitionLevel + 1
+		entries := &field.Children[0]
+		n.children = []*exportNode{{
+			field:           entries,
+			column:          *columnIndex,
+			repetitionLevel: n.elemRepetition,
+			slotLevel:       n.elemDefinition,
+			validLevel:      n.elemDefinition,
+		}}
+		n.children[0].children = w.newExportChildren(entries, parquet.MapKeyValueOf(node), columnIndex, n.elemRepetition, n.elemDefinition, n.elemDefinition)
+
+	case Struct:
+		n.children = w.newExportChildren(field, node, columnIndex, repetitionLevel, definitionLevel, slotLevel)
+
+	default:
+		n.maxLevel = definitionLevel
+		*columnIndex++
+		w.leaves = append(w.leaves, n)
+	}
+
+	return n
+}
+
+// newExportChildren constructs the nodes of the children of a group, in the
+// order of the fields of the Arrow struct. Leaf columns are indexed in the
+// order of the parquet group, which may be different.
+func (w *Writer) newExportChildren(field *Field, node parquet.Node, columnIndex *int, repetitionLevel, definitionLevel, slotLevel int) []*exportNode {
+	children := make([]*exportNode, len(field.Children))
+	for _, f := range node.Fields() {
+		for i := range field.Children {
+			if field.Children[i].Name == f.Name() {
+				children[i] = w.newExportNode(&field.Children[i], f, columnIndex, repetitionLevel, definitionLevel, slotLevel)
+				break
+			}
+		}
+	}
+	return children
+}
+
+// columnData holds the content of a parquet column chunk being converted to
+// Arrow arrays.
+//
+// The repetition and definition levels are only retained for columns which
+// may have non-zero levels. The non-null values are stored in their PLAIN
+// representation (booleans use one byte per value), without the length prefix
+// for byte arrays, the offsets of byte array values are stored separately.
+type columnData struct {
+	numValues        int
+	valueSize        int
+	repetitionLevels []int8
+	definitionLevels []int8
+	values           []byte
+	offsets          []int32
+}
+
+func (c *columnData) reset() {
+	c.numValues = 0
+	c.repetitionLevels = c.repetitionLevels[:0]
+	c.definitionLevels = c.definitionLevels[:0]
+	c.values = c.values[:0]
+	c.offsets = append(c.offsets[:0], 0)
+}
+
+func (c *columnData) levels(i int) (repetitionLevel, definitionLevel int) {
+	if len(c.repetitionLevels) != 0 {
+		repetitionLevel = int(c.repetitionLevels[i])
+	}
+	if len(c.definitionLevels) != 0 {
+		definitionLevel = int(c.definitionLevels[i])
+	}
+	return repetitionLevel, definitionLevel
+}
+
+func (w *Writer) readColumnChunk(col *columnData, leaf *exportNode, chunk parquet.ColumnChunk) error {
+	col.reset()
+	hasLevels := leaf.maxLevel > 0 || leaf.repetitionLevel > 0
+	kind := chunk.Type().Kind()
+
+	col.valueSize = plainSizeOf(chunk.Type())
+	pages := chunk.Pages()
+
+	for {
+		page, err := pages.ReadPage()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		values := page.Values()
+		numValues := int(page.NumValues())
+
+		// Pages of required columns which are not dictionary encoded expose
+		// their values in the PLAIN encoding, which is the memory layout of
+		// Arrow arrays for fixed-size types: they can be copied directly.
+		if r, ok := values.(io.Reader); ok && !hasLevels && page.Dictionary() == nil && kind != parquet.Boolean && kind != parquet.ByteArray {
+			offset := len(col.values)
+			col.values = append(col.values, make([]byte, numValues*col.valueSize)...)
+			if _, err := io.ReadFull(r, col.values[offset:]); err != nil {
+				return unexpectedEOF(err)
+			}
+			col.numValues += numValues
+			continue
+		}
+
+		if err := w.readValues(col, values, hasLevels); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *Writer) readValues(col *columnData, values parquet.ValueReader, hasLevels bool) error {
+	if cap(w.values) == 0 {
+		w.values = make([]parquet.Value, 256)
+	}
+	for {
+		n, err := values.ReadValues(w.values)
+
+		for _, v := range w.values[:n] {
+			if hasLevels {
+				col.repetitionLevels = append(col.repetitionLevels, int8(v.RepetitionLevel()))
+				col.definitionLevels = append(col.definitionLevels, int8(v.DefinitionLevel()))
+			}
+			if !v.IsNull() {
+				switch v.Kind() {
+				case parquet.ByteArray:
+					col.values = append(col.values, v.ByteArray()...)
+					col.offsets = append(col.offsets, int32(len(col.values)))
+				default:
+					col.values = v.AppendBytes(col.values)
+				}
+			}
+		}
+		col.numValues += n
+
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// plainSizeOf returns the size of values of type t in the PLAIN encoding, or
+// zero for byte arrays which have a variable size.
+func plainSizeOf(t parquet.Type) int {
+	switch t.Kind() {
+	case parquet.Boolean:
+		return 1
+	case parquet.Int32, parquet.Float:
+		return 4
+	case parquet.Int64, parquet.Double:
+		return 8
+	case parquet.Int96:
+		return 12
+	case parquet.FixedLenByteArray:
+		return t.Length()
+	default:
+		return 0
+	}
+}
+
+// writeArray appends the Arrow array of n and its children to the record
+// batch being built.
+func (w *Writer) writeArray(n *exportNode) {
+	col := &w.columns[n.column]
+	nullable := n.validLevel > n.slotLevel
+
+	length, nullCount := 0, 0
+	validity := w.scratch[:0]
+
+	for i := 0; i < col.numValues; i++ {
+		r, d := col.levels(i)
+		if r > n.repetitionLevel || d < n.slotLevel {
+			continue
+		}
+		if nullable {
+			if length%8 == 0 {
+				validity = append(validity, 0)
+			}
+			if d >= n.validLevel {
+				validity[length/8] |= 1 << (length % 8)
+			} else {
+				nullCount++
+			}
+		}
+		length++
+	}
+
+	w.batch.appendNode(length, nullCount)
+	if nullCount == 0 {
+		validity = validity[:0]
+	}
+	w.batch.appendBuffer(validity)
+	w.scratch = validity[:0]
+
+	switch n.field.Type.(type) {
+	case List, Map:
+		w.writeOffsets(n, col)
+	case Struct:
+	default:
+		w.writeValues(n, col, length)
+		return
+	}
+
+	for _, child := range n.children {
+		w.writeArray(child)
+	}
+}
+
+func (w *Writer) writeOffsets(n *exportNode, col *columnData) {
+	offsets := append(w.scratch[:0], 0, 0, 0, 0)
+	count, started := 0, false
+
+	for i := 0; i < col.numValues; i++ {
+		r, d := col.levels(i)
+		if r <= n.repetitionLevel && d >= n.slotLevel {
+			if started {
+				offsets = appendUint32(offsets, uint32(count))
+			}
+			started = true
+		}
+		if r <= n.elemRepetition && d >= n.elemDefinition {
+			count++
+		}
+	}
+	if started {
+		offsets = appendUint32(offsets, uint32(count))
+	}
+
+	w.batch.appendBuffer(offsets)
+	w.scratch = offsets[:0]
+}
+
+func (w *Writer) writeValues(n *exportNode, col *columnData, length int) {
+	buf := w.scratch[:0]
+
+	switch t := n.field.Type.(type) {
+	case Bool:
+		buf = append(buf, make([]byte, (length+7)/8)...)
+		slot, value := 0, 0
+		for i := 0; i < col.numValues; i++ {
+			_, d := col.levels(i)
+			if d < n.slotLevel {
+				continue
+			}
+			if d == n.maxLevel {
+				if col.values[value] != 0 {
+					buf[slot/8] |= 1 << (slot % 8)
+				}
+				value++
+			}
+			slot++
+		}
+		w.batch.appendBuffer(buf)
+
+	case Binary, Utf8:
+		buf = append(buf, 0, 0, 0, 0)
+		value := 0
+		for i := 0; i < col.numValues; i++ {
+			_, d := col.levels(i)
+			if d < n.slotLevel {
+				continue
+			}
+			if d == n.maxLevel {
+				value++
+			}
+			buf = appendUint32(buf, uint32(col.offsets[value]))
+		}
+		w.batch.appendBuffer(buf)
+		w.batch.appendBuffer(col.values[:col.offsets[value]])
+
+	default:
+		size := byteWidthOf(t)
+		if length == col.numValues && len(col.values) == length*size {
+			// All values are present and in the Arrow representation.
+			w.batch.appendBuffer(col.values)
+			break
+		}
+		zero := make([]byte, size)
+		value := 0
+		for i := 0; i < col.numValues; i++ {
+			_, d := col.levels(i)
+			if d < n.slotLevel {
+				continue
+			}
+			if d == n.maxLevel {
+				offset := value * col.valueSize
+				buf = append(buf, col.values[offset:offset+size]...)
+				value++
+			} else {
+				buf = append(buf, zero...)
+			}
+		}
+		w.batch.appendBuffer(buf)
+	}
+
+	w.scratch = buf[:0]
+}
+
+// byteWidthOf returns the size of values of fixed-size Arrow types.
+func byteWidthOf(t Type) int {
+	switch t := t.(type) {
+	case Int:
+		return t.BitWidth / 8
+	case FloatingPoint:
+		return 2 << t.Precision
+	case FixedSizeBinary:
+		return t.ByteWidth
+	case Date:
+		if t.Unit == Day {
+			return 4
+		}
+		return 8
+	case Time:
+		return t.BitWidth / 8
+	case Timestamp:
+		return 8
+	default:
+		return 0
+	}
+}
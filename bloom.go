@@ -1,6 +1,7 @@
 package parquet
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/segmentio/parquet-go/bloom"
@@ -10,6 +11,7 @@ import (
 	"github.com/segmentio/parquet-go/encoding/plain"
 	"github.com/segmentio/parquet-go/format"
 	"github.com/segmentio/parquet-go/internal/bits"
+	"github.com/segmentio/parquet-go/internal/hyperloglog"
 )
 
 // BloomFilter is an interface allowing applications to test whether a key
@@ -104,6 +106,283 @@ func (f splitBlockFilter) Size(numValues int64, bitsPerValue uint) int {
 	return bloom.BlockSize * bloom.NumSplitBlocksOf(numValues, bitsPerValue)
 }
 
+// SplitBlockFilterFPP constructs a split block bloom filter object for the
+// column at the given path, sized to have a false positive probability of at
+// most fpp, which must be between zero and one (exclusive).
+//
+// Unlike filters created by SplitBlockFilter, which are sized from the number
+// of values in column chunks, the size of these filters depends on the number
+// of distinct values. Writers count the distinct values of dictionary-encoded
+// columns, and estimate it for other columns with a HyperLogLog sketch of the
+// values buffered to generate the filter.
+func SplitBlockFilterFPP(fpp float64, path ...string) BloomFilterColumn {
+	return SplitBlockFilterNDV(fpp, 0, path...)
+}
+
+// SplitBlockFilterNDV is like SplitBlockFilterFPP but the number of distinct
+// values in column chunks is given by ndv instead of being counted by writers,
+// which allows the filter to be allocated before the values are written and
+// avoids buffering pages of the column until the end of the row group.
+//
+// The ndv hint is ignored if it is zero or negative.
+func SplitBlockFilterNDV(fpp float64, ndv int64, path ...string) BloomFilterColumn {
+	if !(fpp > 0 && fpp < 1) {
+		panic(fmt.Sprintf("false positive probability of bloom filter must be between 0 and 1 (exclusive) but got %g", fpp))
+	}
+	if ndv < 0 {
+		ndv = 0
+	}
+	return &splitBlockFilterFPP{path: path, fpp: fpp, ndv: ndv}
+}
+
+type splitBlockFilterFPP struct {
+	path []string
+	fpp  float64
+	ndv  int64
+}
+
+func (f *splitBlockFilterFPP) Path() []string              { return f.path }
+func (f *splitBlockFilterFPP) Hash() bloom.Hash            { return bloom.XXH64{} }
+func (f *splitBlockFilterFPP) Encoding() encoding.Encoding { return splitBlockEncoding{} }
+
+// Size returns the size of the filter holding numValues distinct values with
+// the false positive probability of f, the number of bits per value is derived
+// from the probability and the bitsPerValue argument is ignored.
+func (f *splitBlockFilterFPP) Size(numValues int64, bitsPerValue uint) int {
+	return bloom.BlockSize * bloom.NumSplitBlocksOfFPP(numValues, f.fpp)
+}
+
+func (f *splitBlockFilterFPP) distinctValues() (ndv int64, estimate bool) {
+	return f.ndv, f.ndv == 0
+}
+
+// distinctValuesFilter is implemented by bloom filter columns which are sized
+// from the number of distinct values of column chunks.
+//
+// The distinctValues method returns the number of distinct values that the
+// filter was configured with, or a boolean set to true if it must be estimated
+// by the writer.
+type distinctValuesFilter interface {
+	distinctValues() (ndv int64, estimate bool)
+}
+
+// memoryBloomFilter is an implementation of the BloomFilter interface for split
+// block filters held in memory.
+type memoryBloomFilter struct{ bits []byte }
+
+func (f *memoryBloomFilter) ReadAt(b []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("reading bloom filter: negative offset: %d", off)
+	}
+	if off >= int64(len(f.bits)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.bits[off:])
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *memoryBloomFilter) Size() int64 { return int64(len(f.bits)) }
+
+func (f *memoryBloomFilter) Check(v Value) (bool, error) {
+	if len(f.bits) == 0 {
+		return false, nil
+	}
+	return bloom.MakeSplitBlockFilter(f.bits).Check(v.hash(bloom.XXH64{})), nil
+}
+
+// splitBlockFilterBits returns the bits of the given bloom filter if it is a
+// split block filter using the XXH64 hash function, which is the only kind of
+// filter that this package creates. The boolean is false if the filter is of
+// a different kind, in which case it cannot be copied or merged.
+func splitBlockFilterBits(filter BloomFilter) ([]byte, bool, error) {
+	switch f := filter.(type) {
+	case *memoryBloomFilter:
+		return f.bits, true, nil
+	case *bloomFilter:
+		// Filters read from files are only created for the split block
+		// algorithm and XXH64 hash function (see newBloomFilter).
+		bits := make([]byte, f.Size())
+		n, err := f.ReadAt(bits, 0)
+		if n == len(bits) {
+			err = nil
+		}
+		return bits, err == nil, err
+	default:
+		return nil, false, nil
+	}
+}
+
+// mergeBloomFilters combines split block filters of the same size into a single
+// filter containing the values of all the filters. Empty filters (e.g. of empty
+// column chunks) are ignored.
+//
+// The function returns nil if the filters cannot be combined.
+func mergeBloomFilters(filters []BloomFilter) BloomFilter {
+	var merged []byte
+
+	for _, filter := range filters {
+		if filter.Size() == 0 {
+			continue
+		}
+		bits, ok, err := splitBlockFilterBits(filter)
+		if !ok || err != nil {
+			return nil
+		}
+		switch {
+		case merged == nil:
+			merged = append(make([]byte, 0, len(bits)), bits...)
+		case len(merged) != len(bits):
+			return nil
+		default:
+			for i, b := range bits {
+				merged[i] |= b
+			}
+		}
+	}
+
+	return &memoryBloomFilter{bits: merged}
+}
+
+const (
+	// Number of values that bloom filters of buffers are initially sized for,
+	// unless the number of distinct values was given.
+	defaultBufferBloomFilterNumValues = 1024
+)
+
+// bufferBloomFilter is a split block filter built incrementally as values are
+// written to a column buffer.
+//
+// Filters are sized for a number of values (or distinct values for filters
+// created by SplitBlockFilterFPP), when more values are written the size of
+// the filter is doubled and the values held in the column buffer are inserted
+// again. This amortizes the cost of growing the filter, the same way appending
+// to a slice does.
+type bufferBloomFilter struct {
+	memoryBloomFilter
+	column BloomFilterColumn
+	// Number of values that the filter was sized for, and number of values
+	// inserted in the filter (including duplicates).
+	capacity  int64
+	numValues int64
+	// The number of distinct values is estimated when the number of values
+	// reaches the checkpoint; there cannot be more distinct values in the
+	// filter than its capacity until then.
+	checkpoint int64
+	sketch     *hyperloglog.Sketch
+	hashes     []uint64
+}
+
+func newBufferBloomFilter(column BloomFilterColumn) *bufferBloomFilter {
+	f := &bufferBloomFilter{column: column}
+	if _, ok := column.(distinctValuesFilter); ok {
+		f.sketch = new(hyperloglog.Sketch)
+	}
+	f.reset()
+	return f
+}
+
+func (f *bufferBloomFilter) clone() *bufferBloomFilter {
+	c := *f
+	c.bits = append([]byte{}, f.bits...)
+	c.hashes = nil
+	if f.sketch != nil {
+		c.sketch = new(hyperloglog.Sketch)
+		*c.sketch = *f.sketch
+	}
+	return &c
+}
+
+func (f *bufferBloomFilter) reset() {
+	capacity := int64(defaultBufferBloomFilterNumValues)
+	if d, ok := f.column.(distinctValuesFilter); ok {
+		if ndv, estimate := d.distinctValues(); !estimate {
+			capacity = ndv
+		}
+	}
+	if f.sketch != nil {
+		f.sketch.Reset()
+	}
+	f.numValues = 0
+	f.checkpoint = capacity
+	f.resize(capacity)
+}
+
+func (f *bufferBloomFilter) resize(capacity int64) {
+	const bitsPerValue = 10 // same as the default of writers
+	size := f.column.Size(capacity, bitsPerValue)
+	if cap(f.bits) < size {
+		f.bits = make([]byte, size)
+	} else {
+		f.bits = f.bits[:size]
+		for i := range f.bits {
+			f.bits[i] = 0
+		}
+	}
+	f.capacity = capacity
+}
+
+// insert adds values written to column to the filter. The values must already
+// have been written to the column, which is used to rebuild the filter when it
+// needs to grow.
+func (f *bufferBloomFilter) insert(column ColumnBuffer, values []Value) error {
+	f.hashes = f.hashes[:0]
+	for i := range values {
+		if !values[i].IsNull() {
+			f.hashes = append(f.hashes, values[i].hash(bloom.XXH64{}))
+		}
+	}
+	f.numValues += int64(len(f.hashes))
+	if f.sketch != nil {
+		f.sketch.InsertBulk(f.hashes)
+	}
+
+	if f.numValues > f.checkpoint {
+		numValues := f.numValues
+		if f.sketch != nil {
+			numValues = f.sketch.Estimate()
+		}
+		if numValues > f.capacity {
+			capacity := 2 * f.capacity
+			if capacity < numValues {
+				capacity = numValues
+			}
+			f.resize(capacity)
+			f.checkpoint = f.numValues + (f.capacity - numValues)
+			return f.rebuild(column)
+		}
+		f.checkpoint = f.numValues + (f.capacity - numValues)
+	}
+
+	bloom.MakeSplitBlockFilter(f.bits).InsertBulk(f.hashes)
+	return nil
+}
+
+// rebuild inserts all the values held in column into the filter.
+func (f *bufferBloomFilter) rebuild(column ColumnBuffer) error {
+	filter := bloom.MakeSplitBlockFilter(f.bits)
+	values := make([]Value, filterEncodeBufferSize)
+	reader := column.Page().Values()
+	for {
+		n, err := reader.ReadValues(values)
+		f.hashes = f.hashes[:0]
+		for i := range values[:n] {
+			if !values[i].IsNull() {
+				f.hashes = append(f.hashes, values[i].hash(bloom.XXH64{}))
+			}
+		}
+		filter.InsertBulk(f.hashes)
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return err
+		}
+	}
+}
+
 // Creates a header from the given bloom filter.
 //
 // For now there is only one type of filter supported, but we provide this
@@ -111,7 +390,7 @@ func (f splitBlockFilter) Size(numValues int64, bitsPerValue uint) int {
 // are added to the parquet specs.
 func bloomFilterHeader(filter BloomFilterColumn) (header format.BloomFilterHeader) {
 	switch filter.(type) {
-	case splitBlockFilter:
+	case splitBlockFilter, *splitBlockFilterFPP:
 		header.Algorithm.Block = &format.SplitBlockAlgorithm{}
 	}
 	switch filter.Hash().(type) {
@@ -144,6 +423,13 @@ type splitBlockEncoding struct {
 	encoding.NotSupported
 }
 
+// hashInserter is the interface implemented by the types receiving the hashes
+// of values, which are bloom filters or the buffers retaining hashes until the
+// filters can be sized (see hashEncoding).
+type hashInserter interface {
+	InsertBulk([]uint64)
+}
+
 func (splitBlockEncoding) EncodeBoolean(dst []byte, src []bool) ([]byte, error) {
 	splitBlockEncodeUint8(bloom.MakeSplitBlockFilter(dst), bits.BoolToBytes(src))
 	return dst, nil
@@ -175,7 +461,10 @@ func (splitBlockEncoding) EncodeDouble(dst []byte, src []float64) ([]byte, error
 }
 
 func (splitBlockEncoding) EncodeByteArray(dst, src []byte) ([]byte, error) {
-	filter := bloom.MakeSplitBlockFilter(dst)
+	return dst, splitBlockEncodeByteArray(bloom.MakeSplitBlockFilter(dst), src)
+}
+
+func splitBlockEncodeByteArray(filter hashInserter, src []byte) error {
 	buffer := make([]uint64, 0, filterEncodeBufferSize)
 
 	err := plain.RangeByteArrays(src, func(value []byte) error {
@@ -188,20 +477,67 @@ func (splitBlockEncoding) EncodeByteArray(dst, src []byte) ([]byte, error) {
 	})
 
 	filter.InsertBulk(buffer)
-	return dst, err
+	return err
 }
 
 func (splitBlockEncoding) EncodeFixedLenByteArray(dst, src []byte, size int) ([]byte, error) {
-	filter := bloom.MakeSplitBlockFilter(dst)
-	if size == 16 {
-		splitBlockEncodeUint128(filter, bits.BytesToUint128(src))
-	} else {
-		splitBlockEncodeFixedLenByteArray(filter, src, size)
-	}
+	splitBlockEncodeFixedLenByteArray(bloom.MakeSplitBlockFilter(dst), src, size)
+	return dst, nil
+}
+
+// hashEncoding is an encoding which passes the hashes of values to a
+// hashInserter. The values are hashed the same way as when they are inserted
+// in bloom filters.
+type hashEncoding struct {
+	encoding.NotSupported
+	hashes hashInserter
+}
+
+func (e hashEncoding) EncodeBoolean(dst []byte, src []bool) ([]byte, error) {
+	splitBlockEncodeUint8(e.hashes, bits.BoolToBytes(src))
+	return dst, nil
+}
+
+func (e hashEncoding) EncodeInt32(dst []byte, src []int32) ([]byte, error) {
+	splitBlockEncodeUint32(e.hashes, bits.Int32ToUint32(src))
+	return dst, nil
+}
+
+func (e hashEncoding) EncodeInt64(dst []byte, src []int64) ([]byte, error) {
+	splitBlockEncodeUint64(e.hashes, bits.Int64ToUint64(src))
+	return dst, nil
+}
+
+func (e hashEncoding) EncodeInt96(dst []byte, src []deprecated.Int96) ([]byte, error) {
+	splitBlockEncodeFixedLenByteArray(e.hashes, deprecated.Int96ToBytes(src), 12)
+	return dst, nil
+}
+
+func (e hashEncoding) EncodeFloat(dst []byte, src []float32) ([]byte, error) {
+	splitBlockEncodeUint32(e.hashes, bits.Float32ToUint32(src))
 	return dst, nil
 }
 
-func splitBlockEncodeFixedLenByteArray(filter bloom.SplitBlockFilter, data []byte, size int) {
+func (e hashEncoding) EncodeDouble(dst []byte, src []float64) ([]byte, error) {
+	splitBlockEncodeUint64(e.hashes, bits.Float64ToUint64(src))
+	return dst, nil
+}
+
+func (e hashEncoding) EncodeByteArray(dst, src []byte) ([]byte, error) {
+	return dst, splitBlockEncodeByteArray(e.hashes, src)
+}
+
+func (e hashEncoding) EncodeFixedLenByteArray(dst, src []byte, size int) ([]byte, error) {
+	splitBlockEncodeFixedLenByteArray(e.hashes, src, size)
+	return dst, nil
+}
+
+func splitBlockEncodeFixedLenByteArray(filter hashInserter, data []byte, size int) {
+	if size == 16 {
+		splitBlockEncodeUint128(filter, bits.BytesToUint128(data))
+		return
+	}
+
 	buffer := make([]uint64, 0, filterEncodeBufferSize)
 
 	for i, j := 0, size; j <= len(data); {
@@ -217,7 +553,7 @@ func splitBlockEncodeFixedLenByteArray(filter bloom.SplitBlockFilter, data []byt
 	filter.InsertBulk(buffer)
 }
 
-func splitBlockEncodeUint8(filter bloom.SplitBlockFilter, values []uint8) {
+func splitBlockEncodeUint8(filter hashInserter, values []uint8) {
 	buffer := make([]uint64, filterEncodeBufferSize)
 
 	for i := 0; i < len(values); {
@@ -227,7 +563,7 @@ func splitBlockEncodeUint8(filter bloom.SplitBlockFilter, values []uint8) {
 	}
 }
 
-func splitBlockEncodeUint32(filter bloom.SplitBlockFilter, values []uint32) {
+func splitBlockEncodeUint32(filter hashInserter, values []uint32) {
 	buffer := make([]uint64, filterEncodeBufferSize)
 
 	for i := 0; i < len(values); {
@@ -237,7 +573,7 @@ func splitBlockEncodeUint32(filter bloom.SplitBlockFilter, values []uint32) {
 	}
 }
 
-func splitBlockEncodeUint64(filter bloom.SplitBlockFilter, values []uint64) {
+func splitBlockEncodeUint64(filter hashInserter, values []uint64) {
 	buffer := make([]uint64, filterEncodeBufferSize)
 
 	for i := 0; i < len(values); {
@@ -247,7 +583,7 @@ func splitBlockEncodeUint64(filter bloom.SplitBlockFilter, values []uint64) {
 	}
 }
 
-func splitBlockEncodeUint128(filter bloom.SplitBlockFilter, values [][16]byte) {
+func splitBlockEncodeUint128(filter hashInserter, values [][16]byte) {
 	buffer := make([]uint64, filterEncodeBufferSize)
 
 	for i := 0; i < len(values); {
@@ -2,6 +2,7 @@ package bloom
 
 import (
 	"io"
+	"math"
 	"sync"
 	"unsafe"
 
@@ -52,6 +53,58 @@ func NumSplitBlocksOf(numValues int64, bitsPerValue uint) int {
 	return numBlocks
 }
 
+// NumSplitBlocksOfFPP returns the number of blocks in a filter intended to
+// hold the given number of distinct values with a false positive probability
+// of at most fpp, which must be between zero and one (exclusive).
+func NumSplitBlocksOfFPP(numValues int64, fpp float64) int {
+	numBits := math.Ceil(float64(numValues) * BitsPerValueOf(fpp))
+	numBlocks := int(math.Ceil(numBits / (8 * BlockSize)))
+	if numBlocks < 1 {
+		numBlocks = 1
+	}
+	return numBlocks
+}
+
+// BitsPerValueOf returns the number of bits of split block filter needed per
+// distinct value to obtain a false positive probability of at most fpp.
+//
+// For example, 10.5 bits per value give a false positive probability of 1%,
+// and 16.9 bits per value give 0.1%.
+func BitsPerValueOf(fpp float64) float64 {
+	lo, hi := 1.0, 2.0
+	for falsePositiveProbabilityOf(hi) > fpp {
+		lo, hi = hi, 2*hi
+	}
+	for i := 0; i < 32; i++ {
+		if mid := (lo + hi) / 2; falsePositiveProbabilityOf(mid) > fpp {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
+
+// falsePositiveProbabilityOf computes the false positive probability of split
+// block filters given the number of bits per distinct value.
+//
+// Each value sets one bit in each of the 8 words of 32 bits of a block. The
+// number of values in a block follows a Poisson distribution, a value absent
+// from the filter is a false positive if the 8 bits that it maps to in its
+// block were set by other values.
+func falsePositiveProbabilityOf(bitsPerValue float64) float64 {
+	const bitsPerBlock = 8 * BlockSize
+	lambda := bitsPerBlock / bitsPerValue
+	limit := int(lambda + 10*math.Sqrt(lambda) + 10)
+	poisson := math.Exp(-lambda)
+	fpp := 0.0
+	for k := 0; k < limit; k++ {
+		fpp += poisson * math.Pow(1-math.Pow(31.0/32.0, float64(k)), 8)
+		poisson *= lambda / float64(k+1)
+	}
+	return fpp
+}
+
 // Reset clears the content of the filter f.
 func (f SplitBlockFilter) Reset() {
 	for i := range f {
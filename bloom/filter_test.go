@@ -93,6 +93,29 @@ func TestSplitBlockFilterBug1(t *testing.T) {
 	}
 }
 
+func TestSplitBlockFilterFPP(t *testing.T) {
+	const N = 20e3
+	for _, fpp := range []float64{0.1, 0.01, 0.001} {
+		f := make(bloom.SplitBlockFilter, bloom.NumSplitBlocksOfFPP(N, fpp))
+		p := rand.New(rand.NewSource(1))
+		for i := 0; i < N; i++ {
+			f.Insert(p.Uint64())
+		}
+
+		falsePositives := 0
+		for i := 0; i < N; i++ {
+			if f.Check(p.Uint64()) {
+				falsePositives++
+			}
+		}
+
+		// Allow some margin since the rate is measured on a random sample.
+		if r := float64(falsePositives) / N; r > 1.5*fpp {
+			t.Errorf("bloom filter sized for %g false positive probability triggered too many false positives: %g", fpp, r)
+		}
+	}
+}
+
 type serializedFilter struct {
 	bytes.Reader
 }
@@ -79,6 +79,9 @@ func (buf *Buffer) configure(schema *Schema) {
 		case leaf.maxDefinitionLevel > 0:
 			column = newOptionalColumnBuffer(column, leaf.maxDefinitionLevel, nullOrdering)
 		}
+		if filter := searchBloomFilterColumn(buf.config.BloomFilters, leaf.path); filter != nil {
+			column = &bloomFilterColumnBuffer{column, newBufferBloomFilter(filter)}
+		}
 		buf.columns = append(buf.columns, column)
 
 		if sortingIndex := searchSortingColumn(sortingColumns, leaf.path); sortingIndex < len(sortingColumns) {
@@ -186,7 +189,10 @@ func (buf *Buffer) Write(row interface{}) error {
 	defer func() {
 		clearValues(buf.rowbuf)
 	}()
-	buf.rowbuf = buf.schema.Deconstruct(buf.rowbuf[:0], row)
+	var err error
+	if buf.rowbuf, err = buf.schema.deconstructValue(buf.rowbuf[:0], row); err != nil {
+		return err
+	}
 	return buf.WriteRow(buf.rowbuf)
 }
 
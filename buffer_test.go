@@ -2,6 +2,7 @@ package parquet_test
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"math"
 	"reflect"
@@ -414,6 +415,127 @@ func TestBufferGenerateBloomFilters(t *testing.T) {
 	}
 }
 
+func TestBufferBloomFilters(t *testing.T) {
+	type Row struct {
+		ID   int64   `parquet:"id"`
+		Name *string `parquet:"name,optional"`
+	}
+
+	const numRows = 10e3
+
+	filters := parquet.BloomFilters(
+		parquet.SplitBlockFilter("id"),
+		parquet.SplitBlockFilterFPP(0.01, "name"),
+	)
+
+	name := func(i int) string { return fmt.Sprintf("name-%d", i%100) }
+
+	newBuffer := func(offset int) *parquet.Buffer {
+		buffer := parquet.NewBuffer(filters, parquet.SortingColumns(parquet.Ascending("id")))
+		for i := offset; i < offset+numRows; i++ {
+			row := Row{ID: int64(i)}
+			if i%2 == 0 {
+				s := name(i)
+				row.Name = &s
+			}
+			if err := buffer.Write(&row); err != nil {
+				t.Fatal(err)
+			}
+		}
+		sort.Sort(buffer)
+		return buffer
+	}
+
+	check := func(t *testing.T, rowGroup parquet.RowGroup, begin, end int) {
+		t.Helper()
+		columns := rowGroup.ColumnChunks()
+		ids := columns[0].BloomFilter()
+		names := columns[1].BloomFilter()
+		if ids == nil || names == nil {
+			t.Fatal("missing bloom filters")
+		}
+		for i := begin; i < end; i++ {
+			if ok, err := ids.Check(parquet.ValueOf(int64(i))); err != nil {
+				t.Fatal(err)
+			} else if !ok {
+				t.Fatalf("bloom filter does not contain id %d", i)
+			}
+			if i%2 != 0 {
+				continue
+			}
+			if ok, err := names.Check(parquet.ValueOf(name(i))); err != nil {
+				t.Fatal(err)
+			} else if !ok {
+				t.Fatalf("bloom filter does not contain name %q", name(i))
+			}
+		}
+	}
+
+	t.Run("buffer", func(t *testing.T) {
+		buffer := newBuffer(0)
+		check(t, buffer, 0, numRows)
+
+		// The filters grow with the number of values written to the buffer.
+		filter := buffer.ColumnChunks()[0].BloomFilter()
+		if size := parquet.SplitBlockFilter("id").Size(numRows, 10); filter.Size() < int64(size) {
+			t.Errorf("bloom filter is too small: %d < %d", filter.Size(), size)
+		}
+
+		buffer.Reset()
+		if ok, _ := filter.Check(parquet.ValueOf(int64(0))); ok {
+			t.Error("bloom filter still contains values after the buffer was reset")
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		merged, err := parquet.MergeRowGroups(
+			[]parquet.RowGroup{newBuffer(0), newBuffer(numRows)},
+			parquet.SortingColumns(parquet.Ascending("id")),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, merged, 0, 2*numRows)
+	})
+
+	t.Run("write", func(t *testing.T) {
+		buffer := newBuffer(0)
+		output := new(bytes.Buffer)
+		writer := parquet.NewWriter(output, buffer.Schema(), filters)
+		if _, err := writer.WriteRowGroup(buffer); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rowGroup := f.RowGroups()[0]
+		check(t, rowGroup, 0, numRows)
+
+		// The filters of the buffer are written to the file as-is.
+		for i, column := range rowGroup.ColumnChunks() {
+			want := readBloomFilter(t, buffer.ColumnChunks()[i].BloomFilter())
+			got := readBloomFilter(t, column.BloomFilter())
+			if !bytes.Equal(want, got) {
+				t.Errorf("bloom filter of column %d was not copied from the buffer", i)
+			}
+		}
+	})
+}
+
+func readBloomFilter(t *testing.T, filter parquet.BloomFilter) []byte {
+	t.Helper()
+	b := make([]byte, filter.Size())
+	if _, err := filter.ReadAt(b, 0); err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	return b
+}
+
 func TestBufferRoundtripNestedRepeated(t *testing.T) {
 	type C struct {
 		D int
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/jsonl"
+)
+
+var catCommand = &command{
+	name:  "cat",
+	usage: "[-format json|csv] [-limit n] <file>...",
+	help:  "print the rows of parquet files as JSON or CSV",
+	run:   runCat,
+}
+
+func runCat(stdout io.Writer, flags *flag.FlagSet, args []string) error {
+	format := flags.String("format", "json", "output format, one of json or csv")
+	limit := flags.Int64("limit", 0, "maximum number of rows to print, zero means no limit")
+
+	files, err := parseFiles(flags, args, 1, 0)
+	if err != nil {
+		return err
+	}
+
+	var output rowWriter
+	switch *format {
+	case "json":
+		output = &jsonRowWriter{output: stdout}
+	case "csv":
+		output = &csvRowWriter{csv: csv.NewWriter(stdout)}
+	default:
+		flags.Usage()
+		return fmt.Errorf("cat: unsupported output format %q", *format)
+	}
+
+	numRows := int64(0)
+	for _, path := range files {
+		if *limit > 0 && numRows >= *limit {
+			break
+		}
+		n, err := catFile(output, path, *limit-numRows)
+		numRows += n
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return output.Flush()
+}
+
+func catFile(output rowWriter, path string, limit int64) (int64, error) {
+	f, close, err := openFile(path, parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return 0, err
+	}
+	defer close()
+
+	w := output.Reset(f.Schema())
+	reader := parquet.NewReader(f)
+	defer reader.Close()
+
+	var row parquet.Row
+	for n := int64(0); limit <= 0 || n < limit; n++ {
+		row, err = reader.ReadRow(row[:0])
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return n, err
+		}
+		if err := w.WriteRow(row); err != nil {
+			return n, err
+		}
+	}
+	return limit, nil
+}
+
+// rowWriter is the interface implemented by the output formats of the cat
+// command. Rows are converted to JSON by the jsonl package, Reset is called
+// with the schema of each file to return the writer of its rows.
+type rowWriter interface {
+	Reset(*parquet.Schema) parquet.RowWriter
+	Flush() error
+}
+
+type jsonRowWriter struct {
+	output io.Writer
+}
+
+func (w *jsonRowWriter) Reset(schema *parquet.Schema) parquet.RowWriter {
+	return jsonl.NewWriter(w.output, schema)
+}
+
+func (w *jsonRowWriter) Flush() error { return nil }
+
+// csvRowWriter writes one CSV record per row, with one column for each of the
+// top-level fields of the schema. Nested values are written in JSON.
+type csvRowWriter struct {
+	csv    *csv.Writer
+	header bool
+	line   bytes.Buffer
+	record []string
+}
+
+func (w *csvRowWriter) Reset(schema *parquet.Schema) parquet.RowWriter {
+	if !w.header {
+		w.header = true
+		w.record = w.record[:0]
+		for _, field := range schema.Fields() {
+			w.record = append(w.record, field.Name())
+		}
+		// The error is reported by Flush.
+		_ = w.csv.Write(w.record)
+	}
+	return &csvRowConverter{csv: w, json: jsonl.NewWriter(&w.line, schema)}
+}
+
+func (w *csvRowWriter) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+type csvRowConverter struct {
+	csv  *csvRowWriter
+	json *jsonl.Writer
+}
+
+func (c *csvRowConverter) WriteRows(rows []parquet.Row) (int, error) {
+	for i, row := range rows {
+		if err := c.WriteRow(row); err != nil {
+			return i, err
+		}
+	}
+	return len(rows), nil
+}
+
+func (c *csvRowConverter) WriteRow(row parquet.Row) error {
+	w := c.csv
+	w.line.Reset()
+	if err := c.json.WriteRow(row); err != nil {
+		return err
+	}
+
+	// The members of the JSON object are in the order of the schema fields,
+	// each of them becomes a column of the CSV record.
+	d := json.NewDecoder(&w.line)
+	if _, err := d.Token(); err != nil {
+		return err
+	}
+	w.record = w.record[:0]
+	for d.More() {
+		if _, err := d.Token(); err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := d.Decode(&value); err != nil {
+			return err
+		}
+		s, err := formatCSV(value)
+		if err != nil {
+			return err
+		}
+		w.record = append(w.record, s)
+	}
+	return w.csv.Write(w.record)
+}
+
+func formatCSV(value json.RawMessage) (string, error) {
+	switch value[0] {
+	case 'n':
+		return "", nil
+	case '"':
+		var s string
+		err := json.Unmarshal(value, &s)
+		return s, err
+	default:
+		return string(value), nil
+	}
+}
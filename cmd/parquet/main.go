@@ -0,0 +1,134 @@
+// Command parquet is a tool to inspect and manipulate parquet files.
+//
+// Usage:
+//
+//	parquet <command> [options] <file>...
+//
+// The commands are:
+//
+//	cat       print the rows of parquet files as JSON or CSV
+//	schema    print the schema of a parquet file
+//	meta      print the footer metadata of a parquet file
+//	pages     print the page headers of the column chunks of a parquet file
+//	rowcount  print the number of rows in parquet files
+//	merge     merge the row groups of parquet files into a new file
+//
+// Run "parquet <command> -h" to see the options of each command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type command struct {
+	name  string
+	usage string
+	help  string
+	run   func(stdout io.Writer, flags *flag.FlagSet, args []string) error
+}
+
+var commands = []*command{
+	catCommand,
+	schemaCommand,
+	metaCommand,
+	pagesCommand,
+	rowcountCommand,
+	mergeCommand,
+}
+
+func main() {
+	if err := run(os.Stdout, os.Stderr, os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "parquet: %s\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(stdout, stderr io.Writer, args []string) error {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return flag.ErrHelp
+	}
+
+	name, args := args[0], args[1:]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+			flags.SetOutput(stderr)
+			flags.Usage = func() {
+				fmt.Fprintf(stderr, "usage: parquet %s %s\n\n%s\n", cmd.name, cmd.usage, cmd.help)
+				flags.PrintDefaults()
+			}
+			return cmd.run(stdout, flags, args)
+		}
+	}
+
+	switch name {
+	case "help", "-h", "-help", "--help":
+		printUsage(stderr)
+		return flag.ErrHelp
+	}
+	return fmt.Errorf("unknown command %q (run \"parquet help\" to list the commands)", name)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "usage: parquet <command> [options] <file>...\n\ncommands:\n")
+	names := make([]string, len(commands))
+	for i, cmd := range commands {
+		names[i] = cmd.name
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, cmd := range commands {
+			if cmd.name == name {
+				fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.help)
+			}
+		}
+	}
+}
+
+// parseFiles parses the command line flags, and returns the list of files
+// passed as positional arguments.
+func parseFiles(flags *flag.FlagSet, args []string, minFiles, maxFiles int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	files := flags.Args()
+	switch {
+	case len(files) < minFiles:
+		flags.Usage()
+		return nil, fmt.Errorf("%s: missing file argument", flags.Name())
+	case maxFiles > 0 && len(files) > maxFiles:
+		flags.Usage()
+		return nil, fmt.Errorf("%s: too many file arguments", flags.Name())
+	}
+	return files, nil
+}
+
+// openFile opens the parquet file at path. The returned function must be
+// called to close the file when the program is done using it.
+func openFile(path string, options ...parquet.FileOption) (*parquet.File, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	p, err := parquet.OpenFile(f, s.Size(), options...)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, f.Close, nil
+}
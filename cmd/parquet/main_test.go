@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testdata(name string) string {
+	return filepath.Join("..", "..", "testdata", name)
+}
+
+func runCommand(t *testing.T, args ...string) string {
+	t.Helper()
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if err := run(stdout, stderr, args); err != nil {
+		t.Fatalf("parquet %s: %v\n%s", strings.Join(args, " "), err, stderr)
+	}
+	return stdout.String()
+}
+
+func TestCat(t *testing.T) {
+	tests := []struct {
+		scenario string
+		args     []string
+		want     string
+	}{
+		{
+			scenario: "json",
+			args:     []string{"cat", "-limit", "2", testdata("nested_maps.snappy.parquet")},
+			want: `{"a":{"a":{"1":true,"2":false}},"b":1,"c":1}
+{"a":{"b":{"1":true}},"b":1,"c":1}
+`,
+		},
+
+		{
+			scenario: "lists",
+			args:     []string{"cat", testdata("list_columns.parquet")},
+			want: `{"int64_list":[1,2,3],"utf8_list":["abc","efg","hij"]}
+{"int64_list":[null,1],"utf8_list":null}
+{"int64_list":[4],"utf8_list":["efg",null,"hij","xyz"]}
+`,
+		},
+
+		{
+			scenario: "csv",
+			args:     []string{"cat", "-format", "csv", "-limit", "3", testdata("list_columns.parquet")},
+			want: `int64_list,utf8_list
+"[1,2,3]","[""abc"",""efg"",""hij""]"
+"[null,1]",
+[4],"[""efg"",null,""hij"",""xyz""]"
+`,
+		},
+
+		{
+			scenario: "multiple files",
+			args:     []string{"cat", "-limit", "4", testdata("list_columns.parquet"), testdata("list_columns.parquet")},
+			want: `{"int64_list":[1,2,3],"utf8_list":["abc","efg","hij"]}
+{"int64_list":[null,1],"utf8_list":null}
+{"int64_list":[4],"utf8_list":["efg",null,"hij","xyz"]}
+{"int64_list":[1,2,3],"utf8_list":["abc","efg","hij"]}
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			if got := runCommand(t, test.args...); got != test.want {
+				t.Errorf("output mismatch:\nwant:\n%s\ngot:\n%s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	want := `message spark_schema {
+	optional group a (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			optional group value (MAP) {
+				repeated group key_value {
+					required int32 key;
+					required boolean value;
+				}
+			}
+		}
+	}
+	required int32 b;
+	required double c;
+}
+`
+	if got := runCommand(t, "schema", testdata("nested_maps.snappy.parquet")); got != want {
+		t.Errorf("output mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestRowCount(t *testing.T) {
+	file := testdata("list_columns.parquet")
+
+	if got := runCommand(t, "rowcount", file); got != "3\n" {
+		t.Errorf("output mismatch: want=%q got=%q", "3\n", got)
+	}
+
+	want := "3\t" + file + "\n3\t" + file + "\n6\ttotal\n"
+	if got := runCommand(t, "rowcount", file, file); got != want {
+		t.Errorf("output mismatch: want=%q got=%q", want, got)
+	}
+}
+
+func TestMeta(t *testing.T) {
+	output := runCommand(t, "meta", testdata("data_index_bloom_encoding_stats.parquet"))
+
+	for _, want := range []string{
+		"rows:        14\n",
+		"row groups:  1\n",
+		"writer.model.name = avro\n",
+		`| String | BYTE_ARRAY | GZIP  | BIT_PACKED,RLE,PLAIN |     14 |  163 |        152 |     0 | "Hello" | "today" | yes          | yes        |`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestPages(t *testing.T) {
+	output := runCommand(t, "pages", "-column", "id", testdata("alltypes_dictionary.parquet"))
+
+	for _, want := range []string{
+		"row group 0, column 0 (id):\n",
+		"|      4 | DICTIONARY_PAGE | PLAIN_DICTIONARY |      2 |       |      |          13 |    8 |          8 |     |\n",
+		"|     25 | DATA_PAGE       | PLAIN_DICTIONARY |      2 |       |      |          17 |    9 |          9 |     |\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+
+	if strings.Contains(output, "bool_col") {
+		t.Errorf("output contains pages of columns which were not selected:\n%s", output)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	input := testdata("nested_maps.snappy.parquet")
+	output := filepath.Join(t.TempDir(), "merged.parquet")
+
+	runCommand(t, "merge", "-o", output, input, input)
+
+	if got, want := runCommand(t, "rowcount", output), "12\n"; got != want {
+		t.Errorf("row count mismatch: want=%q got=%q", want, got)
+	}
+	if got, want := runCommand(t, "schema", output), runCommand(t, "schema", input); got != want {
+		t.Errorf("schema mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	rows := runCommand(t, "cat", input)
+	if got, want := runCommand(t, "cat", output), rows+rows; got != want {
+		t.Errorf("rows mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	err := run(new(bytes.Buffer), new(bytes.Buffer), []string{"merge", "-o", output, input, testdata("list_columns.parquet")})
+	if err == nil {
+		t.Error("merging files with different schemas must return an error")
+	}
+}
+
+func TestUsage(t *testing.T) {
+	stderr := new(bytes.Buffer)
+
+	if err := run(new(bytes.Buffer), stderr, nil); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("running without arguments must return flag.ErrHelp, got %v", err)
+	}
+	for _, cmd := range commands {
+		if !strings.Contains(stderr.String(), cmd.name) {
+			t.Errorf("usage does not list the %q command:\n%s", cmd.name, stderr)
+		}
+	}
+
+	if err := run(new(bytes.Buffer), new(bytes.Buffer), []string{"whatever"}); err == nil {
+		t.Error("running an unknown command must return an error")
+	}
+	if err := run(new(bytes.Buffer), new(bytes.Buffer), []string{"merge", testdata("list_columns.parquet")}); err == nil {
+		t.Error("merging without an output file must return an error")
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/segmentio/parquet-go"
+)
+
+var mergeCommand = &command{
+	name:  "merge",
+	usage: "-o <output> [-sort columns] <file>...",
+	help:  "merge the row groups of parquet files into a new file",
+	run:   runMerge,
+}
+
+func runMerge(stdout io.Writer, flags *flag.FlagSet, args []string) error {
+	output := flags.String("o", "", "path of the output file (required)")
+	sorting := flags.String("sort", "", "comma-separated list of columns that the input row groups are sorted by, prefix a column with '-' for descending order")
+
+	files, err := parseFiles(flags, args, 1, 0)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		flags.Usage()
+		return errors.New("merge: missing output file")
+	}
+
+	var rowGroups []parquet.RowGroup
+	var schema *parquet.Schema
+
+	for _, path := range files {
+		f, close, err := openFile(path, parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+		if err != nil {
+			return err
+		}
+		defer close()
+
+		if schema == nil {
+			schema = f.Schema()
+		}
+		rowGroups = append(rowGroups, f.RowGroups()...)
+	}
+
+	var sortingColumns []parquet.SortingColumn
+	if *sorting != "" {
+		sortingColumns = parseSortingColumns(*sorting)
+	}
+
+	// The row groups are not converted to a common schema, MergeRowGroups
+	// reports an error if the files have different schemas since it is not
+	// given one.
+	merged, err := parquet.MergeRowGroups(rowGroups, parquet.SortingColumns(sortingColumns...))
+	if err != nil {
+		if errors.Is(err, parquet.ErrRowGroupSchemaMismatch) {
+			err = errors.New("merge: the input files do not have the same schema")
+		}
+		return err
+	}
+	if len(rowGroups) != 0 {
+		schema = merged.Schema()
+	}
+
+	out, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w := parquet.NewWriter(out, schema, parquet.SortingColumns(sortingColumns...))
+	if _, err := w.WriteRowGroup(merged); err != nil {
+		return fmt.Errorf("writing %s: %w", *output, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("writing %s: %w", *output, err)
+	}
+	return out.Close()
+}
+
+func parseSortingColumns(s string) []parquet.SortingColumn {
+	columns := strings.Split(s, ",")
+	sortingColumns := make([]parquet.SortingColumn, len(columns))
+	for i, column := range columns {
+		if strings.HasPrefix(column, "-") {
+			sortingColumns[i] = parquet.Descending(strings.Split(column[1:], ".")...)
+		} else {
+			sortingColumns[i] = parquet.Ascending(strings.Split(column, ".")...)
+		}
+	}
+	return sortingColumns
+}
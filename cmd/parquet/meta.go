@@ -0,0 +1,177 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+var metaCommand = &command{
+	name:  "meta",
+	usage: "<file>",
+	help:  "print the footer metadata of a parquet file",
+	run:   runMeta,
+}
+
+func runMeta(stdout io.Writer, flags *flag.FlagSet, args []string) error {
+	files, err := parseFiles(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	f, close, err := openFile(files[0], parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	metadata := f.Metadata()
+	fmt.Fprintf(stdout, "file:        %s\n", files[0])
+	fmt.Fprintf(stdout, "size:        %d\n", f.Size())
+	fmt.Fprintf(stdout, "version:     %d\n", metadata.Version)
+	fmt.Fprintf(stdout, "created by:  %s\n", metadata.CreatedBy)
+	fmt.Fprintf(stdout, "rows:        %d\n", metadata.NumRows)
+	fmt.Fprintf(stdout, "row groups:  %d\n", len(metadata.RowGroups))
+
+	if len(metadata.KeyValueMetadata) > 0 {
+		keyValues := append([]format.KeyValue{}, metadata.KeyValueMetadata...)
+		sort.Slice(keyValues, func(i, j int) bool { return keyValues[i].Key < keyValues[j].Key })
+		fmt.Fprintf(stdout, "metadata:\n")
+		for _, kv := range keyValues {
+			fmt.Fprintf(stdout, "  %s = %s\n", kv.Key, kv.Value)
+		}
+	}
+
+	columnTypes := make(map[string]parquet.Type)
+	forEachLeafColumn(f.Root(), func(leaf *parquet.Column) {
+		columnTypes[strings.Join(leaf.Path(), ".")] = leaf.Type()
+	})
+
+	for i := range metadata.RowGroups {
+		rowGroup := &metadata.RowGroups[i]
+		fmt.Fprintf(stdout, "\nrow group %d: rows=%d size=%d compressed=%d offset=%d\n",
+			i,
+			rowGroup.NumRows,
+			rowGroup.TotalByteSize,
+			rowGroup.TotalCompressedSize,
+			rowGroup.FileOffset,
+		)
+
+		tw := tablewriter.NewWriter(stdout)
+		tw.SetAutoFormatHeaders(false)
+		tw.SetAutoWrapText(false)
+		tw.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		tw.SetHeader([]string{
+			"column",
+			"type",
+			"codec",
+			"encodings",
+			"values",
+			"size",
+			"compressed",
+			"nulls",
+			"min",
+			"max",
+			"bloom filter",
+			"page index",
+		})
+
+		for j := range rowGroup.Columns {
+			chunk := &rowGroup.Columns[j]
+			path := strings.Join(chunk.MetaData.PathInSchema, ".")
+
+			if chunk.MetaData.PathInSchema == nil && isEncrypted(chunk) {
+				tw.Append([]string{fmt.Sprintf("(encrypted column %d)", j)})
+				continue
+			}
+
+			encodings := make([]string, len(chunk.MetaData.Encoding))
+			for k, encoding := range chunk.MetaData.Encoding {
+				encodings[k] = encoding.String()
+			}
+
+			nulls, minValue, maxValue := "", "", ""
+			if stats := &chunk.MetaData.Statistics; !isEmptyStatistics(stats) {
+				nulls = fmt.Sprint(stats.NullCount)
+				minValue = formatStatistic(columnTypes[path], stats.MinValue, stats.Min)
+				maxValue = formatStatistic(columnTypes[path], stats.MaxValue, stats.Max)
+			}
+
+			tw.Append([]string{
+				path,
+				chunk.MetaData.Type.String(),
+				chunk.MetaData.Codec.String(),
+				strings.Join(encodings, ","),
+				fmt.Sprint(chunk.MetaData.NumValues),
+				fmt.Sprint(chunk.MetaData.TotalUncompressedSize),
+				fmt.Sprint(chunk.MetaData.TotalCompressedSize),
+				nulls,
+				minValue,
+				maxValue,
+				yesOrNo(chunk.MetaData.BloomFilterOffset > 0),
+				yesOrNo(chunk.ColumnIndexOffset > 0 && chunk.OffsetIndexOffset > 0),
+			})
+		}
+
+		tw.Render()
+	}
+
+	return nil
+}
+
+func forEachLeafColumn(col *parquet.Column, do func(*parquet.Column)) {
+	if col.Leaf() {
+		do(col)
+		return
+	}
+	for _, child := range col.Columns() {
+		forEachLeafColumn(child, do)
+	}
+}
+
+func isEmptyStatistics(stats *format.Statistics) bool {
+	return stats.NullCount == 0 &&
+		stats.DistinctCount == 0 &&
+		stats.Min == nil && stats.Max == nil &&
+		stats.MinValue == nil && stats.MaxValue == nil
+}
+
+// formatStatistic formats the min or max statistic of a column chunk, which
+// are stored in the PLAIN encoding of the column type. The deprecated value is
+// used when the file was written by an application which did not populate the
+// new fields.
+func formatStatistic(t parquet.Type, value, deprecated []byte) string {
+	if value == nil {
+		value = deprecated
+	}
+	if value == nil || t == nil {
+		return ""
+	}
+	v := t.Kind().Value(value)
+	if v.Kind() == parquet.ByteArray || v.Kind() == parquet.FixedLenByteArray {
+		const maxLength = 32
+		s := fmt.Sprintf("%q", v.ByteArray())
+		if len(s) > maxLength {
+			s = s[:maxLength-3] + "..."
+		}
+		return s
+	}
+	return v.String()
+}
+
+func yesOrNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func isEncrypted(chunk *format.ColumnChunk) bool {
+	crypto := &chunk.CryptoMetadata
+	return crypto.EncryptionWithFooterKey != nil || crypto.EncryptionWithColumnKey != nil
+}
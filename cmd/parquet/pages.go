@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+var pagesCommand = &command{
+	name:  "pages",
+	usage: "[-column path] <file>",
+	help:  "print the page headers of the column chunks of a parquet file",
+	run:   runPages,
+}
+
+func runPages(stdout io.Writer, flags *flag.FlagSet, args []string) error {
+	column := flags.String("column", "", "only print the pages of the column at this dot-separated path")
+
+	files, err := parseFiles(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	f, close, err := openFile(files[0], parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	metadata := f.Metadata()
+	for i := range metadata.RowGroups {
+		for j := range metadata.RowGroups[i].Columns {
+			chunk := &metadata.RowGroups[i].Columns[j]
+			path := strings.Join(chunk.MetaData.PathInSchema, ".")
+			if *column != "" && path != *column {
+				continue
+			}
+
+			fmt.Fprintf(stdout, "row group %d, column %d (%s):\n", i, j, path)
+			if isEncrypted(chunk) {
+				fmt.Fprintf(stdout, "  (encrypted column chunk)\n\n")
+				continue
+			}
+
+			tw := tablewriter.NewWriter(stdout)
+			tw.SetAutoFormatHeaders(false)
+			tw.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+			tw.SetHeader([]string{
+				"offset",
+				"type",
+				"encoding",
+				"values",
+				"nulls",
+				"rows",
+				"header size",
+				"size",
+				"compressed",
+				"crc",
+			})
+
+			err := forEachPageHeader(f, &chunk.MetaData, func(offset int64, headerSize int, header *format.PageHeader) {
+				tw.Append(pageHeaderRow(offset, headerSize, header))
+			})
+			tw.Render()
+			if err != nil {
+				return fmt.Errorf("reading pages of column %q in row group %d: %w", path, i, err)
+			}
+			fmt.Fprintln(stdout)
+		}
+	}
+
+	return nil
+}
+
+func pageHeaderRow(offset int64, headerSize int, header *format.PageHeader) []string {
+	encoding, values, nulls, rows := "", "", "", ""
+
+	switch header.Type {
+	case format.DataPage:
+		if h := header.DataPageHeader; h != nil {
+			encoding = h.Encoding.String()
+			values = fmt.Sprint(h.NumValues)
+		}
+	case format.DataPageV2:
+		if h := header.DataPageHeaderV2; h != nil {
+			encoding = h.Encoding.String()
+			values = fmt.Sprint(h.NumValues)
+			nulls = fmt.Sprint(h.NumNulls)
+			rows = fmt.Sprint(h.NumRows)
+		}
+	case format.DictionaryPage:
+		if h := header.DictionaryPageHeader; h != nil {
+			encoding = h.Encoding.String()
+			values = fmt.Sprint(h.NumValues)
+		}
+	}
+
+	crc := ""
+	if header.CRC != 0 {
+		crc = fmt.Sprintf("0x%08X", uint32(header.CRC))
+	}
+
+	return []string{
+		fmt.Sprint(offset),
+		header.Type.String(),
+		encoding,
+		values,
+		nulls,
+		rows,
+		fmt.Sprint(headerSize),
+		fmt.Sprint(header.UncompressedPageSize),
+		fmt.Sprint(header.CompressedPageSize),
+		crc,
+	}
+}
+
+// forEachPageHeader calls fn with the offset, size, and content of the header
+// of each page of a column chunk.
+func forEachPageHeader(r io.ReaderAt, chunk *format.ColumnMetaData, fn func(int64, int, *format.PageHeader)) error {
+	// Page headers are usually small, but may embed statistics holding large
+	// values; the buffer grows when it is not large enough to hold a header.
+	const minBufferSize = 4096
+
+	offset := chunk.DataPageOffset
+	if chunk.DictionaryPageOffset > 0 && chunk.DictionaryPageOffset < offset {
+		offset = chunk.DictionaryPageOffset
+	}
+	end := offset + chunk.TotalCompressedSize
+
+	protocol := &thrift.CompactProtocol{}
+	buffer := make([]byte, minBufferSize)
+
+	for offset < end {
+		size := int64(len(buffer))
+		if remain := end - offset; remain < size {
+			size = remain
+		}
+
+		n, err := r.ReadAt(buffer[:size], offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		header := new(format.PageHeader)
+		input := bytes.NewReader(buffer[:n])
+		if err := thrift.NewDecoder(protocol.NewReader(input)).Decode(header); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) && int64(n) == int64(len(buffer)) {
+				buffer = make([]byte, 2*len(buffer))
+				continue
+			}
+			return fmt.Errorf("decoding page header at offset %d: %w", offset, err)
+		}
+
+		headerSize := n - input.Len()
+		fn(offset, headerSize, header)
+		offset += int64(headerSize) + int64(header.CompressedPageSize)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/segmentio/parquet-go"
+)
+
+var schemaCommand = &command{
+	name:  "schema",
+	usage: "<file>",
+	help:  "print the schema of a parquet file",
+	run:   runSchema,
+}
+
+func runSchema(stdout io.Writer, flags *flag.FlagSet, args []string) error {
+	files, err := parseFiles(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	f, close, err := openFile(files[0], parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return err
+	}
+	defer close()
+
+	if err := parquet.PrintSchema(stdout, f.Schema().Name(), f.Schema()); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout)
+	return err
+}
+
+var rowcountCommand = &command{
+	name:  "rowcount",
+	usage: "<file>...",
+	help:  "print the number of rows in parquet files",
+	run:   runRowCount,
+}
+
+func runRowCount(stdout io.Writer, flags *flag.FlagSet, args []string) error {
+	files, err := parseFiles(flags, args, 1, 0)
+	if err != nil {
+		return err
+	}
+
+	total := int64(0)
+	for _, path := range files {
+		f, close, err := openFile(path, parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+		if err != nil {
+			return err
+		}
+		numRows := f.NumRows()
+		close()
+
+		if len(files) == 1 {
+			_, err = fmt.Fprintln(stdout, numRows)
+			return err
+		}
+		if _, err := fmt.Fprintf(stdout, "%d\t%s\n", numRows, path); err != nil {
+			return err
+		}
+		total += numRows
+	}
+
+	_, err = fmt.Fprintf(stdout, "%d\ttotal\n", total)
+	return err
+}
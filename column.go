@@ -70,6 +70,9 @@ func (c *Column) Encoding() encoding.Encoding { return c.encoding }
 // Compression returns the compression codecs used by this column.
 func (c *Column) Compression() compress.Codec { return c.compression }
 
+// FieldID returns the field id of the column, or zero if it has none.
+func (c *Column) FieldID() int { return int(c.schema.FieldID) }
+
 // Path of the column in the parquet schema.
 func (c *Column) Path() []string { return c.path }
 
@@ -317,7 +320,18 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 			// each page of the column should iterate through the pages and read
 			// the page headers to determine which compression and encodings are
 			// applied.
+			//
+			// The list of encodings also contains those of the repetition and
+			// definition levels, which are skipped since they do not apply to
+			// the column values. RLE is also an encoding of boolean values, it
+			// is selected when a boolean column lists no other encoding.
 			for _, encoding := range c.chunks[0].MetaData.Encoding {
+				if isLevelEncoding(encoding) {
+					if encoding == format.RLE && c.typ.Kind() == Boolean {
+						c.encoding = &RLE
+					}
+					continue
+				}
 				c.encoding = LookupEncoding(encoding)
 				break
 			}
@@ -328,6 +342,7 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 	}
 
 	c.typ = &groupType{}
+	c.index = -1 // set early so Leaf returns false while loading the group
 	c.columns = make([]*Column, numChildren)
 
 	for i := range c.columns {
@@ -343,9 +358,28 @@ func (cl *columnLoader) open(file *File, path []string) (*Column, error) {
 		}
 	}
 
+	// Groups retain the MAP and LIST logical types when they are laid out the
+	// way the package expects them to be. Legacy layouts, for example LIST
+	// groups with a repeated "array" or "bag" child, are exposed as plain groups
+	// since their values cannot be mapped to Go maps and slices.
+	switch t := schemaElementTypeOf(c.schema).(type) {
+	case *mapType:
+		if lookupMapKeyValueOf(c) != nil {
+			c.typ = t
+		}
+	case *listType:
+		if lookupListElementOf(c) != nil {
+			c.typ = t
+		}
+	}
+
 	return c, nil
 }
 
+func isLevelEncoding(encoding format.Encoding) bool {
+	return encoding == format.RLE || encoding == format.BitPacked
+}
+
 func schemaElementTypeOf(s *format.SchemaElement) Type {
 	if lt := s.LogicalType; lt != nil {
 		// A logical type exists, the Type interface implementations in this
@@ -362,8 +396,9 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		case lt.Enum != nil:
 			return (*enumType)(lt.Enum)
 		case lt.Decimal != nil:
-			// TODO:
-			// return (*decimalType)(lt.Decimal)
+			if t := physicalTypeOf(s); t != nil {
+				return &decimalType{decimal: *lt.Decimal, Type: t}
+			}
 		case lt.Date != nil:
 			return (*dateType)(lt.Date)
 		case lt.Time != nil:
@@ -380,6 +415,8 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 			return (*bsonType)(lt.Bson)
 		case lt.UUID != nil:
 			return (*uuidType)(lt.UUID)
+		case lt.Float16 != nil:
+			return (*float16Type)(lt.Float16)
 		}
 	}
 
@@ -399,7 +436,16 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		case deprecated.Enum:
 			return &enumType{}
 		case deprecated.Decimal:
-			// TODO
+			if t := physicalTypeOf(s); t != nil {
+				decimal := format.DecimalType{}
+				if s.Scale != nil {
+					decimal.Scale = *s.Scale
+				}
+				if s.Precision != nil {
+					decimal.Precision = *s.Precision
+				}
+				return &decimalType{decimal: decimal, Type: t}
+			}
 		case deprecated.Date:
 			return &dateType{}
 		case deprecated.TimeMillis:
@@ -431,13 +477,26 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 		case deprecated.Bson:
 			return &bsonType{}
 		case deprecated.Interval:
-			// TODO
+			if s.Type != nil && *s.Type == format.FixedLenByteArray && s.TypeLength != nil && *s.TypeLength == 12 {
+				return &intervalType{}
+			}
 		}
 	}
 
+	if t := physicalTypeOf(s); t != nil {
+		// The column only has a physical type, use one of the primitive types
+		// supported by this package.
+		return t
+	}
+
+	// If we reach this point, we are likely reading a parquet column that was
+	// written with a non-standard type or is in a newer version of the format
+	// than this package supports.
+	return &nullType{}
+}
+
+func physicalTypeOf(s *format.SchemaElement) Type {
 	if t := s.Type; t != nil {
-		// The column only has a physical type, convert it to one of the
-		// primitive types supported by this package.
 		switch kind := Kind(*t); kind {
 		case Boolean:
 			return BooleanType
@@ -459,11 +518,7 @@ func schemaElementTypeOf(s *format.SchemaElement) Type {
 			}
 		}
 	}
-
-	// If we reach this point, we are likely reading a parquet column that was
-	// written with a non-standard type or is in a newer version of the format
-	// than this package supports.
-	return &nullType{}
+	return nil
 }
 
 func schemaRepetitionTypeOf(s *format.SchemaElement) format.FieldRepetitionType {
@@ -659,8 +714,9 @@ func (c *Column) decodeDataPageV2(header DataPageHeaderV2, page *dataPage) (Page
 func (c *Column) decodeDataPage(header DataPageHeader, numValues int64, page *dataPage, data []byte) (Page, error) {
 	encoding := LookupEncoding(header.Encoding())
 	pageType := c.Type()
+	indexed := isDictionaryEncoding(encoding)
 
-	if isDictionaryEncoding(encoding) {
+	if indexed {
 		// In some legacy configurations, the PLAIN_DICTIONARY encoding is used
 		// on data page headers to indicate that the page contains indexes into
 		// the dictionary page, but the page is still encoded using the RLE
@@ -672,8 +728,11 @@ func (c *Column) decodeDataPage(header DataPageHeader, numValues int64, page *da
 		return nil, err
 	}
 
+	// Writers may fall back to a different encoding when the dictionary grows
+	// too large, so column chunks can have a dictionary page and yet contain
+	// data pages that do not reference it.
 	var newPage Page
-	if page.dictionary != nil {
+	if indexed && page.dictionary != nil {
 		newPage = newIndexedPage(page.dictionary, int16(c.index), int32(numValues), page.values)
 	} else {
 		newPage = pageType.NewPage(c.Index(), int(numValues), page.values)
@@ -747,6 +806,13 @@ func (c *Column) decodeDictionary(header DictionaryPageHeader, page *dataPage, d
 	}
 
 	dict.values = append(dict.values[:0], page.values...)
+	if pageType.Kind() == Boolean {
+		// The PLAIN encoding packs boolean values in bits, decoding the last
+		// byte of the page may have produced values from its padding bits.
+		if n := int(header.NumValues()); n >= 0 && n < len(dict.values) {
+			dict.values = dict.values[:n]
+		}
+	}
 	return pageType.NewDictionary(int(c.index), int(header.NumValues()), dict.values), nil
 }
 
@@ -102,6 +102,37 @@ type reversedColumnBuffer struct{ ColumnBuffer }
 
 func (col *reversedColumnBuffer) Less(i, j int) bool { return col.ColumnBuffer.Less(j, i) }
 
+// bloomFilterColumnBuffer is an adapter of ColumnBuffer which inserts the values
+// written to the column in a bloom filter.
+//
+// This type is used when buffers are constructed with bloom filters.
+type bloomFilterColumnBuffer struct {
+	ColumnBuffer
+	filter *bufferBloomFilter
+}
+
+func (col *bloomFilterColumnBuffer) BloomFilter() BloomFilter { return &col.filter.memoryBloomFilter }
+
+func (col *bloomFilterColumnBuffer) Clone() ColumnBuffer {
+	return &bloomFilterColumnBuffer{
+		ColumnBuffer: col.ColumnBuffer.Clone(),
+		filter:       col.filter.clone(),
+	}
+}
+
+func (col *bloomFilterColumnBuffer) Reset() {
+	col.ColumnBuffer.Reset()
+	col.filter.reset()
+}
+
+func (col *bloomFilterColumnBuffer) WriteValues(values []Value) (int, error) {
+	n, err := col.ColumnBuffer.WriteValues(values)
+	if ferr := col.filter.insert(col.ColumnBuffer, values[:n]); ferr != nil && err == nil {
+		err = ferr
+	}
+	return n, err
+}
+
 // optionalColumnBuffer is an implementation of the ColumnBuffer interface used
 // as a wrapper to an underlying ColumnBuffer to manage the creation of
 // definition levels.
@@ -813,6 +844,30 @@ func (col *fixedLenByteArrayColumnBuffer) Clone() ColumnBuffer {
 
 func (col *fixedLenByteArrayColumnBuffer) Type() Type { return col.typ }
 
+// float16ColumnBuffer wraps the column buffers of FLOAT16 columns to order the
+// rows and compute the bounds of pages by comparing floating point values.
+type float16ColumnBuffer struct{ *fixedLenByteArrayColumnBuffer }
+
+func (col float16ColumnBuffer) Clone() ColumnBuffer {
+	return float16ColumnBuffer{col.fixedLenByteArrayColumnBuffer.Clone().(*fixedLenByteArrayColumnBuffer)}
+}
+
+func (col float16ColumnBuffer) ColumnIndex() ColumnIndex {
+	return float16ColumnIndex{col.page()}
+}
+
+func (col float16ColumnBuffer) Pages() Pages { return onePage(col.Page()) }
+
+func (col float16ColumnBuffer) Page() BufferedPage { return col.page() }
+
+func (col float16ColumnBuffer) page() float16Page {
+	return float16Page{&col.fixedLenByteArrayPage}
+}
+
+func (col float16ColumnBuffer) Less(i, j int) bool {
+	return compareFloat16(col.index(i), col.index(j)) < 0
+}
+
 func (col *fixedLenByteArrayColumnBuffer) ColumnIndex() ColumnIndex {
 	return fixedLenByteArrayColumnIndex{&col.fixedLenByteArrayPage}
 }
@@ -40,3 +40,32 @@ func TestOptionalDictWriteRowGroup(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestOptionalColumnBufferWriteValues(t *testing.T) {
+	type Row struct {
+		A *int64 `parquet:"a,optional"`
+	}
+	s := parquet.SchemaOf(Row{})
+
+	values := make([]parquet.Value, 10)
+	values[0] = parquet.Value{}.Level(0, 0, 0)
+	for i := 1; i < len(values); i++ {
+		values[i] = parquet.ValueOf(int64(i)).Level(0, 1, 0)
+	}
+
+	buf := parquet.NewBuffer(s)
+	if _, err := buf.ColumnBuffers()[0].WriteValues(values); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := buf.Rows()
+	for i := range values {
+		row, err := rows.ReadRow(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(row) != 1 || !parquet.Equal(row[0], values[i]) || row[0].DefinitionLevel() != values[i].DefinitionLevel() {
+			t.Errorf("row %d mismatch: want=%+v got=%+v", i, values[i], row)
+		}
+	}
+}
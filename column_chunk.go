@@ -110,6 +110,13 @@ func (r *columnChunkReader) readPage() (err error) {
 	}
 }
 
+func (r *columnChunkReader) close() (err error) {
+	if closer, ok := r.reader.(io.Closer); ok {
+		err = closer.Close()
+	}
+	return err
+}
+
 func (r *columnChunkReader) readValues() error {
 	for {
 		err := r.readValuesFromCurrentPage()
@@ -340,7 +347,18 @@ func columnReadRowFuncOfLeaf(columnIndex int, repetitionDepth int8) (int, column
 					}
 					return row, nil
 				}
-				if err := col.readValues(); err != nil {
+				err := col.readValuesFromCurrentPage()
+				if err == io.EOF {
+					// Values that were already appended to the row may
+					// reference memory of the current page, which is reused
+					// when loading the next page; when the row spans multiple
+					// pages they must be copied to remain valid.
+					if repetitionLevel > 0 {
+						cloneColumnValues(row, columnIndex)
+					}
+					err = col.readPage()
+				}
+				if err != nil {
 					if repetitionLevel > 0 && err == io.EOF {
 						err = nil
 					}
@@ -353,6 +371,14 @@ func columnReadRowFuncOfLeaf(columnIndex int, repetitionDepth int8) (int, column
 	return columnIndex + 1, read
 }
 
+func cloneColumnValues(row Row, columnIndex int) {
+	for i, v := range row {
+		if v.Column() == columnIndex {
+			row[i] = v.Clone()
+		}
+	}
+}
+
 var (
 	_ RowReaderWithSchema = (*Reader)(nil)
 )
@@ -1,6 +1,8 @@
 package parquet
 
 import (
+	"math"
+
 	"github.com/segmentio/parquet-go/encoding/plain"
 	"github.com/segmentio/parquet-go/format"
 	"github.com/segmentio/parquet-go/internal/bits"
@@ -160,6 +162,20 @@ func (i fixedLenByteArrayColumnIndex) MaxValue(int) Value {
 func (i fixedLenByteArrayColumnIndex) IsAscending() bool  { return false }
 func (i fixedLenByteArrayColumnIndex) IsDescending() bool { return false }
 
+type float16ColumnIndex struct{ page float16Page }
+
+func (i float16ColumnIndex) NumPages() int       { return 1 }
+func (i float16ColumnIndex) NullCount(int) int64 { return 0 }
+func (i float16ColumnIndex) NullPage(int) bool   { return false }
+func (i float16ColumnIndex) MinValue(int) Value {
+	return makeValueBytes(FixedLenByteArray, i.page.min())
+}
+func (i float16ColumnIndex) MaxValue(int) Value {
+	return makeValueBytes(FixedLenByteArray, i.page.max())
+}
+func (i float16ColumnIndex) IsAscending() bool  { return false }
+func (i float16ColumnIndex) IsDescending() bool { return false }
+
 // The ColumnIndexer interface is implemented by types that support generating
 // parquet column indexes.
 //
@@ -292,6 +308,68 @@ func (i *fixedLenByteArrayColumnIndexer) ColumnIndex() format.ColumnIndex {
 	)
 }
 
+type float16ColumnIndexer struct {
+	baseColumnIndexer
+	minValues []float32
+	maxValues []float32
+	nanPages  bool
+}
+
+func newFloat16ColumnIndexer() *float16ColumnIndexer {
+	return new(float16ColumnIndexer)
+}
+
+func (i *float16ColumnIndexer) Reset() {
+	i.reset()
+	i.minValues = i.minValues[:0]
+	i.maxValues = i.maxValues[:0]
+	i.nanPages = false
+}
+
+func (i *float16ColumnIndexer) IndexPage(numValues, numNulls int64, min, max Value) {
+	i.observe(numValues, numNulls)
+	minValue, maxValue := float16ValueToFloat32(min), float16ValueToFloat32(max)
+	if min.IsNull() && numValues > numNulls {
+		// The page has no bounds because it holds only NaN values, which are
+		// written to the index as the page bounds.
+		minValue, maxValue = float32(math.NaN()), float32(math.NaN())
+		i.nanPages = true
+	}
+	i.minValues = append(i.minValues, minValue)
+	i.maxValues = append(i.maxValues, maxValue)
+}
+
+func (i *float16ColumnIndexer) ColumnIndex() format.ColumnIndex {
+	minOrder := bits.OrderOfFloat32(i.minValues)
+	maxOrder := bits.OrderOfFloat32(i.maxValues)
+	if i.nanPages {
+		minOrder, maxOrder = 0, 0
+	}
+	return i.columnIndex(
+		float16ColumnIndexValues(i.minValues),
+		float16ColumnIndexValues(i.maxValues),
+		minOrder,
+		maxOrder,
+	)
+}
+
+func float16ValueToFloat32(v Value) float32 {
+	if b := v.ByteArray(); len(b) == 2 {
+		return float16ToFloat32(b)
+	}
+	return 0 // null page
+}
+
+func float16ColumnIndexValues(values []float32) [][]byte {
+	data := make([]byte, 2*len(values))
+	for i, v := range values {
+		h := float32ToFloat16Bits(v)
+		data[2*i+0] = byte(h)
+		data[2*i+1] = byte(h >> 8)
+	}
+	return splitFixedLenByteArrays(data, 2)
+}
+
 func truncateLargeMinByteArrayValue(value []byte, sizeLimit int) []byte {
 	if len(value) > sizeLimit {
 		value = value[:sizeLimit]
@@ -300,19 +378,22 @@ func truncateLargeMinByteArrayValue(value []byte, sizeLimit int) []byte {
 }
 
 func truncateLargeMaxByteArrayValue(value []byte, sizeLimit int) []byte {
-	if len(value) > sizeLimit && !isMaxByteArrayValue(value) {
-		value = value[:sizeLimit]
+	if len(value) <= sizeLimit {
+		return value
 	}
-	return value
-}
-
-func isMaxByteArrayValue(value []byte) bool {
-	for i := range value {
+	// A prefix of the value is less than the value itself, so the truncated
+	// value must be incremented to remain an upper bound of the page values.
+	// Trailing 0xFF bytes cannot be incremented and are dropped; when all the
+	// bytes of the prefix are 0xFF, the value is kept whole.
+	for i := sizeLimit - 1; i >= 0; i-- {
 		if value[i] != 0xFF {
-			return false
+			maxValue := make([]byte, i+1)
+			copy(maxValue, value)
+			maxValue[i]++
+			return maxValue
 		}
 	}
-	return true
+	return value
 }
 
 func splitByteArrays(data []byte) [][]byte {
@@ -354,3 +435,31 @@ func boundaryOrderOf(minOrder, maxOrder int) format.BoundaryOrder {
 	}
 	return format.Unordered
 }
+
+// undefinedOrderColumnIndexer is the column indexer of types which have no
+// defined sort order, it records empty min and max values for all pages since
+// they would not be meaningful to readers.
+type undefinedOrderColumnIndexer struct {
+	baseColumnIndexer
+	values [][]byte
+}
+
+func (i *undefinedOrderColumnIndexer) Reset() {
+	i.reset()
+	i.values = i.values[:0]
+}
+
+func (i *undefinedOrderColumnIndexer) IndexPage(numValues, numNulls int64, _, _ Value) {
+	i.observe(numValues, numNulls)
+	i.values = append(i.values, []byte{})
+}
+
+func (i *undefinedOrderColumnIndexer) ColumnIndex() format.ColumnIndex {
+	return format.ColumnIndex{
+		NullPages:     i.nullPages,
+		NullCounts:    i.nullCounts,
+		MinValues:     i.values,
+		MaxValues:     i.values,
+		BoundaryOrder: format.Unordered,
+	}
+}
@@ -1,6 +1,7 @@
 package parquet_test
 
 import (
+	"bytes"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -12,6 +13,132 @@ import (
 	"github.com/segmentio/parquet-go/format"
 )
 
+func TestColumnDecimalType(t *testing.T) {
+	schema := parquet.NewSchema("decimals", parquet.Group{
+		"price":  parquet.Decimal(2, 9, parquet.Int32Type),
+		"amount": parquet.Decimal(3, 20, parquet.FixedLenByteArrayType(9)),
+	})
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	if err := writer.Write(map[string]interface{}{
+		"price":  int32(12345),
+		"amount": make([]byte, 9),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		scenario string
+		modify   func(*format.FileMetaData)
+	}{
+		{
+			scenario: "logical type",
+			modify:   func(*format.FileMetaData) {},
+		},
+		{
+			// Files written by older applications only have the converted
+			// type, with the scale and precision set on the schema element.
+			scenario: "converted type",
+			modify: func(metadata *format.FileMetaData) {
+				for i := range metadata.Schema {
+					metadata.Schema[i].LogicalType = nil
+				}
+			},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			input := rewriteFooter(t, buffer.Bytes(), test.modify)
+			f, err := parquet.OpenFile(input, input.Size())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, want := range []struct {
+				column    string
+				kind      parquet.Kind
+				scale     int32
+				precision int32
+			}{
+				{column: "amount", kind: parquet.FixedLenByteArray, scale: 3, precision: 20},
+				{column: "price", kind: parquet.Int32, scale: 2, precision: 9},
+			} {
+				leaf, ok := f.Schema().Lookup(want.column)
+				if !ok {
+					t.Fatalf("column %q not found", want.column)
+				}
+				typ := leaf.Node.Type()
+				lt := typ.LogicalType()
+				if lt == nil || lt.Decimal == nil {
+					t.Fatalf("column %q is not of DECIMAL type: %s", want.column, typ)
+				}
+				if lt.Decimal.Scale != want.scale || lt.Decimal.Precision != want.precision {
+					t.Errorf("wrong decimal type of column %q: %s", want.column, typ)
+				}
+				if typ.Kind() != want.kind {
+					t.Errorf("wrong kind of column %q: want=%s got=%s", want.column, want.kind, typ.Kind())
+				}
+			}
+		})
+	}
+}
+
+func TestColumnEncodingSkipsLevelEncodings(t *testing.T) {
+	schema := parquet.NewSchema("levels", parquet.Group{
+		"id":      parquet.Optional(parquet.Int(64)),
+		"flag":    parquet.Encoded(parquet.Leaf(parquet.BooleanType), &parquet.RLE),
+		"enabled": parquet.Optional(parquet.Leaf(parquet.BooleanType)),
+	})
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	if err := writer.Write(map[string]interface{}{"id": int64(1), "flag": true, "enabled": false}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Legacy writers list the encodings of repetition and definition levels
+	// before the encoding of the values, which must not be selected as the
+	// encoding of the column unless it is a boolean column using RLE.
+	input := rewriteFooter(t, buffer.Bytes(), func(metadata *format.FileMetaData) {
+		for i := range metadata.RowGroups {
+			for j := range metadata.RowGroups[i].Columns {
+				column := &metadata.RowGroups[i].Columns[j].MetaData
+				encodings := []format.Encoding{format.BitPacked, format.RLE}
+				for _, encoding := range column.Encoding {
+					if encoding != format.RLE && encoding != format.BitPacked {
+						encodings = append(encodings, encoding)
+					}
+				}
+				column.Encoding = encodings
+			}
+		}
+	})
+	f, err := parquet.OpenFile(input, input.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		column   string
+		encoding format.Encoding
+	}{
+		{column: "enabled", encoding: format.Plain},
+		{column: "flag", encoding: format.RLE},
+		{column: "id", encoding: format.Plain},
+	} {
+		c := f.Root().Column(test.column)
+		if encoding := c.Encoding().Encoding(); encoding != test.encoding {
+			t.Errorf("wrong encoding of column %q: want=%s got=%s", test.column, test.encoding, encoding)
+		}
+	}
+}
+
 func TestColumnPageIndex(t *testing.T) {
 	for _, config := range [...]struct {
 		name string
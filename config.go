@@ -2,6 +2,7 @@ package parquet
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/segmentio/parquet-go/compress"
@@ -17,6 +18,10 @@ const (
 	DefaultDataPageStatistics   = false
 	DefaultSkipPageIndex        = false
 	DefaultSkipBloomFilters     = false
+	DefaultReadConcurrency      = 1
+	DefaultReadRangeConcurrency = 4
+	DefaultWriteConcurrency     = 1
+	DefaultDatasetFilePattern   = "*.parquet"
 )
 
 // The FileConfig type carries configuration options for parquet files.
@@ -30,16 +35,25 @@ const (
 //	})
 //
 type FileConfig struct {
-	SkipPageIndex    bool
-	SkipBloomFilters bool
+	SkipPageIndex        bool
+	SkipBloomFilters     bool
+	Columns              [][]string
+	Decryption           *DecryptionConfig
+	ReadFooterSize       int64
+	ReadRangeGap         int64
+	ReadRangeSize        int64
+	ReadRangeConcurrency int
+	MetadataCache        MetadataCache
+	MetadataCacheKey     string
 }
 
 // DefaultFileConfig returns a new FileConfig value initialized with the
 // default file configuration.
 func DefaultFileConfig() *FileConfig {
 	return &FileConfig{
-		SkipPageIndex:    DefaultSkipPageIndex,
-		SkipBloomFilters: DefaultSkipBloomFilters,
+		SkipPageIndex:        DefaultSkipPageIndex,
+		SkipBloomFilters:     DefaultSkipBloomFilters,
+		ReadRangeConcurrency: DefaultReadRangeConcurrency,
 	}
 }
 
@@ -64,14 +78,29 @@ func (c *FileConfig) Apply(options ...FileOption) {
 // ConfigureFile applies configuration options from c to config.
 func (c *FileConfig) ConfigureFile(config *FileConfig) {
 	*config = FileConfig{
-		SkipPageIndex:    config.SkipPageIndex,
-		SkipBloomFilters: config.SkipBloomFilters,
+		SkipPageIndex:        config.SkipPageIndex,
+		SkipBloomFilters:     config.SkipBloomFilters,
+		Columns:              coalesceColumnPaths(c.Columns, config.Columns),
+		Decryption:           coalesceDecryption(c.Decryption, config.Decryption),
+		ReadFooterSize:       coalesceInt64(c.ReadFooterSize, config.ReadFooterSize),
+		ReadRangeGap:         coalesceInt64(c.ReadRangeGap, config.ReadRangeGap),
+		ReadRangeSize:        coalesceInt64(c.ReadRangeSize, config.ReadRangeSize),
+		ReadRangeConcurrency: coalesceInt(c.ReadRangeConcurrency, config.ReadRangeConcurrency),
+		MetadataCache:        coalesceMetadataCache(c.MetadataCache, config.MetadataCache),
+		MetadataCacheKey:     coalesceString(c.MetadataCacheKey, config.MetadataCacheKey),
 	}
 }
 
 // Validate returns a non-nil error if the configuration of c is invalid.
 func (c *FileConfig) Validate() error {
-	return nil
+	const baseName = "parquet.(*FileConfig)."
+	return errorInvalidConfiguration(
+		validateNonNegativeInt64(baseName+"ReadFooterSize", c.ReadFooterSize),
+		validateNonNegativeInt64(baseName+"ReadRangeGap", c.ReadRangeGap),
+		validateNonNegativeInt64(baseName+"ReadRangeSize", c.ReadRangeSize),
+		validatePositiveInt(baseName+"ReadRangeConcurrency", c.ReadRangeConcurrency),
+		validateMetadataCacheKey(baseName+"MetadataCacheKey", c.MetadataCache, c.MetadataCacheKey),
+	)
 }
 
 // The ReaderConfig type carries configuration options for parquet readers.
@@ -84,13 +113,18 @@ func (c *FileConfig) Validate() error {
 //	})
 //
 type ReaderConfig struct {
-	Schema *Schema
+	Schema      *Schema
+	Filter      Filter
+	Columns     [][]string
+	Concurrency int
 }
 
 // DefaultReaderConfig returns a new ReaderConfig value initialized with the
 // default reader configuration.
 func DefaultReaderConfig() *ReaderConfig {
-	return &ReaderConfig{}
+	return &ReaderConfig{
+		Concurrency: DefaultReadConcurrency,
+	}
 }
 
 // NewReaderConfig constructs a new reader configuration applying the options
@@ -114,13 +148,19 @@ func (c *ReaderConfig) Apply(options ...ReaderOption) {
 // ConfigureReader applies configuration options from c to config.
 func (c *ReaderConfig) ConfigureReader(config *ReaderConfig) {
 	*config = ReaderConfig{
-		Schema: coalesceSchema(c.Schema, config.Schema),
+		Schema:      coalesceSchema(c.Schema, config.Schema),
+		Filter:      coalesceFilter(c.Filter, config.Filter),
+		Columns:     coalesceColumnPaths(c.Columns, config.Columns),
+		Concurrency: coalesceInt(c.Concurrency, config.Concurrency),
 	}
 }
 
 // Validate returns a non-nil error if the configuration of c is invalid.
 func (c *ReaderConfig) Validate() error {
-	return nil
+	const baseName = "parquet.(*ReaderConfig)."
+	return errorInvalidConfiguration(
+		validatePositiveInt(baseName+"Concurrency", c.Concurrency),
+	)
 }
 
 // The WriterConfig type carries configuration options for parquet writers.
@@ -133,19 +173,27 @@ func (c *ReaderConfig) Validate() error {
 //	})
 //
 type WriterConfig struct {
-	CreatedBy            string
-	ColumnPageBuffers    PageBufferPool
-	ColumnIndexSizeLimit int
-	PageBufferPool       PageBufferPool
-	PageBufferSize       int
-	WriteBufferSize      int
-	DataPageVersion      int
-	DataPageStatistics   bool
-	KeyValueMetadata     map[string]string
-	Schema               *Schema
-	SortingColumns       []SortingColumn
-	BloomFilters         []BloomFilterColumn
-	Compression          compress.Codec
+	CreatedBy             string
+	ColumnPageBuffers     PageBufferPool
+	ColumnIndexSizeLimit  int
+	PageBufferPool        PageBufferPool
+	PageBufferSize        int
+	WriteBufferSize       int
+	DataPageVersion       int
+	DataPageStatistics    bool
+	KeyValueMetadata      map[string]string
+	Schema                *Schema
+	SortingColumns        []SortingColumn
+	BloomFilters          []BloomFilterColumn
+	Compression           compress.Codec
+	Encryption            *EncryptionConfig
+	Concurrency           int
+	MaxRowsPerRowGroup    int64
+	RowGroupTargetSize    int64
+	MaxRowsPerPage        int64
+	MaxDictionaryPageSize int
+	SortingBuffers        PageBufferPool
+	Int96Timestamps       bool
 }
 
 // DefaultWriterConfig returns a new WriterConfig value initialized with the
@@ -159,6 +207,8 @@ func DefaultWriterConfig() *WriterConfig {
 		WriteBufferSize:      DefaultWriteBufferSize,
 		DataPageVersion:      DefaultDataPageVersion,
 		DataPageStatistics:   DefaultDataPageStatistics,
+		Concurrency:          DefaultWriteConcurrency,
+		SortingBuffers:       &defaultPageBufferPool,
 	}
 }
 
@@ -192,18 +242,26 @@ func (c *WriterConfig) ConfigureWriter(config *WriterConfig) {
 		}
 	}
 	*config = WriterConfig{
-		CreatedBy:            coalesceString(c.CreatedBy, config.CreatedBy),
-		ColumnPageBuffers:    coalescePageBufferPool(c.ColumnPageBuffers, config.ColumnPageBuffers),
-		ColumnIndexSizeLimit: coalesceInt(c.ColumnIndexSizeLimit, config.ColumnIndexSizeLimit),
-		PageBufferSize:       coalesceInt(c.PageBufferSize, config.PageBufferSize),
-		WriteBufferSize:      coalesceInt(c.WriteBufferSize, config.WriteBufferSize),
-		DataPageVersion:      coalesceInt(c.DataPageVersion, config.DataPageVersion),
-		DataPageStatistics:   config.DataPageStatistics,
-		KeyValueMetadata:     keyValueMetadata,
-		Schema:               coalesceSchema(c.Schema, config.Schema),
-		SortingColumns:       coalesceSortingColumns(c.SortingColumns, config.SortingColumns),
-		BloomFilters:         coalesceBloomFilters(c.BloomFilters, config.BloomFilters),
-		Compression:          coalesceCompression(c.Compression, config.Compression),
+		CreatedBy:             coalesceString(c.CreatedBy, config.CreatedBy),
+		ColumnPageBuffers:     coalescePageBufferPool(c.ColumnPageBuffers, config.ColumnPageBuffers),
+		ColumnIndexSizeLimit:  coalesceInt(c.ColumnIndexSizeLimit, config.ColumnIndexSizeLimit),
+		PageBufferSize:        coalesceInt(c.PageBufferSize, config.PageBufferSize),
+		WriteBufferSize:       coalesceInt(c.WriteBufferSize, config.WriteBufferSize),
+		DataPageVersion:       coalesceInt(c.DataPageVersion, config.DataPageVersion),
+		DataPageStatistics:    config.DataPageStatistics,
+		KeyValueMetadata:      keyValueMetadata,
+		Schema:                coalesceSchema(c.Schema, config.Schema),
+		SortingColumns:        coalesceSortingColumns(c.SortingColumns, config.SortingColumns),
+		BloomFilters:          coalesceBloomFilters(c.BloomFilters, config.BloomFilters),
+		Compression:           coalesceCompression(c.Compression, config.Compression),
+		Encryption:            coalesceEncryption(c.Encryption, config.Encryption),
+		Concurrency:           coalesceInt(c.Concurrency, config.Concurrency),
+		MaxRowsPerRowGroup:    coalesceInt64(c.MaxRowsPerRowGroup, config.MaxRowsPerRowGroup),
+		RowGroupTargetSize:    coalesceInt64(c.RowGroupTargetSize, config.RowGroupTargetSize),
+		MaxRowsPerPage:        coalesceInt64(c.MaxRowsPerPage, config.MaxRowsPerPage),
+		MaxDictionaryPageSize: coalesceInt(c.MaxDictionaryPageSize, config.MaxDictionaryPageSize),
+		SortingBuffers:        coalescePageBufferPool(c.SortingBuffers, config.SortingBuffers),
+		Int96Timestamps:       c.Int96Timestamps || config.Int96Timestamps,
 	}
 }
 
@@ -212,9 +270,16 @@ func (c *WriterConfig) Validate() error {
 	const baseName = "parquet.(*WriterConfig)."
 	return errorInvalidConfiguration(
 		validateNotNil(baseName+"ColumnPageBuffers", c.ColumnPageBuffers),
+		validateNotNil(baseName+"SortingBuffers", c.SortingBuffers),
 		validatePositiveInt(baseName+"ColumnIndexSizeLimit", c.ColumnIndexSizeLimit),
 		validatePositiveInt(baseName+"PageBufferSize", c.PageBufferSize),
 		validateOneOfInt(baseName+"DataPageVersion", c.DataPageVersion, 1, 2),
+		validateEncryptionConfig(c.Encryption),
+		validatePositiveInt(baseName+"Concurrency", c.Concurrency),
+		validateNonNegativeInt64(baseName+"MaxRowsPerRowGroup", c.MaxRowsPerRowGroup),
+		validateNonNegativeInt64(baseName+"RowGroupTargetSize", c.RowGroupTargetSize),
+		validateNonNegativeInt64(baseName+"MaxRowsPerPage", c.MaxRowsPerPage),
+		validateNonNegativeInt64(baseName+"MaxDictionaryPageSize", int64(c.MaxDictionaryPageSize)),
 	)
 }
 
@@ -230,6 +295,7 @@ func (c *WriterConfig) Validate() error {
 type RowGroupConfig struct {
 	ColumnBufferSize int
 	SortingColumns   []SortingColumn
+	BloomFilters     []BloomFilterColumn
 	Schema           *Schema
 }
 
@@ -270,10 +336,132 @@ func (c *RowGroupConfig) ConfigureRowGroup(config *RowGroupConfig) {
 	*config = RowGroupConfig{
 		ColumnBufferSize: coalesceInt(c.ColumnBufferSize, config.ColumnBufferSize),
 		SortingColumns:   coalesceSortingColumns(c.SortingColumns, config.SortingColumns),
+		BloomFilters:     coalesceBloomFilters(c.BloomFilters, config.BloomFilters),
 		Schema:           coalesceSchema(c.Schema, config.Schema),
 	}
 }
 
+// The ConvertConfig type carries configuration options for schema conversions.
+//
+// ConvertConfig implements the ConvertOption interface so it can be used
+// directly as argument to the Convert function when needed, for example:
+//
+//	conv, err := parquet.Convert(to, from, &parquet.ConvertConfig{
+//		Renames: map[string]string{"name": "full_name"},
+//	})
+//
+type ConvertConfig struct {
+	// Maps dot-separated paths of columns in the source schema to their new
+	// paths in the target schema.
+	Renames map[string]string
+}
+
+// DefaultConvertConfig returns a new ConvertConfig value initialized with the
+// default conversion configuration.
+func DefaultConvertConfig() *ConvertConfig {
+	return &ConvertConfig{}
+}
+
+// NewConvertConfig constructs a new conversion configuration applying the
+// options passed as arguments.
+//
+// The function returns an non-nil error if some of the options carried invalid
+// configuration values.
+func NewConvertConfig(options ...ConvertOption) (*ConvertConfig, error) {
+	config := DefaultConvertConfig()
+	config.Apply(options...)
+	return config, config.Validate()
+}
+
+// Apply applies the given list of options to c.
+func (c *ConvertConfig) Apply(options ...ConvertOption) {
+	for _, opt := range options {
+		opt.ConfigureConvert(c)
+	}
+}
+
+// ConfigureConvert applies configuration options from c to config.
+func (c *ConvertConfig) ConfigureConvert(config *ConvertConfig) {
+	for from, to := range c.Renames {
+		config.rename(from, to)
+	}
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *ConvertConfig) Validate() error {
+	const baseName = "parquet.(*ConvertConfig)."
+	return errorInvalidConfiguration(
+		validateUniqueValues(baseName+"Renames", c.Renames),
+	)
+}
+
+func (c *ConvertConfig) rename(from, to string) {
+	if c.Renames == nil {
+		c.Renames = make(map[string]string)
+	}
+	c.Renames[from] = to
+}
+
+// The DatasetConfig type carries configuration options for datasets.
+//
+// DatasetConfig implements the DatasetOption interface so it can be used
+// directly as argument to the OpenDataset function when needed, for example:
+//
+//	dataset, err := parquet.OpenDataset(fsys, "events", &parquet.DatasetConfig{
+//		Filter: parquet.Eq([]string{"region"}, parquet.ValueOf("eu")),
+//	})
+//
+type DatasetConfig struct {
+	Schema      *Schema
+	Filter      Filter
+	FilePattern string
+	FileOptions []FileOption
+}
+
+// DefaultDatasetConfig returns a new DatasetConfig value initialized with the
+// default dataset configuration.
+func DefaultDatasetConfig() *DatasetConfig {
+	return &DatasetConfig{
+		FilePattern: DefaultDatasetFilePattern,
+	}
+}
+
+// NewDatasetConfig constructs a new dataset configuration applying the options
+// passed as arguments.
+//
+// The function returns an non-nil error if some of the options carried invalid
+// configuration values.
+func NewDatasetConfig(options ...DatasetOption) (*DatasetConfig, error) {
+	config := DefaultDatasetConfig()
+	config.Apply(options...)
+	return config, config.Validate()
+}
+
+// Apply applies the given list of options to c.
+func (c *DatasetConfig) Apply(options ...DatasetOption) {
+	for _, opt := range options {
+		opt.ConfigureDataset(c)
+	}
+}
+
+// ConfigureDataset applies configuration options from c to config.
+func (c *DatasetConfig) ConfigureDataset(config *DatasetConfig) {
+	*config = DatasetConfig{
+		Schema:      coalesceSchema(c.Schema, config.Schema),
+		Filter:      coalesceFilter(c.Filter, config.Filter),
+		FilePattern: coalesceString(c.FilePattern, config.FilePattern),
+		FileOptions: append(config.FileOptions, c.FileOptions...),
+	}
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *DatasetConfig) Validate() error {
+	const baseName = "parquet.(*DatasetConfig)."
+	return errorInvalidConfiguration(
+		validateFilePattern(baseName+"FilePattern", c.FilePattern),
+	)
+}
+
 // FileOption is an interface implemented by types that carry configuration
 // options for parquet files.
 type FileOption interface {
@@ -298,6 +486,18 @@ type RowGroupOption interface {
 	ConfigureRowGroup(*RowGroupConfig)
 }
 
+// ConvertOption is an interface implemented by types that carry configuration
+// options for schema conversions.
+type ConvertOption interface {
+	ConfigureConvert(*ConvertConfig)
+}
+
+// DatasetOption is an interface implemented by types that carry configuration
+// options for datasets.
+type DatasetOption interface {
+	ConfigureDataset(*DatasetConfig)
+}
+
 // SkipPageIndex is a file configuration option which prevents automatically
 // reading the page index when opening a parquet file, when set to true. This is
 // useful as an optimization when programs know that they will not need to
@@ -318,6 +518,210 @@ func SkipBloomFilters(skip bool) FileOption {
 	return fileOption(func(config *FileConfig) { config.SkipBloomFilters = skip })
 }
 
+// ReadFooterSize is a file configuration option which sets the number of bytes
+// read speculatively from the end of the file when opening it.
+//
+// Opening a file requires reading its footer, then the page index which parquet
+// writers place right before the footer. When the speculative read covers both,
+// the file is opened with a single request instead of one request for the
+// footer length, one for the footer, and one for each section of the page
+// index, which reduces the latency of opening files on object storage.
+//
+// Defaults to zero, which disables speculative reads.
+func ReadFooterSize(size int64) FileOption {
+	return fileOption(func(config *FileConfig) { config.ReadFooterSize = size })
+}
+
+// CoalesceReads is a file configuration option which enables planning the
+// reads of the column chunks and bloom filters of a file.
+//
+// When enabled, the byte ranges of the column chunks selected by the file
+// projection are merged with their neighbors within each row group, as long
+// as they are separated by at most gap bytes and the merged ranges do not
+// exceed size bytes; column chunks larger than size are read with a single
+// request. The first read of a column chunk of a row group fetches all the
+// ranges of the row group concurrently and buffers them in memory, instead of
+// issuing small reads for each page. Row groups which are never read are never
+// fetched, and the memory of ranges is released once all the column chunks
+// that they contain have been read.
+//
+// Bloom filters, which are loaded when opening the file, are fetched the same
+// way.
+//
+// Defaults to zero, reads are not coalesced.
+func CoalesceReads(gap, size int64) FileOption {
+	return fileOption(func(config *FileConfig) {
+		config.ReadRangeGap = gap
+		config.ReadRangeSize = size
+	})
+}
+
+// ReadRangeConcurrency is a file configuration option which sets the maximum
+// number of ranges fetched concurrently when reads are coalesced. The option
+// has no effect unless CoalesceReads was also used.
+//
+// Defaults to 4.
+func ReadRangeConcurrency(concurrency int) FileOption {
+	return fileOption(func(config *FileConfig) { config.ReadRangeConcurrency = concurrency })
+}
+
+// CacheMetadata is a file configuration option which sets the cache used to
+// store the metadata of files, and the name identifying the file being opened
+// in the cache.
+//
+// When the cache contains the metadata of a file with the same name and size,
+// the file footer and page index are not read again. Otherwise, the metadata
+// is stored in the cache after opening the file. The metadata of encrypted
+// files is never cached.
+//
+// The name must not be empty, and must be unique to the content of the file:
+// files with different contents but the same name and size would be opened
+// with the metadata of one another.
+//
+// Defaults to no cache.
+func CacheMetadata(cache MetadataCache, name string) FileOption {
+	return fileOption(func(config *FileConfig) {
+		config.MetadataCache = cache
+		config.MetadataCacheKey = name
+	})
+}
+
+// FilterRows is a reader configuration option which restricts the rows
+// returned by the reader to those matching the filter.
+//
+// The filter is first used to skip row groups and pages which cannot contain
+// matching rows, based on the column chunk statistics, page index and bloom
+// filters of the parquet file, then evaluated on each row read from the
+// remaining pages. As a consequence, the number of rows reported by the NumRows
+// method of readers is an upper bound of the number of rows that will be read,
+// and the row indexes passed to SeekToRow are relative to the rows retained
+// after skipping row groups and pages.
+//
+// Defaults to no filter.
+func FilterRows(filter Filter) ReaderOption {
+	return readerOption(func(config *ReaderConfig) { config.Filter = filter })
+}
+
+// SelectColumns is a file and reader configuration option which restricts the
+// columns exposed by parquet files and readers to those at the given paths.
+//
+// Paths may designate leaf columns or groups, including columns nested in
+// groups, lists and maps; see ProjectSchema for details. Rows are read
+// according to the projected schema, and pages of the columns that were not
+// selected are never read.
+//
+// When applied to a file, the projection applies to the schema and row groups
+// returned by the File methods, while File.Root still describes all the
+// columns of the file. Columns referenced by a filter of a reader must be part
+// of the projection.
+//
+// Defaults to selecting all the columns.
+func SelectColumns(paths ...[]string) interface {
+	FileOption
+	ReaderOption
+} {
+	columns := make([][]string, len(paths))
+	for i, path := range paths {
+		columns[i] = append([]string{}, path...)
+	}
+	return selectColumns(columns)
+}
+
+type selectColumns [][]string
+
+func (columns selectColumns) ConfigureFile(config *FileConfig) {
+	config.Columns = columns
+}
+
+func (columns selectColumns) ConfigureReader(config *ReaderConfig) {
+	config.Columns = columns
+}
+
+// ReadConcurrency is a reader configuration option which sets the number of
+// column chunks that may have their pages read, decompressed, and decoded in
+// parallel.
+//
+// When the concurrency level is greater than one, the pages of each column
+// chunk are prefetched on background goroutines and handed back in order to
+// the reader assembling the rows, which allows scans of wide tables to use
+// multiple CPU cores. Programs that do not read all the rows of a reader
+// configured with concurrency should call its Close method to release the
+// goroutines.
+//
+// Defaults to 1, pages are read on the goroutine reading rows.
+func ReadConcurrency(concurrency int) ReaderOption {
+	return readerOption(func(config *ReaderConfig) { config.Concurrency = concurrency })
+}
+
+// WriteConcurrency is a writer configuration option which sets the number of
+// columns that may have their pages encoded and compressed in parallel.
+//
+// When the concurrency level is greater than one, each column of the writer
+// flushes its pages on a background goroutine while the program keeps writing
+// rows, and the bloom filters and dictionary pages of the column chunks are
+// generated in parallel when a row group is written. The column chunks are
+// then written to the output in column order. Each column uses its own scratch
+// buffers in this mode, which increases the memory footprint of the writer,
+// and the page buffer pool must be safe to use concurrently.
+//
+// Data pages of dictionary-encoded columns are always flushed on the goroutine
+// writing rows, since they reference the dictionary being built.
+//
+// Defaults to 1, pages are encoded on the goroutine writing rows.
+func WriteConcurrency(concurrency int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.Concurrency = concurrency })
+}
+
+// MaxRowsPerRowGroup configures the maximum number of rows that parquet writers
+// buffer in a row group.
+//
+// When the limit is reached, the buffered rows are automatically flushed to a
+// new row group, as if the application had called Flush.
+//
+// Defaults to zero, row groups are only flushed by calls to Flush or Close.
+func MaxRowsPerRowGroup(numRows int64) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.MaxRowsPerRowGroup = numRows })
+}
+
+// RowGroupTargetSize configures the size in bytes that parquet writers aim for
+// when producing row groups.
+//
+// The size of a row group is the uncompressed size of the values written to
+// its columns; rows are flushed to a new row group after the size reaches the
+// target. The size is updated when the columns flush their pages, so row groups
+// may exceed the target by up to the page buffer size of each column. Row
+// groups sized to match the block size of the storage (e.g. 128 MiB) are
+// usually the most efficient to read for query engines.
+//
+// Defaults to zero, row groups are only flushed by calls to Flush or Close.
+func RowGroupTargetSize(size int64) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.RowGroupTargetSize = size })
+}
+
+// MaxRowsPerPage configures the maximum number of rows that parquet writers put
+// in each data page.
+//
+// Pages are still cut when they reach the page buffer size, whichever limit is
+// reached first.
+//
+// Defaults to zero, the number of rows in pages is not limited.
+func MaxRowsPerPage(numRows int64) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.MaxRowsPerPage = numRows })
+}
+
+// MaxDictionaryPageSize configures the maximum size of dictionary pages written
+// by parquet writers.
+//
+// When the dictionary of a column grows larger than this limit, the writer
+// stops adding values to it, and the remaining data pages of the column chunk
+// are written with the PLAIN encoding instead. The dictionary encoding is used
+// again on the next row group.
+//
+// Defaults to zero, the size of dictionaries is not limited.
+func MaxDictionaryPageSize(size int) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.MaxDictionaryPageSize = size })
+}
+
 // PageBufferSize configures the size of column page buffers on parquet writers.
 //
 // Note that the page buffer size refers to the in-memory buffers where pages
@@ -359,6 +763,20 @@ func ColumnPageBuffers(buffers PageBufferPool) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.ColumnPageBuffers = buffers })
 }
 
+// SortingBuffers creates a configuration option to customize the buffer pool
+// used by sorting writers to hold the sorted runs of rows until they are
+// merged into the output. Passing a pool created by NewFileBufferPool allows
+// sorting data sets that do not fit in memory.
+//
+// The sorted runs are read back from the buffers, which must implement
+// io.ReaderAt unless they were created by NewPageBufferPool; writers return an
+// error when flushing rows otherwise.
+//
+// Defaults to using in-memory buffers.
+func SortingBuffers(buffers PageBufferPool) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.SortingBuffers = buffers })
+}
+
 // ColumnIndexSizeLimit creates a configuration option to customize the size
 // limit of page boundaries recorded in column indexes.
 //
@@ -385,6 +803,20 @@ func DataPageStatistics(enabled bool) WriterOption {
 	return writerOption(func(config *WriterConfig) { config.DataPageStatistics = enabled })
 }
 
+// Int96Timestamps creates a configuration option which defines whether columns
+// of TIMESTAMP logical type are written as legacy INT96 values. This option is
+// useful to produce parquet files intended to be consumed by applications which
+// only support this representation of timestamps, such as older versions of
+// Hive, Impala, or Spark.
+//
+// Rows written to the writer still use the writer's schema, the timestamps are
+// converted to INT96 values when written to the file.
+//
+// Defaults to false.
+func Int96Timestamps(enabled bool) WriterOption {
+	return writerOption(func(config *WriterConfig) { config.Int96Timestamps = enabled })
+}
+
 // KeyValueMetadata creates a configuration option which adds key/value metadata
 // to add to the metadata of parquet files.
 //
@@ -415,9 +847,28 @@ func KeyValueMetadata(key, value string) WriterOption {
 // of a parquet schema can be significant, so by default no filters are created
 // and applications need to explicitly declare the columns that they want to
 // create filters for.
-func BloomFilters(filters ...BloomFilterColumn) WriterOption {
+//
+// The option may also be passed to NewBuffer, in which case the filters are
+// built as rows are written to the buffer and returned by the BloomFilter
+// method of its column chunks. When a buffer is written to a parquet writer
+// configured with filters on the same columns, the filters of the buffer are
+// copied to the file instead of being generated again.
+func BloomFilters(filters ...BloomFilterColumn) interface {
+	RowGroupOption
+	WriterOption
+} {
 	filters = append([]BloomFilterColumn{}, filters...)
-	return writerOption(func(config *WriterConfig) { config.BloomFilters = filters })
+	return bloomFilters(filters)
+}
+
+type bloomFilters []BloomFilterColumn
+
+func (filters bloomFilters) ConfigureRowGroup(config *RowGroupConfig) {
+	config.BloomFilters = filters
+}
+
+func (filters bloomFilters) ConfigureWriter(config *WriterConfig) {
+	config.BloomFilters = filters
 }
 
 // Compression creates a configuration option which sets the default compression
@@ -462,6 +913,23 @@ func (columns sortingColumns) ConfigureWriter(config *WriterConfig) {
 	config.SortingColumns = columns
 }
 
+// RenameColumns creates a configuration option which declares columns that
+// were renamed between the source and target schemas of a conversion.
+//
+// Keys of the map are the dot-separated paths of columns in the source schema,
+// values are their paths in the target schema. Renaming a group applies to all
+// the columns nested in it.
+//
+// This option is additive, it may be used multiple times to declare more than
+// one set of renamed columns.
+func RenameColumns(renames map[string]string) ConvertOption {
+	return convertOption(func(config *ConvertConfig) {
+		for from, to := range renames {
+			config.rename(from, to)
+		}
+	})
+}
+
 type fileOption func(*FileConfig)
 
 func (opt fileOption) ConfigureFile(config *FileConfig) { opt(config) }
@@ -478,6 +946,44 @@ type rowGroupOption func(*RowGroupConfig)
 
 func (opt rowGroupOption) ConfigureRowGroup(config *RowGroupConfig) { opt(config) }
 
+// FilterPartitions is a dataset configuration option which restricts the files
+// of a dataset to those where the values of the partition columns match the
+// filter. Files that do not match are not opened.
+//
+// The filter may only reference partition columns, which are string columns.
+//
+// Defaults to no filter.
+func FilterPartitions(filter Filter) DatasetOption {
+	return datasetOption(func(config *DatasetConfig) { config.Filter = filter })
+}
+
+// DatasetFilePattern is a dataset configuration option which sets the pattern
+// that the names of files must match to be part of a dataset, using the syntax
+// of path.Match. Other files, such as the _SUCCESS markers or checksum files
+// written next to parquet files by some applications, are ignored.
+//
+// Defaults to "*.parquet".
+func DatasetFilePattern(pattern string) DatasetOption {
+	return datasetOption(func(config *DatasetConfig) { config.FilePattern = pattern })
+}
+
+// DatasetFileOptions is a dataset configuration option which sets the options
+// used to open the files of a dataset.
+//
+// Defaults to no options.
+func DatasetFileOptions(options ...FileOption) DatasetOption {
+	options = append([]FileOption{}, options...)
+	return datasetOption(func(config *DatasetConfig) { config.FileOptions = append(config.FileOptions, options...) })
+}
+
+type datasetOption func(*DatasetConfig)
+
+func (opt datasetOption) ConfigureDataset(config *DatasetConfig) { opt(config) }
+
+type convertOption func(*ConvertConfig)
+
+func (opt convertOption) ConfigureConvert(config *ConvertConfig) { opt(config) }
+
 func coalesceInt(i1, i2 int) int {
 	if i1 != 0 {
 		return i1
@@ -513,6 +1019,13 @@ func coalescePageBufferPool(p1, p2 PageBufferPool) PageBufferPool {
 	return p2
 }
 
+func coalesceMetadataCache(c1, c2 MetadataCache) MetadataCache {
+	if c1 != nil {
+		return c1
+	}
+	return c2
+}
+
 func coalesceSchema(s1, s2 *Schema) *Schema {
 	if s1 != nil {
 		return s1
@@ -520,6 +1033,34 @@ func coalesceSchema(s1, s2 *Schema) *Schema {
 	return s2
 }
 
+func coalesceFilter(f1, f2 Filter) Filter {
+	if f1 != nil {
+		return f1
+	}
+	return f2
+}
+
+func coalesceDecryption(d1, d2 *DecryptionConfig) *DecryptionConfig {
+	if d1 != nil {
+		return d1
+	}
+	return d2
+}
+
+func coalesceEncryption(e1, e2 *EncryptionConfig) *EncryptionConfig {
+	if e1 != nil {
+		return e1
+	}
+	return e2
+}
+
+func coalesceColumnPaths(c1, c2 [][]string) [][]string {
+	if c1 != nil {
+		return c1
+	}
+	return c2
+}
+
 func coalesceSortingColumns(s1, s2 []SortingColumn) []SortingColumn {
 	if s1 != nil {
 		return s1
@@ -555,6 +1096,13 @@ func validatePositiveInt64(optionName string, optionValue int64) error {
 	return errorInvalidOptionValue(optionName, optionValue)
 }
 
+func validateNonNegativeInt64(optionName string, optionValue int64) error {
+	if optionValue >= 0 {
+		return nil
+	}
+	return errorInvalidOptionValue(optionName, optionValue)
+}
+
 func validateOneOfInt(optionName string, optionValue int, supportedValues ...int) error {
 	for _, value := range supportedValues {
 		if value == optionValue {
@@ -564,6 +1112,17 @@ func validateOneOfInt(optionName string, optionValue int, supportedValues ...int
 	return errorInvalidOptionValue(optionName, optionValue)
 }
 
+func validateUniqueValues(optionName string, optionValue map[string]string) error {
+	values := make(map[string]struct{}, len(optionValue))
+	for _, value := range optionValue {
+		if _, exists := values[value]; exists {
+			return errorInvalidOptionValue(optionName, optionValue)
+		}
+		values[value] = struct{}{}
+	}
+	return nil
+}
+
 func validateNotNil(optionName string, optionValue interface{}) error {
 	if optionValue != nil {
 		return nil
@@ -571,6 +1130,20 @@ func validateNotNil(optionName string, optionValue interface{}) error {
 	return errorInvalidOptionValue(optionName, optionValue)
 }
 
+func validateFilePattern(optionName string, pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return errorInvalidOptionValue(optionName, pattern)
+	}
+	return nil
+}
+
+func validateMetadataCacheKey(optionName string, cache MetadataCache, key string) error {
+	if cache == nil || key != "" {
+		return nil
+	}
+	return fmt.Errorf("invalid option value: %s: files must be named to cache their metadata", optionName)
+}
+
 func errorInvalidOptionValue(optionName string, optionValue interface{}) error {
 	return fmt.Errorf("invalid option value: %s: %v", optionName, optionValue)
 }
@@ -616,4 +1189,6 @@ var (
 	_ ReaderOption   = (*ReaderConfig)(nil)
 	_ WriterOption   = (*WriterConfig)(nil)
 	_ RowGroupOption = (*RowGroupConfig)(nil)
+	_ DatasetOption  = (*DatasetConfig)(nil)
+	_ DatasetOption  = (*Schema)(nil)
 )
@@ -3,6 +3,7 @@ package parquet
 import (
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 )
 
@@ -48,13 +49,44 @@ type Conversion interface {
 }
 
 type conversion struct {
-	targetColumnKinds   []Kind
+	columns             []conversionColumn
 	targetToSourceIndex []int16
 	sourceToTargetIndex []int16
 	schema              *Schema
 	buffers             sync.Pool
 }
 
+// conversionColumn holds the transformations applied to the values of a column
+// of the target schema.
+type conversionColumn struct {
+	kind Kind
+	// Non-nil when the physical type of the column was promoted, the function
+	// converts non-null values of the source column to the target type.
+	promote func(Value) Value
+	// Non-nil when required fields of the source schema are optional in the
+	// target schema, the slice is indexed by the definition levels of values
+	// in the source column and holds the target definition levels.
+	definitionLevels []int8
+}
+
+func (c *conversionColumn) convertValue(value Value, columnIndex int16) Value {
+	if c.promote != nil && !value.IsNull() {
+		repetitionLevel, definitionLevel := value.repetitionLevel, value.definitionLevel
+		value = c.promote(value)
+		value.repetitionLevel, value.definitionLevel = repetitionLevel, definitionLevel
+	}
+	if c.definitionLevels != nil && int(value.definitionLevel) < len(c.definitionLevels) {
+		value.definitionLevel = c.definitionLevels[value.definitionLevel]
+	}
+	value.kind = ^int8(c.kind)
+	value.columnIndex = ^columnIndex
+	return value
+}
+
+func (c *conversionColumn) isIdentity() bool {
+	return c.promote == nil && c.definitionLevels == nil
+}
+
 type conversionBuffer struct {
 	columns [][]Value
 }
@@ -62,7 +94,7 @@ type conversionBuffer struct {
 func (c *conversion) getBuffer() *conversionBuffer {
 	b, _ := c.buffers.Get().(*conversionBuffer)
 	if b == nil {
-		n := len(c.targetColumnKinds)
+		n := len(c.columns)
 		columns, values := make([][]Value, n), make([]Value, n)
 		for i := range columns {
 			columns[i] = values[i : i : i+1]
@@ -88,8 +120,7 @@ func (c *conversion) Convert(target, source Row) (Row, error) {
 		sourceIndex := value.Column()
 		targetIndex := c.sourceToTargetIndex[sourceIndex]
 		if targetIndex >= 0 {
-			value.kind = ^int8(c.targetColumnKinds[targetIndex])
-			value.columnIndex = ^targetIndex
+			value = c.columns[targetIndex].convertValue(value, targetIndex)
 			buffer.columns[targetIndex] = append(buffer.columns[targetIndex], value)
 		}
 	}
@@ -97,7 +128,7 @@ func (c *conversion) Convert(target, source Row) (Row, error) {
 	for i, values := range buffer.columns {
 		if len(values) == 0 {
 			values = append(values, Value{
-				kind:        ^int8(c.targetColumnKinds[i]),
+				kind:        ^int8(c.columns[i].kind),
 				columnIndex: ^int16(i),
 			})
 		}
@@ -128,60 +159,203 @@ func (id identity) Schema() *Schema                   { return id.schema }
 // stripped out of the rows. Extra columns in the target schema will be set to
 // null or zero values.
 //
+// Columns are matched by field id when the target schema carries one and the
+// source schema has field ids at the same level, otherwise they are matched by
+// name, taking into account the renames declared with the RenameColumns option.
+//
+// The schemas may differ in the following ways, all other changes of types or
+// repetitions result in a *ConvertError:
+//
+//   - INT32 columns promoted to INT64
+//   - FLOAT columns promoted to DOUBLE
+//   - INT96 columns converted to INT64 columns of TIMESTAMP logical type, and
+//     vice versa; the precision of the values is truncated to the time unit of
+//     the target column
+//   - required fields relaxed to optional
+//
 // The returned function is intended to be used to append the converted source
 // row to the destination buffer.
-func Convert(to, from Node) (conv Conversion, err error) {
+func Convert(to, from Node, options ...ConvertOption) (conv Conversion, err error) {
+	config, err := NewConvertConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+
 	schema, _ := to.(*Schema)
 	if schema == nil {
 		schema = NewSchema("", to)
 	}
 
-	if nodesAreEqual(to, from) {
+	if len(config.Renames) == 0 && nodesAreEqual(to, from) {
 		return identity{schema}, nil
 	}
 
-	targetMapping, targetColumns := columnMappingOf(to)
 	sourceMapping, sourceColumns := columnMappingOf(from)
+	numTargetColumns := numLeafColumnsOf(to)
+
+	m := &columnMatcher{
+		sourceMapping: sourceMapping,
+		renames:       make(map[string]string, len(config.Renames)),
+		renamed:       config.Renames,
+		columns:       make([]conversionColumn, numTargetColumns),
+		sourceIndex:   make([]int16, numTargetColumns),
+	}
+	for sourcePath, targetPath := range config.Renames {
+		m.renames[targetPath] = sourcePath
+	}
+	if err := m.match(to, from, nil, nil, 0, nil); err != nil {
+		return nil, err
+	}
+
+	sourceToTargetIndex := make([]int16, len(sourceColumns))
+	for i := range sourceToTargetIndex {
+		sourceToTargetIndex[i] = -1
+	}
+	for i, j := range m.sourceIndex {
+		if j >= 0 {
+			sourceToTargetIndex[j] = int16(i)
+		}
+	}
 
-	columnIndexBuffer := make([]int16, len(targetColumns)+len(sourceColumns))
-	targetColumnKinds := make([]Kind, len(targetColumns))
-	targetToSourceIndex := columnIndexBuffer[:len(targetColumns)]
-	sourceToTargetIndex := columnIndexBuffer[len(targetColumns):]
+	return &conversion{
+		columns:             m.columns,
+		targetToSourceIndex: m.sourceIndex,
+		sourceToTargetIndex: sourceToTargetIndex,
+		schema:              schema,
+	}, nil
+}
+
+// columnMatcher walks the target and source schemas of a conversion to pair
+// their leaf columns and determine how values need to be converted.
+type columnMatcher struct {
+	sourceMapping columnMappingGroup
+	renames       map[string]string // target path => source path
+	renamed       map[string]string // source path => target path
+	columns       []conversionColumn
+	sourceIndex   []int16
+	numColumns    int
+}
 
-	for i, path := range targetColumns {
-		sourceColumn := sourceMapping.lookup(path)
-		targetColumn := targetMapping.lookup(path)
-		targetToSourceIndex[i] = sourceColumn.columnIndex
-		targetColumnKinds[i] = targetColumn.node.Type().Kind()
+func (m *columnMatcher) match(target, source Node, targetPath, sourcePath columnPath, sourceDefinitionLevel int, relaxedLevels []int) error {
+	if source != nil {
+		switch {
+		case target.Repeated() != source.Repeated(), target.Required() && !source.Required():
+			return &ConvertError{Path: sourcePath, From: source, To: target}
+		case target.Optional() && source.Required():
+			relaxedLevels = append(relaxedLevels[:len(relaxedLevels):len(relaxedLevels)], sourceDefinitionLevel)
+		case !source.Required():
+			sourceDefinitionLevel++
+		}
 	}
 
-	for i, path := range sourceColumns {
-		sourceColumn := sourceMapping.lookup(path)
-		targetColumn := targetMapping.lookup(path)
+	if target.Leaf() {
+		columnIndex := m.numColumns
+		m.numColumns++
+		m.columns[columnIndex].kind = target.Type().Kind()
+		m.sourceIndex[columnIndex] = -1
+
+		if source == nil || !source.Leaf() {
+			return nil
+		}
 
-		if targetColumn.node != nil {
-			sourceType := sourceColumn.node.Type()
-			targetType := targetColumn.node.Type()
-			if sourceType.Kind() != targetType.Kind() {
-				return nil, &ConvertError{Path: path, From: sourceColumn.node, To: targetColumn.node}
+		column := &m.columns[columnIndex]
+		sourceKind := source.Type().Kind()
+		if sourceKind != column.kind {
+			if column.promote = promotionOf(source.Type(), target.Type()); column.promote == nil {
+				return &ConvertError{Path: sourcePath, From: source, To: target}
 			}
+		}
 
-			sourceRepetition := fieldRepetitionTypeOf(sourceColumn.node)
-			targetRepetition := fieldRepetitionTypeOf(targetColumn.node)
-			if sourceRepetition != targetRepetition {
-				return nil, &ConvertError{Path: path, From: sourceColumn.node, To: targetColumn.node}
+		if len(relaxedLevels) > 0 {
+			column.definitionLevels = make([]int8, sourceDefinitionLevel+1)
+			for definitionLevel := range column.definitionLevels {
+				targetDefinitionLevel := definitionLevel
+				for _, relaxedLevel := range relaxedLevels {
+					if relaxedLevel <= definitionLevel {
+						targetDefinitionLevel++
+					}
+				}
+				column.definitionLevels[definitionLevel] = makeDefinitionLevel(targetDefinitionLevel)
 			}
 		}
 
-		sourceToTargetIndex[i] = targetColumn.columnIndex
+		m.sourceIndex[columnIndex] = m.sourceMapping.lookup(sourcePath).columnIndex
+		return nil
 	}
 
-	return &conversion{
-		targetColumnKinds:   targetColumnKinds,
-		targetToSourceIndex: targetToSourceIndex,
-		sourceToTargetIndex: sourceToTargetIndex,
-		schema:              schema,
-	}, nil
+	for _, field := range target.Fields() {
+		fieldPath := targetPath.append(field.Name())
+		sourceField, sourceFieldPath := m.lookup(source, sourcePath, field, fieldPath)
+		if err := m.match(field, sourceField, fieldPath, sourceFieldPath, sourceDefinitionLevel, relaxedLevels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the field of the source group which matches the target field
+// at the given path, or nil if none exist.
+func (m *columnMatcher) lookup(source Node, sourcePath columnPath, target Node, targetPath columnPath) (Node, columnPath) {
+	if source == nil || source.Leaf() {
+		return nil, nil
+	}
+
+	if id := fieldIDOf(target); id != 0 {
+		hasFieldIDs := false
+		for _, field := range source.Fields() {
+			switch fieldIDOf(field) {
+			case id:
+				return field, sourcePath.append(field.Name())
+			case 0:
+			default:
+				hasFieldIDs = true
+			}
+		}
+		// When the source schema has field ids, columns that were added
+		// to the target schema must not be matched by name since they may
+		// reuse names of columns that were removed.
+		if hasFieldIDs {
+			return nil, nil
+		}
+	}
+
+	name := targetPath[len(targetPath)-1]
+	if renamed, ok := m.renames[targetPath.String()]; ok {
+		path := columnPath(strings.Split(renamed, "."))
+		if !path[:len(path)-1].equal(sourcePath) {
+			return nil, nil
+		}
+		name = path[len(path)-1]
+	} else if _, ok := m.renamed[sourcePath.append(name).String()]; ok {
+		return nil, nil
+	}
+
+	if field := childByName(source, name); field != nil {
+		return field, sourcePath.append(name)
+	}
+	return nil, nil
+}
+
+// promotionOf returns the function converting values of the source type to
+// the target type, or nil if the conversion is not supported.
+func promotionOf(source, target Type) func(Value) Value {
+	switch sourceKind, targetKind := source.Kind(), target.Kind(); {
+	case sourceKind == Int32 && targetKind == Int64:
+		return func(v Value) Value { return makeValueInt64(int64(v.Int32())) }
+	case sourceKind == Float && targetKind == Double:
+		return func(v Value) Value { return makeValueDouble(float64(v.Float())) }
+	case sourceKind == Int96 && targetKind == Int64:
+		if t := timestampTypeOf(target); t != nil {
+			t := t.utc()
+			return func(v Value) Value { return makeValueInt64(t.timestamp(int96ToTime(v.Int96()))) }
+		}
+	case sourceKind == Int64 && targetKind == Int96:
+		if t := timestampTypeOf(source); t != nil {
+			t := t.utc()
+			return func(v Value) Value { return makeValueInt96(timeToInt96(t.time(v.Int64()))) }
+		}
+	}
+	return nil
 }
 
 // ConvertRowGroup constructs a wrapper of the given row group which applies
@@ -208,7 +382,7 @@ func ConvertRowGroup(rowGroup RowGroup, conv Conversion) RowGroup {
 				numNulls:  numRows,
 			}
 		} else {
-			columns[i] = rowGroupColumns[j]
+			columns[i] = convertColumnChunk(rowGroupColumns[j], leaf, conv)
 		}
 	})
 
@@ -357,6 +531,166 @@ func (r *missingValues) ReadValues(values []Value) (int, error) {
 	return len(values), nil
 }
 
+func convertColumnChunk(chunk ColumnChunk, leaf leafColumn, conv Conversion) ColumnChunk {
+	c, _ := conv.(*conversion)
+	if c == nil {
+		return chunk
+	}
+	column := &c.columns[leaf.columnIndex]
+	if column.isIdentity() && chunk.Column() == int(leaf.columnIndex) {
+		return chunk
+	}
+	return &convertedColumnChunk{
+		base:               chunk,
+		typ:                leaf.node.Type(),
+		conv:               column,
+		column:             leaf.columnIndex,
+		maxRepetitionLevel: leaf.maxRepetitionLevel,
+		maxDefinitionLevel: leaf.maxDefinitionLevel,
+	}
+}
+
+// convertedColumnChunk wraps column chunks of row groups which had their type,
+// definition levels, or column index changed by a conversion.
+type convertedColumnChunk struct {
+	base               ColumnChunk
+	typ                Type
+	conv               *conversionColumn
+	column             int16
+	maxRepetitionLevel int8
+	maxDefinitionLevel int8
+}
+
+func (c *convertedColumnChunk) Type() Type   { return c.typ }
+func (c *convertedColumnChunk) Column() int  { return int(c.column) }
+func (c *convertedColumnChunk) Pages() Pages { return &convertedPages{c.base.Pages(), c} }
+func (c *convertedColumnChunk) ColumnIndex() ColumnIndex {
+	index := c.base.ColumnIndex()
+	if index == nil {
+		return nil
+	}
+	return &convertedColumnIndex{index, c}
+}
+func (c *convertedColumnChunk) OffsetIndex() OffsetIndex { return c.base.OffsetIndex() }
+func (c *convertedColumnChunk) NumValues() int64         { return c.base.NumValues() }
+
+func (c *convertedColumnChunk) BloomFilter() BloomFilter {
+	if c.conv.promote != nil {
+		// The bloom filter holds hashes of values of the source type, it
+		// cannot be used to test the presence of promoted values.
+		return nil
+	}
+	return c.base.BloomFilter()
+}
+
+func (c *convertedColumnChunk) convertValue(value Value) Value {
+	return c.conv.convertValue(value, c.column)
+}
+
+type convertedColumnIndex struct {
+	ColumnIndex
+	chunk *convertedColumnChunk
+}
+
+func (i *convertedColumnIndex) MinValue(page int) Value {
+	return i.chunk.convertValue(i.ColumnIndex.MinValue(page))
+}
+
+func (i *convertedColumnIndex) MaxValue(page int) Value {
+	return i.chunk.convertValue(i.ColumnIndex.MaxValue(page))
+}
+
+type convertedPages struct {
+	base  Pages
+	chunk *convertedColumnChunk
+}
+
+func (p *convertedPages) ReadPage() (Page, error) {
+	page, err := p.base.ReadPage()
+	if page != nil {
+		page = &convertedPage{page, p.chunk}
+	}
+	return page, err
+}
+
+func (p *convertedPages) SeekToRow(rowIndex int64) error { return p.base.SeekToRow(rowIndex) }
+
+type convertedPage struct {
+	Page
+	chunk *convertedColumnChunk
+}
+
+func (p *convertedPage) Column() int         { return int(p.chunk.column) }
+func (p *convertedPage) Values() ValueReader { return &convertedValues{p.Page.Values(), p.chunk} }
+
+func (p *convertedPage) Dictionary() Dictionary {
+	if p.chunk.conv.promote != nil {
+		// Values of the dictionary have the source type, the page values
+		// are exposed decoded and converted instead.
+		return nil
+	}
+	return p.Page.Dictionary()
+}
+
+func (p *convertedPage) Bounds() (min, max Value, ok bool) {
+	if min, max, ok = p.Page.Bounds(); ok {
+		min, max = p.chunk.convertValue(min), p.chunk.convertValue(max)
+	}
+	return min, max, ok
+}
+
+func (p *convertedPage) Buffer() BufferedPage {
+	c := p.chunk
+	values := make([]Value, p.NumValues())
+	n, err := readAllValues(p.Values(), values)
+	if err != nil {
+		return newErrorPage(c.Column(), "converting page values: %w", err)
+	}
+
+	buffer := c.typ.NewColumnBuffer(c.Column(), n)
+	switch {
+	case c.maxRepetitionLevel > 0:
+		buffer = newRepeatedColumnBuffer(buffer, c.maxRepetitionLevel, c.maxDefinitionLevel, nullsGoLast)
+	case c.maxDefinitionLevel > 0:
+		buffer = newOptionalColumnBuffer(buffer, c.maxDefinitionLevel, nullsGoLast)
+	}
+	if _, err := buffer.WriteValues(values[:n]); err != nil {
+		return newErrorPage(c.Column(), "converting page values: %w", err)
+	}
+	return buffer.Page()
+}
+
+type convertedValues struct {
+	base  ValueReader
+	chunk *convertedColumnChunk
+}
+
+func (r *convertedValues) ReadValues(values []Value) (int, error) {
+	n, err := r.base.ReadValues(values)
+	for i := range values[:n] {
+		values[i] = r.chunk.convertValue(values[i])
+	}
+	return n, err
+}
+
+func readAllValues(r ValueReader, values []Value) (int, error) {
+	n := 0
+	for n < len(values) {
+		v, err := r.ReadValues(values[n:])
+		n += v
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return n, err
+		}
+		if v == 0 {
+			return n, io.ErrNoProgress
+		}
+	}
+	return n, nil
+}
+
 type convertedRowGroup struct {
 	rowGroup RowGroup
 	columns  []ColumnChunk
@@ -401,3 +735,10 @@ func (c *convertedRows) Schema() *Schema {
 func (c *convertedRows) SeekToRow(rowIndex int64) error {
 	return c.rows.SeekToRow(rowIndex)
 }
+
+func (c *convertedRows) Close() error {
+	if closer, ok := c.rows.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package parquet_test
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -97,6 +100,59 @@ var conversionTests = [...]struct {
 			Names []string
 		}{ID: 1, Names: []string{}},
 	},
+
+	{
+		scenario: "promote int32 to int64 and float to double",
+		from: struct {
+			Count int32
+			Ratio float32
+		}{Count: -42, Ratio: 0.25},
+		to: struct {
+			Count int64
+			Ratio float64
+		}{Count: -42, Ratio: 0.25},
+	},
+
+	{
+		scenario: "relax required column to optional",
+		from:     struct{ ID, Name string }{ID: "1", Name: "Luke"},
+		to: struct {
+			ID   string
+			Name *string
+		}{ID: "1", Name: newString("Luke")},
+	},
+
+	{
+		scenario: "relax required group to optional",
+		from: struct {
+			Details struct {
+				Name string
+				Age  *int32
+			}
+		}{Details: struct {
+			Name string
+			Age  *int32
+		}{Name: "Leia"}},
+		to: struct {
+			Details *struct {
+				Name *string
+				Age  *int64
+			}
+		}{Details: &struct {
+			Name *string
+			Age  *int64
+		}{Name: newString("Leia")}},
+	},
+
+	{
+		scenario: "relax required column nested in repeated group",
+		from: struct {
+			Contacts []struct{ Name string }
+		}{Contacts: []struct{ Name string }{{Name: "Han"}, {Name: "Chewie"}}},
+		to: struct {
+			Contacts []struct{ Name *string }
+		}{Contacts: []struct{ Name *string }{{Name: newString("Han")}, {Name: newString("Chewie")}}},
+	},
 }
 
 func TestConvert(t *testing.T) {
@@ -129,4 +185,279 @@ func TestConvert(t *testing.T) {
 	}
 }
 
+func TestConvertError(t *testing.T) {
+	tests := []struct {
+		scenario string
+		from     parquet.Node
+		to       parquet.Node
+	}{
+		{
+			scenario: "narrowing int64 to int32",
+			from:     parquet.Group{"a": parquet.Int(64)},
+			to:       parquet.Group{"a": parquet.Int(32)},
+		},
+		{
+			scenario: "narrowing double to float",
+			from:     parquet.Group{"a": parquet.Leaf(parquet.DoubleType)},
+			to:       parquet.Group{"a": parquet.Leaf(parquet.FloatType)},
+		},
+		{
+			scenario: "converting int32 to string",
+			from:     parquet.Group{"a": parquet.Int(32)},
+			to:       parquet.Group{"a": parquet.String()},
+		},
+		{
+			scenario: "changing optional column to required",
+			from:     parquet.Group{"a": parquet.Optional(parquet.String())},
+			to:       parquet.Group{"a": parquet.String()},
+		},
+		{
+			scenario: "changing repeated column to optional",
+			from:     parquet.Group{"a": parquet.Repeated(parquet.String())},
+			to:       parquet.Group{"a": parquet.Optional(parquet.String())},
+		},
+		{
+			scenario: "changing optional group to required",
+			from:     parquet.Group{"a": parquet.Optional(parquet.Group{"b": parquet.String()})},
+			to:       parquet.Group{"a": parquet.Group{"b": parquet.String()}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			_, err := parquet.Convert(test.to, test.from)
+			var convertError *parquet.ConvertError
+			if !errors.As(err, &convertError) {
+				t.Fatalf("expected a *parquet.ConvertError but got %v", err)
+			}
+		})
+	}
+}
+
+func TestConvertRenameColumns(t *testing.T) {
+	type Name struct {
+		First string
+		Last  string
+	}
+	type OldRow struct {
+		ID       int64
+		Name     Name
+		Nickname string
+	}
+	type NewRow struct {
+		ID        int64
+		FullName  struct{ Given, Last string }
+		Alias     string
+		Nickname  string
+		Greetings string
+	}
+
+	from := parquet.SchemaOf(OldRow{})
+	to := parquet.SchemaOf(NewRow{})
+
+	conv, err := parquet.Convert(to, from,
+		parquet.RenameColumns(map[string]string{
+			"Name":       "FullName",
+			"Name.First": "FullName.Given",
+		}),
+		parquet.RenameColumns(map[string]string{
+			"Nickname": "Alias",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row := from.Deconstruct(nil, &OldRow{
+		ID:       1,
+		Name:     Name{First: "Luke", Last: "Skywalker"},
+		Nickname: "Red Five",
+	})
+	if row, err = conv.Convert(nil, row); err != nil {
+		t.Fatal(err)
+	}
+
+	got := NewRow{}
+	if err := to.Reconstruct(&got, row); err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewRow{ID: 1, Alias: "Red Five"}
+	want.FullName.Given = "Luke"
+	want.FullName.Last = "Skywalker"
+	// The Nickname column was renamed so it must not be matched to the
+	// column of the same name in the target schema.
+	if got != want {
+		t.Errorf("converted value mismatch:\nwant = %+v\ngot  = %+v", want, got)
+	}
+}
+
+func TestConvertInvalidRenameColumns(t *testing.T) {
+	schema := parquet.SchemaOf(struct{ A, B string }{})
+	_, err := parquet.Convert(schema, schema, parquet.RenameColumns(map[string]string{
+		"A": "C",
+		"B": "C",
+	}))
+	if err == nil {
+		t.Fatal("expected an error when renaming two columns to the same name")
+	}
+}
+
+func TestConvertFieldIDs(t *testing.T) {
+	from := parquet.Group{
+		"id":    parquet.FieldID(parquet.Int(32), 1),
+		"name":  parquet.FieldID(parquet.String(), 2),
+		"email": parquet.FieldID(parquet.String(), 3),
+	}
+	to := parquet.Group{
+		"key":       parquet.FieldID(parquet.Int(64), 1),
+		"full_name": parquet.FieldID(parquet.Optional(parquet.String()), 2),
+		// The email column was dropped and recreated, it has the same name
+		// but a different id so it must not be matched with the old one.
+		"email": parquet.FieldID(parquet.String(), 4),
+	}
+
+	conv, err := parquet.Convert(to, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fields of groups are sorted by name: email, id, name => email, full_name, key
+	row := parquet.Row{
+		parquet.ValueOf("luke@example.com").Level(0, 0, 0),
+		parquet.ValueOf(int32(42)).Level(0, 0, 1),
+		parquet.ValueOf("Luke").Level(0, 0, 2),
+	}
+	if row, err = conv.Convert(nil, row); err != nil {
+		t.Fatal(err)
+	}
+
+	want := parquet.Row{
+		parquet.ValueOf("").Level(0, 0, 0),
+		parquet.ValueOf("Luke").Level(0, 1, 1),
+		parquet.ValueOf(int64(42)).Level(0, 0, 2),
+	}
+	if !row.Equal(want) {
+		t.Errorf("converted row mismatch:\nwant = %+v\ngot  = %+v", want, row)
+	}
+}
+
+func TestConvertRowGroup(t *testing.T) {
+	type OldRow struct {
+		ID    int32
+		Name  string
+		Score float32
+	}
+	type NewRow struct {
+		ID    int64
+		Name  *string
+		Score float64
+	}
+
+	from := parquet.SchemaOf(OldRow{})
+	to := parquet.SchemaOf(NewRow{})
+
+	buffer := parquet.NewBuffer(from)
+	for i := 0; i < 10; i++ {
+		if err := buffer.Write(&OldRow{ID: int32(i), Name: fmt.Sprint("name-", i), Score: float32(i) / 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conv, err := parquet.Convert(to, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowGroup := parquet.ConvertRowGroup(buffer, conv)
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output, to)
+	if _, err := writer.WriteRowGroup(rowGroup); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(output.Bytes()))
+	for i := 0; i < 10; i++ {
+		row := NewRow{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		want := NewRow{ID: int64(i), Name: newString(fmt.Sprint("name-", i)), Score: float64(i) / 2}
+		if !reflect.DeepEqual(row, want) {
+			t.Errorf("row %d mismatch:\nwant = %+v\ngot  = %+v", i, want, row)
+		}
+	}
+
+	for i, column := range rowGroup.ColumnChunks() {
+		leaf, _ := to.Lookup(to.Fields()[i].Name())
+		if kind := column.Type().Kind(); kind != leaf.Node.Type().Kind() {
+			t.Errorf("column %d has the wrong kind: want %s but got %s", i, leaf.Node.Type().Kind(), kind)
+		}
+
+		page, err := column.Pages().ReadPage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := make([]parquet.Value, page.NumValues())
+		n, _ := page.Buffer().Values().ReadValues(values)
+		if n != len(values) {
+			t.Fatalf("column %d: wrong number of values: want %d but got %d", i, len(values), n)
+		}
+		for _, v := range values {
+			if v.Kind() != leaf.Node.Type().Kind() || v.Column() != i || v.DefinitionLevel() != leaf.MaxDefinitionLevel {
+				t.Errorf("column %d: wrong value: %+v", i, v)
+				break
+			}
+		}
+	}
+}
+
 func newString(s string) *string { return &s }
+
+func TestConvertRowGroupWithoutColumnIndex(t *testing.T) {
+	type OldRow struct {
+		ID int32 `parquet:"id"`
+	}
+	type NewRow struct {
+		ID int64 `parquet:"id"`
+	}
+
+	rows := make([]OldRow, 10)
+	for i := range rows {
+		rows[i].ID = int32(i)
+	}
+
+	output := new(bytes.Buffer)
+	if err := writeParquetFile(output, makeRows(rows)); err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()), parquet.SkipPageIndex(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	to := parquet.SchemaOf(NewRow{})
+	conv, err := parquet.Convert(to, f.Schema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowGroup := parquet.ConvertRowGroup(f.RowGroups()[0], conv)
+
+	if index := rowGroup.ColumnChunks()[0].ColumnIndex(); index != nil {
+		t.Errorf("converted column chunk has a column index: %v", index)
+	}
+
+	reader := parquet.NewRowGroupReader(rowGroup, parquet.FilterRows(
+		parquet.Eq([]string{"id"}, parquet.ValueOf(int64(3))),
+	))
+	found, err := readAllRows(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0][0].Int64() != 3 {
+		t.Errorf("wrong rows: %v", found)
+	}
+}
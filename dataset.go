@@ -0,0 +1,740 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// hiveDefaultPartition is the directory name used by Hive for partitions where
+// the value of the partition column is null.
+const hiveDefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+
+// Dataset represents a collection of parquet files stored in a directory tree,
+// laid out with Hive-style partitioning where the directories are named after
+// the values of partition columns; for example:
+//
+//	events/date=2022-05-01/region=eu/part-0.parquet
+//	events/date=2022-05-01/region=us/part-0.parquet
+//	events/date=2022-05-02/region=eu/part-0.parquet
+//
+// The schema of a dataset is the union of the schemas of its files, with the
+// partition columns appended as optional string columns. The row groups of
+// the files are converted to the schema of the dataset, and the values of the
+// partition columns are those of the directories that contain the files.
+// Directories named __HIVE_DEFAULT_PARTITION__ represent null values.
+//
+// Files and directories with names starting with "." or "_" are ignored, and so
+// are files with names not matching the DatasetFilePattern option, which only
+// selects files with the ".parquet" extension by default.
+type Dataset struct {
+	schema     *Schema
+	partitions []string
+	files      []*DatasetFile
+	rowGroups  []RowGroup
+	index      int
+}
+
+// DatasetFile represents a file of a dataset.
+type DatasetFile struct {
+	path      string
+	partition Partition
+	file      fs.File
+	parquet   *File
+}
+
+// Path returns the path of the file in the dataset file system.
+func (f *DatasetFile) Path() string { return f.path }
+
+// Partition returns the values of the partition columns of f.
+func (f *DatasetFile) Partition() Partition { return f.partition }
+
+// File returns the parquet file.
+func (f *DatasetFile) File() *File { return f.parquet }
+
+// PartitionValue is the value of a partition column.
+type PartitionValue struct {
+	Column string
+	Value  Value
+}
+
+// Partition is a list of partition column values, in the order they appear in
+// the paths of files.
+type Partition []PartitionValue
+
+// Lookup returns the value of the partition column with the given name, and a
+// boolean indicating whether the column was found.
+func (p Partition) Lookup(column string) (Value, bool) {
+	for _, v := range p {
+		if v.Column == column {
+			return v.Value, true
+		}
+	}
+	return Value{}, false
+}
+
+// Path returns the relative path of the directory representing p, using the
+// same escaping rules as Hive.
+func (p Partition) Path() string {
+	elems := make([]string, len(p))
+	for i, v := range p {
+		value := hiveDefaultPartition
+		if !v.Value.IsNull() {
+			value = escapePartitionValue(v.Value.String())
+		}
+		elems[i] = escapePartitionValue(v.Column) + "=" + value
+	}
+	return path.Join(elems...)
+}
+
+// OpenDataset opens the dataset of parquet files found in the directory tree
+// starting at root in fsys.
+//
+// The files of fsys must implement io.ReaderAt, which is the case of the files
+// opened by os.DirFS for example. They remain open until the Close method of
+// the dataset is called.
+//
+// All the files of the dataset must have the same partition columns, appearing
+// in the same order in their paths. Files where the partition values do not
+// match the filter configured with the FilterPartitions option are not opened.
+func OpenDataset(fsys fs.FS, root string, options ...DatasetOption) (*Dataset, error) {
+	config, err := NewDatasetConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	d := new(Dataset)
+	var partitionSchema *Schema
+	var partitionFilter boundFilter
+	var partitionRow Row
+
+	err = fs.WalkDir(fsys, root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if filePath != root && isHiddenDatasetFile(entry.Name()) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		if match, _ := path.Match(config.FilePattern, entry.Name()); !match {
+			return nil
+		}
+
+		partition, err := parsePartition(strings.TrimPrefix(path.Dir(filePath), root))
+		if err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+
+		if partitionSchema == nil {
+			for _, v := range partition {
+				d.partitions = append(d.partitions, v.Column)
+			}
+			partitionSchema = NewSchema("", partitionGroupOf(d.partitions))
+			if config.Filter != nil {
+				if partitionFilter, err = config.Filter.bind(partitionSchema); err != nil {
+					return fmt.Errorf("filtering partitions: %w", err)
+				}
+			}
+		}
+
+		if !partitionColumnsAreEqual(partition, d.partitions) {
+			return fmt.Errorf("%s: partition columns mismatch: want %q but got %q", filePath, d.partitions, partition)
+		}
+
+		if partitionFilter != nil {
+			partitionRow = partitionRow[:0]
+			for i, v := range partition {
+				partitionRow = append(partitionRow, partitionValueLevel(v.Value, i))
+			}
+			if !partitionFilter.matchRow(partitionRow) {
+				return nil
+			}
+		}
+
+		d.files = append(d.files, &DatasetFile{path: filePath, partition: partition})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range d.files {
+		if err := f.open(fsys, config.FileOptions); err != nil {
+			d.Close()
+			return nil, err
+		}
+	}
+
+	if err := d.init(config.Schema); err != nil {
+		d.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+func (f *DatasetFile) open(fsys fs.FS, options []FileOption) error {
+	file, err := fsys.Open(f.path)
+	if err != nil {
+		return err
+	}
+	f.file = file
+
+	r, ok := file.(io.ReaderAt)
+	if !ok {
+		return fmt.Errorf("%s: dataset files must implement io.ReaderAt", f.path)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if f.parquet, err = OpenFile(r, stat.Size(), options...); err != nil {
+		return fmt.Errorf("%s: %w", f.path, err)
+	}
+	return nil
+}
+
+func (d *Dataset) init(schema *Schema) error {
+	var fields []Field
+	if schema != nil {
+		fields = schema.Fields()
+	} else {
+		fileSchemas := make([]*Schema, len(d.files))
+		for i, f := range d.files {
+			fileSchemas[i] = f.parquet.Schema()
+		}
+		fields = mergeDatasetFields(fileSchemas)
+	}
+
+	for _, field := range fields {
+		for _, partition := range d.partitions {
+			if field.Name() == partition {
+				return fmt.Errorf("partition column %q conflicts with a column of the dataset schema", partition)
+			}
+		}
+	}
+
+	partitionFields := partitionGroupOf(d.partitions).fields
+	d.schema = NewSchema("", &projectedGroup{
+		Node:   Group{},
+		fields: append(fields[:len(fields):len(fields)], partitionFields...),
+	})
+
+	// Partition columns are the last leaf columns of the schema.
+	firstPartition := int(numLeafColumnsOf(d.schema)) - len(d.partitions)
+
+	for _, f := range d.files {
+		conv, err := Convert(d.schema, f.parquet.Schema())
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+		for _, rowGroup := range f.parquet.RowGroups() {
+			d.rowGroups = append(d.rowGroups, newPartitionedRowGroup(ConvertRowGroup(rowGroup, conv), firstPartition, f.partition))
+		}
+	}
+	return nil
+}
+
+// Schema returns the schema of rows in the dataset.
+func (d *Dataset) Schema() *Schema { return d.schema }
+
+// Partitions returns the names of the partition columns of the dataset.
+func (d *Dataset) Partitions() []string { return d.partitions }
+
+// Files returns the list of files of the dataset, sorted by path.
+func (d *Dataset) Files() []*DatasetFile { return d.files }
+
+// RowGroups returns the row groups of all the files of the dataset, converted
+// to the dataset schema.
+func (d *Dataset) RowGroups() []RowGroup { return d.rowGroups }
+
+// ReadRowGroup returns the next row group of the dataset, or io.EOF after the
+// last row group was returned.
+func (d *Dataset) ReadRowGroup() (RowGroup, error) {
+	if d.index == len(d.rowGroups) {
+		return nil, io.EOF
+	}
+	rowGroup := d.rowGroups[d.index]
+	d.index++
+	return rowGroup, nil
+}
+
+// Close closes the files of the dataset.
+func (d *Dataset) Close() error {
+	var lastErr error
+	for _, f := range d.files {
+		if f.file != nil {
+			if err := f.file.Close(); err != nil {
+				lastErr = err
+			}
+		}
+	}
+	return lastErr
+}
+
+func isHiddenDatasetFile(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// parsePartition parses the partition column values from the "key=value"
+// elements of the given directory path. Elements of other forms are ignored.
+func parsePartition(dir string) (Partition, error) {
+	var partition Partition
+
+	for _, elem := range strings.Split(dir, "/") {
+		i := strings.IndexByte(elem, '=')
+		if i < 0 {
+			continue
+		}
+		column, err := url.PathUnescape(elem[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid partition column name: %w", err)
+		}
+		v := PartitionValue{Column: column}
+		if value := elem[i+1:]; value != hiveDefaultPartition {
+			unescaped, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of partition column %q: %w", column, err)
+			}
+			v.Value = ValueOf(unescaped)
+		}
+		partition = append(partition, v)
+	}
+
+	return partition, nil
+}
+
+// escapePartitionValue escapes the characters of s which Hive does not allow
+// in the names of partition directories.
+func escapePartitionValue(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c == 0x7F || strings.IndexByte("\"#%'*/:=?\\{[]^", c) >= 0 {
+			if b.Len() == 0 {
+				b.WriteString(s[:i])
+			}
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xF])
+		} else if b.Len() != 0 {
+			b.WriteByte(c)
+		}
+	}
+
+	if b.Len() == 0 {
+		return s
+	}
+	return b.String()
+}
+
+func partitionColumnsAreEqual(partition Partition, columns []string) bool {
+	if len(partition) != len(columns) {
+		return false
+	}
+	for i, v := range partition {
+		if v.Column != columns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func partitionGroupOf(columns []string) *projectedGroup {
+	fields := make([]Field, len(columns))
+	for i, column := range columns {
+		fields[i] = &projectedField{Node: Optional(String()), name: column}
+	}
+	return &projectedGroup{Node: Group{}, fields: fields}
+}
+
+func partitionValueLevel(value Value, columnIndex int) Value {
+	if value.IsNull() {
+		return value.Level(0, 0, columnIndex)
+	}
+	return value.Level(0, 1, columnIndex)
+}
+
+// mergeDatasetFields returns the union of the fields of the given schemas, in
+// the order they first appear. Fields which are not present in all the schemas
+// are made optional since the rows of some files have no values for them.
+func mergeDatasetFields(schemas []*Schema) []Field {
+	var fields []Field
+	index := make(map[string]int)
+	count := make([]int, 0)
+
+	for _, schema := range schemas {
+		for _, field := range schema.Fields() {
+			i, ok := index[field.Name()]
+			if !ok {
+				i = len(fields)
+				index[field.Name()] = i
+				fields = append(fields, field)
+				count = append(count, 0)
+			}
+			if field.Optional() && fields[i].Required() {
+				fields[i] = field
+			}
+			count[i]++
+		}
+	}
+
+	for i, field := range fields {
+		if count[i] < len(schemas) && field.Required() {
+			fields[i] = &projectedField{Node: Optional(field), name: field.Name()}
+		}
+	}
+
+	return fields
+}
+
+// partitionedRowGroup wraps a row group converted to the schema of a dataset,
+// exposing the values of partition columns.
+type partitionedRowGroup struct {
+	base    RowGroup
+	columns []ColumnChunk
+	// Partition values indexed by column index, only columns following the
+	// columns of files are partition columns.
+	partition      []Value
+	firstPartition int
+}
+
+func newPartitionedRowGroup(base RowGroup, firstPartition int, partition Partition) *partitionedRowGroup {
+	baseColumns := base.ColumnChunks()
+	numRows := base.NumRows()
+
+	g := &partitionedRowGroup{
+		base:           base,
+		columns:        make([]ColumnChunk, len(baseColumns)),
+		partition:      make([]Value, len(partition)),
+		firstPartition: firstPartition,
+	}
+	copy(g.columns, baseColumns)
+
+	for i, v := range partition {
+		columnIndex := firstPartition + i
+		g.partition[i] = partitionValueLevel(v.Value, columnIndex)
+		g.columns[columnIndex] = &partitionColumnChunk{
+			value:   g.partition[i],
+			column:  int16(columnIndex),
+			numRows: numRows,
+		}
+	}
+
+	return g
+}
+
+func (g *partitionedRowGroup) NumRows() int64                  { return g.base.NumRows() }
+func (g *partitionedRowGroup) ColumnChunks() []ColumnChunk     { return g.columns }
+func (g *partitionedRowGroup) Schema() *Schema                 { return g.base.Schema() }
+func (g *partitionedRowGroup) SortingColumns() []SortingColumn { return g.base.SortingColumns() }
+func (g *partitionedRowGroup) Rows() Rows {
+	return &partitionedRows{Rows: g.base.Rows(), rowGroup: g}
+}
+
+type partitionedRows struct {
+	Rows
+	rowGroup *partitionedRowGroup
+}
+
+func (r *partitionedRows) ReadRow(row Row) (Row, error) {
+	n := len(row)
+	row, err := r.Rows.ReadRow(row)
+	// The conversion to the dataset schema produced placeholder values for
+	// the partition columns, which are missing from the files.
+	for i := n; i < len(row); i++ {
+		if j := row[i].Column() - r.rowGroup.firstPartition; j >= 0 {
+			row[i] = r.rowGroup.partition[j]
+		}
+	}
+	return row, err
+}
+
+func (r *partitionedRows) Close() error {
+	if c, ok := r.Rows.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// partitionColumnChunk is the column chunk of a partition column, where all
+// values are equal to the value of the partition.
+type partitionColumnChunk struct {
+	value   Value
+	column  int16
+	numRows int64
+}
+
+func (c *partitionColumnChunk) Type() Type               { return String().Type() }
+func (c *partitionColumnChunk) Column() int              { return int(c.column) }
+func (c *partitionColumnChunk) ColumnIndex() ColumnIndex { return partitionColumnIndex{c} }
+func (c *partitionColumnChunk) OffsetIndex() OffsetIndex { return partitionOffsetIndex{c} }
+func (c *partitionColumnChunk) BloomFilter() BloomFilter { return nil }
+func (c *partitionColumnChunk) NumValues() int64         { return c.numRows }
+func (c *partitionColumnChunk) Pages() Pages             { return &partitionPages{chunk: c} }
+
+// partitionPageSize is the approximate size of the pages generated when
+// reading partition column chunks, which bounds the memory footprint of the
+// pages regardless of the number of rows in the column chunks.
+const partitionPageSize = DefaultPageBufferSize
+
+func (c *partitionColumnChunk) rowsPerPage() int64 {
+	rowSize := 1 // definition level
+	if !c.value.IsNull() {
+		rowSize += 4 + len(c.value.ByteArray()) // offset and value
+	}
+	if rowSize > partitionPageSize {
+		return 1
+	}
+	return int64(partitionPageSize / rowSize)
+}
+
+func (c *partitionColumnChunk) numPages() int {
+	rowsPerPage := c.rowsPerPage()
+	return int((c.numRows + rowsPerPage - 1) / rowsPerPage)
+}
+
+func (c *partitionColumnChunk) pageRows(i int) int64 {
+	firstRow := int64(i) * c.rowsPerPage()
+	if n := c.numRows - firstRow; n < c.rowsPerPage() {
+		return n
+	}
+	return c.rowsPerPage()
+}
+
+type partitionColumnIndex struct{ *partitionColumnChunk }
+
+func (i partitionColumnIndex) NumPages() int      { return i.numPages() }
+func (i partitionColumnIndex) NullPage(int) bool  { return i.value.IsNull() }
+func (i partitionColumnIndex) MinValue(int) Value { return i.value }
+func (i partitionColumnIndex) MaxValue(int) Value { return i.value }
+func (i partitionColumnIndex) IsAscending() bool  { return true }
+func (i partitionColumnIndex) IsDescending() bool { return false }
+func (i partitionColumnIndex) NullCount(page int) int64 {
+	if i.value.IsNull() {
+		return i.pageRows(page)
+	}
+	return 0
+}
+
+type partitionOffsetIndex struct{ *partitionColumnChunk }
+
+func (i partitionOffsetIndex) NumPages() int                { return i.numPages() }
+func (i partitionOffsetIndex) Offset(int) int64             { return 0 }
+func (i partitionOffsetIndex) CompressedPageSize(int) int64 { return 0 }
+func (i partitionOffsetIndex) FirstRowIndex(page int) int64 {
+	return int64(page) * i.rowsPerPage()
+}
+
+// partitionPages generates the pages of partition column chunks as they are
+// read, the pages are backed by a buffer which is reused across calls to
+// ReadPage.
+type partitionPages struct {
+	chunk  *partitionColumnChunk
+	buffer ColumnBuffer
+	values []Value
+	seek   int64
+}
+
+func (r *partitionPages) ReadPage() (Page, error) {
+	c := r.chunk
+	if r.seek >= c.numRows {
+		return nil, io.EOF
+	}
+
+	rowsPerPage := c.rowsPerPage()
+	if r.buffer == nil {
+		numRows := rowsPerPage
+		if numRows > c.numRows {
+			numRows = c.numRows
+		}
+		r.buffer = newOptionalColumnBuffer(c.Type().NewColumnBuffer(c.Column(), int(numRows)), 1, nullsGoLast)
+		r.values = make([]Value, numRows)
+		for i := range r.values {
+			r.values[i] = c.value
+		}
+	}
+
+	// Pages start at the row index where the reader was positioned, and end
+	// at the boundary of pages described by the offset index.
+	numRows := rowsPerPage - r.seek%rowsPerPage
+	if n := c.numRows - r.seek; numRows > n {
+		numRows = n
+	}
+
+	r.buffer.Reset()
+	if _, err := r.buffer.WriteValues(r.values[:numRows]); err != nil {
+		return nil, fmt.Errorf("writing values of partition column: %w", err)
+	}
+	r.seek += numRows
+	return r.buffer.Page(), nil
+}
+
+func (r *partitionPages) SeekToRow(rowIndex int64) error {
+	r.seek = rowIndex
+	return nil
+}
+
+// PartitionedWriter writes rows to parquet files laid out with Hive-style
+// partitioning, routing each row to a Writer selected by the values of the
+// partition columns.
+//
+// The partition columns are not written to the files since their values are
+// encoded in the directory names, which means that files written by a
+// PartitionedWriter can be read back with OpenDataset. The values of partition
+// columns are formatted with Value.String, and read back as strings.
+type PartitionedWriter struct {
+	schema     *Schema
+	fileSchema *Schema
+	conv       Conversion
+	columns    []int
+	create     func(dir string) (io.WriteCloser, error)
+	options    []WriterOption
+	writers    map[string]*partitionWriter
+	partition  Partition
+	rowbuf     Row
+	fileRow    Row
+}
+
+type partitionWriter struct {
+	output io.WriteCloser
+	writer *Writer
+}
+
+// NewPartitionedWriter constructs a writer of rows of the given schema, which
+// are partitioned by the values of the columns passed as arguments.
+//
+// The create function is called to create a file the first time a row is
+// written to a partition, it receives the relative path of the partition
+// directory (e.g. "date=2022-05-01/region=eu") and is responsible for choosing
+// the file name. The options are passed to the writers of each partition.
+// Unless configured with the DatasetFilePattern option, OpenDataset only reads
+// files named with the ".parquet" extension.
+//
+// Partition columns must be top-level columns of the schema that are not
+// repeated.
+func NewPartitionedWriter(schema *Schema, columns []string, create func(dir string) (io.WriteCloser, error), options ...WriterOption) (*PartitionedWriter, error) {
+	w := &PartitionedWriter{
+		schema:  schema,
+		columns: make([]int, len(columns)),
+		create:  create,
+		writers: make(map[string]*partitionWriter),
+	}
+
+	fields := make([]Field, 0, len(schema.Fields()))
+	for _, field := range schema.Fields() {
+		isPartition := false
+		for _, column := range columns {
+			isPartition = isPartition || field.Name() == column
+		}
+		if !isPartition {
+			fields = append(fields, field)
+		}
+	}
+
+	for i, column := range columns {
+		leaf, ok := schema.Lookup(column)
+		if !ok || len(leaf.Path) != 1 {
+			return nil, fmt.Errorf("partition column %q is not a top-level leaf column of the schema", column)
+		}
+		if leaf.Node.Repeated() {
+			return nil, fmt.Errorf("partition column %q is repeated", column)
+		}
+		w.columns[i] = leaf.ColumnIndex
+		w.partition = append(w.partition, PartitionValue{Column: column})
+	}
+
+	w.fileSchema = NewSchema(schema.Name(), &projectedGroup{Node: Group{}, fields: fields})
+	conv, err := Convert(w.fileSchema, schema)
+	if err != nil {
+		return nil, err
+	}
+	w.conv = conv
+	w.options = append([]WriterOption{w.fileSchema}, options...)
+	return w, nil
+}
+
+// Schema returns the schema of rows written to w.
+func (w *PartitionedWriter) Schema() *Schema { return w.schema }
+
+// Write writes a row held in a Go value to the partition it belongs to.
+func (w *PartitionedWriter) Write(row interface{}) error {
+	defer clearValues(w.rowbuf)
+	var err error
+	if w.rowbuf, err = w.schema.deconstructValue(w.rowbuf[:0], row); err != nil {
+		return err
+	}
+	return w.WriteRow(w.rowbuf)
+}
+
+// WriteRow writes a parquet row to the partition it belongs to.
+func (w *PartitionedWriter) WriteRow(row Row) error {
+	for i := range w.partition {
+		w.partition[i].Value = Value{}
+	}
+	for _, v := range row {
+		for i, columnIndex := range w.columns {
+			if v.Column() == columnIndex {
+				w.partition[i].Value = v
+			}
+		}
+	}
+
+	dir := w.partition.Path()
+	pw := w.writers[dir]
+	if pw == nil {
+		output, err := w.create(dir)
+		if err != nil {
+			return err
+		}
+		pw = &partitionWriter{output: output, writer: NewWriter(output, w.options...)}
+		w.writers[dir] = pw
+	}
+
+	var err error
+	if w.fileRow, err = w.conv.Convert(w.fileRow[:0], row); err != nil {
+		return err
+	}
+	return pw.writer.WriteRow(w.fileRow)
+}
+
+// Close flushes and closes the writers of all partitions, then closes their
+// outputs.
+func (w *PartitionedWriter) Close() error {
+	dirs := make([]string, 0, len(w.writers))
+	for dir := range w.writers {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var lastErr error
+	for _, dir := range dirs {
+		pw := w.writers[dir]
+		if err := pw.writer.Close(); err != nil {
+			lastErr = err
+		}
+		if err := pw.output.Close(); err != nil {
+			lastErr = err
+		}
+		delete(w.writers, dir)
+	}
+	return lastErr
+}
+
+var (
+	_ RowGroupReader      = (*Dataset)(nil)
+	_ RowWriterWithSchema = (*PartitionedWriter)(nil)
+	_ io.Closer           = (*PartitionedWriter)(nil)
+	_ ColumnChunk         = (*partitionColumnChunk)(nil)
+	_ Rows                = (*partitionedRows)(nil)
+)
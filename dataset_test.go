@@ -0,0 +1,326 @@
+package parquet_test
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type datasetEvent struct {
+	Date   string  `parquet:"date"`
+	Region *string `parquet:"region,optional"`
+	ID     int64   `parquet:"id"`
+	Name   string  `parquet:"name"`
+}
+
+// datasetOutput is an io.WriteCloser adding the content written to it to a
+// file system when it is closed.
+type datasetOutput struct {
+	bytes.Buffer
+	fsys fstest.MapFS
+	path string
+}
+
+func (out *datasetOutput) Close() error {
+	out.fsys[out.path] = &fstest.MapFile{Data: out.Bytes()}
+	return nil
+}
+
+func writeDataset(t *testing.T, fsys fstest.MapFS, root string, events []datasetEvent) {
+	t.Helper()
+	w, err := parquet.NewPartitionedWriter(
+		parquet.SchemaOf(datasetEvent{}),
+		[]string{"date", "region"},
+		func(dir string) (io.WriteCloser, error) {
+			return &datasetOutput{fsys: fsys, path: path.Join(root, dir, "part-0.parquet")}, nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range events {
+		if err := w.Write(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readDataset(t *testing.T, d *parquet.Dataset, options ...parquet.ReaderOption) []map[string]interface{} {
+	t.Helper()
+	var rows []map[string]interface{}
+	for {
+		rowGroup, err := d.ReadRowGroup()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err := readAllRows(parquet.NewRowGroupReader(rowGroup, options...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range found {
+			var value map[string]interface{}
+			if err := d.Schema().Reconstruct(&value, row); err != nil {
+				t.Fatal(err)
+			}
+			rows = append(rows, value)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i]["id"].(int64) < rows[j]["id"].(int64) })
+	return rows
+}
+
+func TestDataset(t *testing.T) {
+	eu, us, slash := "eu", "us", "a/b"
+	events := []datasetEvent{
+		{Date: "2022-05-01", Region: &eu, ID: 0, Name: "A"},
+		{Date: "2022-05-01", Region: &us, ID: 1, Name: "B"},
+		{Date: "2022-05-02", Region: &eu, ID: 2, Name: "C"},
+		{Date: "2022-05-01", Region: &eu, ID: 3, Name: "D"},
+		{Date: "2022-05-02", Region: nil, ID: 4, Name: "E"},
+		{Date: "2022-05-03", Region: &slash, ID: 5, Name: "F"},
+	}
+
+	fsys := fstest.MapFS{
+		"events/_SUCCESS":      &fstest.MapFile{},
+		"events/.hidden/x.txt": &fstest.MapFile{Data: []byte("not parquet")},
+		"events/README.md":     &fstest.MapFile{Data: []byte("not parquet")},
+		"events/date=2022-05-01/region=eu/part-0.parquet.crc": &fstest.MapFile{Data: []byte("not parquet")},
+	}
+	writeDataset(t, fsys, "events", events)
+
+	if _, ok := fsys["events/date=2022-05-02/region=__HIVE_DEFAULT_PARTITION__/part-0.parquet"]; !ok {
+		t.Error("null partition value was not written to the default partition")
+	}
+	if _, ok := fsys["events/date=2022-05-03/region=a%2Fb/part-0.parquet"]; !ok {
+		t.Error("partition value was not escaped")
+	}
+
+	eventOf := func(e datasetEvent) map[string]interface{} {
+		m := map[string]interface{}{"id": e.ID, "name": e.Name, "date": e.Date, "region": nil}
+		if e.Region != nil {
+			m["region"] = *e.Region
+		}
+		return m
+	}
+
+	t.Run("read all", func(t *testing.T) {
+		d, err := parquet.OpenDataset(fsys, "events")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer d.Close()
+
+		if partitions := d.Partitions(); !reflect.DeepEqual(partitions, []string{"date", "region"}) {
+			t.Errorf("wrong partition columns: %q", partitions)
+		}
+		if n := len(d.Files()); n != 5 {
+			t.Errorf("wrong number of files: %d", n)
+		}
+
+		rows := readDataset(t, d)
+		if len(rows) != len(events) {
+			t.Fatalf("wrong number of rows: want=%d got=%d", len(events), len(rows))
+		}
+		for i, event := range events {
+			if want := eventOf(event); !reflect.DeepEqual(want, rows[i]) {
+				t.Errorf("row %d mismatch:\nwant: %v\ngot:  %v", i, want, rows[i])
+			}
+		}
+	})
+
+	t.Run("filter partitions", func(t *testing.T) {
+		d, err := parquet.OpenDataset(fsys, "events",
+			parquet.FilterPartitions(parquet.Eq([]string{"region"}, parquet.ValueOf("eu"))),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer d.Close()
+
+		if n := len(d.Files()); n != 2 {
+			t.Errorf("wrong number of files: %d", n)
+		}
+		rows := readDataset(t, d)
+		if len(rows) != 3 {
+			t.Fatalf("wrong number of rows: %d", len(rows))
+		}
+		for i, id := range []int64{0, 2, 3} {
+			if want := eventOf(events[id]); !reflect.DeepEqual(want, rows[i]) {
+				t.Errorf("row %d mismatch:\nwant: %v\ngot:  %v", i, want, rows[i])
+			}
+		}
+	})
+
+	t.Run("filter rows", func(t *testing.T) {
+		d, err := parquet.OpenDataset(fsys, "events")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer d.Close()
+
+		rows := readDataset(t, d, parquet.FilterRows(parquet.And(
+			parquet.Eq([]string{"date"}, parquet.ValueOf("2022-05-01")),
+			parquet.Not(parquet.IsNull([]string{"region"})),
+		)))
+		if len(rows) != 3 {
+			t.Fatalf("wrong number of rows: %d", len(rows))
+		}
+		for i, id := range []int64{0, 1, 3} {
+			if want := eventOf(events[id]); !reflect.DeepEqual(want, rows[i]) {
+				t.Errorf("row %d mismatch:\nwant: %v\ngot:  %v", i, want, rows[i])
+			}
+		}
+	})
+
+	t.Run("file pattern", func(t *testing.T) {
+		if _, err := parquet.OpenDataset(fsys, "events", parquet.DatasetFilePattern("*")); err == nil {
+			t.Error("expected an error when opening files which are not parquet files")
+		}
+		if _, err := parquet.OpenDataset(fsys, "events", parquet.DatasetFilePattern("[")); err == nil {
+			t.Error("expected an error when the file pattern is malformed")
+		}
+	})
+
+	t.Run("filter on non-partition column", func(t *testing.T) {
+		if _, err := parquet.OpenDataset(fsys, "events",
+			parquet.FilterPartitions(parquet.Eq([]string{"name"}, parquet.ValueOf("A"))),
+		); err == nil {
+			t.Error("expected an error when filtering partitions on a regular column")
+		}
+	})
+}
+
+func TestDatasetSchemaEvolution(t *testing.T) {
+	type RowV1 struct {
+		ID int64 `parquet:"id"`
+	}
+	type RowV2 struct {
+		ID    int64  `parquet:"id"`
+		Extra string `parquet:"extra"`
+	}
+
+	fsys := fstest.MapFS{}
+	for file, rows := range map[string]interface{}{
+		"v=1/part-0.parquet": []RowV1{{ID: 1}},
+		"v=2/part-0.parquet": []RowV2{{ID: 2, Extra: "hello"}},
+	} {
+		buffer := new(bytes.Buffer)
+		if err := writeParquetFile(buffer, makeRows(rows)); err != nil {
+			t.Fatal(err)
+		}
+		fsys[file] = &fstest.MapFile{Data: buffer.Bytes()}
+	}
+
+	d, err := parquet.OpenDataset(fsys, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	extra, ok := d.Schema().Lookup("extra")
+	if !ok {
+		t.Fatal("column missing from some files is not part of the dataset schema")
+	}
+	if !extra.Node.Optional() {
+		t.Error("column missing from some files is not optional in the dataset schema")
+	}
+
+	rows := readDataset(t, d)
+	want := []map[string]interface{}{
+		{"id": int64(1), "extra": nil, "v": "1"},
+		{"id": int64(2), "extra": "hello", "v": "2"},
+	}
+	if !reflect.DeepEqual(want, rows) {
+		t.Errorf("rows mismatch:\nwant: %v\ngot:  %v", want, rows)
+	}
+}
+
+func TestDatasetPartitionPages(t *testing.T) {
+	const numRows = 500e3
+	region := "eu"
+	events := make([]datasetEvent, numRows)
+	for i := range events {
+		events[i] = datasetEvent{Date: "2022-05-01", Region: &region, ID: int64(i)}
+	}
+
+	fsys := fstest.MapFS{}
+	writeDataset(t, fsys, "events", events)
+
+	d, err := parquet.OpenDataset(fsys, "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	rowGroup := d.RowGroups()[0]
+	regionColumn, _ := d.Schema().Lookup("region")
+	columnChunk := rowGroup.ColumnChunks()[regionColumn.ColumnIndex]
+
+	// The values of partition columns are generated in pages of bounded size
+	// rather than in a single page holding a value for each row.
+	numPages := 0
+	readRows := int64(0)
+	pages := columnChunk.Pages()
+	for {
+		page, err := pages.ReadPage()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := page.Size(); size > parquet.DefaultPageBufferSize {
+			t.Errorf("page %d is too large: %d", numPages, size)
+		}
+		values := make([]parquet.Value, page.NumValues())
+		if n, err := page.Values().ReadValues(values); n != len(values) || (err != nil && err != io.EOF) {
+			t.Fatalf("reading values of page %d: n=%d err=%v", numPages, n, err)
+		}
+		for _, v := range values {
+			if v.String() != region {
+				t.Fatalf("wrong partition value in page %d: %v", numPages, v)
+			}
+		}
+		numPages++
+		readRows += page.NumRows()
+	}
+	if readRows != numRows {
+		t.Errorf("wrong number of rows: want=%d got=%d", int64(numRows), readRows)
+	}
+	if numPages < 2 || numPages != columnChunk.OffsetIndex().NumPages() {
+		t.Errorf("wrong number of pages: offset index=%d pages=%d", columnChunk.OffsetIndex().NumPages(), numPages)
+	}
+
+	if err := pages.SeekToRow(numRows - 10); err != nil {
+		t.Fatal(err)
+	}
+	page, err := pages.ReadPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := page.NumRows(); n != 10 {
+		t.Errorf("wrong number of rows after seeking: want=10 got=%d", n)
+	}
+
+	rows, err := readAllRows(parquet.NewRowGroupReader(rowGroup,
+		parquet.FilterRows(parquet.Eq([]string{"id"}, parquet.ValueOf(int64(numRows-1)))),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("wrong number of rows: want=1 got=%d", len(rows))
+	}
+}
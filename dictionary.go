@@ -43,7 +43,8 @@ type Dictionary interface {
 	// is negative or greater than the highest index in the dictionary.
 	Lookup(indexes []int32, values []Value)
 
-	// Returns the min and max values found in the given indexes.
+	// Returns the min and max values found in the given indexes, or null
+	// values if none of the indexed values bound the page (e.g. NaN).
 	Bounds(indexed []int32) (min, max Value)
 
 	// Resets the dictionary to its initial state, removing all values.
@@ -231,6 +232,47 @@ func (d *fixedLenByteArrayDictionary) Bounds(indexes []int32) (min, max Value) {
 	return min, max
 }
 
+// float16Dictionary wraps the dictionaries of FLOAT16 columns to compute the
+// bounds of indexed pages by comparing floating point values.
+type float16Dictionary struct{ *fixedLenByteArrayDictionary }
+
+func (d float16Dictionary) Type() Type { return newIndexedType(d.typ, d) }
+
+func (d float16Dictionary) Bounds(indexes []int32) (min, max Value) {
+	var minValue, maxValue []byte
+
+	for _, i := range indexes {
+		value := d.value(i)
+		switch {
+		case isFloat16NaN(value):
+		case minValue == nil:
+			minValue, maxValue = value, value
+		case compareFloat16(value, minValue) < 0:
+			minValue = value
+		case compareFloat16(value, maxValue) > 0:
+			maxValue = value
+		}
+	}
+
+	// NaN values do not bound FLOAT16 pages, see boundsOfFloat16; the bounds
+	// are null values if only NaN values are indexed.
+	if minValue != nil {
+		if float16ToFloat32(minValue) == 0 {
+			minValue = float16NegativeZero[:]
+		}
+		if float16ToFloat32(maxValue) == 0 {
+			maxValue = float16PositiveZero[:]
+		}
+		min = makeValueBytes(FixedLenByteArray, minValue)
+		max = makeValueBytes(FixedLenByteArray, maxValue)
+	}
+	return min, max
+}
+
+func (d float16Dictionary) Page() BufferedPage {
+	return float16Page{&d.fixedLenByteArrayPage}
+}
+
 func (d *fixedLenByteArrayDictionary) Reset() {
 	d.data = d.data[:0]
 	d.index = nil
@@ -291,6 +333,9 @@ func (page *indexedPage) NumNulls() int64 { return 0 }
 func (page *indexedPage) Bounds() (min, max Value, ok bool) {
 	if ok = len(page.values) > 0; ok {
 		min, max = page.dict.Bounds(page.values)
+		if min.IsNull() {
+			return Value{}, Value{}, false
+		}
 		min.columnIndex = page.columnIndex
 		max.columnIndex = page.columnIndex
 	}
@@ -0,0 +1,904 @@
+package parquet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// EncryptionAlgorithm enumerates the algorithms of the parquet modular
+// encryption specification.
+//
+// See https://github.com/apache/parquet-format/blob/master/Encryption.md
+type EncryptionAlgorithm int
+
+const (
+	// AesGcm encrypts all the modules of parquet files with AES-GCM, which
+	// guarantees both the confidentiality and the integrity of the data
+	// (AES_GCM_V1).
+	AesGcm EncryptionAlgorithm = iota
+
+	// AesGcmCtr encrypts the data and dictionary pages with AES-CTR, and the
+	// other modules with AES-GCM (AES_GCM_CTR_V1). The content of pages is not
+	// authenticated, in exchange the encryption has a lower overhead.
+	AesGcmCtr
+)
+
+// String returns the name of the algorithm in the parquet specification.
+func (a EncryptionAlgorithm) String() string {
+	switch a {
+	case AesGcm:
+		return "AES_GCM_V1"
+	case AesGcmCtr:
+		return "AES_GCM_CTR_V1"
+	default:
+		return fmt.Sprintf("EncryptionAlgorithm(%d)", int(a))
+	}
+}
+
+// EncryptionKey represents an AES key used to encrypt parts of parquet files.
+//
+// The key must be 16, 24, or 32 bytes long. The metadata is stored in the file
+// in order to let readers retrieve the key when decrypting the file; it must
+// not contain sensitive information.
+type EncryptionKey struct {
+	Key      []byte
+	Metadata []byte
+}
+
+// EncryptionKeys is an interface used by parquet writers to obtain the keys
+// used to encrypt the files that they produce.
+type EncryptionKeys interface {
+	// Returns the key used to encrypt the footer of the file (or to sign it
+	// when the footer is left in plaintext), and the columns which do not
+	// have a key of their own.
+	FooterKey() EncryptionKey
+
+	// Returns the key used to encrypt the column at the given path. The zero
+	// value indicates that the column is encrypted with the footer key.
+	ColumnKey(path []string) EncryptionKey
+}
+
+// KeyRetriever is an interface used when opening parquet files to obtain the
+// keys needed to decrypt them.
+type KeyRetriever interface {
+	// Returns the key identified by the key metadata stored in the file.
+	RetrieveKey(keyMetadata []byte) ([]byte, error)
+}
+
+// KeyRetrieverFunc is an implementation of the KeyRetriever interface for
+// functions.
+type KeyRetrieverFunc func(keyMetadata []byte) ([]byte, error)
+
+// RetrieveKey calls f.
+func (f KeyRetrieverFunc) RetrieveKey(keyMetadata []byte) ([]byte, error) {
+	return f(keyMetadata)
+}
+
+// Keyring is an in-memory set of keys implementing both the EncryptionKeys and
+// KeyRetriever interfaces, which allows the same value to be used to write and
+// read parquet files.
+//
+// Column keys are indexed by the dot-separated path of the columns, keys are
+// retrieved by matching their metadata.
+type Keyring struct {
+	Footer  EncryptionKey
+	Columns map[string]EncryptionKey
+}
+
+// FooterKey satisfies the EncryptionKeys interface.
+func (k *Keyring) FooterKey() EncryptionKey { return k.Footer }
+
+// ColumnKey satisfies the EncryptionKeys interface.
+func (k *Keyring) ColumnKey(path []string) EncryptionKey {
+	return k.Columns[columnPath(path).String()]
+}
+
+// RetrieveKey satisfies the KeyRetriever interface.
+func (k *Keyring) RetrieveKey(keyMetadata []byte) ([]byte, error) {
+	if k.Footer.Key != nil && bytes.Equal(k.Footer.Metadata, keyMetadata) {
+		return k.Footer.Key, nil
+	}
+	for _, key := range k.Columns {
+		if key.Key != nil && bytes.Equal(key.Metadata, keyMetadata) {
+			return key.Key, nil
+		}
+	}
+	return nil, fmt.Errorf("no key matching metadata %q: %w", keyMetadata, ErrMissingDecryptionKey)
+}
+
+// The EncryptionConfig type carries the configuration of parquet writers
+// producing encrypted files.
+//
+// By default, all the modules of the file are encrypted (including the
+// footer), the columns which have no key of their own are encrypted with the
+// footer key.
+//
+// EncryptionConfig implements the WriterOption interface so it can be used
+// directly as argument to the NewWriter function, for example:
+//
+//	writer := parquet.NewWriter(output, &parquet.EncryptionConfig{
+//		Keys: &parquet.Keyring{
+//			Footer: parquet.EncryptionKey{Key: footerKey, Metadata: []byte("kf")},
+//			Columns: map[string]parquet.EncryptionKey{
+//				"ssn": {Key: columnKey, Metadata: []byte("kc")},
+//			},
+//		},
+//	})
+//
+type EncryptionConfig struct {
+	// The algorithm used to encrypt the file, AesGcm by default.
+	Algorithm EncryptionAlgorithm
+
+	// The source of the keys used to encrypt the file.
+	Keys EncryptionKeys
+
+	// Paths of columns which are not encrypted.
+	PlaintextColumns [][]string
+
+	// When true, the footer is not encrypted, allowing legacy readers to read
+	// the plaintext columns. The footer is signed with the footer key so that
+	// readers which have the key can verify its integrity.
+	PlaintextFooter bool
+
+	// A prefix bound to the encryption of all the modules of the file, which
+	// may be used to identify the file (e.g. by its name) and prevent parts of
+	// files from being replaced by others.
+	AADPrefix []byte
+
+	// When true, the AAD prefix is not stored in the file; readers must then
+	// supply it when opening the file.
+	SupplyAADPrefix bool
+}
+
+// ConfigureWriter satisfies the WriterOption interface.
+func (c *EncryptionConfig) ConfigureWriter(config *WriterConfig) {
+	config.Encryption = c
+}
+
+// Validate returns a non-nil error if the configuration of c is invalid.
+func (c *EncryptionConfig) Validate() error {
+	const baseName = "parquet.(*EncryptionConfig)."
+	var footerKey []byte
+	if c.Keys != nil {
+		footerKey = c.Keys.FooterKey().Key
+	}
+	return errorInvalidConfiguration(
+		validateOneOfInt(baseName+"Algorithm", int(c.Algorithm), int(AesGcm), int(AesGcmCtr)),
+		validateNotNil(baseName+"Keys", c.Keys),
+		validateEncryptionKey(baseName+"Keys.FooterKey", footerKey),
+		validateAADPrefix(baseName+"AADPrefix", c.AADPrefix, c.SupplyAADPrefix),
+	)
+}
+
+// The DecryptionConfig type carries the configuration used to open encrypted
+// parquet files.
+//
+// DecryptionConfig implements the FileOption interface so it can be used
+// directly as argument to the OpenFile function, for example:
+//
+//	f, err := parquet.OpenFile(input, size, &parquet.DecryptionConfig{
+//		Keys: keyring,
+//	})
+//
+// Files with a plaintext footer may be opened without a decryption
+// configuration, in which case only their plaintext columns can be read.
+type DecryptionConfig struct {
+	// The source of keys used to decrypt the file.
+	Keys KeyRetriever
+
+	// The AAD prefix of the file, which must be set when the file was written
+	// with SupplyAADPrefix. When the file stores its own prefix, it must be
+	// equal to this value.
+	AADPrefix []byte
+}
+
+// ConfigureFile satisfies the FileOption interface.
+func (c *DecryptionConfig) ConfigureFile(config *FileConfig) {
+	config.Decryption = c
+}
+
+func validateEncryptionKey(optionName string, key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	}
+	// Do not print the key in the error message.
+	return errorInvalidOptionValue(optionName, fmt.Sprintf("key of %d bytes", len(key)))
+}
+
+func validateAADPrefix(optionName string, aadPrefix []byte, supply bool) error {
+	if supply && len(aadPrefix) == 0 {
+		return errorInvalidOptionValue(optionName, "AAD prefix must be set when it is supplied by readers")
+	}
+	return nil
+}
+
+func validateEncryptionConfig(config *EncryptionConfig) error {
+	if config == nil {
+		return nil
+	}
+	return config.Validate()
+}
+
+const (
+	encryptionLengthSize = 4
+	encryptionNonceSize  = 12
+	encryptionTagSize    = 16
+	footerSignatureSize  = encryptionNonceSize + encryptionTagSize
+	aadFileUniqueSize    = 8
+	noPageOrdinal        = -1
+)
+
+// Module types of the parquet modular encryption specification. They are part
+// of the additional authenticated data (AAD) of modules, which binds each
+// module to its position in the file.
+const (
+	footerModule int8 = iota
+	columnMetaDataModule
+	dataPageModule
+	dictionaryPageModule
+	dataPageHeaderModule
+	dictionaryPageHeaderModule
+	columnIndexModule
+	offsetIndexModule
+	bloomFilterHeaderModule
+	bloomFilterBitsetModule
+)
+
+// moduleAAD constructs the additional authenticated data of a module.
+//
+// The page ordinal is only part of the AAD of data pages and their headers,
+// the row group and column ordinals are omitted for the footer. Ordinals are
+// encoded on 2 bytes, writers verify that they do not exceed math.MaxInt16
+// with checkModuleOrdinal.
+func moduleAAD(fileAAD []byte, moduleType int8, rowGroup, column, page int) []byte {
+	aad := make([]byte, 0, len(fileAAD)+7)
+	aad = append(aad, fileAAD...)
+	aad = append(aad, byte(moduleType))
+	if moduleType != footerModule {
+		aad = append(aad, byte(rowGroup), byte(rowGroup>>8))
+		aad = append(aad, byte(column), byte(column>>8))
+		if page >= 0 {
+			aad = append(aad, byte(page), byte(page>>8))
+		}
+	}
+	return aad
+}
+
+// checkModuleOrdinal returns an error if the ordinal of a row group, column or
+// page cannot be part of the AAD of modules. The specification requires that
+// writers fail rather than produce files where modules could share AADs.
+func checkModuleOrdinal(name string, ordinal int) error {
+	if ordinal > math.MaxInt16 {
+		return fmt.Errorf("cannot encrypt parquet files with more than %d %s", math.MaxInt16+1, name)
+	}
+	return nil
+}
+
+// moduleCipher encrypts and decrypts parquet modules with a single key.
+//
+// Modules are made of a 4 bytes little-endian length, followed by a 12 bytes
+// nonce and the ciphertext. When using AES-GCM, the ciphertext is followed by
+// a 16 bytes authentication tag.
+type moduleCipher struct {
+	block cipher.Block
+	gcm   cipher.AEAD
+	ctr   bool // whether pages are encrypted with AES-CTR
+}
+
+func newModuleCipher(key []byte, algorithm EncryptionAlgorithm) (*moduleCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &moduleCipher{block: block, gcm: gcm, ctr: algorithm == AesGcmCtr}, nil
+}
+
+// encrypt appends to dst the module produced by encrypting plaintext with
+// AES-GCM.
+func (c *moduleCipher) encrypt(dst, plaintext, aad []byte) []byte {
+	nonce := makeNonce()
+	offset := len(dst)
+	dst = append(dst, 0, 0, 0, 0)
+	dst = append(dst, nonce[:]...)
+	dst = c.gcm.Seal(dst, nonce[:], plaintext, aad)
+	binary.LittleEndian.PutUint32(dst[offset:], uint32(len(dst)-(offset+encryptionLengthSize)))
+	return dst
+}
+
+// encryptPage is like encrypt but uses AES-CTR when the algorithm of the file
+// is AesGcmCtr.
+func (c *moduleCipher) encryptPage(dst, plaintext, aad []byte) []byte {
+	if !c.ctr {
+		return c.encrypt(dst, plaintext, aad)
+	}
+	nonce := makeNonce()
+	offset := len(dst)
+	dst = append(dst, 0, 0, 0, 0)
+	dst = append(dst, nonce[:]...)
+	start := len(dst)
+	dst = append(dst, plaintext...)
+	cipher.NewCTR(c.block, ctrIV(nonce)).XORKeyStream(dst[start:], dst[start:])
+	binary.LittleEndian.PutUint32(dst[offset:], uint32(len(dst)-(offset+encryptionLengthSize)))
+	return dst
+}
+
+// decrypt appends to dst the plaintext of a module encrypted with AES-GCM.
+func (c *moduleCipher) decrypt(dst, module, aad []byte) ([]byte, error) {
+	data, err := moduleData(module, encryptionNonceSize+encryptionTagSize)
+	if err != nil {
+		return dst, err
+	}
+	dst, err = c.gcm.Open(dst, data[:encryptionNonceSize], data[encryptionNonceSize:], aad)
+	if err != nil {
+		return dst, fmt.Errorf("decrypting parquet module: %w", err)
+	}
+	return dst, nil
+}
+
+// decryptPage is like decrypt but uses AES-CTR when the algorithm of the file
+// is AesGcmCtr.
+func (c *moduleCipher) decryptPage(dst, module, aad []byte) ([]byte, error) {
+	if !c.ctr {
+		return c.decrypt(dst, module, aad)
+	}
+	data, err := moduleData(module, encryptionNonceSize)
+	if err != nil {
+		return dst, err
+	}
+	var nonce [encryptionNonceSize]byte
+	copy(nonce[:], data)
+	start := len(dst)
+	dst = append(dst, data[encryptionNonceSize:]...)
+	cipher.NewCTR(c.block, ctrIV(nonce)).XORKeyStream(dst[start:], dst[start:])
+	return dst, nil
+}
+
+// sign computes the signature of a plaintext footer, made of the nonce and the
+// tag generated by encrypting the footer with AES-GCM.
+func (c *moduleCipher) sign(footer, aad []byte) []byte {
+	nonce := makeNonce()
+	sealed := c.gcm.Seal(nil, nonce[:], footer, aad)
+	return append(nonce[:], sealed[len(sealed)-encryptionTagSize:]...)
+}
+
+func (c *moduleCipher) verify(footer, signature, aad []byte) error {
+	if len(signature) != footerSignatureSize {
+		return ErrInvalidFooterSignature
+	}
+	sealed := c.gcm.Seal(nil, signature[:encryptionNonceSize], footer, aad)
+	if subtle.ConstantTimeCompare(sealed[len(sealed)-encryptionTagSize:], signature[encryptionNonceSize:]) != 1 {
+		return ErrInvalidFooterSignature
+	}
+	return nil
+}
+
+func makeNonce() (nonce [encryptionNonceSize]byte) {
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		panic(fmt.Errorf("generating random nonce: %w", err))
+	}
+	return nonce
+}
+
+// ctrIV returns the initialization vector of AES-CTR, made of the nonce and a
+// 4 bytes big-endian counter starting at 1.
+func ctrIV(nonce [encryptionNonceSize]byte) []byte {
+	iv := make([]byte, aes.BlockSize)
+	copy(iv, nonce[:])
+	iv[aes.BlockSize-1] = 1
+	return iv
+}
+
+func moduleData(module []byte, minSize int) ([]byte, error) {
+	if len(module) < encryptionLengthSize+minSize {
+		return nil, fmt.Errorf("parquet encrypted module is too short (%d bytes): %w", len(module), ErrCorrupted)
+	}
+	data := module[encryptionLengthSize:]
+	if length := binary.LittleEndian.Uint32(module); int64(length) != int64(len(data)) {
+		return nil, fmt.Errorf("parquet encrypted module length mismatch: %d != %d: %w", length, len(data), ErrCorrupted)
+	}
+	return data, nil
+}
+
+// readModule reads an encrypted module from r, reusing the buffer passed as
+// argument.
+func readModule(r io.Reader, buf []byte) ([]byte, error) {
+	var length [encryptionLengthSize]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return buf, err
+	}
+	size := int64(binary.LittleEndian.Uint32(length[:]))
+	if size > math.MaxInt32 {
+		return buf, fmt.Errorf("parquet encrypted module is too large (%d bytes): %w", size, ErrCorrupted)
+	}
+	if n := encryptionLengthSize + int(size); cap(buf) < n {
+		buf = make([]byte, n)
+	} else {
+		buf = buf[:n]
+	}
+	copy(buf, length[:])
+	_, err := io.ReadFull(r, buf[encryptionLengthSize:])
+	return buf, err
+}
+
+func readModuleAt(r io.ReaderAt, offset int64) ([]byte, error) {
+	return readModule(io.NewSectionReader(r, offset, math.MaxInt64-offset), nil)
+}
+
+func newFileAAD(aadPrefix, aadFileUnique []byte) []byte {
+	aad := make([]byte, 0, len(aadPrefix)+len(aadFileUnique))
+	aad = append(aad, aadPrefix...)
+	aad = append(aad, aadFileUnique...)
+	return aad
+}
+
+// fileEncryptor holds the state used by writers to encrypt parquet files.
+type fileEncryptor struct {
+	config    *EncryptionConfig
+	footer    *moduleCipher
+	footerKey EncryptionKey
+	algorithm format.EncryptionAlgorithm
+	aad       []byte
+	// Ordinal of the row group being written.
+	rowGroup int
+}
+
+func newFileEncryptor(config *EncryptionConfig) (*fileEncryptor, error) {
+	footerKey := config.Keys.FooterKey()
+	footer, err := newModuleCipher(footerKey.Key, config.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher of parquet footer key: %w", err)
+	}
+	e := &fileEncryptor{
+		config:    config,
+		footer:    footer,
+		footerKey: footerKey,
+	}
+	e.reset()
+	return e, nil
+}
+
+// reset prepares e to write a new file; each file has a unique AAD so modules
+// cannot be swapped between files.
+func (e *fileEncryptor) reset() {
+	aadFileUnique := make([]byte, aadFileUniqueSize)
+	if _, err := io.ReadFull(rand.Reader, aadFileUnique); err != nil {
+		panic(fmt.Errorf("generating random AAD: %w", err))
+	}
+
+	aadPrefix := e.config.AADPrefix
+	if e.config.SupplyAADPrefix {
+		aadPrefix = nil
+	}
+
+	switch e.config.Algorithm {
+	case AesGcmCtr:
+		e.algorithm = format.EncryptionAlgorithm{AesGcmCtrV1: &format.AesGcmCtrV1{
+			AadPrefix:       aadPrefix,
+			AadFileUnique:   aadFileUnique,
+			SupplyAadPrefix: e.config.SupplyAADPrefix,
+		}}
+	default:
+		e.algorithm = format.EncryptionAlgorithm{AesGcmV1: &format.AesGcmV1{
+			AadPrefix:       aadPrefix,
+			AadFileUnique:   aadFileUnique,
+			SupplyAadPrefix: e.config.SupplyAADPrefix,
+		}}
+	}
+
+	e.aad = newFileAAD(e.config.AADPrefix, aadFileUnique)
+	e.rowGroup = 0
+}
+
+func (e *fileEncryptor) magic() string {
+	if e.config.PlaintextFooter {
+		return "PAR1"
+	}
+	return "PARE"
+}
+
+// column returns the encryptor of the column at the given path, or nil if the
+// column is not encrypted.
+func (e *fileEncryptor) column(path columnPath, columnIndex int) (*columnEncryptor, error) {
+	for _, plaintextPath := range e.config.PlaintextColumns {
+		if path.equal(plaintextPath) {
+			return nil, nil
+		}
+	}
+
+	if err := checkModuleOrdinal("columns", columnIndex); err != nil {
+		return nil, err
+	}
+
+	c := &columnEncryptor{file: e, column: columnIndex}
+
+	if key := e.config.Keys.ColumnKey(path); key.Key == nil {
+		c.cipher = e.footer
+		c.metadata.EncryptionWithFooterKey = &format.EncryptionWithFooterKey{}
+	} else {
+		cipher, err := newModuleCipher(key.Key, e.config.Algorithm)
+		if err != nil {
+			return nil, fmt.Errorf("creating cipher of parquet column %q: %w", path, err)
+		}
+		c.cipher = cipher
+		c.metadata.EncryptionWithColumnKey = &format.EncryptionWithColumnKey{
+			PathInSchema: path,
+			KeyMetadata:  key.Metadata,
+		}
+	}
+
+	return c, nil
+}
+
+// encryptFooter returns the encrypted footer of a file, made of the plaintext
+// crypto metadata of the file followed by the encrypted file metadata.
+func (e *fileEncryptor) encryptFooter(footer []byte) ([]byte, error) {
+	data, err := thrift.Marshal(new(thrift.CompactProtocol), &format.FileCryptoMetaData{
+		EncryptionAlgorithm: e.algorithm,
+		KeyMetadata:         e.footerKey.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e.footer.encrypt(data, footer, moduleAAD(e.aad, footerModule, 0, 0, noPageOrdinal)), nil
+}
+
+// signFooter returns the plaintext footer followed by its signature.
+func (e *fileEncryptor) signFooter(footer []byte) []byte {
+	return append(footer, e.footer.sign(footer, moduleAAD(e.aad, footerModule, 0, 0, noPageOrdinal))...)
+}
+
+// encryptColumnMetaData sets the crypto metadata of column chunks, and moves
+// their metadata to the encrypted section when it is not already protected
+// by the encrypted footer.
+func (e *fileEncryptor) encryptColumnMetaData(rowGroups []format.RowGroup, columns []*writerColumn) error {
+	protocol := new(thrift.CompactProtocol)
+
+	for i := range rowGroups {
+		for j, c := range columns {
+			if c.encryption == nil {
+				continue
+			}
+			chunk := &rowGroups[i].Columns[j]
+			chunk.CryptoMetadata = c.encryption.metadata
+
+			if c.encryption.metadata.EncryptionWithFooterKey != nil && !e.config.PlaintextFooter {
+				continue
+			}
+
+			data, err := thrift.Marshal(protocol, &chunk.MetaData)
+			if err != nil {
+				return err
+			}
+			chunk.EncryptedColumnMetadata = c.encryption.encrypt(nil, data, columnMetaDataModule, i)
+
+			if e.config.PlaintextFooter {
+				// Legacy readers need the column metadata to read plaintext
+				// footers, only the statistics are hidden.
+				chunk.MetaData.Statistics = format.Statistics{}
+			} else {
+				chunk.MetaData = format.ColumnMetaData{}
+			}
+		}
+	}
+
+	return nil
+}
+
+// columnEncryptor encrypts the modules of a column.
+type columnEncryptor struct {
+	file     *fileEncryptor
+	cipher   *moduleCipher
+	metadata format.ColumnCryptoMetaData
+	column   int
+	buffer   []byte
+}
+
+// checkPage returns an error if the page at the given ordinal of the row group
+// being written cannot be encrypted.
+func (e *columnEncryptor) checkPage(page int) error {
+	if err := checkModuleOrdinal("row groups", e.file.rowGroup); err != nil {
+		return err
+	}
+	return checkModuleOrdinal("pages per column chunk", page)
+}
+
+func (e *columnEncryptor) encrypt(dst, plaintext []byte, moduleType int8, rowGroup int) []byte {
+	return e.cipher.encrypt(dst, plaintext, moduleAAD(e.file.aad, moduleType, rowGroup, e.column, noPageOrdinal))
+}
+
+func (e *columnEncryptor) encryptPage(dst, plaintext []byte, moduleType int8, page int) []byte {
+	return e.cipher.encryptPage(dst, plaintext, moduleAAD(e.file.aad, moduleType, e.file.rowGroup, e.column, page))
+}
+
+// encryptHeader replaces the content of a buffer holding a page or bloom
+// filter header with its encrypted module.
+func (e *columnEncryptor) encryptHeader(header *bytes.Buffer, moduleType int8, page int) {
+	aad := moduleAAD(e.file.aad, moduleType, e.file.rowGroup, e.column, page)
+	e.buffer = e.cipher.encrypt(e.buffer[:0], header.Bytes(), aad)
+	header.Reset()
+	header.Write(e.buffer)
+}
+
+// fileDecryptor holds the state used to decrypt parquet files.
+type fileDecryptor struct {
+	keys              KeyRetriever
+	algorithm         EncryptionAlgorithm
+	aad               []byte
+	footerKeyMetadata []byte
+	ciphers           map[string]*moduleCipher
+}
+
+func newFileDecryptor(config *DecryptionConfig, algorithm *format.EncryptionAlgorithm, footerKeyMetadata []byte) (*fileDecryptor, error) {
+	d := &fileDecryptor{
+		footerKeyMetadata: footerKeyMetadata,
+		ciphers:           make(map[string]*moduleCipher),
+	}
+
+	var aadPrefix, aadFileUnique []byte
+	var supplyAADPrefix bool
+
+	switch {
+	case algorithm.AesGcmV1 != nil:
+		d.algorithm = AesGcm
+		aadPrefix = algorithm.AesGcmV1.AadPrefix
+		aadFileUnique = algorithm.AesGcmV1.AadFileUnique
+		supplyAADPrefix = algorithm.AesGcmV1.SupplyAadPrefix
+	case algorithm.AesGcmCtrV1 != nil:
+		d.algorithm = AesGcmCtr
+		aadPrefix = algorithm.AesGcmCtrV1.AadPrefix
+		aadFileUnique = algorithm.AesGcmCtrV1.AadFileUnique
+		supplyAADPrefix = algorithm.AesGcmCtrV1.SupplyAadPrefix
+	default:
+		return nil, fmt.Errorf("unsupported parquet encryption algorithm")
+	}
+
+	if config != nil {
+		d.keys = config.Keys
+
+		if len(config.AADPrefix) > 0 {
+			if len(aadPrefix) > 0 && !bytes.Equal(aadPrefix, config.AADPrefix) {
+				return nil, fmt.Errorf("AAD prefix of parquet file does not match the configured value")
+			}
+			aadPrefix = config.AADPrefix
+		} else if supplyAADPrefix {
+			return nil, fmt.Errorf("parquet file requires an AAD prefix to be supplied for decryption")
+		}
+	}
+
+	d.aad = newFileAAD(aadPrefix, aadFileUnique)
+	return d, nil
+}
+
+func (d *fileDecryptor) cipher(keyMetadata []byte) (*moduleCipher, error) {
+	if c := d.ciphers[string(keyMetadata)]; c != nil {
+		return c, nil
+	}
+	if d.keys == nil {
+		return nil, ErrMissingDecryptionKey
+	}
+	key, err := d.keys.RetrieveKey(keyMetadata)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newModuleCipher(key, d.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	d.ciphers[string(keyMetadata)] = c
+	return c, nil
+}
+
+func (d *fileDecryptor) decryptFooter(module []byte) ([]byte, error) {
+	c, err := d.cipher(d.footerKeyMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving parquet footer key: %w", err)
+	}
+	return c.decrypt(nil, module, moduleAAD(d.aad, footerModule, 0, 0, noPageOrdinal))
+}
+
+func (d *fileDecryptor) verifyFooter(footer, signature []byte) error {
+	c, err := d.cipher(d.footerKeyMetadata)
+	if err != nil {
+		return fmt.Errorf("retrieving parquet footer key: %w", err)
+	}
+	return c.verify(footer, signature, moduleAAD(d.aad, footerModule, 0, 0, noPageOrdinal))
+}
+
+// column returns the decryptor of a column chunk, or nil if the column is not
+// encrypted.
+//
+// When the key of the column cannot be retrieved, the decryptor carries the
+// error which is reported when attempting to read the column.
+func (d *fileDecryptor) column(chunk *format.ColumnChunk, rowGroup, column int) *columnDecryptor {
+	crypto := &chunk.CryptoMetadata
+	c := &columnDecryptor{aad: d.aad, rowGroup: rowGroup, column: column}
+
+	switch {
+	case crypto.EncryptionWithFooterKey != nil:
+		c.cipher, c.err = d.cipher(d.footerKeyMetadata)
+	case crypto.EncryptionWithColumnKey != nil:
+		c.cipher, c.err = d.cipher(crypto.EncryptionWithColumnKey.KeyMetadata)
+	default:
+		return nil
+	}
+
+	if c.err != nil {
+		c.err = fmt.Errorf("retrieving key of parquet column %q: %w", columnPath(chunk.MetaData.PathInSchema), c.err)
+	}
+	return c
+}
+
+// columnDecryptor decrypts the modules of a column chunk.
+type columnDecryptor struct {
+	cipher   *moduleCipher
+	aad      []byte
+	rowGroup int
+	column   int
+	err      error
+}
+
+func (d *columnDecryptor) decrypt(dst, module []byte, moduleType int8) ([]byte, error) {
+	if d.err != nil {
+		return dst, d.err
+	}
+	return d.cipher.decrypt(dst, module, moduleAAD(d.aad, moduleType, d.rowGroup, d.column, noPageOrdinal))
+}
+
+func (d *columnDecryptor) decryptHeader(dst, module []byte, moduleType int8, page int) ([]byte, error) {
+	if d.err != nil {
+		return dst, d.err
+	}
+	return d.cipher.decrypt(dst, module, moduleAAD(d.aad, moduleType, d.rowGroup, d.column, page))
+}
+
+func (d *columnDecryptor) decryptPage(dst, module []byte, moduleType int8, page int) ([]byte, error) {
+	if d.err != nil {
+		return dst, d.err
+	}
+	return d.cipher.decryptPage(dst, module, moduleAAD(d.aad, moduleType, d.rowGroup, d.column, page))
+}
+
+// isEncryptedFileMetaData returns true if the metadata was read from the
+// plaintext footer of an encrypted file.
+func isEncryptedFileMetaData(metadata *format.FileMetaData) bool {
+	return metadata.EncryptionAlgorithm.AesGcmV1 != nil || metadata.EncryptionAlgorithm.AesGcmCtrV1 != nil
+}
+
+// openEncryptedFooter decodes the footer of files written in encrypted footer
+// mode, which starts with the plaintext crypto metadata of the file, followed
+// by the encrypted file metadata.
+func (f *File) openEncryptedFooter(config *DecryptionConfig, footerData []byte) error {
+	if config == nil {
+		return fmt.Errorf("opening parquet file with an encrypted footer: %w", ErrMissingDecryptionKey)
+	}
+
+	crypto := format.FileCryptoMetaData{}
+	n, err := decodeThrift(&f.protocol, footerData, &crypto)
+	if err != nil {
+		return fmt.Errorf("reading parquet file crypto metadata: %w", err)
+	}
+
+	if f.decryptor, err = newFileDecryptor(config, &crypto.EncryptionAlgorithm, crypto.KeyMetadata); err != nil {
+		return err
+	}
+
+	footer, err := f.decryptor.decryptFooter(footerData[n:])
+	if err != nil {
+		return fmt.Errorf("decrypting parquet file metadata: %w", err)
+	}
+
+	if err := thrift.Unmarshal(&f.protocol, footer, &f.metadata); err != nil {
+		return fmt.Errorf("reading parquet file metadata: %w", err)
+	}
+	return nil
+}
+
+// openSignedFooter configures the decryption of files written in plaintext
+// footer mode, verifying the signature of the footer when the decryption
+// configuration was set.
+func (f *File) openSignedFooter(config *DecryptionConfig, footer, signature []byte) (err error) {
+	f.decryptor, err = newFileDecryptor(config, &f.metadata.EncryptionAlgorithm, f.metadata.FooterSigningKeyMetadata)
+	if err != nil {
+		return err
+	}
+	if config != nil {
+		if err := f.decryptor.verifyFooter(footer, signature); err != nil {
+			return fmt.Errorf("verifying parquet footer signature: %w", err)
+		}
+	}
+	return nil
+}
+
+// decryptColumnMetaData initializes the decryptors of the column chunks of
+// f, and decrypts the metadata of the column chunks that can be decrypted.
+func (f *File) decryptColumnMetaData() error {
+	if f.decryptor == nil || len(f.metadata.RowGroups) == 0 {
+		return nil
+	}
+
+	numColumns := len(f.metadata.RowGroups[0].Columns)
+	f.decryptors = make([]*columnDecryptor, len(f.metadata.RowGroups)*numColumns)
+
+	for i := range f.metadata.RowGroups {
+		for j := range f.metadata.RowGroups[i].Columns {
+			chunk := &f.metadata.RowGroups[i].Columns[j]
+			d := f.decryptor.column(chunk, i, j)
+			f.decryptors[(i*numColumns)+j] = d
+
+			if d == nil || d.err != nil || len(chunk.EncryptedColumnMetadata) == 0 {
+				continue
+			}
+
+			data, err := d.decrypt(nil, chunk.EncryptedColumnMetadata, columnMetaDataModule)
+			if err != nil {
+				return fmt.Errorf("decrypting metadata of column chunk: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
+			}
+			chunk.MetaData = format.ColumnMetaData{}
+			if err := thrift.Unmarshal(&f.protocol, data, &chunk.MetaData); err != nil {
+				return fmt.Errorf("decoding metadata of column chunk: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// columnDecryptor returns the decryptor of a column chunk, or nil if the
+// column chunk is not encrypted.
+func (f *File) columnDecryptor(rowGroup, column int) *columnDecryptor {
+	if f.decryptors == nil {
+		return nil
+	}
+	return f.decryptors[(rowGroup*len(f.metadata.RowGroups[0].Columns))+column]
+}
+
+// decodeThrift decodes a thrift value at the beginning of data, returning the
+// number of bytes that were consumed.
+func decodeThrift(protocol thrift.Protocol, data []byte, value interface{}) (int, error) {
+	r := bytes.NewReader(data)
+	err := thrift.NewDecoder(protocol.NewReader(r)).Decode(value)
+	return len(data) - r.Len(), err
+}
+
+// readEncryptedBloomFilter reads the bloom filter of an encrypted column
+// chunk. Unlike plaintext filters which are read lazily, the bitset is
+// decrypted and loaded in memory.
+func readEncryptedBloomFilter(r io.ReaderAt, offset int64, d *columnDecryptor) (*bloomFilter, error) {
+	headerModule, err := readModuleAt(r, offset)
+	if err != nil {
+		return nil, err
+	}
+	headerData, err := d.decrypt(nil, headerModule, bloomFilterHeaderModule)
+	if err != nil {
+		return nil, err
+	}
+	header := format.BloomFilterHeader{}
+	if err := thrift.Unmarshal(new(thrift.CompactProtocol), headerData, &header); err != nil {
+		return nil, err
+	}
+	bitsetModule, err := readModuleAt(r, offset+int64(len(headerModule)))
+	if err != nil {
+		return nil, err
+	}
+	bitset, err := d.decrypt(nil, bitsetModule, bloomFilterBitsetModule)
+	if err != nil {
+		return nil, err
+	}
+	header.NumBytes = int32(len(bitset))
+	return newBloomFilter(bytes.NewReader(bitset), 0, &header), nil
+}
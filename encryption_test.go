@@ -0,0 +1,511 @@
+package parquet_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+type encryptedRow struct {
+	ID     int64    `parquet:"id"`
+	Name   string   `parquet:"name,dict"`
+	SSN    string   `parquet:"ssn"`
+	Score  *float64 `parquet:"score,optional"`
+	Labels []string `parquet:"labels"`
+}
+
+func makeEncryptedRows(numRows int) []encryptedRow {
+	rows := make([]encryptedRow, numRows)
+	for i := range rows {
+		rows[i] = encryptedRow{
+			ID:     int64(i),
+			Name:   fmt.Sprintf("name-%d", i%10),
+			SSN:    fmt.Sprintf("secret-%09d", i),
+			Labels: make([]string, i%3),
+		}
+		if i%4 != 0 {
+			score := float64(i) / 2
+			rows[i].Score = &score
+		}
+		for j := range rows[i].Labels {
+			rows[i].Labels[j] = fmt.Sprintf("label-%d", j)
+		}
+	}
+	return rows
+}
+
+var (
+	testFooterKey = parquet.EncryptionKey{
+		Key:      []byte("0123456789012345"),
+		Metadata: []byte("footer"),
+	}
+	testColumnKey = parquet.EncryptionKey{
+		Key:      []byte("abcdefghijklmnopqrstuvwxyz012345"),
+		Metadata: []byte("ssn"),
+	}
+)
+
+func writeEncryptedRows(rows []encryptedRow, config *parquet.EncryptionConfig, options ...parquet.WriterOption) (*bytes.Reader, error) {
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, append(options,
+		config,
+		parquet.PageBufferSize(512),
+		parquet.BloomFilters(parquet.SplitBlockFilter("ssn")),
+	)...)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			return nil, err
+		}
+		if (i+1)%100 == 0 {
+			if err := writer.Flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(buffer.Bytes()), nil
+}
+
+func readEncryptedRows(f *parquet.File, options ...parquet.ReaderOption) ([]encryptedRow, error) {
+	reader := parquet.NewReader(f, options...)
+	rows := []encryptedRow{}
+	for {
+		row := encryptedRow{}
+		if err := reader.Read(&row); err != nil {
+			if err == io.EOF {
+				return rows, nil
+			}
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	keyring := &parquet.Keyring{
+		Footer:  testFooterKey,
+		Columns: map[string]parquet.EncryptionKey{"ssn": testColumnKey},
+	}
+
+	rows := makeEncryptedRows(350)
+
+	for _, algorithm := range []parquet.EncryptionAlgorithm{parquet.AesGcm, parquet.AesGcmCtr} {
+		for _, plaintextFooter := range []bool{false, true} {
+			for _, concurrency := range []int{1, 4} {
+				scenario := fmt.Sprintf("%s/plaintext-footer=%t/concurrency=%d", algorithm, plaintextFooter, concurrency)
+
+				t.Run(scenario, func(t *testing.T) {
+					input, err := writeEncryptedRows(rows, &parquet.EncryptionConfig{
+						Algorithm:       algorithm,
+						Keys:            keyring,
+						PlaintextFooter: plaintextFooter,
+						AADPrefix:       []byte("table/file.parquet"),
+					},
+						parquet.Compression(&parquet.Snappy),
+						parquet.WriteConcurrency(concurrency),
+					)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					data := make([]byte, input.Size())
+					input.ReadAt(data, 0)
+					if bytes.Contains(data, []byte(rows[42].SSN)) {
+						t.Error("the encrypted file contains plaintext column values")
+					}
+
+					f, err := parquet.OpenFile(input, input.Size(), &parquet.DecryptionConfig{Keys: keyring})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if n := len(f.RowGroups()); n != 4 {
+						t.Errorf("wrong number of row groups: want=4 got=%d", n)
+					}
+
+					got, err := readEncryptedRows(f)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !reflect.DeepEqual(got, rows) {
+						t.Error("rows mismatch")
+					}
+
+					// Filters exercise the decryption of page indexes and bloom
+					// filters.
+					got, err = readEncryptedRows(f, parquet.FilterRows(
+						parquet.Eq([]string{"ssn"}, parquet.ValueOf(rows[123].SSN)),
+					))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !reflect.DeepEqual(got, rows[123:124]) {
+						t.Errorf("filtered rows mismatch: %+v", got)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestEncryptionMissingKeys(t *testing.T) {
+	rows := makeEncryptedRows(100)
+
+	t.Run("encrypted footer", func(t *testing.T) {
+		input, err := writeEncryptedRows(rows, &parquet.EncryptionConfig{
+			Keys: &parquet.Keyring{Footer: testFooterKey},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parquet.OpenFile(input, input.Size()); !errors.Is(err, parquet.ErrMissingDecryptionKey) {
+			t.Errorf("opening a file with an encrypted footer without keys returned the wrong error: %v", err)
+		}
+		_, err = parquet.OpenFile(input, input.Size(), &parquet.DecryptionConfig{
+			Keys: &parquet.Keyring{Footer: parquet.EncryptionKey{
+				Key:      []byte("wrong key 012345"),
+				Metadata: testFooterKey.Metadata,
+			}},
+		})
+		if err == nil {
+			t.Error("opening a file with the wrong footer key did not fail")
+		}
+	})
+
+	t.Run("plaintext footer", func(t *testing.T) {
+		input, err := writeEncryptedRows(rows, &parquet.EncryptionConfig{
+			Keys: &parquet.Keyring{
+				Footer:  testFooterKey,
+				Columns: map[string]parquet.EncryptionKey{"ssn": testColumnKey},
+			},
+			PlaintextFooter:  true,
+			PlaintextColumns: [][]string{{"id"}, {"name"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Without keys, only the plaintext columns can be read.
+		f, err := parquet.OpenFile(input, input.Size())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		type plaintextRow struct {
+			ID   int64  `parquet:"id"`
+			Name string `parquet:"name,dict"`
+		}
+		reader := parquet.NewReader(f, parquet.SelectColumns([]string{"id"}, []string{"name"}))
+		for i := range rows {
+			row := plaintextRow{}
+			if err := reader.Read(&row); err != nil {
+				t.Fatal(err)
+			}
+			if row.ID != rows[i].ID || row.Name != rows[i].Name {
+				t.Fatalf("row %d mismatch: %+v", i, row)
+			}
+		}
+
+		if _, err := readEncryptedRows(f); !errors.Is(err, parquet.ErrMissingDecryptionKey) {
+			t.Errorf("reading encrypted columns without keys returned the wrong error: %v", err)
+		}
+
+		// With only the footer key, the columns encrypted with their own key
+		// cannot be read.
+		f, err = parquet.OpenFile(input, input.Size(), &parquet.DecryptionConfig{
+			Keys: &parquet.Keyring{Footer: testFooterKey},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readEncryptedRows(f); !errors.Is(err, parquet.ErrMissingDecryptionKey) {
+			t.Errorf("reading a column without its key returned the wrong error: %v", err)
+		}
+	})
+}
+
+func TestEncryptionFooterSignature(t *testing.T) {
+	keyring := &parquet.Keyring{Footer: testFooterKey}
+	input, err := writeEncryptedRows(makeEncryptedRows(10), &parquet.EncryptionConfig{
+		Keys:            keyring,
+		PlaintextFooter: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := make([]byte, input.Size())
+	input.ReadAt(data, 0)
+	// The 28 bytes signature is located right before the footer length and
+	// magic number.
+	data[len(data)-9] ^= 0xFF
+
+	tampered := bytes.NewReader(data)
+	_, err = parquet.OpenFile(tampered, tampered.Size(), &parquet.DecryptionConfig{Keys: keyring})
+	if !errors.Is(err, parquet.ErrInvalidFooterSignature) {
+		t.Errorf("opening a file with an invalid footer signature returned the wrong error: %v", err)
+	}
+}
+
+func TestEncryptionSupplyAADPrefix(t *testing.T) {
+	keyring := &parquet.Keyring{Footer: testFooterKey}
+	rows := makeEncryptedRows(10)
+
+	input, err := writeEncryptedRows(rows, &parquet.EncryptionConfig{
+		Algorithm:       parquet.AesGcmCtr,
+		Keys:            keyring,
+		AADPrefix:       []byte("file-1"),
+		SupplyAADPrefix: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parquet.OpenFile(input, input.Size(), &parquet.DecryptionConfig{Keys: keyring}); err == nil {
+		t.Error("opening a file without supplying its AAD prefix did not fail")
+	}
+
+	if _, err := parquet.OpenFile(input, input.Size(), &parquet.DecryptionConfig{
+		Keys:      keyring,
+		AADPrefix: []byte("file-2"),
+	}); err == nil {
+		t.Error("opening a file with the wrong AAD prefix did not fail")
+	}
+
+	f, err := parquet.OpenFile(input, input.Size(), &parquet.DecryptionConfig{
+		Keys:      keyring,
+		AADPrefix: []byte("file-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := readEncryptedRows(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Error("rows mismatch")
+	}
+}
+
+// The encrypted files of the apache/parquet-testing repository, written by the
+// C++ and Java implementations, are encrypted with the keys published in the
+// README of the repository.
+var parquetTestingKeys = parquet.KeyRetrieverFunc(func(keyMetadata []byte) ([]byte, error) {
+	switch string(keyMetadata) {
+	case "kf":
+		return []byte("0123456789012345"), nil
+	case "kc1":
+		return []byte("1234567890123450"), nil
+	case "kc2":
+		return []byte("1234567890123451"), nil
+	}
+	return nil, fmt.Errorf("no key matching metadata %q: %w", keyMetadata, parquet.ErrMissingDecryptionKey)
+})
+
+func TestEncryptionDictionaryPageWithoutOffset(t *testing.T) {
+	keyring := &parquet.Keyring{Footer: testFooterKey}
+	rows := makeEncryptedRows(100)
+	input, err := writeEncryptedRows(rows, &parquet.EncryptionConfig{Keys: keyring})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Some writers leave the dictionary page offset unset and record the
+	// offset of the dictionary page as the offset of the first data page.
+	for _, test := range []struct {
+		scenario string
+		mutate   func(*format.ColumnMetaData)
+	}{
+		{
+			scenario: "dictionary encoding",
+			mutate:   func(*format.ColumnMetaData) {},
+		},
+		{
+			scenario: "no dictionary encoding",
+			mutate: func(metadata *format.ColumnMetaData) {
+				metadata.Encoding = []format.Encoding{format.Plain}
+			},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			f, err := parquet.OpenFile(input, input.Size(), &parquet.DecryptionConfig{Keys: keyring})
+			if err != nil {
+				t.Fatal(err)
+			}
+			leaf, _ := f.Schema().Lookup("name")
+
+			var names []string
+			for i, rowGroup := range f.RowGroups() {
+				metadata := &f.Metadata().RowGroups[i].Columns[leaf.ColumnIndex].MetaData
+				if metadata.DictionaryPageOffset == 0 {
+					t.Fatal("the column chunk has no dictionary page offset")
+				}
+				metadata.DataPageOffset = metadata.DictionaryPageOffset
+				metadata.DictionaryPageOffset = 0
+				test.mutate(metadata)
+
+				pages := rowGroup.ColumnChunks()[leaf.ColumnIndex].Pages()
+				for {
+					page, err := pages.ReadPage()
+					if err != nil {
+						if err == io.EOF {
+							break
+						}
+						t.Fatal(err)
+					}
+					values := make([]parquet.Value, page.NumValues())
+					if _, err := page.Values().ReadValues(values); err != nil && err != io.EOF {
+						t.Fatal(err)
+					}
+					for _, v := range values {
+						names = append(names, v.String())
+					}
+				}
+			}
+
+			if len(names) != len(rows) {
+				t.Fatalf("wrong number of values: want=%d got=%d", len(rows), len(names))
+			}
+			for i, name := range names {
+				if name != rows[i].Name {
+					t.Fatalf("value %d mismatch: want=%q got=%q", i, rows[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestEncryptionOrdinalLimits(t *testing.T) {
+	type Row struct {
+		ID int64 `parquet:"id"`
+	}
+
+	// Ordinals are encoded on 2 bytes in the AAD of modules, writers must fail
+	// instead of producing modules which would share the same AAD.
+	for _, test := range []struct {
+		scenario string
+		option   parquet.WriterOption
+	}{
+		{scenario: "pages", option: parquet.MaxRowsPerPage(1)},
+		{scenario: "row groups", option: parquet.MaxRowsPerRowGroup(1)},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			writer := parquet.NewWriter(io.Discard,
+				&parquet.EncryptionConfig{Keys: &parquet.Keyring{Footer: testFooterKey}},
+				test.option,
+			)
+			err := func() error {
+				for i := 0; i <= math.MaxInt16+1; i++ {
+					if err := writer.Write(&Row{ID: int64(i)}); err != nil {
+						return err
+					}
+				}
+				return writer.Close()
+			}()
+			if err == nil {
+				t.Errorf("writing more than %d %s did not fail", math.MaxInt16+1, test.scenario)
+			}
+		})
+	}
+}
+
+func TestEncryptionInteroperability(t *testing.T) {
+	tests := []struct {
+		file      string
+		aadPrefix []byte
+	}{
+		{file: "uniform_encryption.parquet.encrypted"},
+		{file: "encrypt_columns_and_footer.parquet.encrypted"},
+		{file: "encrypt_columns_plaintext_footer.parquet.encrypted"},
+		{file: "encrypt_columns_and_footer_aad.parquet.encrypted"},
+		{file: "encrypt_columns_and_footer_disable_aad_storage.parquet.encrypted", aadPrefix: []byte("tester")},
+		{file: "encrypt_columns_and_footer_ctr.parquet.encrypted"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			// The files are copied from the data directory of
+			// https://github.com/apache/parquet-testing, they were written by
+			// parquet-mr.
+			b, err := os.ReadFile(filepath.Join("testdata", test.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := parquet.OpenFile(bytes.NewReader(b), int64(len(b)), &parquet.DecryptionConfig{
+				Keys:      parquetTestingKeys,
+				AADPrefix: test.aadPrefix,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := readAllRows(parquet.NewReader(f))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) == 0 {
+				t.Fatal("no rows read from the file")
+			}
+
+			columnIndexOf := func(name string) int {
+				leaf, ok := f.Schema().Lookup(name)
+				if !ok {
+					t.Fatalf("column %q is missing from the file", name)
+				}
+				return leaf.ColumnIndex
+			}
+			booleanColumn := columnIndexOf("boolean_field")
+			int32Column := columnIndexOf("int32_field")
+			floatColumn := columnIndexOf("float_field")   // encrypted with kc2
+			doubleColumn := columnIndexOf("double_field") // encrypted with kc1
+
+			valueOf := func(row parquet.Row, columnIndex int) parquet.Value {
+				for _, v := range row {
+					if v.Column() == columnIndex {
+						return v
+					}
+				}
+				return parquet.Value{}
+			}
+
+			for i, row := range rows {
+				if v := valueOf(row, booleanColumn).Boolean(); v != (i%2 == 0) {
+					t.Errorf("row %d: wrong boolean_field: %t", i, v)
+				}
+				if v := valueOf(row, int32Column).Int32(); v != int32(i) {
+					t.Errorf("row %d: wrong int32_field: %d", i, v)
+				}
+				if v := valueOf(row, floatColumn).Float(); v != float32(i)*1.1 {
+					t.Errorf("row %d: wrong float_field: %g", i, v)
+				}
+				if v := valueOf(row, doubleColumn).Double(); v != float64(i)*1.1111111 {
+					t.Errorf("row %d: wrong double_field: %g", i, v)
+				}
+			}
+		})
+	}
+}
+
+func TestEncryptionConfigValidate(t *testing.T) {
+	_, err := parquet.NewWriterConfig(&parquet.EncryptionConfig{
+		Keys: &parquet.Keyring{Footer: parquet.EncryptionKey{Key: []byte("short")}},
+	})
+	if err == nil {
+		t.Error("configuring a footer key of invalid length did not fail")
+	}
+	if bytes.Contains([]byte(fmt.Sprint(err)), []byte("short")) {
+		t.Errorf("the configuration error leaked the key: %v", err)
+	}
+}
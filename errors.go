@@ -26,6 +26,18 @@ var (
 	// destination.
 	ErrRowGroupSortingColumnsMismatch = errors.New("cannot write row groups with mismatching sorting columns")
 
+	// ErrColumnNotFound is an error returned when a column path does not
+	// exist in the schema of a parquet file.
+	ErrColumnNotFound = errors.New("column not found in parquet schema")
+
+	// ErrMissingDecryptionKey is an error returned when reading encrypted parts
+	// of a parquet file for which no decryption key could be obtained.
+	ErrMissingDecryptionKey = errors.New("missing key to decrypt parquet file")
+
+	// ErrInvalidFooterSignature is an error returned when opening a parquet
+	// file with a plaintext footer which does not match its signature.
+	ErrInvalidFooterSignature = errors.New("invalid parquet footer signature")
+
 	// ErrSeekOutOfRange is an error returned when seeking to a row index which
 	// is less than the first row of a page.
 	ErrSeekOutOfRange = errors.New("seek to row index out of page range")
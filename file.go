@@ -30,6 +30,8 @@ type File struct {
 	columnIndexes []format.ColumnIndex
 	offsetIndexes []format.OffsetIndex
 	rowGroups     []RowGroup
+	decryptor     *fileDecryptor
+	decryptors    []*columnDecryptor
 }
 
 // OpenFile opens a parquet file and reads the content between offset 0 and the given
@@ -38,25 +40,105 @@ type File struct {
 // Only the parquet magic bytes and footer are read, column chunks and other
 // parts of the file are left untouched; this means that successfully opening
 // a file does not validate that the pages have valid checksums.
+//
+// Encrypted files are opened by passing a DecryptionConfig in the options.
+//
+// When a MetadataCache is configured with the CacheMetadata option, the footer
+// and page index are loaded from the cache instead of being read from r if the
+// file was already opened with the same key.
 func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
-	b := make([]byte, 8)
-	f := &File{reader: r, size: size}
 	c, err := NewFileConfig(options...)
 	if err != nil {
 		return nil, err
 	}
+	// The metadata of encrypted files cannot be cached since opening them
+	// requires decrypting the column chunks.
+	if c.MetadataCache == nil || c.Decryption != nil {
+		return openFileAt(r, size, c)
+	}
+
+	key := MetadataKey{Name: c.MetadataCacheKey, Size: size}
+	if metadata, ok := c.MetadataCache.Load(key); ok {
+		return openFileWithMetadata(r, size, metadata, c)
+	}
+
+	f, err := openFileAt(r, size, c)
+	if err != nil {
+		return nil, err
+	}
+	if metadata, err := f.FileMetadata(); err == nil {
+		c.MetadataCache.Store(key, metadata)
+	}
+	return f, nil
+}
+
+// OpenFileWithMetadata opens a parquet file of the given size in r, using the
+// footer and page index from metadata instead of reading them from r.
+//
+// The metadata is usually obtained by calling FileMetadata on a File previously
+// opened from the same content, or by decoding the output of its MarshalBinary
+// method. The function does not validate that the metadata matches the content
+// of r.
+//
+// If metadata does not contain a page index, it is read from r unless the
+// SkipPageIndex option is set. Bloom filters are read from r unless the
+// SkipBloomFilters option is set.
+func OpenFileWithMetadata(r io.ReaderAt, size int64, metadata *FileMetadata, options ...FileOption) (*File, error) {
+	c, err := NewFileConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+	return openFileWithMetadata(r, size, metadata, c)
+}
+
+func openFileWithMetadata(r io.ReaderAt, size int64, metadata *FileMetadata, c *FileConfig) (*File, error) {
+	if c.Decryption != nil {
+		return nil, fmt.Errorf("cannot open encrypted parquet file with metadata")
+	}
+	f := &File{
+		metadata: metadata.metadata,
+		reader:   r,
+		size:     size,
+	}
+	if !c.SkipPageIndex {
+		f.columnIndexes = metadata.columnIndexes
+		f.offsetIndexes = metadata.offsetIndexes
+	}
+	if err := f.open(c); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
 
-	if _, err := r.ReadAt(b[:4], 0); err != nil {
+func openFileAt(r io.ReaderAt, size int64, c *FileConfig) (*File, error) {
+	b := make([]byte, 8)
+	f := &File{reader: r, size: size}
+
+	if c.ReadFooterSize > 0 {
+		tailSize := c.ReadFooterSize
+		if tailSize > size {
+			tailSize = size
+		}
+		tail := &tailReader{reader: r, offset: size - tailSize, tail: make([]byte, tailSize)}
+		if _, err := r.ReadAt(tail.tail, tail.offset); err != nil {
+			return nil, fmt.Errorf("reading end of parquet file: %w", err)
+		}
+		f.reader = tail
+	}
+
+	if _, err := f.reader.ReadAt(b[:4], 0); err != nil {
 		return nil, fmt.Errorf("reading magic header of parquet file: %w", err)
 	}
-	if string(b[:4]) != "PAR1" {
+	// Files with an encrypted footer use a different magic number.
+	magic := string(b[:4])
+	if magic != "PAR1" && magic != "PARE" {
 		return nil, fmt.Errorf("invalid magic header of parquet file: %q", b[:4])
 	}
 
-	if _, err := r.ReadAt(b[:8], size-8); err != nil {
+	if _, err := f.reader.ReadAt(b[:8], size-8); err != nil {
 		return nil, fmt.Errorf("reading magic footer of parquet file: %w", err)
 	}
-	if string(b[4:8]) != "PAR1" {
+	if string(b[4:8]) != magic {
 		return nil, fmt.Errorf("invalid magic footer of parquet file: %q", b[4:8])
 	}
 
@@ -66,21 +148,57 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 	if _, err := f.reader.ReadAt(footerData, size-(footerSize+8)); err != nil {
 		return nil, fmt.Errorf("reading footer of parquet file: %w", err)
 	}
-	if err := thrift.Unmarshal(&f.protocol, footerData, &f.metadata); err != nil {
-		return nil, fmt.Errorf("reading parquet file metadata: %w", err)
+	if magic == "PARE" {
+		if err := f.openEncryptedFooter(c.Decryption, footerData); err != nil {
+			return nil, err
+		}
+	} else {
+		n, err := decodeThrift(&f.protocol, footerData, &f.metadata)
+		if err != nil {
+			return nil, fmt.Errorf("reading parquet file metadata: %w", err)
+		}
+		// Encrypted files with a plaintext footer have a signature appended
+		// to the file metadata.
+		if isEncryptedFileMetaData(&f.metadata) {
+			if err := f.openSignedFooter(c.Decryption, footerData[:n], footerData[n:]); err != nil {
+				return nil, err
+			}
+		} else if n != len(footerData) {
+			return nil, fmt.Errorf("reading parquet file metadata: %d unexpected trailing bytes", len(footerData)-n)
+		}
+	}
+	if err := f.decryptColumnMetaData(); err != nil {
+		return nil, err
+	}
+	sortKeyValueMetadata(f.metadata.KeyValueMetadata)
+
+	if err := f.open(c); err != nil {
+		return nil, err
 	}
+	return f, nil
+}
+
+// open initializes f after its metadata was loaded.
+func (f *File) open(c *FileConfig) (err error) {
 	if len(f.metadata.Schema) == 0 {
-		return nil, ErrMissingRootColumn
+		return ErrMissingRootColumn
 	}
 
-	if !c.SkipPageIndex {
+	// When the end of the file was loaded in memory, it serves the reads made
+	// while opening the file but is not retained by f afterwards.
+	reader := f.reader
+	if tail, ok := reader.(*tailReader); ok {
+		reader = tail.reader
+	}
+
+	if !c.SkipPageIndex && !f.hasIndexes() {
 		if f.columnIndexes, f.offsetIndexes, err = f.ReadPageIndex(); err != nil {
-			return nil, fmt.Errorf("reading page index of parquet file: %w", err)
+			return fmt.Errorf("reading page index of parquet file: %w", err)
 		}
 	}
 
 	if f.root, err = openColumns(f); err != nil {
-		return nil, fmt.Errorf("opening columns of parquet file: %w", err)
+		return fmt.Errorf("opening columns of parquet file: %w", err)
 	}
 
 	schema := NewSchema(f.root.Name(), f.root)
@@ -91,39 +209,75 @@ func OpenFile(r io.ReaderAt, size int64, options ...FileOption) (*File, error) {
 	rowGroups := make([]fileRowGroup, len(f.metadata.RowGroups))
 	for i := range rowGroups {
 		rowGroups[i].init(f, schema, columns, &f.metadata.RowGroups[i])
+		for j, c := range rowGroups[i].columns {
+			c.(*fileColumnChunk).decryptor = f.columnDecryptor(i, j)
+		}
 	}
 	f.rowGroups = make([]RowGroup, len(rowGroups))
 	for i := range rowGroups {
 		f.rowGroups[i] = &rowGroups[i]
 	}
 
+	// Bloom filters are only loaded for the columns exposed by the file.
+	selected := make([]bool, len(columns))
+	for i := range selected {
+		selected[i] = c.Columns == nil
+	}
+
+	if c.Columns != nil {
+		if f.schema, err = ProjectSchema(schema, c.Columns...); err != nil {
+			return err
+		}
+		forEachLeafColumnOf(f.schema, func(leaf leafColumn) {
+			column, _ := schema.Lookup(leaf.path...)
+			selected[column.ColumnIndex] = true
+		})
+		for i := range f.rowGroups {
+			f.rowGroups[i] = newProjectedRowGroup(f.rowGroups[i], f.schema)
+		}
+	}
+
+	if c.ReadRangeSize > 0 {
+		reader = newRangeReader(f, reader, c, selected, !c.SkipBloomFilters)
+		f.reader = reader
+	}
+
 	if !c.SkipBloomFilters {
 		h := format.BloomFilterHeader{}
 		p := thrift.CompactProtocol{}
-		s := io.NewSectionReader(r, 0, size)
+		s := io.NewSectionReader(f.reader, 0, f.size)
 		d := thrift.NewDecoder(p.NewReader(s))
 
 		for i := range rowGroups {
 			g := &rowGroups[i]
 
 			for j := range g.columns {
+				if !selected[j] {
+					continue
+				}
 				c := g.columns[j].(*fileColumnChunk)
 
-				if offset := c.chunk.MetaData.BloomFilterOffset; offset > 0 {
+				if offset := c.chunk.MetaData.BloomFilterOffset; offset > 0 && c.decryptor != nil {
+					if c.decryptor.err == nil {
+						if c.bloomFilter, err = readEncryptedBloomFilter(f.reader, offset, c.decryptor); err != nil {
+							return fmt.Errorf("reading bloom filter of column %q: %w", c.column.Path(), err)
+						}
+					}
+				} else if offset > 0 {
 					s.Seek(offset, io.SeekStart)
 					h = format.BloomFilterHeader{}
 					if err := d.Decode(&h); err != nil {
-						return nil, err
+						return err
 					}
 					offset, _ = s.Seek(0, io.SeekCurrent)
-					c.bloomFilter = newBloomFilter(r, offset, &h)
+					c.bloomFilter = newBloomFilter(reader, offset, &h)
 				}
 			}
 		}
 	}
 
-	sortKeyValueMetadata(f.metadata.KeyValueMetadata)
-	return f, nil
+	f.reader = reader
+	return nil
 }
 
 // ReadPageIndex reads the page index section of the parquet file f.
@@ -199,6 +353,15 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 			offset := c.ColumnIndexOffset - columnIndexOffset
 			length := int64(c.ColumnIndexLength)
 			buffer := columnIndexData[offset : offset+length]
+			if d := f.columnDecryptor(i, j); d != nil {
+				if d.err != nil {
+					return nil // the index of columns that cannot be decrypted is left empty
+				}
+				var err error
+				if buffer, err = d.decrypt(nil, buffer, columnIndexModule); err != nil {
+					return fmt.Errorf("decrypting column index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
+				}
+			}
 			if err := thrift.Unmarshal(&f.protocol, buffer, &columnIndexes[(i*numColumns)+j]); err != nil {
 				return fmt.Errorf("decoding column index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
 			}
@@ -220,6 +383,15 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 			offset := c.OffsetIndexOffset - offsetIndexOffset
 			length := int64(c.OffsetIndexLength)
 			buffer := offsetIndexData[offset : offset+length]
+			if d := f.columnDecryptor(i, j); d != nil {
+				if d.err != nil {
+					return nil // the index of columns that cannot be decrypted is left empty
+				}
+				var err error
+				if buffer, err = d.decrypt(nil, buffer, offsetIndexModule); err != nil {
+					return fmt.Errorf("decrypting offset index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
+				}
+			}
 			if err := thrift.Unmarshal(&f.protocol, buffer, &offsetIndexes[(i*numColumns)+j]); err != nil {
 				return fmt.Errorf("decoding column index: rowGroup=%d columnChunk=%d/%d: %w", i, j, numColumns, err)
 			}
@@ -236,13 +408,25 @@ func (f *File) ReadPageIndex() ([]format.ColumnIndex, []format.OffsetIndex, erro
 // NumRows returns the number of rows in the file.
 func (f *File) NumRows() int64 { return f.metadata.NumRows }
 
+// Metadata returns the metadata of f, as decoded from the file footer.
+//
+// The returned value is shared with f and must be treated as read-only by the
+// program.
+func (f *File) Metadata() *format.FileMetaData { return &f.metadata }
+
 // RowGroups returns the list of row group in the file.
+//
+// When the file was opened with the SelectColumns option, the row groups only
+// expose the selected columns.
 func (f *File) RowGroups() []RowGroup { return f.rowGroups }
 
 // Root returns the root column of f.
 func (f *File) Root() *Column { return f.root }
 
 // Schema returns the schema of f.
+//
+// When the file was opened with the SelectColumns option, the schema is the
+// projection of the file schema on the selected columns.
 func (f *File) Schema() *Schema { return f.schema }
 
 // Size returns the size of f (in bytes).
@@ -382,6 +566,7 @@ type fileColumnChunk struct {
 	columnIndex *format.ColumnIndex
 	offsetIndex *format.OffsetIndex
 	chunk       *format.ColumnChunk
+	decryptor   *columnDecryptor
 }
 
 func (c *fileColumnChunk) Type() Type {
@@ -423,6 +608,22 @@ func (c *fileColumnChunk) NumValues() int64 {
 	return c.chunk.MetaData.NumValues
 }
 
+// hasDictionaryPage returns true if the column chunk starts with a dictionary
+// page, which is the case when its metadata has a dictionary page offset or
+// lists a dictionary encoding.
+func (c *fileColumnChunk) hasDictionaryPage() bool {
+	if c.chunk.MetaData.DictionaryPageOffset != 0 {
+		return true
+	}
+	for _, encoding := range c.chunk.MetaData.Encoding {
+		switch encoding {
+		case format.PlainDictionary, format.RLEDictionary:
+			return true
+		}
+	}
+	return false
+}
+
 type filePages struct {
 	chunk    *fileColumnChunk
 	dictPage *dictPage
@@ -438,6 +639,17 @@ type filePages struct {
 	dictOffset int64
 	index      int
 	skip       int64
+
+	// When seeking before the dictionary page was read, the reader loads the
+	// dictionary first, then seeks to this data page.
+	seekOffset int64
+	seekIndex  int
+
+	// State used to decrypt the pages of encrypted columns; the ordinal is
+	// the position of the next data page in the column chunk.
+	ordinal int
+	header  []byte
+	module  []byte
 }
 
 func (r *filePages) init(c *fileColumnChunk) {
@@ -455,10 +667,20 @@ func (r *filePages) init(c *fileColumnChunk) {
 }
 
 func (r *filePages) ReadPage() (Page, error) {
+	if d := r.chunk.decryptor; d != nil && d.err != nil {
+		return nil, d.err
+	}
+
 	for {
 		header := new(format.PageHeader)
-		if err := r.decoder.Decode(header); err != nil {
-			return nil, err
+		if r.chunk.decryptor == nil {
+			if err := r.decoder.Decode(header); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := r.readEncryptedPageHeader(header); err != nil {
+				return nil, err
+			}
 		}
 
 		if cap(r.dataPage.data) < int(header.CompressedPageSize) {
@@ -498,6 +720,12 @@ func (r *filePages) ReadPage() (Page, error) {
 			}
 		}
 
+		if r.chunk.decryptor != nil {
+			if err := r.decryptPageData(header); err != nil {
+				return nil, fmt.Errorf("decrypting page %d of column %q: %w", r.index, r.columnPath(), err)
+			}
+		}
+
 		var column = r.chunk.column
 		var page Page
 		var err error
@@ -532,6 +760,10 @@ func (r *filePages) ReadPage() (Page, error) {
 					r.dataPage,
 					r.dictPage,
 				)
+				if err == nil && r.seekOffset > 0 {
+					err = r.seek(r.seekOffset, r.seekIndex)
+					r.seekOffset = 0
+				}
 			}
 
 		default:
@@ -544,6 +776,7 @@ func (r *filePages) ReadPage() (Page, error) {
 
 		if page != nil {
 			r.index++
+			r.ordinal++
 			if r.skip == 0 {
 				return page, nil
 			}
@@ -565,30 +798,91 @@ func (r *filePages) ReadPage() (Page, error) {
 	}
 }
 
+func (r *filePages) readEncryptedPageHeader(header *format.PageHeader) (err error) {
+	// The dictionary page is always the first page of the column chunk; its
+	// header is authenticated with a different module type than the headers
+	// of data pages. Some writers do not record the offset of dictionary pages,
+	// so the first page is expected to be a dictionary page when the column
+	// chunk has a dictionary encoding, and the other module type is tried if
+	// the header cannot be authenticated.
+	firstPage := r.offset() == r.baseOffset
+	dictionaryPage := firstPage && r.chunk.hasDictionaryPage()
+
+	if r.module, err = readModule(r.rbuf, r.module); err != nil {
+		return err
+	}
+	if r.header, err = r.decryptPageHeader(dictionaryPage); err != nil && firstPage {
+		r.header, err = r.decryptPageHeader(!dictionaryPage)
+	}
+	if err != nil {
+		return fmt.Errorf("decrypting header of page %d of column %q: %w", r.index, r.columnPath(), err)
+	}
+	return thrift.Unmarshal(&r.protocol, r.header, header)
+}
+
+func (r *filePages) decryptPageHeader(dictionaryPage bool) ([]byte, error) {
+	moduleType, ordinal := dataPageHeaderModule, r.ordinal
+	if dictionaryPage {
+		moduleType, ordinal = dictionaryPageHeaderModule, noPageOrdinal
+	}
+	return r.chunk.decryptor.decryptHeader(r.header[:0], r.module, moduleType, ordinal)
+}
+
+func (r *filePages) decryptPageData(header *format.PageHeader) (err error) {
+	moduleType, ordinal := dataPageModule, r.ordinal
+	if header.Type == format.DictionaryPage {
+		moduleType, ordinal = dictionaryPageModule, noPageOrdinal
+	}
+	r.module, r.dataPage.data = r.dataPage.data, r.module[:0]
+	r.dataPage.data, err = r.chunk.decryptor.decryptPage(r.dataPage.data, r.module, moduleType, ordinal)
+	return err
+}
+
+// offset returns the position of the next byte read from the column chunk.
+func (r *filePages) offset() int64 {
+	offset, _ := r.section.Seek(0, io.SeekCurrent)
+	return r.baseOffset + offset - int64(r.rbuf.Buffered())
+}
+
 func (r *filePages) columnPath() columnPath {
 	return columnPath(r.chunk.column.Path())
 }
 
 func (r *filePages) SeekToRow(rowIndex int64) (err error) {
+	offset, index := r.dataOffset, 0
+
 	if r.chunk.offsetIndex == nil {
-		_, err = r.section.Seek(r.dataOffset-r.baseOffset, io.SeekStart)
 		r.skip = rowIndex
-		r.index = 0
+		r.ordinal = 0
 		if r.dictOffset > 0 {
-			r.index = 1
+			index = 1
 		}
 	} else {
 		pages := r.chunk.offsetIndex.PageLocations
-		index := sort.Search(len(pages), func(i int) bool {
+		index = sort.Search(len(pages), func(i int) bool {
 			return pages[i].FirstRowIndex > rowIndex
 		}) - 1
 		if index < 0 {
 			return ErrSeekOutOfRange
 		}
-		_, err = r.section.Seek(pages[index].Offset-r.baseOffset, io.SeekStart)
+		offset = pages[index].Offset
 		r.skip = rowIndex - pages[index].FirstRowIndex
-		r.index = index
+		r.ordinal = index
+	}
+
+	if r.dictOffset > 0 && r.dataPage.dictionary == nil {
+		// Data pages cannot be decoded without the dictionary, which has to
+		// be read before seeking to the data page.
+		r.seekOffset, r.seekIndex = offset, index
+		offset, index = r.dictOffset, 0
 	}
+
+	return r.seek(offset, index)
+}
+
+func (r *filePages) seek(offset int64, index int) (err error) {
+	_, err = r.section.Seek(offset-r.baseOffset, io.SeekStart)
 	r.rbuf.Reset(r.section)
+	r.index = index
 	return err
 }
@@ -1,11 +1,16 @@
 package parquet_test
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/segmentio/parquet-go"
 )
@@ -15,7 +20,11 @@ var testdataFiles []string
 func init() {
 	entries, _ := os.ReadDir("testdata")
 	for _, e := range entries {
-		testdataFiles = append(testdataFiles, filepath.Join("testdata", e.Name()))
+		// Encrypted files (*.parquet.encrypted) can only be opened with their
+		// keys, they are tested separately.
+		if filepath.Ext(e.Name()) == ".parquet" {
+			testdataFiles = append(testdataFiles, filepath.Join("testdata", e.Name()))
+		}
 	}
 }
 
@@ -142,3 +151,415 @@ func TestFileKeyValueMetadata(t *testing.T) {
 		}
 	}
 }
+
+func TestFileMetadata(t *testing.T) {
+	type Row struct {
+		Name string `parquet:"name"`
+	}
+
+	f, err := createParquetFile(
+		makeRows([]Row{{Name: "A"}, {Name: "B"}, {Name: "C"}}),
+		parquet.CreatedBy("test version 1.0"),
+		parquet.KeyValueMetadata("hello", "world"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := f.Metadata()
+	if metadata.NumRows != 3 {
+		t.Errorf("wrong number of rows: want=3 got=%d", metadata.NumRows)
+	}
+	if metadata.CreatedBy != "test version 1.0" {
+		t.Errorf("wrong created_by: %q", metadata.CreatedBy)
+	}
+	if len(metadata.Schema) != 2 || metadata.Schema[1].Name != "name" {
+		t.Errorf("wrong schema elements: %+v", metadata.Schema)
+	}
+	if len(metadata.RowGroups) != len(f.RowGroups()) {
+		t.Errorf("wrong number of row groups: want=%d got=%d", len(f.RowGroups()), len(metadata.RowGroups))
+	}
+	if len(metadata.KeyValueMetadata) != 1 || metadata.KeyValueMetadata[0].Key != "hello" || metadata.KeyValueMetadata[0].Value != "world" {
+		t.Errorf("wrong key/value metadata: %+v", metadata.KeyValueMetadata)
+	}
+}
+
+func TestFileFieldIDs(t *testing.T) {
+	type Contact struct {
+		Name  string `parquet:"name,id(3)"`
+		Phone string `parquet:"phone,optional,id(4)"`
+	}
+	type RowV1 struct {
+		ID      int32   `parquet:"id,id(1)"`
+		Contact Contact `parquet:"contact,id(2)"`
+	}
+
+	f, err := createParquetFile(makeRows([]RowV1{
+		{ID: 1, Contact: Contact{Name: "Luke", Phone: "555"}},
+		{ID: 2, Contact: Contact{Name: "Leia"}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		path []string
+		id   int
+	}{
+		{[]string{"id"}, 1},
+		{[]string{"contact"}, 2},
+		{[]string{"contact", "name"}, 3},
+		{[]string{"contact", "phone"}, 4},
+	} {
+		col := f.Root()
+		for _, name := range test.path {
+			col = col.Column(name)
+		}
+		if id := col.FieldID(); id != test.id {
+			t.Errorf("%s: wrong field id: want %d but got %d", strings.Join(test.path, "."), test.id, id)
+		}
+	}
+
+	// Writing a file with the schema of another file must preserve the ids.
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, f.Schema())
+	if _, err := writer.WriteRowGroup(f.RowGroups()[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	output, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := f.Schema().String(), output.Schema().String(); want != got {
+		t.Errorf("schema mismatch:\nwant: %s\ngot:  %s", want, got)
+	}
+
+	// Columns are matched by id when reading the file with a schema where
+	// they were renamed and promoted.
+	type RowV2 struct {
+		Key     int64 `parquet:"key,id(1)"`
+		Contact struct {
+			FullName string `parquet:"full_name,id(3)"`
+		} `parquet:"person,id(2)"`
+	}
+	reader := parquet.NewReader(output, parquet.SchemaOf(RowV2{}))
+	for i, want := range []string{"Luke", "Leia"} {
+		row := RowV2{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row.Key != int64(i+1) || row.Contact.FullName != want {
+			t.Errorf("wrong row: want {%d %q} but got {%d %q}", i+1, want, row.Key, row.Contact.FullName)
+		}
+	}
+}
+
+func TestWriteFileSchema(t *testing.T) {
+	// Writing rows with the schema of a file must produce a file with the same
+	// schema and content, even when the original file was written by another
+	// application using legacy encodings.
+	for _, path := range []string{
+		"testdata/alltypes_dictionary.parquet",
+		"testdata/alltypes_plain.parquet",
+		"testdata/list_columns.parquet",
+		"testdata/nested_lists.snappy.parquet",
+		"testdata/nested_maps.snappy.parquet",
+		"testdata/nullable.impala.parquet",
+	} {
+		t.Run(path, func(t *testing.T) {
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			s, err := f.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := parquet.OpenFile(f, s.Size())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := readAllRows(parquet.NewReader(p))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buffer := new(bytes.Buffer)
+			writer := parquet.NewWriter(buffer, p.Schema())
+			for _, row := range rows {
+				if err := writer.WriteRow(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			output, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := p.Schema().String(), output.Schema().String(); want != got {
+				t.Errorf("schema mismatch:\nwant: %s\ngot:  %s", want, got)
+			}
+
+			found, err := readAllRows(parquet.NewReader(output))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(found) != len(rows) {
+				t.Fatalf("number of rows mismatch: want=%d got=%d", len(rows), len(found))
+			}
+			for i := range rows {
+				if !rows[i].Equal(found[i]) {
+					t.Fatalf("row %d mismatch:\nwant: %v\ngot:  %v", i, rows[i], found[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFileSchemaAnnotations(t *testing.T) {
+	// Groups of files retain their MAP and LIST annotations when they use the
+	// standard layouts, legacy layouts like the repeated "map" group written
+	// by impala are exposed as plain groups.
+	for _, test := range []struct {
+		file string
+		path []string
+		want string
+	}{
+		{file: "testdata/nested_maps.snappy.parquet", path: []string{"a"}, want: "MAP"},
+		{file: "testdata/nested_maps.snappy.parquet", path: []string{"a", "key_value", "value"}, want: "MAP"},
+		{file: "testdata/nested_lists.snappy.parquet", path: []string{"a"}, want: "LIST"},
+		{file: "testdata/nested_lists.snappy.parquet", path: []string{"a", "list", "element"}, want: "LIST"},
+		{file: "testdata/nullable.impala.parquet", path: []string{"int_array"}, want: "LIST"},
+		{file: "testdata/nullable.impala.parquet", path: []string{"int_map"}, want: ""},
+	} {
+		t.Run(test.file+":"+strings.Join(test.path, "."), func(t *testing.T) {
+			f, err := os.Open(test.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			s, err := f.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := parquet.OpenFile(f, s.Size())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			column := p.Root()
+			for _, name := range test.path {
+				if column = column.Column(name); column == nil {
+					t.Fatalf("column %q not found", name)
+				}
+			}
+
+			got := ""
+			if lt := column.Type().LogicalType(); lt != nil {
+				switch {
+				case lt.Map != nil:
+					got = "MAP"
+				case lt.List != nil:
+					got = "LIST"
+				}
+			}
+			if got != test.want {
+				t.Errorf("wrong annotation: want=%q got=%q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFileListElementNames(t *testing.T) {
+	// The file was written with the "item" name used by older versions of
+	// arrow for the elements of lists, instead of "element".
+	f, err := os.Open("testdata/list_columns.parquet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	s, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := parquet.OpenFile(f, s.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := p.Schema().Fields()[0]
+	if elem := parquet.ListElementOf(list); elem == nil || !elem.Leaf() || !elem.Optional() {
+		t.Fatalf("wrong list element: %v", elem)
+	}
+
+	rows, err := readAllRows(parquet.NewReader(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value map[string]interface{}
+	if err := p.Schema().Reconstruct(&value, rows[0]); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"int64_list": []interface{}{int64(1), int64(2), int64(3)},
+		"utf8_list":  []interface{}{"abc", "efg", "hij"},
+	}
+	if !reflect.DeepEqual(want, value) {
+		t.Errorf("row mismatch:\nwant: %#v\ngot:  %#v", want, value)
+	}
+}
+
+func readAllRows(reader *parquet.Reader) ([]parquet.Row, error) {
+	defer reader.Close()
+	var rows []parquet.Row
+	for {
+		row, err := reader.ReadRow(nil)
+		if err != nil {
+			if err == io.EOF {
+				return rows, nil
+			}
+			return rows, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+// latencyReaderAt simulates reading a file from object storage, where each
+// request has a high latency.
+type latencyReaderAt struct {
+	reader  io.ReaderAt
+	latency time.Duration
+	reads   int64
+}
+
+func (r *latencyReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	atomic.AddInt64(&r.reads, 1)
+	time.Sleep(r.latency)
+	return r.reader.ReadAt(b, off)
+}
+
+func TestFileCoalesceReads(t *testing.T) {
+	type Row struct {
+		ID    int64  `parquet:"id"`
+		Name  string `parquet:"name"`
+		Value int64  `parquet:"value"`
+	}
+
+	rows := make([]Row, 1000)
+	for i := range rows {
+		rows[i] = Row{ID: int64(i), Name: fmt.Sprintf("name-%d", i), Value: int64(i * i)}
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := writeParquetFile(buffer, makeRows(rows),
+		parquet.MaxRowsPerRowGroup(250),
+		parquet.MaxRowsPerPage(10),
+		parquet.BloomFilters(
+			parquet.SplitBlockFilter("id"),
+			parquet.SplitBlockFilter("name"),
+		),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	readFile := func(options ...parquet.FileOption) ([]parquet.Row, int64) {
+		t.Helper()
+		r := &latencyReaderAt{reader: bytes.NewReader(buffer.Bytes()), latency: 100 * time.Microsecond}
+		f, err := parquet.OpenFile(r, int64(buffer.Len()), options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, rowGroup := range f.RowGroups() {
+			for _, chunk := range rowGroup.ColumnChunks() {
+				if bf := chunk.BloomFilter(); bf != nil {
+					if _, err := bf.Check(parquet.ValueOf(int64(-1))); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		}
+		found, err := readAllRows(parquet.NewReader(f))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return found, atomic.LoadInt64(&r.reads)
+	}
+
+	for _, test := range []struct {
+		scenario string
+		options  []parquet.FileOption
+	}{
+		{
+			scenario: "all columns",
+		},
+		{
+			scenario: "selected columns",
+			options:  []parquet.FileOption{parquet.SelectColumns([]string{"id"}, []string{"value"})},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			want, baseReads := readFile(test.options...)
+
+			for _, options := range [][]parquet.FileOption{
+				{parquet.ReadFooterSize(64 * 1024)},
+				{parquet.CoalesceReads(1024, 64*1024)},
+				{parquet.CoalesceReads(0, 1), parquet.ReadRangeConcurrency(1)},
+				{parquet.ReadFooterSize(1024), parquet.CoalesceReads(64*1024, 1024*1024)},
+			} {
+				got, reads := readFile(append(options, test.options...)...)
+				if reads >= baseReads {
+					t.Errorf("the number of reads was not reduced: %d >= %d", reads, baseReads)
+				}
+				if len(got) != len(want) {
+					t.Fatalf("number of rows mismatch: want=%d got=%d", len(want), len(got))
+				}
+				for i := range want {
+					if !want[i].Equal(got[i]) {
+						t.Fatalf("row %d mismatch:\nwant: %v\ngot:  %v", i, want[i], got[i])
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("read footer in a single request", func(t *testing.T) {
+		r := &latencyReaderAt{reader: bytes.NewReader(buffer.Bytes())}
+		f, err := parquet.OpenFile(r, int64(buffer.Len()),
+			parquet.ReadFooterSize(int64(buffer.Len())),
+			parquet.SkipBloomFilters(true),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reads := atomic.LoadInt64(&r.reads); reads != 1 {
+			t.Errorf("opening the file issued %d reads instead of one", reads)
+		}
+		if len(f.ColumnIndexes()) == 0 {
+			t.Error("the page index was not loaded")
+		}
+
+		// The end of the file is only held in memory while the file is
+		// opened, reading rows must issue new reads.
+		if _, err := readAllRows(parquet.NewReader(f)); err != nil {
+			t.Fatal(err)
+		}
+		if reads := atomic.LoadInt64(&r.reads); reads == 1 {
+			t.Error("rows were read from the end of the file loaded when opening it")
+		}
+	})
+}
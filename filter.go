@@ -0,0 +1,894 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Filter is an interface representing predicates on the rows of parquet files.
+//
+// Filters are pushed down to readers to skip data that cannot contain matching
+// rows: row groups are eliminated using the statistics of their column chunks,
+// pages are eliminated using the column and offset indexes, and equality
+// predicates are tested against the bloom filters of column chunks.
+//
+// Filters are built by combining the expressions returned by the Eq, In, Lt,
+// LtEq, Gt, GtEq, IsNull, And, Or, and Not functions, and are applied to
+// readers with the FilterRows option; for example:
+//
+//	reader := parquet.NewReader(file, parquet.FilterRows(
+//		parquet.And(
+//			parquet.Eq([]string{"country"}, parquet.ValueOf("FR")),
+//			parquet.GtEq([]string{"age"}, parquet.ValueOf(18)),
+//		),
+//	))
+//
+// Column paths are the sequence of names leading to a leaf column of the
+// schema (not including the root). When the column is repeated, a predicate
+// holds for a row if at least one of the values of the column in that row
+// satisfies it.
+type Filter interface {
+	// Returns a human-readable representation of the filter.
+	String() string
+
+	// Resolves the columns referenced by the filter in the given schema.
+	bind(schema *Schema) (boundFilter, error)
+}
+
+// Eq constructs a filter matching rows where the column at the given path is
+// equal to value.
+func Eq(path []string, value Value) Filter { return newColumnFilter(filterEq, path, value) }
+
+// In constructs a filter matching rows where the column at the given path is
+// equal to one of the values.
+func In(path []string, values ...Value) Filter { return newColumnFilter(filterIn, path, values...) }
+
+// Lt constructs a filter matching rows where the column at the given path is
+// less than value.
+func Lt(path []string, value Value) Filter { return newColumnFilter(filterLt, path, value) }
+
+// LtEq constructs a filter matching rows where the column at the given path is
+// less than or equal to value.
+func LtEq(path []string, value Value) Filter { return newColumnFilter(filterLtEq, path, value) }
+
+// Gt constructs a filter matching rows where the column at the given path is
+// greater than value.
+func Gt(path []string, value Value) Filter { return newColumnFilter(filterGt, path, value) }
+
+// GtEq constructs a filter matching rows where the column at the given path is
+// greater than or equal to value.
+func GtEq(path []string, value Value) Filter { return newColumnFilter(filterGtEq, path, value) }
+
+// IsNull constructs a filter matching rows where the column at the given path
+// is null.
+func IsNull(path []string) Filter { return newColumnFilter(filterIsNull, path) }
+
+// And constructs a filter matching rows which match all the filters passed as
+// arguments.
+//
+// With no arguments, the filter matches all rows.
+func And(filters ...Filter) Filter { return andFilter(append([]Filter{}, filters...)) }
+
+// Or constructs a filter matching rows which match at least one of the filters
+// passed as arguments.
+//
+// With no arguments, the filter matches no rows.
+func Or(filters ...Filter) Filter { return orFilter(append([]Filter{}, filters...)) }
+
+// Not constructs a filter matching rows which do not match the filter passed
+// as argument.
+func Not(filter Filter) Filter { return notFilter{filter} }
+
+type filterOp int8
+
+const (
+	filterEq filterOp = iota
+	filterIn
+	filterLt
+	filterLtEq
+	filterGt
+	filterGtEq
+	filterIsNull
+)
+
+func (op filterOp) String() string {
+	switch op {
+	case filterEq:
+		return "="
+	case filterIn:
+		return "IN"
+	case filterLt:
+		return "<"
+	case filterLtEq:
+		return "<="
+	case filterGt:
+		return ">"
+	case filterGtEq:
+		return ">="
+	default:
+		return "IS NULL"
+	}
+}
+
+type columnFilter struct {
+	op     filterOp
+	path   columnPath
+	values []Value
+}
+
+func newColumnFilter(op filterOp, path []string, values ...Value) *columnFilter {
+	f := &columnFilter{
+		op:     op,
+		path:   append(columnPath{}, path...),
+		values: make([]Value, len(values)),
+	}
+	for i, v := range values {
+		f.values[i] = v.Clone()
+	}
+	return f
+}
+
+func (f *columnFilter) String() string {
+	switch f.op {
+	case filterIsNull:
+		return fmt.Sprintf("%s %s", f.path, f.op)
+	case filterIn:
+		values := make([]string, len(f.values))
+		for i, v := range f.values {
+			values[i] = v.String()
+		}
+		return fmt.Sprintf("%s %s (%s)", f.path, f.op, strings.Join(values, ", "))
+	default:
+		return fmt.Sprintf("%s %s %s", f.path, f.op, f.values[0])
+	}
+}
+
+func (f *columnFilter) bind(schema *Schema) (boundFilter, error) {
+	leaf, ok := schema.Lookup(f.path...)
+	if !ok {
+		return nil, fmt.Errorf("cannot apply filter %s: %w: %s", f, ErrColumnNotFound, f.path)
+	}
+	typ := leaf.Node.Type()
+	values := make([]Value, len(f.values))
+	for i, v := range f.values {
+		c, err := convertFilterValue(typ, v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot apply filter %s: %w", f, err)
+		}
+		values[i] = c
+	}
+	return &boundColumnFilter{
+		op:          f.op,
+		columnIndex: leaf.ColumnIndex,
+		typ:         typ,
+		values:      values,
+	}, nil
+}
+
+func convertFilterValue(typ Type, v Value) (Value, error) {
+	kind := typ.Kind()
+	switch v.Kind() {
+	case kind:
+		return v, nil
+	case Int32:
+		switch kind {
+		case Int64:
+			return makeValueInt64(int64(v.Int32())), nil
+		case Float:
+			return makeValueFloat(float32(v.Int32())), nil
+		case Double:
+			return makeValueDouble(float64(v.Int32())), nil
+		}
+	case Int64:
+		switch kind {
+		case Int32:
+			if i := v.Int64(); i >= math.MinInt32 && i <= math.MaxInt32 {
+				return makeValueInt32(int32(i)), nil
+			}
+		case Float:
+			return makeValueFloat(float32(v.Int64())), nil
+		case Double:
+			return makeValueDouble(float64(v.Int64())), nil
+		}
+	case Float:
+		if kind == Double {
+			return makeValueDouble(float64(v.Float())), nil
+		}
+	case Double:
+		if kind == Float {
+			return makeValueFloat(float32(v.Double())), nil
+		}
+	case ByteArray:
+		if kind == FixedLenByteArray && len(v.ByteArray()) == typ.Length() {
+			return makeValueBytes(kind, v.ByteArray()), nil
+		}
+	case FixedLenByteArray:
+		if kind == ByteArray {
+			return makeValueBytes(kind, v.ByteArray()), nil
+		}
+	}
+	if v.IsNull() {
+		return v, fmt.Errorf("cannot compare null values to column values of type %s", typ)
+	}
+	return v, fmt.Errorf("cannot compare values of kind %s to column values of type %s", v.Kind(), typ)
+}
+
+type andFilter []Filter
+
+func (f andFilter) String() string { return joinFilters(f, " AND ") }
+
+func (f andFilter) bind(schema *Schema) (boundFilter, error) {
+	filters, err := bindFilters(f, schema)
+	return boundAndFilter(filters), err
+}
+
+type orFilter []Filter
+
+func (f orFilter) String() string { return joinFilters(f, " OR ") }
+
+func (f orFilter) bind(schema *Schema) (boundFilter, error) {
+	filters, err := bindFilters(f, schema)
+	return boundOrFilter(filters), err
+}
+
+type notFilter struct{ filter Filter }
+
+func (f notFilter) String() string { return "NOT (" + f.filter.String() + ")" }
+
+func (f notFilter) bind(schema *Schema) (boundFilter, error) {
+	filter, err := f.filter.bind(schema)
+	return boundNotFilter{filter}, err
+}
+
+func joinFilters(filters []Filter, sep string) string {
+	s := make([]string, len(filters))
+	for i, f := range filters {
+		s[i] = "(" + f.String() + ")"
+	}
+	return strings.Join(s, sep)
+}
+
+func bindFilters(filters []Filter, schema *Schema) ([]boundFilter, error) {
+	bound := make([]boundFilter, len(filters))
+	for i, f := range filters {
+		b, err := f.bind(schema)
+		if err != nil {
+			return nil, err
+		}
+		bound[i] = b
+	}
+	return bound, nil
+}
+
+// filterMatch represents the result of evaluating a filter on a set of rows
+// using the metadata describing the column values.
+type filterMatch int8
+
+const (
+	filterMatchNone filterMatch = iota // no rows match
+	filterMatchSome                    // some rows may match
+	filterMatchAll                     // all rows match
+)
+
+func (m filterMatch) and(other filterMatch) filterMatch {
+	if m < other {
+		return m
+	}
+	return other
+}
+
+func (m filterMatch) or(other filterMatch) filterMatch {
+	if m > other {
+		return m
+	}
+	return other
+}
+
+func (m filterMatch) not() filterMatch { return filterMatchAll - m }
+
+// filterRange is a range of rows within a row group associated with the result
+// of evaluating a filter on the rows. Filters produce sorted lists of ranges
+// covering all the rows of a row group.
+type filterRange struct {
+	first int64 // index of the first row in the range
+	last  int64 // index of the row following the last row of the range
+	match filterMatch
+}
+
+func appendFilterRange(ranges []filterRange, r filterRange) []filterRange {
+	if r.first == r.last {
+		return ranges
+	}
+	if n := len(ranges) - 1; n >= 0 && ranges[n].match == r.match && ranges[n].last == r.first {
+		ranges[n].last = r.last
+		return ranges
+	}
+	return append(ranges, r)
+}
+
+func combineFilterRanges(ranges1, ranges2 []filterRange, combine func(filterMatch, filterMatch) filterMatch) []filterRange {
+	ranges := make([]filterRange, 0, len(ranges1)+len(ranges2))
+	first := int64(0)
+
+	for i, j := 0, 0; i < len(ranges1) && j < len(ranges2); {
+		r1, r2 := ranges1[i], ranges2[j]
+		last := r1.last
+		if r2.last < last {
+			last = r2.last
+		}
+		ranges = appendFilterRange(ranges, filterRange{
+			first: first,
+			last:  last,
+			match: combine(r1.match, r2.match),
+		})
+		first = last
+		if r1.last == last {
+			i++
+		}
+		if r2.last == last {
+			j++
+		}
+	}
+
+	return ranges
+}
+
+// boundFilter is the representation of filters after resolving the columns
+// that they apply to in a schema.
+type boundFilter interface {
+	// Evaluates the filter on the rows of a row group using the metadata of
+	// the column chunks, returning the list of row ranges and the result of
+	// evaluating the filter on each of them.
+	rowRanges(numRows int64, columns []ColumnChunk) []filterRange
+
+	// Evaluates the filter on the values of a row.
+	matchRow(row Row) bool
+}
+
+type boundColumnFilter struct {
+	op          filterOp
+	columnIndex int
+	typ         Type
+	values      []Value
+}
+
+func (f *boundColumnFilter) matchRow(row Row) bool {
+	columnIndex := ^int16(f.columnIndex)
+	for _, v := range row {
+		if v.columnIndex == columnIndex && f.matchValue(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *boundColumnFilter) matchValue(v Value) bool {
+	if v.IsNull() {
+		return f.op == filterIsNull
+	}
+	switch f.op {
+	case filterEq, filterIn:
+		for _, x := range f.values {
+			if f.typ.Compare(v, x) == 0 {
+				return true
+			}
+		}
+		return false
+	case filterLt:
+		return f.typ.Compare(v, f.values[0]) < 0
+	case filterLtEq:
+		return f.typ.Compare(v, f.values[0]) <= 0
+	case filterGt:
+		return f.typ.Compare(v, f.values[0]) > 0
+	case filterGtEq:
+		return f.typ.Compare(v, f.values[0]) >= 0
+	default:
+		return false
+	}
+}
+
+func (f *boundColumnFilter) rowRanges(numRows int64, columns []ColumnChunk) []filterRange {
+	chunk := columns[f.columnIndex]
+	match := filterMatchSome
+
+	if stats, ok := columnChunkStatsOf(chunk); ok {
+		match = f.matchStats(&stats)
+	}
+
+	if match == filterMatchSome && (f.op == filterEq || f.op == filterIn) {
+		if bloomFilter := chunk.BloomFilter(); bloomFilter != nil {
+			match = f.matchBloomFilter(bloomFilter)
+		}
+	}
+
+	if match == filterMatchSome {
+		if ranges := f.pageRanges(numRows, chunk); ranges != nil {
+			return ranges
+		}
+	}
+
+	return appendFilterRange(nil, filterRange{first: 0, last: numRows, match: match})
+}
+
+func (f *boundColumnFilter) pageRanges(numRows int64, chunk ColumnChunk) []filterRange {
+	columnIndex := chunk.ColumnIndex()
+	if columnIndex == nil {
+		return nil
+	}
+	numPages := columnIndex.NumPages()
+	if numPages == 0 {
+		return nil
+	}
+
+	stats := filterStats{
+		numValues:    -1,
+		hasBounds:    chunk.Type().ColumnOrder() != nil,
+		hasNullCount: columnIndexHasNullCounts(columnIndex),
+	}
+
+	matchPage := func(i int) filterMatch {
+		stats.nullPage = columnIndex.NullPage(i)
+		stats.nullCount = columnIndex.NullCount(i)
+		stats.minValue = columnIndex.MinValue(i)
+		stats.maxValue = columnIndex.MaxValue(i)
+		return f.matchStats(&stats)
+	}
+
+	offsetIndex := chunk.OffsetIndex()
+	if offsetIndex == nil || offsetIndex.NumPages() != numPages {
+		// Without the offset index, the pages cannot be mapped to row ranges,
+		// but the column index may still tell whether the column chunk can
+		// contain matching rows.
+		match := matchPage(0)
+		for i := 1; i < numPages; i++ {
+			if matchPage(i) != match {
+				match = filterMatchSome
+				break
+			}
+		}
+		return appendFilterRange(nil, filterRange{first: 0, last: numRows, match: match})
+	}
+
+	ranges := make([]filterRange, 0, numPages)
+	ranges = appendFilterRange(ranges, filterRange{
+		first: 0,
+		last:  offsetIndex.FirstRowIndex(0),
+		match: filterMatchSome,
+	})
+
+	for i := 0; i < numPages; i++ {
+		first := offsetIndex.FirstRowIndex(i)
+		last := numRows
+		if i+1 < numPages {
+			last = offsetIndex.FirstRowIndex(i + 1)
+		}
+		ranges = appendFilterRange(ranges, filterRange{
+			first: first,
+			last:  last,
+			match: matchPage(i),
+		})
+	}
+
+	return ranges
+}
+
+func (f *boundColumnFilter) matchBloomFilter(bloomFilter BloomFilter) filterMatch {
+	for _, v := range f.values {
+		if ok, err := bloomFilter.Check(v); ok || err != nil {
+			return filterMatchSome
+		}
+	}
+	return filterMatchNone
+}
+
+func (f *boundColumnFilter) matchStats(stats *filterStats) filterMatch {
+	noNulls := stats.hasNullCount && stats.nullCount == 0
+	allNulls := stats.nullPage || (stats.hasNullCount && stats.nullCount == stats.numValues)
+
+	if f.op == filterIsNull {
+		switch {
+		case noNulls:
+			return filterMatchNone
+		case allNulls:
+			return filterMatchAll
+		default:
+			return filterMatchSome
+		}
+	}
+
+	if allNulls {
+		return filterMatchNone
+	}
+	if !stats.hasBounds {
+		return filterMatchSome
+	}
+
+	minValue, maxValue := stats.minValue, stats.maxValue
+	// Bounds of byte arrays may have been truncated, in which case they do
+	// not tell whether all values are equal.
+	exactBounds := f.typ.Kind() != ByteArray && f.typ.Kind() != FixedLenByteArray
+
+	switch f.op {
+	case filterEq, filterIn:
+		match := filterMatchNone
+		for _, v := range f.values {
+			if f.typ.Compare(v, minValue) < 0 || f.typ.Compare(v, maxValue) > 0 {
+				continue
+			}
+			if noNulls && exactBounds && f.typ.Compare(minValue, maxValue) == 0 {
+				return filterMatchAll
+			}
+			match = filterMatchSome
+		}
+		return match
+	case filterLt:
+		return matchBounds(f.typ.Compare(minValue, f.values[0]) >= 0, noNulls && f.typ.Compare(maxValue, f.values[0]) < 0)
+	case filterLtEq:
+		return matchBounds(f.typ.Compare(minValue, f.values[0]) > 0, noNulls && f.typ.Compare(maxValue, f.values[0]) <= 0)
+	case filterGt:
+		return matchBounds(f.typ.Compare(maxValue, f.values[0]) <= 0, noNulls && f.typ.Compare(minValue, f.values[0]) > 0)
+	case filterGtEq:
+		return matchBounds(f.typ.Compare(maxValue, f.values[0]) < 0, noNulls && f.typ.Compare(minValue, f.values[0]) >= 0)
+	default:
+		return filterMatchSome
+	}
+}
+
+func matchBounds(none, all bool) filterMatch {
+	switch {
+	case none:
+		return filterMatchNone
+	case all:
+		return filterMatchAll
+	default:
+		return filterMatchSome
+	}
+}
+
+type boundAndFilter []boundFilter
+
+func (f boundAndFilter) rowRanges(numRows int64, columns []ColumnChunk) []filterRange {
+	ranges := appendFilterRange(nil, filterRange{first: 0, last: numRows, match: filterMatchAll})
+	for _, filter := range f {
+		ranges = combineFilterRanges(ranges, filter.rowRanges(numRows, columns), filterMatch.and)
+	}
+	return ranges
+}
+
+func (f boundAndFilter) matchRow(row Row) bool {
+	for _, filter := range f {
+		if !filter.matchRow(row) {
+			return false
+		}
+	}
+	return true
+}
+
+type boundOrFilter []boundFilter
+
+func (f boundOrFilter) rowRanges(numRows int64, columns []ColumnChunk) []filterRange {
+	ranges := appendFilterRange(nil, filterRange{first: 0, last: numRows, match: filterMatchNone})
+	for _, filter := range f {
+		ranges = combineFilterRanges(ranges, filter.rowRanges(numRows, columns), filterMatch.or)
+	}
+	return ranges
+}
+
+func (f boundOrFilter) matchRow(row Row) bool {
+	for _, filter := range f {
+		if filter.matchRow(row) {
+			return true
+		}
+	}
+	return false
+}
+
+type boundNotFilter struct{ filter boundFilter }
+
+func (f boundNotFilter) rowRanges(numRows int64, columns []ColumnChunk) []filterRange {
+	ranges := f.filter.rowRanges(numRows, columns)
+	for i := range ranges {
+		ranges[i].match = ranges[i].match.not()
+	}
+	return ranges
+}
+
+func (f boundNotFilter) matchRow(row Row) bool { return !f.filter.matchRow(row) }
+
+// filterStats carries the metadata of column values that filters are evaluated
+// against, either from the statistics of column chunks or from column indexes.
+type filterStats struct {
+	minValue     Value
+	maxValue     Value
+	nullCount    int64
+	numValues    int64 // -1 if unknown
+	nullPage     bool  // all values are null
+	hasBounds    bool
+	hasNullCount bool
+}
+
+// columnChunkStatsOf returns the statistics of a column chunk. Column chunks
+// read from files expose the statistics recorded in their metadata, which are
+// forwarded by the column chunks wrapping them; other column chunks have their
+// statistics aggregated from their column index.
+func columnChunkStatsOf(chunk ColumnChunk) (filterStats, bool) {
+	if c, ok := chunk.(interface{ chunkStats() (filterStats, bool) }); ok {
+		if stats, ok := c.chunkStats(); ok {
+			return stats, true
+		}
+	}
+	return columnIndexStatsOf(chunk)
+}
+
+func columnIndexStatsOf(chunk ColumnChunk) (stats filterStats, ok bool) {
+	columnIndex := chunk.ColumnIndex()
+	if columnIndex == nil {
+		return stats, false
+	}
+	numPages := columnIndex.NumPages()
+	if numPages == 0 {
+		return stats, false
+	}
+
+	typ := chunk.Type()
+	stats.numValues = -1
+	stats.nullPage = true
+	stats.hasNullCount = columnIndexHasNullCounts(columnIndex)
+
+	for i := 0; i < numPages; i++ {
+		stats.nullCount += columnIndex.NullCount(i)
+		if columnIndex.NullPage(i) {
+			continue
+		}
+		minValue, maxValue := columnIndex.MinValue(i), columnIndex.MaxValue(i)
+		if stats.nullPage {
+			stats.minValue, stats.maxValue = minValue, maxValue
+			stats.nullPage = false
+			continue
+		}
+		if typ.Compare(minValue, stats.minValue) < 0 {
+			stats.minValue = minValue
+		}
+		if typ.Compare(maxValue, stats.maxValue) > 0 {
+			stats.maxValue = maxValue
+		}
+	}
+
+	// The bounds of columns with an undefined sort order cannot be used to
+	// determine whether values match the filter.
+	stats.hasBounds = !stats.nullPage && typ.ColumnOrder() != nil
+	return stats, true
+}
+
+func (c *fileColumnChunk) chunkStats() (stats filterStats, ok bool) {
+	metadata := &c.chunk.MetaData
+	statistics := &metadata.Statistics
+	if statistics.MinValue == nil && statistics.MaxValue == nil && statistics.NullCount == 0 {
+		return stats, false
+	}
+
+	stats.nullCount = statistics.NullCount
+	stats.numValues = metadata.NumValues
+	stats.hasNullCount = statistics.NullCount != 0 || writerSetsNullCount(c.file.metadata.CreatedBy)
+
+	if statistics.MinValue != nil && statistics.MaxValue != nil && c.column.Type().ColumnOrder() != nil {
+		kind := c.column.Type().Kind()
+		minValue, err1 := parseValue(kind, statistics.MinValue)
+		maxValue, err2 := parseValue(kind, statistics.MaxValue)
+		if err1 == nil && err2 == nil {
+			stats.minValue = minValue
+			stats.maxValue = maxValue
+			stats.hasBounds = true
+		}
+	}
+
+	return stats, true
+}
+
+func (c *projectedColumnChunk) chunkStats() (filterStats, bool) { return columnChunkStatsOf(c.base) }
+
+func (c *prefetchedColumnChunk) chunkStats() (filterStats, bool) {
+	return columnChunkStatsOf(c.ColumnChunk)
+}
+
+func (c *seekColumnChunk) chunkStats() (filterStats, bool) { return columnChunkStatsOf(c.base) }
+
+// The null_count field of column chunk statistics is optional, and since the
+// decoded statistics do not tell whether it was present, a count of zero may
+// mean that the writer did not set it. Zero counts are only trusted for files
+// produced by writers which are known to always set the field.
+var nullCountWriters = [...]string{
+	"parquet-mr",
+	"parquet-cpp",
+	"parquet-rs",
+}
+
+func writerSetsNullCount(createdBy string) bool {
+	for _, writer := range nullCountWriters {
+		if strings.HasPrefix(createdBy, writer+" ") || strings.HasPrefix(createdBy, writer+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+func columnIndexHasNullCounts(columnIndex ColumnIndex) bool {
+	switch index := columnIndex.(type) {
+	case fileColumnIndex:
+		return len(index.chunk.columnIndex.NullCounts) > 0
+	case *formatColumnIndex:
+		return len(index.index.NullCounts) > 0
+	default:
+		return true
+	}
+}
+
+// filterRowGroups returns the list of row groups which may contain rows
+// matching the filter. Row groups where only some of the pages may contain
+// matching rows are replaced by views exposing only the rows of those pages.
+func filterRowGroups(rowGroups []RowGroup, filter Filter) ([]RowGroup, error) {
+	filtered := make([]RowGroup, 0, len(rowGroups))
+
+	for _, rowGroup := range rowGroups {
+		f, err := filter.bind(rowGroup.Schema())
+		if err != nil {
+			return nil, err
+		}
+
+		numRows := rowGroup.NumRows()
+		ranges := f.rowRanges(numRows, rowGroup.ColumnChunks())
+		rows := make([]rowRange, 0, len(ranges))
+
+		for _, r := range ranges {
+			if r.match != filterMatchNone {
+				if n := len(rows) - 1; n >= 0 && rows[n].last == r.first {
+					rows[n].last = r.last
+				} else {
+					rows = append(rows, rowRange{first: r.first, last: r.last})
+				}
+			}
+		}
+
+		switch {
+		case len(rows) == 0:
+		case len(rows) == 1 && rows[0].first == 0 && rows[0].last == numRows:
+			filtered = append(filtered, rowGroup)
+		default:
+			filtered = append(filtered, newFilteredRowGroup(rowGroup, rows))
+		}
+	}
+
+	return filtered, nil
+}
+
+// rowRange is a range of rows retained after filtering a row group.
+type rowRange struct {
+	first int64
+	last  int64
+}
+
+// filteredRowGroup is a view of a row group exposing only the rows within a
+// list of ranges. The column chunks of the row group skip the pages outside of
+// the ranges so they are never decoded.
+type filteredRowGroup struct {
+	base    RowGroup
+	ranges  []rowRange
+	numRows int64
+	columns []ColumnChunk
+}
+
+func newFilteredRowGroup(base RowGroup, ranges []rowRange) *filteredRowGroup {
+	baseColumns := base.ColumnChunks()
+	g := &filteredRowGroup{
+		base:    base,
+		ranges:  ranges,
+		columns: make([]ColumnChunk, len(baseColumns)),
+	}
+	for _, r := range ranges {
+		g.numRows += r.last - r.first
+	}
+	columns := make([]filteredColumnChunk, len(baseColumns))
+	for i, column := range baseColumns {
+		columns[i].base = column
+		columns[i].ranges = ranges
+		g.columns[i] = &columns[i]
+	}
+	return g
+}
+
+func (g *filteredRowGroup) NumRows() int64                  { return g.numRows }
+func (g *filteredRowGroup) ColumnChunks() []ColumnChunk     { return g.columns }
+func (g *filteredRowGroup) Schema() *Schema                 { return g.base.Schema() }
+func (g *filteredRowGroup) SortingColumns() []SortingColumn { return g.base.SortingColumns() }
+func (g *filteredRowGroup) Rows() Rows                      { return &rowGroupRowReader{rowGroup: g} }
+
+type filteredColumnChunk struct {
+	base   ColumnChunk
+	ranges []rowRange
+}
+
+func (c *filteredColumnChunk) Type() Type   { return c.base.Type() }
+func (c *filteredColumnChunk) Column() int  { return c.base.Column() }
+func (c *filteredColumnChunk) Pages() Pages { return &filteredPages{ranges: c.ranges, column: c.base} }
+
+// The page index describes the pages of the base column chunk, which do not
+// match the rows exposed by the filtered view.
+func (c *filteredColumnChunk) ColumnIndex() ColumnIndex { return nil }
+func (c *filteredColumnChunk) OffsetIndex() OffsetIndex { return nil }
+func (c *filteredColumnChunk) BloomFilter() BloomFilter { return c.base.BloomFilter() }
+func (c *filteredColumnChunk) NumValues() int64         { return c.base.NumValues() }
+
+type filteredPages struct {
+	ranges []rowRange
+	column ColumnChunk
+	pages  Pages
+	index  int   // index of the current range
+	row    int64 // index of the next row to read in the base column chunk
+	seek   bool  // whether the base pages must be positioned at row
+}
+
+func (p *filteredPages) ReadPage() (Page, error) {
+	for p.index < len(p.ranges) {
+		r := p.ranges[p.index]
+
+		if p.row < r.first {
+			p.row, p.seek = r.first, true
+		}
+		if p.row >= r.last {
+			p.index++
+			continue
+		}
+
+		if p.pages == nil {
+			p.pages, p.seek = p.column.Pages(), true
+		}
+		if p.seek {
+			if err := p.pages.SeekToRow(p.row); err != nil {
+				return nil, err
+			}
+			p.seek = false
+		}
+
+		page, err := p.pages.ReadPage()
+		if err != nil {
+			return nil, err
+		}
+
+		numRows := page.NumRows()
+		if p.row+numRows > r.last {
+			// The page crosses the end of the range, only the rows up to the
+			// end of the range are exposed, and the base pages are positioned
+			// at the beginning of the next range before reading more pages.
+			page = page.Buffer().Slice(0, r.last-p.row)
+			p.row, p.seek = r.last, true
+		} else {
+			p.row += numRows
+		}
+		return page, nil
+	}
+	return nil, io.EOF
+}
+
+func (p *filteredPages) SeekToRow(rowIndex int64) error {
+	if rowIndex < 0 {
+		return ErrSeekOutOfRange
+	}
+	p.index, p.seek = len(p.ranges), true
+	for i, r := range p.ranges {
+		if n := r.last - r.first; rowIndex < n {
+			p.index, p.row = i, r.first+rowIndex
+			break
+		} else {
+			rowIndex -= n
+		}
+	}
+	return nil
+}
+
+var (
+	_ RowGroup    = (*filteredRowGroup)(nil)
+	_ ColumnChunk = (*filteredColumnChunk)(nil)
+	_ Pages       = (*filteredPages)(nil)
+)
@@ -0,0 +1,479 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/format"
+)
+
+type filterRow struct {
+	ID    int64  `parquet:"id"`
+	Group string `parquet:"group"`
+	Score *int32 `parquet:"score,optional"`
+}
+
+func makeFilterRows(numRows int) []filterRow {
+	rows := make([]filterRow, numRows)
+	for i := range rows {
+		rows[i].ID = int64(i)
+		rows[i].Group = fmt.Sprintf("g%02d", i/100)
+		if i%7 != 0 {
+			score := int32(i % 50)
+			rows[i].Score = &score
+		}
+	}
+	return rows
+}
+
+func writeFilterRows(rows []filterRow, rowGroupSize int) (*bytes.Reader, error) {
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer,
+		parquet.PageBufferSize(256),
+		parquet.BloomFilters(parquet.SplitBlockFilter("group")),
+	)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			return nil, err
+		}
+		if (i+1)%rowGroupSize == 0 {
+			if err := writer.Flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(buffer.Bytes()), nil
+}
+
+func TestFilterRows(t *testing.T) {
+	score := func(row filterRow) int32 { return *row.Score }
+
+	tests := []struct {
+		scenario string
+		filter   parquet.Filter
+		match    func(filterRow) bool
+		maxRows  int64
+	}{
+		{
+			scenario: "eq",
+			filter:   parquet.Eq([]string{"id"}, parquet.ValueOf(int64(42))),
+			match:    func(row filterRow) bool { return row.ID == 42 },
+			maxRows:  250,
+		},
+		{
+			scenario: "eq with int32 value on int64 column",
+			filter:   parquet.Eq([]string{"id"}, parquet.ValueOf(int32(512))),
+			match:    func(row filterRow) bool { return row.ID == 512 },
+			maxRows:  250,
+		},
+		{
+			scenario: "eq with no matches",
+			filter:   parquet.Eq([]string{"id"}, parquet.ValueOf(int64(-1))),
+			match:    func(row filterRow) bool { return false },
+			maxRows:  0,
+		},
+		{
+			scenario: "in",
+			filter:   parquet.In([]string{"id"}, parquet.ValueOf(int64(1)), parquet.ValueOf(int64(999))),
+			match:    func(row filterRow) bool { return row.ID == 1 || row.ID == 999 },
+			maxRows:  500,
+		},
+		{
+			scenario: "bloom filter",
+			filter:   parquet.Eq([]string{"group"}, parquet.ValueOf("g05")),
+			match:    func(row filterRow) bool { return row.Group == "g05" },
+			maxRows:  500,
+		},
+		{
+			scenario: "lt",
+			filter:   parquet.Lt([]string{"id"}, parquet.ValueOf(int64(100))),
+			match:    func(row filterRow) bool { return row.ID < 100 },
+			maxRows:  250,
+		},
+		{
+			scenario: "lteq",
+			filter:   parquet.LtEq([]string{"id"}, parquet.ValueOf(int64(100))),
+			match:    func(row filterRow) bool { return row.ID <= 100 },
+			maxRows:  250,
+		},
+		{
+			scenario: "gt",
+			filter:   parquet.Gt([]string{"id"}, parquet.ValueOf(int64(900))),
+			match:    func(row filterRow) bool { return row.ID > 900 },
+			maxRows:  250,
+		},
+		{
+			scenario: "gteq",
+			filter:   parquet.GtEq([]string{"id"}, parquet.ValueOf(int64(900))),
+			match:    func(row filterRow) bool { return row.ID >= 900 },
+			maxRows:  250,
+		},
+		{
+			scenario: "is null",
+			filter:   parquet.IsNull([]string{"score"}),
+			match:    func(row filterRow) bool { return row.Score == nil },
+			maxRows:  1000,
+		},
+		{
+			scenario: "and",
+			filter: parquet.And(
+				parquet.GtEq([]string{"id"}, parquet.ValueOf(int64(300))),
+				parquet.Lt([]string{"id"}, parquet.ValueOf(int64(400))),
+				parquet.Gt([]string{"score"}, parquet.ValueOf(int32(40))),
+			),
+			match: func(row filterRow) bool {
+				return row.ID >= 300 && row.ID < 400 && row.Score != nil && score(row) > 40
+			},
+			maxRows: 250,
+		},
+		{
+			scenario: "or",
+			filter: parquet.Or(
+				parquet.Lt([]string{"id"}, parquet.ValueOf(int64(10))),
+				parquet.Gt([]string{"id"}, parquet.ValueOf(int64(990))),
+			),
+			match:   func(row filterRow) bool { return row.ID < 10 || row.ID > 990 },
+			maxRows: 500,
+		},
+		{
+			scenario: "not",
+			filter:   parquet.Not(parquet.GtEq([]string{"id"}, parquet.ValueOf(int64(10)))),
+			match:    func(row filterRow) bool { return row.ID < 10 },
+			maxRows:  250,
+		},
+		{
+			scenario: "not null",
+			filter:   parquet.Not(parquet.IsNull([]string{"score"})),
+			match:    func(row filterRow) bool { return row.Score != nil },
+			maxRows:  1000,
+		},
+	}
+
+	rows := makeFilterRows(1000)
+	input, err := writeFilterRows(rows, 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			want := []filterRow{}
+			for _, row := range rows {
+				if test.match(row) {
+					want = append(want, row)
+				}
+			}
+
+			reader := parquet.NewReader(input, parquet.FilterRows(test.filter))
+			if numRows := reader.NumRows(); numRows < int64(len(want)) || numRows > test.maxRows {
+				t.Errorf("wrong number of rows to read: want=[%d,%d] got=%d", len(want), test.maxRows, numRows)
+			}
+
+			got := []filterRow{}
+			for {
+				row := filterRow{}
+				if err := reader.Read(&row); err != nil {
+					if err != io.EOF {
+						t.Fatal(err)
+					}
+					break
+				}
+				got = append(got, row)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("rows mismatch: want=%d rows got=%d rows", len(want), len(got))
+			}
+
+			reader.Reset()
+			numRows := 0
+			for {
+				_, err := reader.ReadRow(nil)
+				if err != nil {
+					if err != io.EOF {
+						t.Fatal(err)
+					}
+					break
+				}
+				numRows++
+			}
+
+			if numRows != len(want) {
+				t.Errorf("wrong number of rows read with ReadRow: want=%d got=%d", len(want), numRows)
+			}
+		})
+	}
+}
+
+func TestFilterRowsRowGroupReader(t *testing.T) {
+	rows := makeFilterRows(1000)
+	input, err := writeFilterRows(rows, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(input, input.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewRowGroupReader(f.RowGroups()[0],
+		parquet.FilterRows(parquet.Eq([]string{"id"}, parquet.ValueOf(int64(777)))),
+	)
+
+	if numRows := reader.NumRows(); numRows == 0 || numRows >= 1000 {
+		t.Errorf("pages were not skipped: %d rows to read", numRows)
+	}
+
+	row := filterRow{}
+	if err := reader.Read(&row); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(row, rows[777]) {
+		t.Errorf("row mismatch: want=%+v got=%+v", rows[777], row)
+	}
+	if err := reader.Read(&row); err != io.EOF {
+		t.Errorf("expected io.EOF after reading the matching row, got %v", err)
+	}
+}
+
+func TestFilterRowsMissingNullCount(t *testing.T) {
+	rows := makeFilterRows(100)
+	input, err := writeFilterRows(rows, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, input.Size())
+	if _, err := input.ReadAt(data, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	numNulls := 0
+	for _, row := range rows {
+		if row.Score == nil {
+			numNulls++
+		}
+	}
+
+	// Rewrites the footer of the file as if it had been produced by a writer
+	// setting the bounds of the column chunk statistics, but not null_count.
+	withStatistics := func(createdBy string) *bytes.Reader {
+		return rewriteFooter(t, data, func(metadata *format.FileMetaData) {
+			metadata.CreatedBy = createdBy
+			for i := range metadata.RowGroups {
+				column := &metadata.RowGroups[i].Columns[2]
+				column.MetaData.Statistics = format.Statistics{
+					MinValue: []byte{0, 0, 0, 0},
+					MaxValue: []byte{49, 0, 0, 0},
+				}
+			}
+		})
+	}
+
+	for _, test := range []struct {
+		createdBy string
+		numRows   int
+	}{
+		{createdBy: "some-writer version 1.0", numRows: numNulls},
+		{createdBy: "parquet-mr version 1.12.3 (build f8dced182c4c1fbdec6ccb3185537b5a01e6ed6b)", numRows: 0},
+	} {
+		t.Run(test.createdBy, func(t *testing.T) {
+			input := withStatistics(test.createdBy)
+			f, err := parquet.OpenFile(input, input.Size(), parquet.SkipPageIndex(true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			found, err := readAllRows(parquet.NewReader(f,
+				parquet.FilterRows(parquet.IsNull([]string{"score"})),
+			))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(found) != test.numRows {
+				t.Errorf("wrong number of rows: want=%d got=%d", test.numRows, len(found))
+			}
+		})
+	}
+}
+
+// rewriteFooter returns a copy of the parquet file in data where the footer was
+// modified by the given function.
+func rewriteFooter(t *testing.T, data []byte, modify func(*format.FileMetaData)) *bytes.Reader {
+	t.Helper()
+	footerSize := binary.LittleEndian.Uint32(data[len(data)-8:])
+	footerOffset := len(data) - 8 - int(footerSize)
+	metadata := format.FileMetaData{}
+	if err := thrift.Unmarshal(new(thrift.CompactProtocol), data[footerOffset:len(data)-8], &metadata); err != nil {
+		t.Fatal(err)
+	}
+	modify(&metadata)
+	footer, err := thrift.Marshal(new(thrift.CompactProtocol), &metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := append([]byte{}, data[:footerOffset]...)
+	b = append(b, footer...)
+	b = append(b, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(b[len(b)-4:], uint32(len(footer)))
+	b = append(b, "PAR1"...)
+	return bytes.NewReader(b)
+}
+
+func TestFilterString(t *testing.T) {
+	tests := []struct {
+		filter parquet.Filter
+		want   string
+	}{
+		{
+			filter: parquet.Eq([]string{"a", "b"}, parquet.ValueOf(int64(42))),
+			want:   "a.b = 42",
+		},
+		{
+			filter: parquet.In([]string{"a"}, parquet.ValueOf(int64(1)), parquet.ValueOf(int64(2))),
+			want:   "a IN (1, 2)",
+		},
+		{
+			filter: parquet.Not(parquet.IsNull([]string{"a"})),
+			want:   "NOT (a IS NULL)",
+		},
+		{
+			filter: parquet.And(
+				parquet.Lt([]string{"a"}, parquet.ValueOf(int64(1))),
+				parquet.Or(
+					parquet.GtEq([]string{"b"}, parquet.ValueOf(int64(2))),
+					parquet.LtEq([]string{"c"}, parquet.ValueOf(int64(3))),
+				),
+			),
+			want: "(a < 1) AND ((b >= 2) OR (c <= 3))",
+		},
+	}
+
+	for _, test := range tests {
+		if s := test.filter.String(); s != test.want {
+			t.Errorf("filter string mismatch: want=%q got=%q", test.want, s)
+		}
+	}
+}
+
+func TestFilterRowsColumnNotFound(t *testing.T) {
+	input, err := writeFilterRows(makeFilterRows(10), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("creating a reader with a filter on a missing column did not panic")
+		}
+	}()
+
+	parquet.NewReader(input, parquet.FilterRows(parquet.IsNull([]string{"missing"})))
+}
+
+func TestFilterRowsDictionary(t *testing.T) {
+	type dictRow struct {
+		ID    int64  `parquet:"id"`
+		Group string `parquet:"group,dict"`
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, parquet.PageBufferSize(256))
+	rows := make([]dictRow, 1000)
+	for i := range rows {
+		rows[i] = dictRow{ID: int64(i), Group: fmt.Sprintf("g%02d", i%10)}
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Seeking to the pages which may contain the row must still load the
+	// dictionary page of the column chunk.
+	reader := parquet.NewReader(bytes.NewReader(buffer.Bytes()),
+		parquet.FilterRows(parquet.Eq([]string{"id"}, parquet.ValueOf(int64(777)))),
+	)
+	row := dictRow{}
+	if err := reader.Read(&row); err != nil {
+		t.Fatal(err)
+	}
+	if row != rows[777] {
+		t.Errorf("row mismatch: want=%+v got=%+v", rows[777], row)
+	}
+}
+
+func TestFilterRowsWrappedColumnChunks(t *testing.T) {
+	rows := makeFilterRows(1000)
+	input, err := writeFilterRows(rows, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, input.Size())
+	if _, err := input.ReadAt(data, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the page index, only the statistics of the column chunks tell
+	// whether the row groups can contain matching rows.
+	input = rewriteFooter(t, data, func(metadata *format.FileMetaData) {
+		metadata.CreatedBy = "parquet-mr version 1.12.3"
+		columns := metadata.RowGroups[0].Columns
+		columns[0].MetaData.Statistics = format.Statistics{
+			MinValue: []byte{0, 0, 0, 0, 0, 0, 0, 0},
+			MaxValue: []byte{0xe7, 0x03, 0, 0, 0, 0, 0, 0},
+		}
+		columns[2].MetaData.Statistics = format.Statistics{
+			NullCount: 143,
+			MinValue:  []byte{0, 0, 0, 0},
+			MaxValue:  []byte{49, 0, 0, 0},
+		}
+	})
+	projected, err := parquet.OpenFile(input, input.Size(),
+		parquet.SkipPageIndex(true),
+		parquet.SelectColumns([]string{"id"}, []string{"score"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := parquet.NewBuffer()
+	for i := range rows {
+		if err := buffer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		scenario string
+		rowGroup parquet.RowGroup
+	}{
+		{scenario: "projected", rowGroup: projected.RowGroups()[0]},
+		{scenario: "buffer", rowGroup: buffer},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			for _, filter := range []struct {
+				filter  parquet.Filter
+				numRows int64
+			}{
+				{filter: parquet.Gt([]string{"id"}, parquet.ValueOf(int64(1000))), numRows: 0},
+				{filter: parquet.Lt([]string{"id"}, parquet.ValueOf(int64(1000))), numRows: 1000},
+				{filter: parquet.Eq([]string{"score"}, parquet.ValueOf(int32(50))), numRows: 0},
+			} {
+				reader := parquet.NewRowGroupReader(test.rowGroup, parquet.FilterRows(filter.filter))
+				if numRows := reader.NumRows(); numRows != filter.numRows {
+					t.Errorf("%s: wrong number of rows: want=%d got=%d", filter.filter, filter.numRows, numRows)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package format
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/segmentio/parquet-go/deprecated"
 )
@@ -99,19 +100,21 @@ type Statistics struct {
 }
 
 // Empty structs to use as logical type annotations.
-type StringType struct{} // allowed for BINARY, must be encoded with UTF-8
-type UUIDType struct{}   // allowed for FIXED[16], must encode raw UUID bytes
-type MapType struct{}    // see see LogicalTypes.md
-type ListType struct{}   // see LogicalTypes.md
-type EnumType struct{}   // allowed for BINARY, must be encoded with UTF-8
-type DateType struct{}   // allowed for INT32
-
-func (*StringType) String() string { return "STRING" }
-func (*UUIDType) String() string   { return "UUID" }
-func (*MapType) String() string    { return "MAP" }
-func (*ListType) String() string   { return "LIST" }
-func (*EnumType) String() string   { return "ENUM" }
-func (*DateType) String() string   { return "DATE" }
+type StringType struct{}  // allowed for BINARY, must be encoded with UTF-8
+type UUIDType struct{}    // allowed for FIXED[16], must encode raw UUID bytes
+type MapType struct{}     // see see LogicalTypes.md
+type ListType struct{}    // see LogicalTypes.md
+type EnumType struct{}    // allowed for BINARY, must be encoded with UTF-8
+type DateType struct{}    // allowed for INT32
+type Float16Type struct{} // allowed for FIXED[2], must encode raw FLOAT16 bytes
+
+func (*StringType) String() string  { return "STRING" }
+func (*UUIDType) String() string    { return "UUID" }
+func (*MapType) String() string     { return "MAP" }
+func (*ListType) String() string    { return "LIST" }
+func (*EnumType) String() string    { return "ENUM" }
+func (*DateType) String() string    { return "DATE" }
+func (*Float16Type) String() string { return "FLOAT16" }
 
 // Logical type to annotate a column that is always null.
 //
@@ -134,7 +137,7 @@ type DecimalType struct {
 }
 
 func (t *DecimalType) String() string {
-	return fmt.Sprintf("DECIMAL(%d,%d)", t.Scale, t.Precision)
+	return fmt.Sprintf("DECIMAL(%d,%d)", t.Precision, t.Scale)
 }
 
 // Time units for logical types.
@@ -165,6 +168,19 @@ func (u *TimeUnit) String() string {
 	}
 }
 
+// Duration returns the precision of the time unit, defaulting to nanoseconds
+// when no unit is set.
+func (u *TimeUnit) Duration() time.Duration {
+	switch {
+	case u.Millis != nil:
+		return time.Millisecond
+	case u.Micros != nil:
+		return time.Microsecond
+	default:
+		return time.Nanosecond
+	}
+}
+
 // Timestamp logical type annotation
 //
 // Allowed for physical types: INT64
@@ -239,11 +255,12 @@ type LogicalType struct { // union
 	Timestamp *TimestampType `thrift:"8"`
 
 	// 9: reserved for Interval
-	Integer *IntType  `thrift:"10"` // use ConvertedType Int* or Uint*
-	Unknown *NullType `thrift:"11"` // no compatible ConvertedType
-	Json    *JsonType `thrift:"12"` // use ConvertedType JSON
-	Bson    *BsonType `thrift:"13"` // use ConvertedType BSON
-	UUID    *UUIDType `thrift:"14"` // no compatible ConvertedType
+	Integer *IntType     `thrift:"10"` // use ConvertedType Int* or Uint*
+	Unknown *NullType    `thrift:"11"` // no compatible ConvertedType
+	Json    *JsonType    `thrift:"12"` // use ConvertedType JSON
+	Bson    *BsonType    `thrift:"13"` // use ConvertedType BSON
+	UUID    *UUIDType    `thrift:"14"` // no compatible ConvertedType
+	Float16 *Float16Type `thrift:"15"` // no compatible ConvertedType
 }
 
 func (t *LogicalType) String() string {
@@ -274,6 +291,8 @@ func (t *LogicalType) String() string {
 		return t.Bson.String()
 	case t.UUID != nil:
 		return t.UUID.String()
+	case t.Float16 != nil:
+		return t.Float16.String()
 	default:
 		return ""
 	}
@@ -0,0 +1,34 @@
+package hyperloglog_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/segmentio/parquet-go/bloom/xxhash"
+	"github.com/segmentio/parquet-go/internal/hyperloglog"
+)
+
+func TestSketch(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 100, 1000, 10e3, 100e3, 1e6} {
+		s := new(hyperloglog.Sketch)
+		b := [8]byte{}
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint64(b[:], uint64(i))
+			h := xxhash.Sum64(b[:])
+			// Inserting values multiple times must not change the estimate.
+			s.Insert(h)
+			s.Insert(h)
+		}
+
+		estimate := s.Estimate()
+		if delta := math.Abs(float64(estimate)-float64(n)) / math.Max(float64(n), 1); delta > 0.03 {
+			t.Errorf("estimate of %d distinct values is off by %.2f%%: %d", n, 100*delta, estimate)
+		}
+
+		s.Reset()
+		if estimate := s.Estimate(); estimate != 0 {
+			t.Errorf("estimate of empty sketch is not zero: %d", estimate)
+		}
+	}
+}
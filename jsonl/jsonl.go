@@ -0,0 +1,1001 @@
+// Package jsonl implements conversions between parquet rows and JSON Lines,
+// the format where each line of the input or output holds a JSON object.
+//
+// The conversions are driven by the parquet schema: JSON objects map to parquet
+// groups, JSON arrays to lists and repeated fields, and the values of leaf
+// columns are represented according to their logical type rather than their
+// physical type. For example, timestamps are represented as RFC 3339 strings,
+// decimals as JSON numbers, and UUIDs in their canonical text form:
+//
+//	Logical Type     | JSON representation
+//	---------------- | --------------------------------------------------------
+//	STRING, ENUM     | string
+//	JSON             | embedded JSON value
+//	BSON, (binary)   | base64 encoded string
+//	UUID             | string like "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"
+//	DECIMAL          | number with the scale of the column, like 123.45
+//	DATE             | string like "2006-01-02"
+//	TIME             | string like "15:04:05.999"
+//	TIMESTAMP, INT96 | RFC 3339 string, without a time zone for local timestamps
+//	INTERVAL         | object like {"months":1,"days":2,"milliseconds":3}
+//	FLOAT16          | number
+//
+// Numbers that cannot be represented in JSON (NaN and infinities) are written
+// as strings, which the reader accepts as well. When reading, the values of
+// DATE, TIME and TIMESTAMP columns may also be numbers holding the physical
+// representation of the values.
+package jsonl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/deprecated"
+)
+
+// codec converts values between their JSON representation, as produced by a
+// json.Decoder configured with UseNumber, and the dynamic Go values accepted
+// by parquet.Schema.Deconstruct and produced by parquet.Schema.Reconstruct.
+type codec interface {
+	decode(v interface{}) (interface{}, error)
+	encode(b []byte, v interface{}) ([]byte, error)
+}
+
+func codecOf(node parquet.Node) (codec, error) {
+	switch {
+	case node.Optional():
+		c, err := requiredCodecOf(node)
+		return optionalCodec{c}, err
+	case node.Repeated():
+		c, err := requiredCodecOf(node)
+		return repeatedCodec{c}, err
+	default:
+		return requiredCodecOf(node)
+	}
+}
+
+func requiredCodecOf(node parquet.Node) (codec, error) {
+	if node.Leaf() {
+		return leafCodecOf(node.Type())
+	}
+
+	if elem := parquet.ListElementOf(node); elem != nil {
+		c, err := codecOf(elem)
+		return repeatedCodec{c}, err
+	}
+
+	if keyValue := parquet.MapKeyValueOf(node); keyValue != nil {
+		m := mapCodec{}
+		for _, f := range keyValue.Fields() {
+			c, err := codecOf(f)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name(), err)
+			}
+			switch f.Name() {
+			case "key":
+				m.key = c
+			case "value":
+				m.value = c
+			}
+		}
+		return m, nil
+	}
+
+	fields := node.Fields()
+	group := groupCodec{fields: make([]fieldCodec, len(fields))}
+	for i, f := range fields {
+		c, err := codecOf(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name(), err)
+		}
+		group.fields[i] = fieldCodec{
+			name:     f.Name(),
+			required: f.Required(),
+			codec:    c,
+		}
+	}
+	return group, nil
+}
+
+func leafCodecOf(t parquet.Type) (codec, error) {
+	if lt := t.LogicalType(); lt != nil {
+		switch {
+		case lt.UTF8 != nil, lt.Enum != nil:
+			return stringCodec{}, nil
+		case lt.Json != nil:
+			return jsonCodec{}, nil
+		case lt.Bson != nil:
+			return bytesCodec{}, nil
+		case lt.UUID != nil:
+			return uuidCodec{}, nil
+		case lt.Date != nil:
+			return dateCodec{}, nil
+		case lt.Time != nil:
+			return timeCodec{kind: t.Kind(), unit: lt.Time.Unit.Duration()}, nil
+		case lt.Timestamp != nil:
+			return timestampCodec{unit: lt.Timestamp.Unit.Duration(), utc: lt.Timestamp.IsAdjustedToUTC}, nil
+		case lt.Decimal != nil:
+			return decimalCodec{kind: t.Kind(), length: t.Length(), scale: int(lt.Decimal.Scale)}, nil
+		case lt.Integer != nil:
+			return intCodec{kind: t.Kind(), bitWidth: int(lt.Integer.BitWidth), signed: lt.Integer.IsSigned}, nil
+		case lt.Float16 != nil:
+			return float16Codec{}, nil
+		}
+	}
+
+	if ct := t.ConvertedType(); ct != nil && *ct == deprecated.Interval {
+		return intervalCodec{}, nil
+	}
+
+	switch kind := t.Kind(); kind {
+	case parquet.Boolean:
+		return boolCodec{}, nil
+	case parquet.Int32:
+		return intCodec{kind: kind, bitWidth: 32, signed: true}, nil
+	case parquet.Int64:
+		return intCodec{kind: kind, bitWidth: 64, signed: true}, nil
+	case parquet.Int96:
+		return int96Codec{}, nil
+	case parquet.Float:
+		return floatCodec{bitSize: 32}, nil
+	case parquet.Double:
+		return floatCodec{bitSize: 64}, nil
+	case parquet.ByteArray:
+		return bytesCodec{}, nil
+	case parquet.FixedLenByteArray:
+		return bytesCodec{length: t.Length()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported parquet type: %s", t)
+	}
+}
+
+type optionalCodec struct{ codec }
+
+func (c optionalCodec) decode(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return c.codec.decode(v)
+}
+
+func (c optionalCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	if v == nil {
+		return append(b, "null"...), nil
+	}
+	return c.codec.encode(b, v)
+}
+
+// repeatedCodec is used for lists and repeated fields, which are both
+// represented by JSON arrays.
+type repeatedCodec struct{ elem codec }
+
+func (c repeatedCodec) decode(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	array, ok := v.([]interface{})
+	if !ok {
+		return nil, unexpectedValue(v, "array")
+	}
+	values := make([]interface{}, len(array))
+	for i, elem := range array {
+		value, err := c.elem.decode(elem)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (c repeatedCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	values, _ := v.([]interface{})
+	b = append(b, '[')
+	for i, value := range values {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var err error
+		if b, err = c.elem.encode(b, value); err != nil {
+			return b, fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return append(b, ']'), nil
+}
+
+// mapCodec is used for maps, which are represented by JSON objects. Since the
+// names of JSON object members are strings, keys of other types are written
+// using the text of their JSON representation.
+type mapCodec struct{ key, value codec }
+
+func (c mapCodec) decode(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, unexpectedValue(v, "object")
+	}
+	values := make(map[interface{}]interface{}, len(object))
+	for k, v := range object {
+		key, err := c.key.decode(k)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", k, err)
+		}
+		if b, ok := key.([]byte); ok {
+			key = string(b) // byte slices cannot be used as map keys
+		}
+		value, err := c.value.decode(v)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", k, err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func (c mapCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	type entry struct {
+		key   string
+		value interface{}
+	}
+
+	m := reflect.ValueOf(v)
+	if m.Kind() != reflect.Map {
+		return append(b, "{}"...), nil
+	}
+
+	entries := make([]entry, 0, m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		k, err := c.key.encode(nil, iter.Key().Interface())
+		if err != nil {
+			return b, err
+		}
+		key := string(k)
+		if len(k) == 0 || k[0] != '"' {
+			key = string(appendString(nil, key))
+		}
+		entries = append(entries, entry{key: key, value: iter.Value().Interface()})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	b = append(b, '{')
+	for i, e := range entries {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, e.key...)
+		b = append(b, ':')
+		var err error
+		if b, err = c.value.encode(b, e.value); err != nil {
+			return b, fmt.Errorf("%s: %w", e.key, err)
+		}
+	}
+	return append(b, '}'), nil
+}
+
+type groupCodec struct{ fields []fieldCodec }
+
+type fieldCodec struct {
+	name     string
+	required bool
+	codec    codec
+}
+
+func (c groupCodec) decode(v interface{}) (interface{}, error) {
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, unexpectedValue(v, "object")
+	}
+	values := make(map[string]interface{}, len(c.fields))
+	for _, f := range c.fields {
+		value, exists := object[f.name]
+		if f.required && (!exists || value == nil) {
+			return nil, fmt.Errorf("missing value of required field %q", f.name)
+		}
+		value, err := f.codec.decode(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		values[f.name] = value
+	}
+	return values, nil
+}
+
+func (c groupCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	values, _ := v.(map[string]interface{})
+	b = append(b, '{')
+	for i, f := range c.fields {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendString(b, f.name)
+		b = append(b, ':')
+		var err error
+		if b, err = f.codec.encode(b, values[f.name]); err != nil {
+			return b, fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	return append(b, '}'), nil
+}
+
+type boolCodec struct{}
+
+func (boolCodec) decode(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case bool:
+		return x, nil
+	case string:
+		if b, err := strconv.ParseBool(x); err == nil {
+			return b, nil
+		}
+	}
+	return nil, unexpectedValue(v, "boolean")
+}
+
+func (boolCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	x, _ := v.(bool)
+	return strconv.AppendBool(b, x), nil
+}
+
+// intCodec is used for integer columns. Unsigned integers are stored in the
+// signed Go types of their physical representation (int32 or int64).
+type intCodec struct {
+	kind     parquet.Kind
+	bitWidth int
+	signed   bool
+}
+
+func (c intCodec) decode(v interface{}) (interface{}, error) {
+	s, err := numberOf(v)
+	if err != nil {
+		return nil, err
+	}
+	var i int64
+	if c.signed {
+		i, err = strconv.ParseInt(s, 10, c.bitWidth)
+	} else {
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, c.bitWidth)
+		i = int64(u)
+	}
+	if err != nil {
+		return nil, invalidValue(s, err)
+	}
+	if c.kind == parquet.Int32 {
+		return int32(i), nil
+	}
+	return i, nil
+}
+
+func (c intCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	switch x := v.(type) {
+	case int32:
+		if !c.signed {
+			return strconv.AppendUint(b, uint64(uint32(x)), 10), nil
+		}
+		return strconv.AppendInt(b, int64(x), 10), nil
+	case int64:
+		if !c.signed {
+			return strconv.AppendUint(b, uint64(x), 10), nil
+		}
+		return strconv.AppendInt(b, x, 10), nil
+	default:
+		return b, unsupportedValue(v)
+	}
+}
+
+type floatCodec struct{ bitSize int }
+
+func (c floatCodec) decode(v interface{}) (interface{}, error) {
+	s, err := numberOf(v)
+	if err != nil {
+		return nil, err
+	}
+	f, err := strconv.ParseFloat(s, c.bitSize)
+	if err != nil {
+		return nil, invalidValue(s, err)
+	}
+	if c.bitSize == 32 {
+		return float32(f), nil
+	}
+	return f, nil
+}
+
+func (c floatCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	switch x := v.(type) {
+	case float32:
+		return appendFloat(b, float64(x), 32), nil
+	case float64:
+		return appendFloat(b, x, 64), nil
+	default:
+		return b, unsupportedValue(v)
+	}
+}
+
+type float16Codec struct{}
+
+func (float16Codec) decode(v interface{}) (interface{}, error) {
+	f, err := floatCodec{bitSize: 32}.decode(v)
+	if err != nil {
+		return nil, err
+	}
+	return parquet.Float32ToFloat16(f.(float32)), nil
+}
+
+func (float16Codec) encode(b []byte, v interface{}) ([]byte, error) {
+	x, ok := v.([2]byte)
+	if !ok {
+		return b, unsupportedValue(v)
+	}
+	return appendFloat(b, float64(parquet.Float16ToFloat32(x)), 32), nil
+}
+
+type stringCodec struct{}
+
+func (stringCodec) decode(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, unexpectedValue(v, "string")
+	}
+	return s, nil
+}
+
+func (stringCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	switch x := v.(type) {
+	case string:
+		return appendString(b, x), nil
+	case []byte:
+		return appendString(b, string(x)), nil
+	default:
+		return b, unsupportedValue(v)
+	}
+}
+
+// bytesCodec is used for binary columns, their values are represented by base64
+// encoded strings. The length is set to the size of fixed length byte arrays.
+type bytesCodec struct{ length int }
+
+func (c bytesCodec) decode(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, unexpectedValue(v, "base64 string")
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalidValue(s, err)
+	}
+	if c.length != 0 && len(b) != c.length {
+		return nil, fmt.Errorf("invalid value: %q: expected %d bytes but got %d", s, c.length, len(b))
+	}
+	return b, nil
+}
+
+func (c bytesCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	var data []byte
+	switch x := v.(type) {
+	case []byte:
+		data = x
+	case string:
+		data = []byte(x)
+	default:
+		array := reflect.ValueOf(v)
+		if array.Kind() != reflect.Array || array.Type().Elem().Kind() != reflect.Uint8 {
+			return b, unsupportedValue(v)
+		}
+		data = make([]byte, array.Len())
+		reflect.Copy(reflect.ValueOf(data), array)
+	}
+	b = append(b, '"')
+	n := len(b)
+	b = append(b, make([]byte, base64.StdEncoding.EncodedLen(len(data)))...)
+	base64.StdEncoding.Encode(b[n:], data)
+	return append(b, '"'), nil
+}
+
+// jsonCodec is used for columns of JSON logical type, which hold JSON values
+// embedded in the lines.
+type jsonCodec struct{}
+
+func (jsonCodec) decode(v interface{}) (interface{}, error) {
+	b := new(bytes.Buffer)
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte{'\n'}), nil
+}
+
+func (jsonCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	var data []byte
+	switch x := v.(type) {
+	case []byte:
+		data = x
+	case string:
+		data = []byte(x)
+	default:
+		return b, unsupportedValue(v)
+	}
+	// The value must be compacted to fit on a single line; values that are not
+	// valid JSON are written as strings to produce valid outputs.
+	buf := bytes.NewBuffer(b)
+	if err := json.Compact(buf, data); err != nil {
+		return appendString(b, string(data)), nil
+	}
+	return buf.Bytes(), nil
+}
+
+type uuidCodec struct{}
+
+func (uuidCodec) decode(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, unexpectedValue(v, "string")
+	}
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return nil, invalidValue(s, err)
+	}
+	return u, nil
+}
+
+func (uuidCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	switch x := v.(type) {
+	case uuid.UUID:
+		return appendString(b, x.String()), nil
+	case [16]byte:
+		return appendString(b, uuid.UUID(x).String()), nil
+	default:
+		return b, unsupportedValue(v)
+	}
+}
+
+const (
+	dateLayout      = "2006-01-02"
+	timeLayout      = "15:04:05.999999999"
+	localTimeLayout = "2006-01-02T15:04:05.999999999"
+	secondsPerDay   = 24 * 3600
+)
+
+// dateCodec is used for columns of DATE logical type, which hold the number
+// of days since the Unix epoch.
+type dateCodec struct{}
+
+func (dateCodec) decode(v interface{}) (interface{}, error) {
+	if s, ok := v.(string); ok {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return nil, invalidValue(s, err)
+		}
+		return int32(t.Unix() / secondsPerDay), nil
+	}
+	return intCodec{kind: parquet.Int32, bitWidth: 32, signed: true}.decode(v)
+}
+
+func (dateCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	days, ok := v.(int32)
+	if !ok {
+		return b, unsupportedValue(v)
+	}
+	t := time.Unix(int64(days)*secondsPerDay, 0).UTC()
+	return appendString(b, t.Format(dateLayout)), nil
+}
+
+// timeCodec is used for columns of TIME logical type, which hold the time
+// elapsed since midnight in the unit of the column.
+type timeCodec struct {
+	kind parquet.Kind
+	unit time.Duration
+}
+
+func (c timeCodec) decode(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		bitWidth := 64
+		if c.kind == parquet.Int32 {
+			bitWidth = 32
+		}
+		return intCodec{kind: c.kind, bitWidth: bitWidth, signed: true}.decode(v)
+	}
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return nil, invalidValue(s, err)
+	}
+	hour, min, sec := t.Clock()
+	d := time.Duration(hour)*time.Hour +
+		time.Duration(min)*time.Minute +
+		time.Duration(sec)*time.Second +
+		time.Duration(t.Nanosecond())
+	if c.kind == parquet.Int32 {
+		return int32(d / c.unit), nil
+	}
+	return int64(d / c.unit), nil
+}
+
+func (c timeCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	var d time.Duration
+	switch x := v.(type) {
+	case int32:
+		d = time.Duration(x) * c.unit
+	case int64:
+		d = time.Duration(x) * c.unit
+	case time.Duration:
+		d = x
+	default:
+		return b, unsupportedValue(v)
+	}
+	return appendString(b, time.Time{}.Add(d).Format(timeLayout)), nil
+}
+
+// timestampCodec is used for columns of TIMESTAMP logical type. Timestamps
+// which are not adjusted to UTC hold local date-times, and are represented by
+// strings without time zones.
+type timestampCodec struct {
+	unit time.Duration
+	utc  bool
+}
+
+func (c timestampCodec) decode(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return intCodec{kind: parquet.Int64, bitWidth: 64, signed: true}.decode(v)
+	}
+	t, err := parseTime(s, c.utc)
+	if err != nil {
+		return nil, err
+	}
+	switch c.unit {
+	case time.Millisecond:
+		return t.UnixMilli(), nil
+	case time.Microsecond:
+		return t.UnixMicro(), nil
+	default:
+		return t.UnixNano(), nil
+	}
+}
+
+func (c timestampCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	var t time.Time
+	switch x := v.(type) {
+	case time.Time:
+		t = x
+	case int64:
+		t = time.Unix(0, 0).Add(time.Duration(x) * c.unit)
+	default:
+		return b, unsupportedValue(v)
+	}
+	return appendTime(b, t, c.utc), nil
+}
+
+// int96Codec is used for INT96 columns, which hold timestamps in the deprecated
+// representation of Impala and Spark.
+type int96Codec struct{}
+
+const julianDayOfUnixEpoch = 2440588
+
+func (int96Codec) decode(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, unexpectedValue(v, "string")
+	}
+	t, err := parseTime(s, true)
+	if err != nil {
+		return nil, err
+	}
+	secs := t.Unix()
+	days := secs / secondsPerDay
+	if secs%secondsPerDay < 0 {
+		days--
+	}
+	nanos := uint64(secs-days*secondsPerDay)*uint64(time.Second) + uint64(t.Nanosecond())
+	return deprecated.Int96{uint32(nanos), uint32(nanos >> 32), uint32(days + julianDayOfUnixEpoch)}, nil
+}
+
+func (int96Codec) encode(b []byte, v interface{}) ([]byte, error) {
+	x, ok := v.(deprecated.Int96)
+	if !ok {
+		return b, unsupportedValue(v)
+	}
+	nanos := uint64(x[1])<<32 | uint64(x[0])
+	days := int64(x[2]) - julianDayOfUnixEpoch
+	t := time.Unix(days*secondsPerDay, int64(nanos))
+	return appendTime(b, t, true), nil
+}
+
+func parseTime(s string, utc bool) (time.Time, error) {
+	if !utc {
+		if t, err := time.Parse(localTimeLayout, s); err == nil {
+			return t, nil
+		}
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return t, invalidValue(s, err)
+	}
+	if !utc {
+		// Local date-times are stored using their wall clock, regardless of
+		// the time zone that they were expressed in.
+		year, month, day := t.Date()
+		hour, min, sec := t.Clock()
+		t = time.Date(year, month, day, hour, min, sec, t.Nanosecond(), time.UTC)
+	}
+	return t, nil
+}
+
+func appendTime(b []byte, t time.Time, utc bool) []byte {
+	if utc {
+		return appendString(b, t.UTC().Format(time.RFC3339Nano))
+	}
+	return appendString(b, t.Format(localTimeLayout))
+}
+
+// decimalCodec is used for columns of DECIMAL logical type, which hold the
+// unscaled values of decimal numbers as integers, or as two's complement big
+// endian byte arrays.
+type decimalCodec struct {
+	kind   parquet.Kind
+	length int
+	scale  int
+}
+
+func (c decimalCodec) decode(v interface{}) (interface{}, error) {
+	s, err := numberOf(v)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid value: %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(pow10(c.scale)))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("invalid value: %q: more than %d digits after the decimal point", s, c.scale)
+	}
+	n := r.Num()
+
+	switch c.kind {
+	case parquet.Int32:
+		if n.IsInt64() && n.Int64() >= math.MinInt32 && n.Int64() <= math.MaxInt32 {
+			return int32(n.Int64()), nil
+		}
+	case parquet.Int64:
+		if n.IsInt64() {
+			return n.Int64(), nil
+		}
+	case parquet.ByteArray, parquet.FixedLenByteArray:
+		length := c.length
+		if c.kind == parquet.ByteArray {
+			length = n.BitLen()/8 + 1
+		}
+		if b, ok := twosComplementOf(n, length); ok {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid value: %q: out of range", s)
+}
+
+func (c decimalCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	n := new(big.Int)
+	switch x := v.(type) {
+	case int32:
+		n.SetInt64(int64(x))
+	case int64:
+		n.SetInt64(x)
+	case []byte:
+		setTwosComplement(n, x)
+	default:
+		array := reflect.ValueOf(v)
+		if array.Kind() != reflect.Array || array.Type().Elem().Kind() != reflect.Uint8 {
+			return b, unsupportedValue(v)
+		}
+		data := make([]byte, array.Len())
+		reflect.Copy(reflect.ValueOf(data), array)
+		setTwosComplement(n, data)
+	}
+
+	if n.Sign() < 0 {
+		b = append(b, '-')
+		n.Neg(n)
+	}
+	digits := n.String()
+	if c.scale <= 0 {
+		return append(b, digits...), nil
+	}
+	if len(digits) <= c.scale {
+		b = append(b, '0')
+	} else {
+		b = append(b, digits[:len(digits)-c.scale]...)
+		digits = digits[len(digits)-c.scale:]
+	}
+	b = append(b, '.')
+	for i := len(digits); i < c.scale; i++ {
+		b = append(b, '0')
+	}
+	return append(b, digits...), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func twosComplementOf(n *big.Int, length int) ([]byte, bool) {
+	b := make([]byte, length)
+	if n.Sign() >= 0 {
+		if n.BitLen() > 8*length-1 {
+			return nil, false
+		}
+		return n.FillBytes(b), true
+	}
+	// -n-1 has the same bit length as the positive numbers that can be
+	// represented with the same number of bytes.
+	if new(big.Int).Not(n).BitLen() > 8*length-1 {
+		return nil, false
+	}
+	x := new(big.Int).Lsh(big.NewInt(1), uint(8*length))
+	return x.Add(x, n).FillBytes(b), true
+}
+
+func setTwosComplement(n *big.Int, b []byte) {
+	n.SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+}
+
+// intervalCodec is used for columns of the INTERVAL converted type.
+type intervalCodec struct{}
+
+func (intervalCodec) decode(v interface{}) (interface{}, error) {
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, unexpectedValue(v, "object")
+	}
+	var fields [3]uint32
+	for i, name := range [3]string{"months", "days", "milliseconds"} {
+		if object[name] == nil {
+			continue
+		}
+		x, err := intCodec{kind: parquet.Int32, bitWidth: 32}.decode(object[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		fields[i] = uint32(x.(int32))
+	}
+	return parquet.IntervalValue{Months: fields[0], Days: fields[1], Milliseconds: fields[2]}, nil
+}
+
+func (intervalCodec) encode(b []byte, v interface{}) ([]byte, error) {
+	var x parquet.IntervalValue
+	switch value := v.(type) {
+	case parquet.IntervalValue:
+		x = value
+	case [12]byte:
+		x.Months = binary.LittleEndian.Uint32(value[0:])
+		x.Days = binary.LittleEndian.Uint32(value[4:])
+		x.Milliseconds = binary.LittleEndian.Uint32(value[8:])
+	default:
+		return b, unsupportedValue(v)
+	}
+	b = append(b, `{"months":`...)
+	b = strconv.AppendUint(b, uint64(x.Months), 10)
+	b = append(b, `,"days":`...)
+	b = strconv.AppendUint(b, uint64(x.Days), 10)
+	b = append(b, `,"milliseconds":`...)
+	b = strconv.AppendUint(b, uint64(x.Milliseconds), 10)
+	return append(b, '}'), nil
+}
+
+// numberOf returns the text of JSON numbers. Strings are accepted as well,
+// since they are used to represent the keys of maps, and numbers which have
+// no JSON representation.
+func numberOf(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case json.Number:
+		return string(x), nil
+	case string:
+		return x, nil
+	default:
+		return "", unexpectedValue(v, "number")
+	}
+}
+
+func appendFloat(b []byte, f float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(b, `"NaN"`...)
+	case math.IsInf(f, +1):
+		return append(b, `"+Inf"`...)
+	case math.IsInf(f, -1):
+		return append(b, `"-Inf"`...)
+	default:
+		return strconv.AppendFloat(b, f, 'g', -1, bitSize)
+	}
+}
+
+// appendString appends the JSON representation of s to b. Invalid UTF-8
+// sequences are replaced by the unicode replacement character.
+func appendString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"', c == '\\':
+				b = append(b, '\\', c)
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			case c == '\t':
+				b = append(b, '\\', 't')
+			case c < 0x20:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			default:
+				b = append(b, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b = append(b, `\ufffd`...)
+		case r == '\u2028', r == '\u2029':
+			// These characters are valid in JSON strings but are line
+			// terminators in JavaScript, they are escaped for safety.
+			b = append(b, '\\', 'u', '2', '0', '2', hex[r&0xF])
+		default:
+			b = append(b, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(b, '"')
+}
+
+func unexpectedValue(v interface{}, want string) error {
+	return fmt.Errorf("expected %s but got %s", want, describe(v))
+}
+
+func invalidValue(s string, err error) error {
+	return fmt.Errorf("invalid value: %q: %w", s, err)
+}
+
+func unsupportedValue(v interface{}) error {
+	return fmt.Errorf("unsupported go value of type %T", v)
+}
+
+func describe(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(x)
+	case json.Number:
+		return string(x)
+	case string:
+		return strconv.Quote(x)
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
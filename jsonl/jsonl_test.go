@@ -0,0 +1,153 @@
+package jsonl_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/jsonl"
+)
+
+const testSchema = `message Event {
+	required int64 id (INT(64,true));
+	required binary name (STRING);
+	optional double score;
+	required boolean valid;
+	optional int32 count (INT(16,false));
+	required int64 time (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	optional int64 local (TIMESTAMP(isAdjustedToUTC=false,unit=MILLIS));
+	optional int32 day (DATE);
+	optional int32 clock (TIME(isAdjustedToUTC=true,unit=MILLIS));
+	optional int64 price (DECIMAL(18,2));
+	optional fixed_len_byte_array(16) amount (DECIMAL(30,4));
+	optional fixed_len_byte_array(16) uuid (UUID);
+	optional binary payload (JSON);
+	optional binary document (BSON);
+	optional binary raw;
+	optional int96 legacy;
+	optional group tags (LIST) {
+		repeated group list {
+			optional binary element (STRING);
+		}
+	}
+	optional group attributes (MAP) {
+		repeated group key_value {
+			required int32 key;
+			required double value;
+		}
+	}
+	optional group location {
+		required float lat;
+		required float lng;
+	}
+}`
+
+var testLines = []string{
+	`{"id":1,"name":"hello","score":null,"valid":true,"count":65535,"time":"2022-06-30T12:34:56.123456Z","local":"2022-06-30T12:34:56.789","day":"2022-06-30","clock":"12:34:56.789","price":-123.45,"amount":12345678901234567890.1234,"uuid":"f81d4fae-7dec-11d0-a765-00a0c91e6bf6","payload":{"a":[1,2.5,"<b>"]},"document":"AQID","raw":"aGVsbG8=","legacy":"2022-06-30T12:34:56.123456789Z","tags":["a",null,"c"],"attributes":{"-1":0.5,"2":"NaN"},"location":{"lat":48.85,"lng":2.35}}`,
+	`{"id":2,"name":"line\nbreak \"quoted\"","score":0.25,"valid":false,"count":null,"time":"1969-12-31T23:59:59.999999Z","local":null,"day":null,"clock":null,"price":0.05,"amount":-0.0001,"uuid":null,"payload":null,"document":null,"raw":null,"legacy":null,"tags":[],"attributes":null,"location":null}`,
+}
+
+func TestRoundTrip(t *testing.T) {
+	schema, err := parquet.ParseSchema(testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := strings.Join(testLines, "\n") + "\n"
+	reader := jsonl.NewReader(strings.NewReader(input), schema)
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewWriter(buffer, schema)
+	n, err := reader.WriteRowsTo(writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(testLines)) {
+		t.Fatalf("wrong number of rows read: want %d but got %d", len(testLines), n)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := new(strings.Builder)
+	n, err = jsonl.NewWriter(output, nil).ReadRowsFrom(parquet.NewReader(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(testLines)) {
+		t.Fatalf("wrong number of rows written: want %d but got %d", len(testLines), n)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	if len(lines) != len(testLines) {
+		t.Fatalf("wrong number of lines: want %d but got %d\n%s", len(testLines), len(lines), output)
+	}
+	for i := range lines {
+		if lines[i] != testLines[i] {
+			t.Errorf("line %d mismatch:\nwant = %s\ngot  = %s", i, testLines[i], lines[i])
+		}
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	schema, err := parquet.ParseSchema(testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scenario string
+		input    string
+		error    string
+	}{
+		{
+			scenario: "missing required field",
+			input:    `{"id":1,"valid":true,"time":0}`,
+			error:    `jsonl: reading row 0: missing value of required field "name"`,
+		},
+		{
+			scenario: "wrong type of value",
+			input:    `{"id":"one","name":"a","valid":true,"time":0}`,
+			error:    `jsonl: reading row 0: id: invalid value: "one"`,
+		},
+		{
+			scenario: "integer overflow",
+			input:    `{"id":1,"name":"a","valid":true,"time":0,"count":65536}`,
+			error:    `jsonl: reading row 0: count: invalid value: "65536"`,
+		},
+		{
+			scenario: "decimal with too many digits",
+			input:    `{"id":1,"name":"a","valid":true,"time":0,"price":1.234}`,
+			error:    `jsonl: reading row 0: price: invalid value: "1.234": more than 2 digits after the decimal point`,
+		},
+		{
+			scenario: "list element of the wrong type",
+			input:    `{"id":1,"name":"a","valid":true,"time":0,"tags":["a",1]}`,
+			error:    `jsonl: reading row 0: tags: [1]: expected string but got 1`,
+		},
+		{
+			scenario: "invalid json",
+			input:    `{"id":1,`,
+			error:    `jsonl: reading row 0: unexpected EOF`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			reader := jsonl.NewReader(strings.NewReader(test.input), schema)
+			_, err := reader.ReadRow(nil)
+			if err == nil {
+				t.Fatal("expected an error but got <nil>")
+			}
+			if !strings.HasPrefix(err.Error(), test.error) {
+				t.Errorf("wrong error:\nwant = %s\ngot  = %s", test.error, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package jsonl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/segmentio/parquet-go"
+)
+
+// Reader reads parquet rows from JSON Lines.
+//
+// The input is decoded one JSON object at a time, each object is converted to
+// a row of the schema that the reader was created with.
+type Reader struct {
+	schema   *parquet.Schema
+	codec    codec
+	decoder  *json.Decoder
+	rowIndex int64
+	err      error
+}
+
+// NewReader constructs a reader of JSON Lines read from input, producing rows
+// of the given schema.
+//
+// The members of JSON objects are matched with the fields of the schema by name,
+// members that do not match any fields are ignored. Reading rows fails if the
+// value of a required field is missing, or if a value cannot be converted to
+// the type of its column.
+func NewReader(input io.Reader, schema *parquet.Schema) *Reader {
+	decoder := json.NewDecoder(input)
+	decoder.UseNumber()
+	c, err := requiredCodecOf(schema)
+	if err != nil {
+		err = fmt.Errorf("jsonl: %w", err)
+	}
+	return &Reader{
+		schema:  schema,
+		codec:   c,
+		decoder: decoder,
+		err:     err,
+	}
+}
+
+// Schema returns the schema of rows produced by r.
+func (r *Reader) Schema() *parquet.Schema { return r.schema }
+
+// ReadRow reads the next JSON object of the input and appends its values to
+// row. The method returns io.EOF when the end of the input was reached.
+func (r *Reader) ReadRow(row parquet.Row) (parquet.Row, error) {
+	if r.err != nil {
+		return row, r.err
+	}
+
+	var object interface{}
+	if err := r.decoder.Decode(&object); err != nil {
+		if err != io.EOF {
+			err = fmt.Errorf("jsonl: reading row %d: %w", r.rowIndex, err)
+		}
+		return row, err
+	}
+
+	value, err := r.codec.decode(object)
+	if err != nil {
+		return row, fmt.Errorf("jsonl: reading row %d: %w", r.rowIndex, err)
+	}
+
+	r.rowIndex++
+	return r.schema.Deconstruct(row, value), nil
+}
+
+// WriteRowsTo writes the rows read from the input to w. The method returns the
+// number of rows written, and any error other than io.EOF.
+func (r *Reader) WriteRowsTo(w parquet.RowWriter) (int64, error) {
+	var row parquet.Row
+	var err error
+	var n int64
+
+	for {
+		if row, err = r.ReadRow(row[:0]); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return n, err
+		}
+		if err = w.WriteRow(row); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+var (
+	_ parquet.RowReaderWithSchema = (*Reader)(nil)
+	_ parquet.RowWriterTo         = (*Reader)(nil)
+)
@@ -0,0 +1,102 @@
+package jsonl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/segmentio/parquet-go"
+)
+
+// Writer writes parquet rows to JSON Lines.
+//
+// Each row is written to the output as a JSON object on a single line, with
+// the members of objects in the order of fields in the schema.
+type Writer struct {
+	output   io.Writer
+	schema   *parquet.Schema
+	codec    codec
+	buffer   []byte
+	row      parquet.Row
+	rowIndex int64
+}
+
+// NewWriter constructs a writer of JSON Lines to output, for rows of the given
+// schema.
+//
+// The schema may be nil, in which case it is set to the schema of the first
+// parquet.RowReaderWithSchema passed to ReadRowsFrom.
+func NewWriter(output io.Writer, schema *parquet.Schema) *Writer {
+	return &Writer{output: output, schema: schema}
+}
+
+// Schema returns the schema of rows written by w, which is nil if no schema
+// has yet been configured.
+func (w *Writer) Schema() *parquet.Schema { return w.schema }
+
+// WriteRow writes a row to the output, the row must have the schema of w.
+func (w *Writer) WriteRow(row parquet.Row) error {
+	if w.schema == nil {
+		return errors.New("jsonl: cannot write rows without a schema")
+	}
+	if w.codec == nil {
+		c, err := requiredCodecOf(w.schema)
+		if err != nil {
+			return fmt.Errorf("jsonl: %w", err)
+		}
+		w.codec = c
+	}
+
+	var value interface{}
+	if err := w.schema.Reconstruct(&value, row); err != nil {
+		return fmt.Errorf("jsonl: writing row %d: %w", w.rowIndex, err)
+	}
+
+	var err error
+	w.buffer, err = w.codec.encode(w.buffer[:0], value)
+	if err != nil {
+		return fmt.Errorf("jsonl: writing row %d: %w", w.rowIndex, err)
+	}
+	w.buffer = append(w.buffer, '\n')
+
+	if _, err := w.output.Write(w.buffer); err != nil {
+		return err
+	}
+	w.rowIndex++
+	return nil
+}
+
+// ReadRowsFrom reads rows from the given reader and writes them to the output.
+// Rows are streamed one at a time, which means that the memory footprint of the
+// conversion does not depend on the number of rows.
+//
+// If no schema was configured on w, the reader must implement
+// parquet.RowReaderWithSchema.
+func (w *Writer) ReadRowsFrom(rows parquet.RowReader) (int64, error) {
+	if w.schema == nil {
+		if r, ok := rows.(parquet.RowReaderWithSchema); ok {
+			w.schema = r.Schema()
+		}
+	}
+
+	var err error
+	var n int64
+
+	for {
+		if w.row, err = rows.ReadRow(w.row[:0]); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return n, err
+		}
+		if err = w.WriteRow(w.row); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+var (
+	_ parquet.RowWriterWithSchema = (*Writer)(nil)
+	_ parquet.RowReaderFrom       = (*Writer)(nil)
+)
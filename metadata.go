@@ -0,0 +1,157 @@
+package parquet
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go/format"
+)
+
+// FileMetadata holds the parsed metadata of a parquet file: the content of its
+// footer and its page index.
+//
+// FileMetadata values are used to open files without reading and decoding
+// their metadata again, see OpenFileWithMetadata and MetadataCache. Files
+// opened with the same metadata share its memory, programs must not modify
+// the values returned by the Metadata, ColumnIndexes, or OffsetIndexes methods
+// of those files.
+type FileMetadata struct {
+	metadata      format.FileMetaData
+	columnIndexes []format.ColumnIndex
+	offsetIndexes []format.OffsetIndex
+}
+
+// fileMetadata is the thrift representation of FileMetadata values.
+type fileMetadata struct {
+	Metadata      format.FileMetaData  `thrift:"1,required"`
+	ColumnIndexes []format.ColumnIndex `thrift:"2,optional"`
+	OffsetIndexes []format.OffsetIndex `thrift:"3,optional"`
+}
+
+// FileMetadata returns the parsed metadata of f.
+//
+// The method returns an error if f is encrypted, since its metadata is only
+// usable in combination with the decryption keys of the file.
+func (f *File) FileMetadata() (*FileMetadata, error) {
+	if f.decryptor != nil || isEncryptedFileMetaData(&f.metadata) {
+		return nil, errors.New("cannot get metadata of encrypted parquet file")
+	}
+	return &FileMetadata{
+		metadata:      f.metadata,
+		columnIndexes: f.columnIndexes,
+		offsetIndexes: f.offsetIndexes,
+	}, nil
+}
+
+// MarshalBinary serializes m to a byte slice, which can be stored and decoded
+// with UnmarshalBinary to open the file later on.
+func (m *FileMetadata) MarshalBinary() ([]byte, error) {
+	return thrift.Marshal(new(thrift.CompactProtocol), &fileMetadata{
+		Metadata:      m.metadata,
+		ColumnIndexes: m.columnIndexes,
+		OffsetIndexes: m.offsetIndexes,
+	})
+}
+
+// UnmarshalBinary decodes metadata serialized by MarshalBinary into m.
+func (m *FileMetadata) UnmarshalBinary(b []byte) error {
+	v := fileMetadata{}
+	if err := thrift.Unmarshal(new(thrift.CompactProtocol), b, &v); err != nil {
+		return fmt.Errorf("decoding parquet file metadata: %w", err)
+	}
+	if isEncryptedFileMetaData(&v.Metadata) {
+		return errors.New("decoding parquet file metadata: unsupported metadata of encrypted file")
+	}
+	sortKeyValueMetadata(v.Metadata.KeyValueMetadata)
+	*m = FileMetadata{
+		metadata:      v.Metadata,
+		columnIndexes: v.ColumnIndexes,
+		offsetIndexes: v.OffsetIndexes,
+	}
+	return nil
+}
+
+// MetadataKey identifies files in a MetadataCache.
+//
+// The name is chosen by the program opening the files, it may be a path, an
+// object key, or an entity tag. Names must not be empty and must be unique to
+// the content of files. Since parquet files are usually immutable, the size
+// only guards against reusing the metadata of a file which was replaced.
+type MetadataKey struct {
+	Name string
+	Size int64
+}
+
+// MetadataCache is an interface representing caches of file metadata, used to
+// reopen files without reading their footer and page index.
+//
+// Caches are configured on OpenFile with the CacheMetadata option.
+// Implementations must be safe to use concurrently from multiple goroutines.
+type MetadataCache interface {
+	// Load returns the metadata associated with key, and a boolean indicating
+	// whether the key was found in the cache.
+	Load(key MetadataKey) (*FileMetadata, bool)
+	// Store associates the metadata with key in the cache.
+	Store(key MetadataKey, metadata *FileMetadata)
+}
+
+// NewMetadataCache constructs an in-memory MetadataCache retaining the metadata
+// of up to capacity files, evicting the least recently used entries when the
+// capacity is exceeded.
+//
+// The function panics if the capacity is not a positive number.
+func NewMetadataCache(capacity int) MetadataCache {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("capacity of metadata cache must be positive but got %d", capacity))
+	}
+	return &lruMetadataCache{
+		capacity: capacity,
+		entries:  make(map[MetadataKey]*list.Element, capacity),
+	}
+}
+
+type lruMetadataCache struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  map[MetadataKey]*list.Element
+	order    list.List // most recently used entries first
+}
+
+type lruMetadataCacheEntry struct {
+	key      MetadataKey
+	metadata *FileMetadata
+}
+
+func (c *lruMetadataCache) Load(key MetadataKey) (*FileMetadata, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruMetadataCacheEntry).metadata, true
+}
+
+func (c *lruMetadataCache) Store(key MetadataKey, metadata *FileMetadata) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruMetadataCacheEntry).metadata = metadata
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruMetadataCacheEntry{key: key, metadata: metadata})
+
+	for c.order.Len() > c.capacity {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.entries, e.Value.(*lruMetadataCacheEntry).key)
+	}
+}
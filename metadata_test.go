@@ -0,0 +1,162 @@
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestOpenFileWithMetadata(t *testing.T) {
+	type Row struct {
+		ID   int64  `parquet:"id"`
+		Name string `parquet:"name"`
+	}
+
+	rows := make([]Row, 100)
+	for i := range rows {
+		rows[i] = Row{ID: int64(i), Name: fmt.Sprintf("name-%d", i)}
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := writeParquetFile(buffer, makeRows(rows),
+		parquet.MaxRowsPerRowGroup(30),
+		parquet.KeyValueMetadata("hello", "world"),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := readAllRows(parquet.NewReader(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := f.FileMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := metadata.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(parquet.FileMetadata)
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, metadata := range []*parquet.FileMetadata{metadata, decoded} {
+		r := &latencyReaderAt{reader: bytes.NewReader(buffer.Bytes())}
+		g, err := parquet.OpenFileWithMetadata(r, int64(buffer.Len()), metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reads := atomic.LoadInt64(&r.reads); reads != 0 {
+			t.Errorf("opening the file with metadata issued %d reads", reads)
+		}
+		if !reflect.DeepEqual(f.Metadata(), g.Metadata()) {
+			t.Error("file metadata mismatch")
+		}
+		if !reflect.DeepEqual(f.ColumnIndexes(), g.ColumnIndexes()) {
+			t.Error("column indexes mismatch")
+		}
+		if !reflect.DeepEqual(f.OffsetIndexes(), g.OffsetIndexes()) {
+			t.Error("offset indexes mismatch")
+		}
+		if value, ok := g.Lookup("hello"); !ok || value != "world" {
+			t.Errorf("key/value metadata mismatch: %q (found=%t)", value, ok)
+		}
+
+		found, err := readAllRows(parquet.NewReader(g))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != len(want) {
+			t.Fatalf("number of rows mismatch: want=%d got=%d", len(want), len(found))
+		}
+		for i := range want {
+			if !want[i].Equal(found[i]) {
+				t.Fatalf("row %d mismatch:\nwant: %v\ngot:  %v", i, want[i], found[i])
+			}
+		}
+	}
+
+	if err := new(parquet.FileMetadata).UnmarshalBinary(b[:len(b)/2]); err == nil {
+		t.Error("decoding truncated metadata did not fail")
+	}
+}
+
+func TestMetadataCache(t *testing.T) {
+	type Row struct {
+		Value int64 `parquet:"value"`
+	}
+
+	files := make([][]byte, 3)
+	for i := range files {
+		buffer := new(bytes.Buffer)
+		if err := writeParquetFile(buffer, makeRows([]Row{{Value: int64(i)}})); err != nil {
+			t.Fatal(err)
+		}
+		files[i] = buffer.Bytes()
+	}
+
+	cache := parquet.NewMetadataCache(2)
+
+	open := func(i int) int64 {
+		t.Helper()
+		r := &latencyReaderAt{reader: bytes.NewReader(files[i])}
+		f, err := parquet.OpenFile(r, int64(len(files[i])),
+			parquet.CacheMetadata(cache, fmt.Sprintf("file-%d", i)),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err := readAllRows(parquet.NewReader(f))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 1 || rows[0][0].Int64() != int64(i) {
+			t.Fatalf("wrong rows read from file %d: %v", i, rows)
+		}
+		return atomic.LoadInt64(&r.reads)
+	}
+
+	uncached := open(0)
+	if cached := open(0); cached >= uncached {
+		t.Errorf("opening a cached file did not reduce the number of reads: %d >= %d", cached, uncached)
+	}
+
+	open(1)
+	open(2) // evicts file-0
+	if _, ok := cache.Load(parquet.MetadataKey{Name: "file-0", Size: int64(len(files[0]))}); ok {
+		t.Error("least recently used file was not evicted from the cache")
+	}
+	if _, ok := cache.Load(parquet.MetadataKey{Name: "file-2", Size: int64(len(files[2]))}); !ok {
+		t.Error("most recently used file is missing from the cache")
+	}
+	if _, ok := cache.Load(parquet.MetadataKey{Name: "file-2", Size: 1}); ok {
+		t.Error("metadata of a file with a different size was found in the cache")
+	}
+}
+
+func TestMetadataCacheEmptyName(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	if err := writeParquetFile(buffer, makeRows([]struct{ Value int64 }{{Value: 1}})); err != nil {
+		t.Fatal(err)
+	}
+	input := bytes.NewReader(buffer.Bytes())
+
+	cache := parquet.NewMetadataCache(1)
+	if _, err := parquet.OpenFile(input, input.Size(), parquet.CacheMetadata(cache, "")); err == nil {
+		t.Error("opening a file with an empty name in the metadata cache did not fail")
+	}
+	if _, ok := cache.Load(parquet.MetadataKey{Size: input.Size()}); ok {
+		t.Error("metadata of a file with an empty name was stored in the cache")
+	}
+}
@@ -144,6 +144,19 @@ func (c *multiColumnChunk) OffsetIndex() OffsetIndex {
 }
 
 func (c *multiColumnChunk) BloomFilter() BloomFilter {
+	filters := make([]BloomFilter, len(c.chunks))
+	for i, chunk := range c.chunks {
+		if filters[i] = chunk.BloomFilter(); filters[i] == nil {
+			// Any value may exist in a column chunk without a filter, so
+			// there is no filter for the merged column chunk either.
+			return nil
+		}
+	}
+	// Split block filters of the same size are combined into a single filter,
+	// which is faster to check and can be written to parquet files.
+	if filter := mergeBloomFilters(filters); filter != nil {
+		return filter
+	}
 	return multiBloomFilter{c}
 }
 
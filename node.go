@@ -121,6 +121,8 @@ func (n *encodedNode) Encoding() encoding.Encoding {
 	return n.encoding
 }
 
+func (n *encodedNode) FieldID() int { return fieldIDOf(n.Node) }
+
 // Compressed wraps the node passed as argument to use the given compression
 // codec.
 //
@@ -146,6 +148,49 @@ func (n *compressedNode) Compression() compress.Codec {
 	return n.codec
 }
 
+func (n *compressedNode) FieldID() int { return fieldIDOf(n.Node) }
+
+// FieldID wraps the node passed as argument to assign it the given field id.
+//
+// Field ids identify columns independently of their names, which allows
+// applications to track columns across renames when the schema evolves.
+// The id zero is used to indicate that a node has no field id.
+//
+// Field ids are not part of the Node interface, nodes which have one expose it
+// with a FieldID method, which can be accessed with a type assertion:
+//
+//	if n, ok := node.(interface{ FieldID() int }); ok {
+//		id := n.FieldID()
+//		...
+//	}
+//
+// Field ids are also assigned with the "id" option of the parquet struct tag,
+// and are carried in the field_id property of the parquet schema elements.
+func FieldID(node Node, id int) Node {
+	return &fieldIDNode{
+		Node: node,
+		id:   id,
+	}
+}
+
+type fieldIDNode struct {
+	Node
+	id int
+}
+
+func (n *fieldIDNode) FieldID() int {
+	return n.id
+}
+
+// fieldIDOf returns the field id of node, or zero if it has none. The node
+// wrappers of this package forward the FieldID method to the nodes they wrap.
+func fieldIDOf(node Node) int {
+	if n, ok := node.(interface{ FieldID() int }); ok {
+		return n.FieldID()
+	}
+	return 0
+}
+
 // Optional wraps the given node to make it optional.
 func Optional(node Node) Node { return &optionalNode{node} }
 
@@ -155,6 +200,7 @@ func (opt *optionalNode) Optional() bool       { return true }
 func (opt *optionalNode) Repeated() bool       { return false }
 func (opt *optionalNode) Required() bool       { return false }
 func (opt *optionalNode) GoType() reflect.Type { return reflect.PtrTo(opt.Node.GoType()) }
+func (opt *optionalNode) FieldID() int         { return fieldIDOf(opt.Node) }
 
 // Repeated wraps the given node to make it repeated.
 func Repeated(node Node) Node { return &repeatedNode{node} }
@@ -165,6 +211,7 @@ func (rep *repeatedNode) Optional() bool       { return false }
 func (rep *repeatedNode) Repeated() bool       { return true }
 func (rep *repeatedNode) Required() bool       { return false }
 func (rep *repeatedNode) GoType() reflect.Type { return reflect.SliceOf(rep.Node.GoType()) }
+func (rep *repeatedNode) FieldID() int         { return fieldIDOf(rep.Node) }
 
 // Required wraps the given node to make it required.
 func Required(node Node) Node { return &requiredNode{node} }
@@ -175,6 +222,7 @@ func (req *requiredNode) Optional() bool       { return false }
 func (req *requiredNode) Repeated() bool       { return false }
 func (req *requiredNode) Required() bool       { return true }
 func (req *requiredNode) GoType() reflect.Type { return req.Node.GoType() }
+func (req *requiredNode) FieldID() int         { return fieldIDOf(req.Node) }
 
 type node struct{}
 
@@ -280,6 +328,8 @@ type groupField struct {
 
 func (f *groupField) Name() string { return f.name }
 
+func (f *groupField) FieldID() int { return fieldIDOf(f.Node) }
+
 func (f *groupField) Value(base reflect.Value) reflect.Value {
 	return base.MapIndex(reflect.ValueOf(&f.name).Elem())
 }
@@ -379,18 +429,53 @@ func numLeafColumns(node Node, columnIndex int) int {
 	return columnIndex
 }
 
+// ListElementOf returns the element node of a group of LIST logical type, laid
+// out with a repeated "list" group holding a single field, or nil if node is not
+// such a group. The element field is usually named "element", but some writers
+// use other names like "item".
+func ListElementOf(node Node) Node {
+	if lt := node.Type().LogicalType(); lt == nil || lt.List == nil {
+		return nil
+	}
+	return lookupListElementOf(node)
+}
+
+// MapKeyValueOf returns the repeated "key_value" group of a group of MAP logical
+// type, which holds the "key" and "value" fields of the map entries, or nil if
+// node is not such a group.
+func MapKeyValueOf(node Node) Node {
+	if lt := node.Type().LogicalType(); lt == nil || lt.Map == nil {
+		return nil
+	}
+	return lookupMapKeyValueOf(node)
+}
+
 func listElementOf(node Node) Node {
+	if elem := lookupListElementOf(node); elem != nil {
+		return elem
+	}
+	panic("node with logical type LIST is not composed of a repeated .list group with a single element field")
+}
+
+func lookupListElementOf(node Node) Node {
 	if !node.Leaf() {
-		if list := childByName(node, "list"); list != nil {
-			if elem := childByName(list, "element"); elem != nil {
-				return elem
+		if list := childByName(node, "list"); list != nil && !list.Leaf() && list.Repeated() {
+			if fields := list.Fields(); len(fields) == 1 {
+				return fields[0]
 			}
 		}
 	}
-	panic("node with logical type LIST is not composed of a repeated .list.element")
+	return nil
 }
 
 func mapKeyValueOf(node Node) Node {
+	if keyValue := lookupMapKeyValueOf(node); keyValue != nil {
+		return keyValue
+	}
+	panic("node with logical type MAP is not composed of a repeated .key_value group with key and value fields")
+}
+
+func lookupMapKeyValueOf(node Node) Node {
 	if !node.Leaf() && (node.Required() || node.Optional()) {
 		if keyValue := childByName(node, "key_value"); keyValue != nil && !keyValue.Leaf() && keyValue.Repeated() {
 			k := childByName(keyValue, "key")
@@ -400,7 +485,7 @@ func mapKeyValueOf(node Node) Node {
 			}
 		}
 	}
-	panic("node with logical type MAP is not composed of a repeated .key_value group with key and value fields")
+	return nil
 }
 
 func encodingOf(node Node) encoding.Encoding {
@@ -442,7 +527,7 @@ func nodesAreEqual(node1, node2 Node) bool {
 	if node1.Leaf() {
 		return node2.Leaf() && leafNodesAreEqual(node1, node2)
 	} else {
-		return !node2.Leaf() && groupNodesAreEqual(node1, node2)
+		return !node2.Leaf() && repetitionsAreEqual(node1, node2) && groupNodesAreEqual(node1, node2)
 	}
 }
 
@@ -177,7 +177,7 @@ func sizeOfFloat32(data []float32) int64 { return 4 * int64(len(data)) }
 
 func sizeOfFloat64(data []float64) int64 { return 8 * int64(len(data)) }
 
-func forEachPageSlice(page BufferedPage, wantSize int64, do func(BufferedPage) error) error {
+func forEachPageSlice(page BufferedPage, wantSize, maxRows int64, do func(BufferedPage) error) error {
 	numRows := page.NumRows()
 	if numRows == 0 {
 		return nil
@@ -185,6 +185,11 @@ func forEachPageSlice(page BufferedPage, wantSize int64, do func(BufferedPage) e
 
 	pageSize := page.Size()
 	numPages := (pageSize + (wantSize - 1)) / wantSize
+	if maxRows > 0 {
+		if n := (numRows + (maxRows - 1)) / maxRows; n > numPages {
+			numPages = n
+		}
+	}
 	rowIndex := int64(0)
 	if numPages < 2 {
 		return do(page)
@@ -261,7 +266,7 @@ func appendLevel(levels []int8, value int8, count int) []int8 {
 			levels = levels[:n]
 		}
 
-		for levels[i] = value; j < len(levels); j *= 2 {
+		for levels[i] = value; j < len(levels); j += j - i {
 			copy(levels[j:], levels[i:j])
 		}
 	}
@@ -866,6 +871,86 @@ func (page *fixedLenByteArrayPage) Encode(dst []byte, enc encoding.Encoding) ([]
 	return enc.EncodeFixedLenByteArray(dst, page.data, page.size)
 }
 
+// float16Page wraps fixed-length byte array pages of FLOAT16 columns, the
+// bounds of the page are computed by comparing the floating point values
+// instead of their byte representation.
+type float16Page struct{ *fixedLenByteArrayPage }
+
+func (page float16Page) min() []byte {
+	min, _ := page.bounds()
+	return min
+}
+
+func (page float16Page) max() []byte {
+	_, max := page.bounds()
+	return max
+}
+
+func (page float16Page) bounds() (min, max []byte) {
+	if min, max = boundsOfFloat16(page.data); min == nil && len(page.data) >= 2 {
+		// The page holds only NaN values, which are its bounds in the order
+		// defined by compareFloat16.
+		min, max = page.data[:2:2], page.data[:2:2]
+	}
+	return min, max
+}
+
+func (page float16Page) Bounds() (min, max Value, ok bool) {
+	minBytes, maxBytes := boundsOfFloat16(page.data)
+	if ok = minBytes != nil; ok {
+		min = makeValueBytes(FixedLenByteArray, minBytes)
+		max = makeValueBytes(FixedLenByteArray, maxBytes)
+	}
+	return min, max, ok
+}
+
+func (page float16Page) Clone() BufferedPage {
+	return float16Page{page.fixedLenByteArrayPage.Clone().(*fixedLenByteArrayPage)}
+}
+
+func (page float16Page) Slice(i, j int64) BufferedPage {
+	return float16Page{page.fixedLenByteArrayPage.Slice(i, j).(*fixedLenByteArrayPage)}
+}
+
+func (page float16Page) Buffer() BufferedPage { return page }
+
+var (
+	float16NegativeZero = [2]byte{0x00, 0x80}
+	float16PositiveZero = [2]byte{0x00, 0x00}
+)
+
+// boundsOfFloat16 returns the bounds of the FLOAT16 values in data, following
+// the rules of the parquet specification: NaN values are ignored, and zero
+// bounds are written as -0 for the minimum and +0 for the maximum. The function
+// returns nil bounds if data holds no values other than NaN.
+func boundsOfFloat16(data []byte) (min, max []byte) {
+	var minValue, maxValue float32
+
+	for i := 0; i+1 < len(data); i += 2 {
+		value := float16ToFloat32(data[i:])
+		switch {
+		case value != value: // NaN
+		case min == nil:
+			min, minValue = data[i:i+2:i+2], value
+			max, maxValue = min, value
+		case value < minValue:
+			min, minValue = data[i:i+2:i+2], value
+		case value > maxValue:
+			max, maxValue = data[i:i+2:i+2], value
+		}
+	}
+
+	if min != nil {
+		if minValue == 0 {
+			min = float16NegativeZero[:]
+		}
+		if maxValue == 0 {
+			max = float16PositiveZero[:]
+		}
+	}
+	return min, max
+}
+
 type fixedLenByteArrayPageReader struct {
 	page   *fixedLenByteArrayPage
 	offset int
@@ -108,6 +108,10 @@ func (buf *fileBuffer) Read(b []byte) (int, error) {
 	return n, err
 }
 
+func (buf *fileBuffer) ReadAt(b []byte, off int64) (int, error) {
+	return buf.file.ReadAt(b, off)
+}
+
 func (buf *fileBuffer) ReadFrom(r io.Reader) (int64, error) {
 	return buf.file.ReadFrom(r)
 }
@@ -123,6 +127,7 @@ func (buf *fileBuffer) WriteString(s string) (int, error) {
 type errorBuffer struct{ err error }
 
 func (buf *errorBuffer) Read([]byte) (int, error)          { return 0, buf.err }
+func (buf *errorBuffer) ReadAt([]byte, int64) (int, error) { return 0, buf.err }
 func (buf *errorBuffer) Write([]byte) (int, error)         { return 0, buf.err }
 func (buf *errorBuffer) WriteString(string) (int, error)   { return 0, buf.err }
 func (buf *errorBuffer) ReadFrom(io.Reader) (int64, error) { return 0, buf.err }
@@ -131,9 +136,11 @@ func (buf *errorBuffer) WriteTo(io.Writer) (int64, error)  { return 0, buf.err }
 var (
 	defaultPageBufferPool pageBufferPool
 
+	_ io.ReaderAt     = (*fileBuffer)(nil)
 	_ io.ReaderFrom   = (*fileBuffer)(nil)
 	_ io.StringWriter = (*fileBuffer)(nil)
 
+	_ io.ReaderAt     = (*errorBuffer)(nil)
 	_ io.ReaderFrom   = (*errorBuffer)(nil)
 	_ io.WriterTo     = (*errorBuffer)(nil)
 	_ io.StringWriter = (*errorBuffer)(nil)
@@ -0,0 +1,162 @@
+package parquet
+
+import "io"
+
+// prefetchRowGroup wraps rowGroup so that the pages of its column chunks are
+// read, decompressed, and decoded ahead of time on background goroutines.
+//
+// Each column reads its pages on its own goroutine, but the number of columns
+// decoding pages at the same time is bounded by the concurrency level, which
+// makes the goroutines behave like a worker pool shared by all the columns of
+// the row group. Pages are handed back to the reader in the order they appear
+// in the column chunks, so the prefetching is transparent to the row assembly.
+func prefetchRowGroup(rowGroup RowGroup, concurrency int) RowGroup {
+	if concurrency <= 1 {
+		return rowGroup
+	}
+
+	baseColumns := rowGroup.ColumnChunks()
+	pool := make(chan struct{}, concurrency)
+
+	g := &prefetchedRowGroup{
+		base:    rowGroup,
+		columns: make([]ColumnChunk, len(baseColumns)),
+	}
+
+	columns := make([]prefetchedColumnChunk, len(baseColumns))
+	for i, column := range baseColumns {
+		columns[i].ColumnChunk = column
+		columns[i].pool = pool
+		g.columns[i] = &columns[i]
+	}
+
+	return g
+}
+
+type prefetchedRowGroup struct {
+	base    RowGroup
+	columns []ColumnChunk
+}
+
+func (g *prefetchedRowGroup) NumRows() int64                  { return g.base.NumRows() }
+func (g *prefetchedRowGroup) ColumnChunks() []ColumnChunk     { return g.columns }
+func (g *prefetchedRowGroup) Schema() *Schema                 { return g.base.Schema() }
+func (g *prefetchedRowGroup) SortingColumns() []SortingColumn { return g.base.SortingColumns() }
+func (g *prefetchedRowGroup) Rows() Rows                      { return &rowGroupRowReader{rowGroup: g} }
+
+type prefetchedColumnChunk struct {
+	ColumnChunk
+	pool chan struct{}
+}
+
+func (c *prefetchedColumnChunk) Pages() Pages {
+	return &prefetchedPages{base: c.ColumnChunk.Pages(), pool: c.pool}
+}
+
+// prefetchedPages reads pages from a base Pages instance on a background
+// goroutine, which is started on the first call to ReadPage and runs until
+// the end of the column chunk is reached, an error occurs, or the reader is
+// repositioned or closed.
+//
+// Page readers usually reuse their buffers when reading the next page, so
+// the goroutine clones the pages before handing them back to the reader.
+type prefetchedPages struct {
+	base  Pages
+	pool  chan struct{}
+	pages chan prefetchedPage
+	done  chan struct{}
+	err   error
+}
+
+type prefetchedPage struct {
+	page Page
+	err  error
+}
+
+// prefetchedPageCount is the number of decoded pages that may be waiting to
+// be consumed in each column, in addition to the page being decoded.
+const prefetchedPageCount = 1
+
+func (r *prefetchedPages) ReadPage() (Page, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.pages == nil {
+		r.pages = make(chan prefetchedPage, prefetchedPageCount)
+		r.done = make(chan struct{})
+		go prefetchPages(r.base, r.pool, r.pages, r.done)
+	}
+	p, ok := <-r.pages
+	if !ok {
+		p.err = io.EOF
+	}
+	if p.err != nil {
+		r.err = p.err
+	}
+	return p.page, p.err
+}
+
+func (r *prefetchedPages) SeekToRow(rowIndex int64) error {
+	r.stop()
+	r.err = nil
+	return r.base.SeekToRow(rowIndex)
+}
+
+// Close stops the goroutine prefetching pages. The base page reader may be
+// repositioned with SeekToRow after closing, which resumes prefetching.
+func (r *prefetchedPages) Close() error {
+	r.stop()
+	return nil
+}
+
+func (r *prefetchedPages) stop() {
+	if r.done != nil {
+		close(r.done)
+		// The channel is closed when the goroutine exits, after which it is
+		// safe to use the base page reader again.
+		for range r.pages {
+		}
+		r.pages, r.done = nil, nil
+	}
+}
+
+func prefetchPages(base Pages, pool chan struct{}, pages chan<- prefetchedPage, done <-chan struct{}) {
+	defer close(pages)
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		select {
+		case pool <- struct{}{}:
+		case <-done:
+			return
+		}
+
+		page, err := base.ReadPage()
+		if page != nil {
+			page = page.Buffer().Clone()
+		}
+		<-pool
+
+		select {
+		case pages <- prefetchedPage{page, err}:
+		case <-done:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+var (
+	_ RowGroup    = (*prefetchedRowGroup)(nil)
+	_ ColumnChunk = (*prefetchedColumnChunk)(nil)
+	_ Pages       = (*prefetchedPages)(nil)
+	_ io.Closer   = (*prefetchedPages)(nil)
+)
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/olekukonko/tablewriter"
+	"github.com/segmentio/parquet-go/deprecated"
 )
 
 func PrintSchema(w io.Writer, name string, node Node) error {
@@ -94,6 +95,7 @@ func printSchemaWithIndent(w io.StringWriter, name string, node Node, indent *pr
 			w.WriteString(")")
 		}
 
+		printFieldID(w, node)
 		w.WriteString(";")
 	} else {
 		w.WriteString("group")
@@ -109,6 +111,7 @@ func printSchemaWithIndent(w io.StringWriter, name string, node Node, indent *pr
 			w.WriteString(")")
 		}
 
+		printFieldID(w, node)
 		w.WriteString(" {")
 		indent.writeNewLine(w)
 		indent.push()
@@ -124,10 +127,30 @@ func printSchemaWithIndent(w io.StringWriter, name string, node Node, indent *pr
 	}
 }
 
+func printFieldID(w io.StringWriter, node Node) {
+	if id := fieldIDOf(node); id != 0 {
+		w.WriteString(" = ")
+		w.WriteString(strconv.Itoa(id))
+	}
+}
+
+// printValue returns the representation of v in the output of PrintRowGroup,
+// using the representation defined by the column type when there is one.
+func printValue(columnType Type, v Value) string {
+	if t, ok := columnType.(interface{ printValue(Value) string }); ok && !v.IsNull() {
+		return t.printValue(v)
+	}
+	return v.String()
+}
+
 func annotationOf(node Node) string {
 	if logicalType := node.Type().LogicalType(); logicalType != nil {
 		return logicalType.String()
 	}
+	// INTERVAL is the only converted type which has no equivalent logical type.
+	if convertedType := node.Type().ConvertedType(); convertedType != nil && *convertedType == deprecated.Interval {
+		return "INTERVAL"
+	}
 	return ""
 }
 
@@ -205,10 +228,12 @@ func PrintRowGroup(w io.Writer, rowGroup RowGroup) error {
 	header := make([]string, len(columns))
 	footer := make([]string, len(columns))
 	alignment := make([]int, len(columns))
+	columnTypes := make([]Type, len(columns))
 
 	for i, column := range columns {
 		leaf, _ := schema.Lookup(column...)
 		columnType := leaf.Node.Type()
+		columnTypes[i] = columnType
 
 		header[i] = strings.Join(column, ".")
 		footer[i] = columnType.String()
@@ -244,12 +269,14 @@ func PrintRowGroup(w io.Writer, rowGroup RowGroup) error {
 				j++
 			}
 
+			columnType := columnTypes[row[i].Column()]
+
 			if (j - i) == 1 {
-				cells = append(cells, row[i].String())
+				cells = append(cells, printValue(columnType, row[i]))
 			} else {
 				parts = parts[:0]
 				for k := i; k < j; k++ {
-					parts = append(parts, row[k].String())
+					parts = append(parts, printValue(columnType, row[k]))
 				}
 				alignment[len(cells)] = tablewriter.ALIGN_LEFT
 				cells = append(cells, strings.Join(parts, ","))
@@ -0,0 +1,274 @@
+package parquet
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ProjectSchema returns a schema made of the subset of columns of schema
+// selected by the list of paths passed as arguments.
+//
+// Each path is the sequence of names leading to a column of the schema (not
+// including the root). Paths may designate leaf columns, or groups, in which
+// case all the leaf columns of the group are selected. Nested columns of
+// groups, lists and maps are selected with paths mirroring the layout of the
+// parquet schema, for example []string{"tags", "key_value", "value"} selects
+// the values of a map named "tags". Selecting columns of a map implicitly
+// selects the keys of the map, which are required by the MAP logical type.
+//
+// The groups of the projected schema retain the repetition and logical types
+// of the original groups, and the order of their fields.
+//
+// The function returns an error wrapping ErrColumnNotFound if one of the paths
+// does not exist in the schema.
+func ProjectSchema(schema *Schema, paths ...[]string) (*Schema, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("cannot project parquet schema %q on an empty list of columns", schema.Name())
+	}
+	root := new(projection)
+	for _, path := range paths {
+		if err := root.add(schema.root, path); err != nil {
+			return nil, err
+		}
+	}
+	return NewSchema(schema.Name(), root.project(schema.root)), nil
+}
+
+// ProjectRowGroup constructs a view of rowGroup exposing only the columns
+// selected by the list of paths passed as arguments.
+//
+// The schema of the returned row group is the result of calling ProjectSchema
+// with the schema of rowGroup and paths, and the rows that it produces are laid
+// out according to this projected schema. Pages of the column chunks that were
+// not selected are never read.
+func ProjectRowGroup(rowGroup RowGroup, paths ...[]string) (RowGroup, error) {
+	schema, err := ProjectSchema(rowGroup.Schema(), paths...)
+	if err != nil {
+		return nil, err
+	}
+	return newProjectedRowGroup(rowGroup, schema), nil
+}
+
+// projection is a tree representing the set of columns selected by a list of
+// paths. A nil map of fields indicates that the whole subtree is selected.
+type projection struct {
+	fields map[string]*projection
+}
+
+func (p *projection) all() bool { return p.fields == nil }
+
+func (p *projection) add(node Node, path []string) error {
+	for i, name := range path {
+		child := childByName(node, name)
+		if child == nil {
+			return fmt.Errorf("cannot project column %s: %w", columnPath(path[:i+1]), ErrColumnNotFound)
+		}
+		if p.fields == nil {
+			p.fields = make(map[string]*projection)
+		}
+		next := p.fields[name]
+		if next == nil {
+			next = new(projection)
+			p.fields[name] = next
+		} else if next.all() {
+			return nil // the column was already selected by a parent path
+		}
+		node, p = child, next
+	}
+	p.fields = nil
+	return nil
+}
+
+func (p *projection) project(node Node) Node {
+	if p.all() {
+		return node
+	}
+
+	// Maps are detected by their layout rather than their logical type, which
+	// is not always present on the groups of schemas read from parquet files.
+	if keyValue := p.fields["key_value"]; keyValue != nil && !keyValue.all() && keyValue.fields["key"] == nil {
+		if n := childByName(node, "key_value"); n != nil && n.Repeated() && childByName(n, "key") != nil {
+			keyValue.fields["key"] = new(projection)
+		}
+	}
+
+	fields := node.Fields()
+	group := &projectedGroup{
+		Node:   node,
+		fields: make([]Field, 0, len(p.fields)),
+	}
+
+	for _, field := range fields {
+		if child := p.fields[field.Name()]; child != nil {
+			group.fields = append(group.fields, &projectedField{
+				Node: child.project(field),
+				name: field.Name(),
+			})
+		}
+	}
+
+	return group
+}
+
+// projectedGroup is a group node exposing a subset of the fields of another
+// group, retaining its repetition, logical type, and the order of the fields.
+type projectedGroup struct {
+	Node
+	fields []Field
+}
+
+func (g *projectedGroup) String() string       { return sprint("", g) }
+func (g *projectedGroup) Fields() []Field      { return g.fields }
+func (g *projectedGroup) GoType() reflect.Type { return goTypeOf(g) }
+func (g *projectedGroup) FieldID() int         { return fieldIDOf(g.Node) }
+
+type projectedField struct {
+	Node
+	name string
+}
+
+func (f *projectedField) Name() string { return f.name }
+
+func (f *projectedField) FieldID() int { return fieldIDOf(f.Node) }
+
+func (f *projectedField) Value(base reflect.Value) reflect.Value {
+	switch base.Kind() {
+	case reflect.Map:
+		return base.MapIndex(reflect.ValueOf(&f.name).Elem())
+	default:
+		// The Go types of projected groups are the struct types generated by
+		// goTypeOfGroup, which names the struct fields after the columns.
+		return base.FieldByName(exportedStructFieldName(f.name))
+	}
+}
+
+// projectedRowGroup is the implementation of row groups returned by
+// ProjectRowGroup.
+//
+// The column chunks of the row group wrap the chunks of the base row group
+// which were selected by the projection, the others are not referenced and
+// therefore their pages are never read.
+type projectedRowGroup struct {
+	base    RowGroup
+	schema  *Schema
+	columns []ColumnChunk
+	sorting []SortingColumn
+}
+
+func newProjectedRowGroup(base RowGroup, schema *Schema) *projectedRowGroup {
+	baseSchema := base.Schema()
+	baseColumns := base.ColumnChunks()
+	numColumns := numLeafColumnsOf(schema)
+
+	g := &projectedRowGroup{
+		base:    base,
+		schema:  schema,
+		columns: make([]ColumnChunk, numColumns),
+	}
+
+	columns := make([]projectedColumnChunk, numColumns)
+	forEachLeafColumnOf(schema, func(leaf leafColumn) {
+		baseLeaf, _ := baseSchema.Lookup(leaf.path...)
+		columns[leaf.columnIndex].base = baseColumns[baseLeaf.ColumnIndex]
+		columns[leaf.columnIndex].column = leaf.columnIndex
+		g.columns[leaf.columnIndex] = &columns[leaf.columnIndex]
+	})
+
+	// Like for converted row groups, only the prefix of sorting columns which
+	// exist in the projected schema describes the order of the rows.
+	for _, col := range base.SortingColumns() {
+		if !hasColumnPath(schema, col.Path()) {
+			break
+		}
+		g.sorting = append(g.sorting, col)
+	}
+
+	return g
+}
+
+func (g *projectedRowGroup) NumRows() int64                  { return g.base.NumRows() }
+func (g *projectedRowGroup) ColumnChunks() []ColumnChunk     { return g.columns }
+func (g *projectedRowGroup) Schema() *Schema                 { return g.schema }
+func (g *projectedRowGroup) SortingColumns() []SortingColumn { return g.sorting }
+func (g *projectedRowGroup) Rows() Rows                      { return &rowGroupRowReader{rowGroup: g} }
+
+// projectedColumnChunk exposes a column chunk at a different index than the
+// one it had in its original row group. Values read from the pages carry the
+// column index of the projected schema.
+type projectedColumnChunk struct {
+	base   ColumnChunk
+	column int16
+}
+
+func (c *projectedColumnChunk) Type() Type               { return c.base.Type() }
+func (c *projectedColumnChunk) Column() int              { return int(c.column) }
+func (c *projectedColumnChunk) Pages() Pages             { return &projectedPages{c.base.Pages(), c.column} }
+func (c *projectedColumnChunk) ColumnIndex() ColumnIndex { return c.base.ColumnIndex() }
+func (c *projectedColumnChunk) OffsetIndex() OffsetIndex { return c.base.OffsetIndex() }
+func (c *projectedColumnChunk) BloomFilter() BloomFilter { return c.base.BloomFilter() }
+func (c *projectedColumnChunk) NumValues() int64         { return c.base.NumValues() }
+
+type projectedPages struct {
+	base   Pages
+	column int16
+}
+
+func (p *projectedPages) ReadPage() (Page, error) {
+	page, err := p.base.ReadPage()
+	if page != nil {
+		page = &projectedPage{page, p.column}
+	}
+	return page, err
+}
+
+func (p *projectedPages) SeekToRow(rowIndex int64) error { return p.base.SeekToRow(rowIndex) }
+
+type projectedPage struct {
+	Page
+	column int16
+}
+
+func (p *projectedPage) Column() int         { return int(p.column) }
+func (p *projectedPage) Values() ValueReader { return &projectedValues{p.Page.Values(), p.column} }
+func (p *projectedPage) Buffer() BufferedPage {
+	return &projectedBufferedPage{p.Page.Buffer(), p.column}
+}
+
+type projectedBufferedPage struct {
+	BufferedPage
+	column int16
+}
+
+func (p *projectedBufferedPage) Column() int { return int(p.column) }
+func (p *projectedBufferedPage) Values() ValueReader {
+	return &projectedValues{p.BufferedPage.Values(), p.column}
+}
+func (p *projectedBufferedPage) Buffer() BufferedPage { return p }
+
+func (p *projectedBufferedPage) Clone() BufferedPage {
+	return &projectedBufferedPage{p.BufferedPage.Clone(), p.column}
+}
+
+func (p *projectedBufferedPage) Slice(i, j int64) BufferedPage {
+	return &projectedBufferedPage{p.BufferedPage.Slice(i, j), p.column}
+}
+
+type projectedValues struct {
+	base   ValueReader
+	column int16
+}
+
+func (r *projectedValues) ReadValues(values []Value) (int, error) {
+	n, err := r.base.ReadValues(values)
+	for i := range values[:n] {
+		values[i].columnIndex = ^r.column
+	}
+	return n, err
+}
+
+var (
+	_ RowGroup     = (*projectedRowGroup)(nil)
+	_ ColumnChunk  = (*projectedColumnChunk)(nil)
+	_ Pages        = (*projectedPages)(nil)
+	_ BufferedPage = (*projectedBufferedPage)(nil)
+)
@@ -0,0 +1,247 @@
+package parquet_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+type projectionItem struct {
+	SKU string `parquet:"sku"`
+	Qty int32  `parquet:"qty"`
+}
+
+type projectionAddress struct {
+	City string `parquet:"city"`
+	Zip  string `parquet:"zip"`
+}
+
+type projectionRow struct {
+	ID      int64             `parquet:"id"`
+	Name    string            `parquet:"name"`
+	Address projectionAddress `parquet:"address"`
+	Tags    map[string]int64  `parquet:"tags"`
+	Items   []projectionItem  `parquet:"items,list"`
+}
+
+type projectedRow struct {
+	ID      int64 `parquet:"id"`
+	Address struct {
+		City string `parquet:"city"`
+	} `parquet:"address"`
+	Tags  map[string]int64 `parquet:"tags"`
+	Items []struct {
+		Qty int32 `parquet:"qty"`
+	} `parquet:"items,list"`
+}
+
+var projectedColumns = [][]string{
+	{"id"},
+	{"address", "city"},
+	{"tags", "key_value", "value"},
+	{"items", "list", "element", "qty"},
+}
+
+func makeProjectionRows(numRows int) []projectionRow {
+	rows := make([]projectionRow, numRows)
+	for i := range rows {
+		rows[i] = projectionRow{
+			ID:   int64(i),
+			Name: fmt.Sprintf("name-%d", i),
+			Address: projectionAddress{
+				City: fmt.Sprintf("city-%d", i%10),
+				Zip:  fmt.Sprintf("%05d", i),
+			},
+			Tags:  map[string]int64{},
+			Items: make([]projectionItem, i%4),
+		}
+		for j := 0; j < i%3; j++ {
+			rows[i].Tags[fmt.Sprintf("tag-%d", j)] = int64(i * j)
+		}
+		for j := range rows[i].Items {
+			rows[i].Items[j] = projectionItem{SKU: fmt.Sprintf("sku-%d-%d", i, j), Qty: int32(j)}
+		}
+	}
+	return rows
+}
+
+func makeProjectedRows(rows []projectionRow) []projectedRow {
+	projected := make([]projectedRow, len(rows))
+	for i, row := range rows {
+		projected[i].ID = row.ID
+		projected[i].Address.City = row.Address.City
+		projected[i].Tags = row.Tags
+		projected[i].Items = make([]struct {
+			Qty int32 `parquet:"qty"`
+		}, len(row.Items))
+		for j, item := range row.Items {
+			projected[i].Items[j].Qty = item.Qty
+		}
+	}
+	return projected
+}
+
+func TestProjectSchema(t *testing.T) {
+	schema := parquet.SchemaOf(projectionRow{})
+
+	projected, err := parquet.ProjectSchema(schema, projectedColumns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `message projectionRow {
+	required int64 id (INT(64,true));
+	required group address {
+		required binary city (STRING);
+	}
+	required group tags (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			required int64 value (INT(64,true));
+		}
+	}
+	required group items (LIST) {
+		repeated group list {
+			required group element {
+				required int32 qty (INT(32,true));
+			}
+		}
+	}
+}`
+
+	if got := projected.String(); got != want {
+		t.Errorf("projected schema mismatch:\nwant:\n%s\ngot:\n%s", want, got)
+	}
+
+	if _, err := parquet.ProjectSchema(schema, []string{"address", "country"}); !errors.Is(err, parquet.ErrColumnNotFound) {
+		t.Errorf("projecting a missing column returned the wrong error: %v", err)
+	}
+}
+
+func TestReaderSelectColumns(t *testing.T) {
+	rows := makeProjectionRows(100)
+	want := makeProjectedRows(rows)
+
+	buffer := new(bytes.Buffer)
+	if err := writeParquetFile(buffer, makeRows(rows)); err != nil {
+		t.Fatal(err)
+	}
+	input := bytes.NewReader(buffer.Bytes())
+
+	t.Run("reader", func(t *testing.T) {
+		reader := parquet.NewReader(input, parquet.SelectColumns(projectedColumns...))
+		got := []projectedRow{}
+		for {
+			row := projectedRow{}
+			if err := reader.Read(&row); err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				break
+			}
+			got = append(got, row)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Error("rows mismatch")
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		f, err := parquet.OpenFile(input, input.Size(), parquet.SelectColumns(projectedColumns...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(f.Schema().Columns()); n != 5 {
+			t.Errorf("wrong number of columns in the projected file schema: want=5 got=%d", n)
+		}
+		reader := parquet.NewReader(f)
+		for i := range want {
+			row := projectedRow{}
+			if err := reader.Read(&row); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(row, want[i]) {
+				t.Fatalf("row %d mismatch: want=%+v got=%+v", i, want[i], row)
+			}
+		}
+	})
+}
+
+// unreadColumnChunk is a column chunk which fails the test if its pages are
+// read.
+type unreadColumnChunk struct {
+	parquet.ColumnChunk
+	t *testing.T
+}
+
+func (c unreadColumnChunk) Pages() parquet.Pages {
+	c.t.Errorf("pages of column %d were read", c.Column())
+	return c.ColumnChunk.Pages()
+}
+
+type unreadColumnsRowGroup struct {
+	parquet.RowGroup
+	columns []parquet.ColumnChunk
+}
+
+func (g unreadColumnsRowGroup) ColumnChunks() []parquet.ColumnChunk { return g.columns }
+
+func TestProjectRowGroup(t *testing.T) {
+	rows := makeProjectionRows(50)
+	want := makeProjectedRows(rows)
+
+	buffer := parquet.NewBuffer()
+	for i := range rows {
+		if err := buffer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	selected := map[int]bool{}
+	for _, path := range append(projectedColumns, []string{"tags", "key_value", "key"}) {
+		leaf, _ := buffer.Schema().Lookup(path...)
+		selected[leaf.ColumnIndex] = true
+	}
+
+	rowGroup := unreadColumnsRowGroup{RowGroup: buffer}
+	for i, column := range buffer.ColumnChunks() {
+		if !selected[i] {
+			column = unreadColumnChunk{ColumnChunk: column, t: t}
+		}
+		rowGroup.columns = append(rowGroup.columns, column)
+	}
+
+	projected, err := parquet.ProjectRowGroup(rowGroup, projectedColumns...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(projected.ColumnChunks()); n != 5 {
+		t.Errorf("wrong number of column chunks in the projected row group: want=5 got=%d", n)
+	}
+
+	// The projected schema has the same layout as the schema of projectedRow,
+	// so rows can be reconstructed directly into values of this type.
+	schema := parquet.SchemaOf(projectedRow{})
+	rowReader := projected.Rows()
+	for i := range want {
+		row, err := rowReader.ReadRow(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value := projectedRow{}
+		if err := schema.Reconstruct(&value, row); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(value, want[i]) {
+			t.Fatalf("row %d mismatch: want=%+v got=%+v", i, want[i], value)
+		}
+	}
+	if _, err := rowReader.ReadRow(nil); err != io.EOF {
+		t.Errorf("expected io.EOF after reading all rows, got %v", err)
+	}
+}
@@ -57,15 +57,9 @@ type Reader struct {
 //	}
 //
 func NewReader(input io.ReaderAt, options ...ReaderOption) *Reader {
-	f, _ := input.(*File)
-	if f == nil {
-		n, err := sizeOf(input)
-		if err != nil {
-			panic(err)
-		}
-		if f, err = OpenFile(input, n); err != nil {
-			panic(err)
-		}
+	f, err := openFile(input)
+	if err != nil {
+		panic(err)
 	}
 
 	c, err := NewReaderConfig(options...)
@@ -73,30 +67,23 @@ func NewReader(input io.ReaderAt, options ...ReaderOption) *Reader {
 		panic(err)
 	}
 
-	column := f.Root()
-	schema := NewSchema(column.Name(), column)
+	rowGroup := fileRowGroupOf(f, c.Filter)
+	schema := rowGroup.Schema()
 
-	r := &Reader{
-		file: reader{schema: schema},
+	if c.Columns != nil {
+		rowGroup = projectRowGroup(rowGroup, c.Columns)
+		schema = rowGroup.Schema()
 	}
 
-	switch rowGroups := f.RowGroups(); len(rowGroups) {
-	case 0:
-		r.file.rowGroup = newEmptyRowGroup(schema)
-	case 1:
-		r.file.rowGroup = rowGroups[0]
-	default:
-		// TODO: should we attempt to merge the row groups via MergeRowGroups
-		// to preserve the global order of sorting columns within the file?
-		r.file.rowGroup = MultiRowGroup(rowGroups...)
-	}
+	rowGroup = prefetchRowGroup(rowGroup, c.Concurrency)
 
 	if c.Schema != nil {
-		r.file.schema = c.Schema
-		r.file.rowGroup = convertRowGroupTo(r.file.rowGroup, c.Schema)
+		schema = c.Schema
+		rowGroup = convertRowGroupTo(rowGroup, c.Schema)
 	}
 
-	r.read.init(r.file.schema, r.file.rowGroup)
+	r := &Reader{}
+	r.init(schema, rowGroup, c.Filter)
 	return r
 }
 
@@ -108,19 +95,86 @@ func NewRowGroupReader(rowGroup RowGroup, options ...ReaderOption) *Reader {
 		panic(err)
 	}
 
+	if c.Filter != nil {
+		rowGroup = filterRowGroup(rowGroup, c.Filter)
+	}
+
+	if c.Columns != nil {
+		rowGroup = projectRowGroup(rowGroup, c.Columns)
+	}
+
+	rowGroup = prefetchRowGroup(rowGroup, c.Concurrency)
+
 	if c.Schema != nil {
 		rowGroup = convertRowGroupTo(rowGroup, c.Schema)
 	}
 
-	r := &Reader{
-		file: reader{
-			schema:   rowGroup.Schema(),
-			rowGroup: rowGroup,
-		},
+	r := &Reader{}
+	r.init(rowGroup.Schema(), rowGroup, c.Filter)
+	return r
+}
+
+func (r *Reader) init(schema *Schema, rowGroup RowGroup, filter Filter) {
+	if err := r.file.init(schema, rowGroup, filter); err != nil {
+		panic(err)
+	}
+	if err := r.read.init(schema, rowGroup, filter); err != nil {
+		panic(err)
 	}
+}
 
-	r.read.init(r.file.schema, r.file.rowGroup)
-	return r
+func openFile(input io.ReaderAt) (*File, error) {
+	f, _ := input.(*File)
+	if f != nil {
+		return f, nil
+	}
+	n, err := sizeOf(input)
+	if err != nil {
+		return nil, err
+	}
+	return OpenFile(input, n)
+}
+
+func fileRowGroupOf(f *File, filter Filter) RowGroup {
+	return rowGroupOf(f.schema, f.RowGroups(), filter)
+}
+
+func filterRowGroup(rowGroup RowGroup, filter Filter) RowGroup {
+	rowGroups := []RowGroup{rowGroup}
+	// Multi row groups are unwrapped so each row group can be eliminated
+	// independently using the metadata of its column chunks.
+	if m, ok := rowGroup.(*multiRowGroup); ok {
+		rowGroups = m.rowGroups
+	}
+	return rowGroupOf(rowGroup.Schema(), rowGroups, filter)
+}
+
+func rowGroupOf(schema *Schema, rowGroups []RowGroup, filter Filter) RowGroup {
+	if filter != nil {
+		filtered, err := filterRowGroups(rowGroups, filter)
+		if err != nil {
+			panic(err)
+		}
+		rowGroups = filtered
+	}
+	switch len(rowGroups) {
+	case 0:
+		return newEmptyRowGroup(schema)
+	case 1:
+		return rowGroups[0]
+	default:
+		// TODO: should we attempt to merge the row groups via MergeRowGroups
+		// to preserve the global order of sorting columns within the file?
+		return MultiRowGroup(rowGroups...)
+	}
+}
+
+func projectRowGroup(rowGroup RowGroup, columns [][]string) RowGroup {
+	projected, err := ProjectRowGroup(rowGroup, columns...)
+	if err != nil {
+		panic(err)
+	}
+	return projected
 }
 
 func convertRowGroupTo(rowGroup RowGroup, schema *Schema) RowGroup {
@@ -165,9 +219,28 @@ func (r *Reader) Reset() {
 	clearValues(r.values)
 }
 
+// Close releases the resources held by the reader.
+//
+// Closing the reader is only required when it was configured to read pages
+// concurrently (see ReadConcurrency) and the program stops reading rows before
+// reaching the end, in which case it stops the goroutines prefetching pages.
+// Reading from the reader after closing it resumes where it left off.
+func (r *Reader) Close() error {
+	err1 := r.file.Close()
+	err2 := r.read.Close()
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
+
 // Read reads the next row from r. The type of the row must match the schema
 // of the underlying parquet file or an error will be returned.
 //
+// The row may also be a pointer to a map[string]interface{}, or an interface,
+// which receive the values of the row as dynamic values (see
+// Schema.Reconstruct).
+//
 // The method returns io.EOF when no more rows can be read from r.
 func (r *Reader) Read(row interface{}) (err error) {
 	if rowType := dereference(reflect.TypeOf(row)); rowType.Kind() == reflect.Struct {
@@ -183,25 +256,32 @@ func (r *Reader) Read(row interface{}) (err error) {
 	}
 
 	r.values, err = r.read.ReadRow(r.values[:0])
+	if err == nil || err == io.EOF {
+		// The reader may have skipped rows which did not match the filter.
+		r.rowIndex = r.read.rowIndex
+	}
 	if err != nil {
 		return err
 	}
 
-	r.rowIndex++
 	return r.read.schema.Reconstruct(row, r.values)
 }
 
 func (r *Reader) updateReadSchema(rowType reflect.Type) error {
 	schema := schemaOf(rowType)
 
-	if nodesAreEqual(schema, r.file.schema) {
-		r.read.init(schema, r.file.rowGroup)
-	} else {
+	rowGroup := r.file.rowGroup
+
+	if !nodesAreEqual(schema, r.file.schema) {
 		conv, err := Convert(schema, r.file.schema)
 		if err != nil {
 			return err
 		}
-		r.read.init(schema, ConvertRowGroup(r.file.rowGroup, conv))
+		rowGroup = ConvertRowGroup(rowGroup, conv)
+	}
+
+	if err := r.read.init(schema, rowGroup, r.file.filter); err != nil {
+		return err
 	}
 
 	r.seen = rowType
@@ -219,8 +299,8 @@ func (r *Reader) ReadRow(row Row) (Row, error) {
 		return row, err
 	}
 	row, err := r.file.ReadRow(row)
-	if err == nil {
-		r.rowIndex++
+	if err == nil || err == io.EOF {
+		r.rowIndex = r.file.rowIndex
 	}
 	return row, err
 }
@@ -245,24 +325,48 @@ func (r *Reader) SeekToRow(rowIndex int64) error {
 // are read from the underlying parquet file), or calling the Read method to
 // read rows into Go values, potentially doing partial reads on a subset of the
 // columns due to using a converted row group view.
+//
+// When a filter is configured, rows which do not match it are skipped, and the
+// row index keeps track of the position in the underlying row group.
 type reader struct {
 	schema   *Schema
 	rowGroup RowGroup
 	rows     Rows
 	rowIndex int64
+	filter   Filter
+	match    func(Row) bool
 }
 
-func (r *reader) init(schema *Schema, rowGroup RowGroup) {
+func (r *reader) init(schema *Schema, rowGroup RowGroup, filter Filter) error {
 	r.schema = schema
 	r.rowGroup = rowGroup
+	r.filter = filter
+	r.match = nil
 	r.Reset()
+
+	if filter != nil {
+		f, err := filter.bind(schema)
+		if err != nil {
+			return err
+		}
+		r.match = f.matchRow
+	}
+	return nil
 }
 
 func (r *reader) Reset() {
+	r.Close()
 	r.rows = nil // TODO: can we make the RowReader reusable?
 	r.rowIndex = 0
 }
 
+func (r *reader) Close() error {
+	if closer, ok := r.rows.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 func (r *reader) ReadRow(row Row) (Row, error) {
 	if r.rows == nil {
 		r.rows = r.rowGroup.Rows()
@@ -273,13 +377,18 @@ func (r *reader) ReadRow(row Row) (Row, error) {
 		}
 	}
 	n := len(row)
-	row, err := r.rows.ReadRow(row)
-	if err == nil && len(row) == n {
-		err = io.EOF
-	} else {
+	for {
+		row, err := r.rows.ReadRow(row)
+		if err == nil && len(row) == n {
+			return row, io.EOF
+		}
 		r.rowIndex++
+		if err != nil || r.match == nil || r.match(row[n:]) {
+			return row, err
+		}
+		clearValues(row[n:])
+		row = row[:n]
 	}
-	return row, err
 }
 
 func (r *reader) SeekToRow(rowIndex int64) error {
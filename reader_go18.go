@@ -0,0 +1,190 @@
+//go:build go1.18
+
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// GenericReader is similar to a Reader but uses a type parameter to define the
+// Go type representing the schema of rows being read.
+//
+// This example showcases a typical use of generic parquet readers:
+//
+//	reader := parquet.NewGenericReader[RowType](file)
+//	rows := make([]RowType, 100)
+//	for {
+//		n, err := reader.Read(rows)
+//		process(rows[:n])
+//		if err != nil {
+//			if err == io.EOF {
+//				break
+//			}
+//			...
+//		}
+//	}
+//
+// The parquet schema of the type parameter is compiled once when the reader is
+// constructed, and rows are decoded directly into the slice passed to Read,
+// which avoids the dynamic type checks and allocations of calling Read on a
+// Reader with values boxed in interfaces.
+type GenericReader[T any] struct {
+	base  Reader
+	model *Schema // schema of T, used to reconstruct the rows
+	err   error   // non-nil if the rows cannot be reconstructed into T
+}
+
+// NewGenericReader is like NewReader but returns GenericReader[T] suited to
+// read rows of Go type T.
+//
+// The type parameter T must be a struct type, the function panics otherwise.
+// When the options contain a schema, it is used to read the rows instead of
+// the schema derived from T, and the rows of the parquet file are converted
+// to it if needed. The schema must have the same structure as the schema of T,
+// otherwise calls to Read return an error.
+func NewGenericReader[T any](input io.ReaderAt, options ...ReaderOption) *GenericReader[T] {
+	c, err := NewReaderConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+
+	f, err := openFile(input)
+	if err != nil {
+		panic(err)
+	}
+
+	model := schemaOf(typeOf[T]())
+	if c.Schema == nil {
+		c.Schema = model
+	}
+
+	rowGroup := fileRowGroupOf(f, c.Filter)
+
+	if c.Columns != nil {
+		rowGroup = projectRowGroup(rowGroup, c.Columns)
+	}
+
+	rowGroup = prefetchRowGroup(rowGroup, c.Concurrency)
+
+	r := &GenericReader[T]{model: model}
+	r.init(c.Schema, convertRowGroupTo(rowGroup, c.Schema), c.Filter)
+	return r
+}
+
+// NewGenericRowGroupReader is like NewRowGroupReader but returns a
+// GenericReader[T] reading rows of Go type T from the row group.
+func NewGenericRowGroupReader[T any](rowGroup RowGroup, options ...ReaderOption) *GenericReader[T] {
+	c, err := NewReaderConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+
+	model := schemaOf(typeOf[T]())
+	if c.Schema == nil {
+		c.Schema = model
+	}
+
+	if c.Filter != nil {
+		rowGroup = filterRowGroup(rowGroup, c.Filter)
+	}
+
+	if c.Columns != nil {
+		rowGroup = projectRowGroup(rowGroup, c.Columns)
+	}
+
+	rowGroup = prefetchRowGroup(rowGroup, c.Concurrency)
+
+	r := &GenericReader[T]{model: model}
+	r.init(c.Schema, convertRowGroupTo(rowGroup, c.Schema), c.Filter)
+	return r
+}
+
+func (r *GenericReader[T]) init(schema *Schema, rowGroup RowGroup, filter Filter) {
+	// The rows are reconstructed with the schema of T, which requires the
+	// rows read with the schema passed in the options to be laid out the
+	// same way.
+	if schema != r.model && !nodesAreEqual(schema, r.model) {
+		r.err = fmt.Errorf("cannot read parquet rows into go values of type %s: the schema of the reader does not match the schema of the go type", r.model.GoType())
+	}
+	r.base.init(schema, rowGroup, filter)
+}
+
+// Reset repositions the reader at the beginning of the underlying parquet file.
+func (r *GenericReader[T]) Reset() { r.base.Reset() }
+
+// Close releases the resources held by the reader, see Reader.Close.
+func (r *GenericReader[T]) Close() error { return r.base.Close() }
+
+// Read reads the next rows from r into the given slice, returning the number
+// of rows that were read.
+//
+// The method returns io.EOF when no more rows can be read from r, the number
+// of rows returned may be greater than zero in that case.
+func (r *GenericReader[T]) Read(rows []T) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	reconstruct := r.model.reconstructFunc()
+	if reconstruct == nil {
+		return 0, fmt.Errorf("cannot read parquet rows into go values of type %s", reflect.TypeOf(rows).Elem())
+	}
+
+	defer func() {
+		clearValues(r.base.values)
+	}()
+
+	values := reflect.ValueOf(rows)
+
+	for i := range rows {
+		if err := r.base.read.SeekToRow(r.base.rowIndex); err != nil {
+			return i, fmt.Errorf("seeking reader to row %d: %w", r.base.rowIndex, err)
+		}
+
+		row, err := r.base.read.ReadRow(r.base.values[:0])
+		r.base.values = row
+		if err == nil || err == io.EOF {
+			r.base.rowIndex = r.base.read.rowIndex
+		}
+		if err != nil {
+			return i, err
+		}
+
+		if row, err = reconstruct(values.Index(i), levels{}, row); err != nil {
+			return i, err
+		}
+		if len(row) > 0 {
+			return i, fmt.Errorf("%d values remain unused after reconstructing go value of type %s from parquet row", len(row), values.Type().Elem())
+		}
+	}
+
+	return len(rows), nil
+}
+
+// ReadRow reads the next row from r and appends in to the given Row buffer.
+//
+// The returned values are laid out in the order expected by the
+// parquet.(*Schema).Reconstruct method.
+//
+// The method returns io.EOF when no more rows can be read from r.
+func (r *GenericReader[T]) ReadRow(row Row) (Row, error) { return r.base.ReadRow(row) }
+
+// Schema returns the schema of rows read by r.
+func (r *GenericReader[T]) Schema() *Schema { return r.base.Schema() }
+
+// NumRows returns the number of rows that can be read from r.
+func (r *GenericReader[T]) NumRows() int64 { return r.base.NumRows() }
+
+// SeekToRow positions r at the given row index.
+func (r *GenericReader[T]) SeekToRow(rowIndex int64) error { return r.base.SeekToRow(rowIndex) }
+
+func typeOf[T any]() reflect.Type {
+	var v T
+	return reflect.TypeOf(&v).Elem()
+}
+
+var (
+	_ Rows = (*GenericReader[struct{}])(nil)
+)
@@ -0,0 +1,222 @@
+//go:build go1.18
+
+package parquet_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestGenericReader(t *testing.T) {
+	t.Run("BOOLEAN", testGenericReader[booleanColumn])
+	t.Run("INT32", testGenericReader[int32Column])
+	t.Run("INT64", testGenericReader[int64Column])
+	t.Run("INT96", testGenericReader[int96Column])
+	t.Run("FLOAT", testGenericReader[floatColumn])
+	t.Run("DOUBLE", testGenericReader[doubleColumn])
+	t.Run("BYTE_ARRAY", testGenericReader[byteArrayColumn])
+	t.Run("FIXED_LEN_BYTE_ARRAY", testGenericReader[fixedLenByteArrayColumn])
+	t.Run("STRING", testGenericReader[stringColumn])
+	t.Run("STRING (dict)", testGenericReader[indexedStringColumn])
+	t.Run("UUID", testGenericReader[uuidColumn])
+	t.Run("DECIMAL", testGenericReader[decimalColumn])
+	t.Run("AddressBook", testGenericReader[addressBook])
+	t.Run("Contact", testGenericReader[contact])
+	t.Run("ListColumn2", testGenericReader[listColumn2])
+	t.Run("ListColumn1", testGenericReader[listColumn1])
+	t.Run("ListColumn0", testGenericReader[listColumn0])
+	t.Run("NestedListColumn1", testGenericReader[nestedListColumn1])
+	t.Run("NestedListColumn", testGenericReader[nestedListColumn])
+}
+
+func testGenericReader[Row any](t *testing.T) {
+	for _, n := range []int{1, 10, 42, 1000} {
+		t.Run(fmt.Sprintf("N=%d", n), func(t *testing.T) {
+			if err := testGenericReaderRows(makeGenericRows[Row](n)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func testGenericReaderRows[Row any](rows []Row) error {
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[Row](buffer)
+	if _, err := writer.Write(rows); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	reader := parquet.NewGenericReader[Row](bytes.NewReader(buffer.Bytes()))
+	result := make([]Row, len(rows))
+	n, err := reader.Read(result)
+	if err != nil {
+		return err
+	}
+	if n < len(rows) {
+		return fmt.Errorf("not enough values were read: want=%d got=%d", len(rows), n)
+	}
+	if !reflect.DeepEqual(rows, result) {
+		return fmt.Errorf("rows mismatch:\nwant: %+v\ngot:  %+v", rows, result)
+	}
+	if n, err := reader.Read(result); n != 0 || err != io.EOF {
+		return fmt.Errorf("expected EOF after reading all rows but got: n=%d err=%v", n, err)
+	}
+	return nil
+}
+
+func TestGenericReaderSeekToRow(t *testing.T) {
+	rows := makeGenericRows[int64Column](100)
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[int64Column](buffer, parquet.PageBufferSize(100))
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewGenericReader[int64Column](bytes.NewReader(buffer.Bytes()))
+	if n := reader.NumRows(); n != int64(len(rows)) {
+		t.Fatalf("wrong number of rows: want=%d got=%d", len(rows), n)
+	}
+
+	result := make([]int64Column, 10)
+	for _, rowIndex := range []int{50, 10, 90, 0} {
+		if err := reader.SeekToRow(int64(rowIndex)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := reader.Read(result); err != nil {
+			t.Fatal(err)
+		}
+		if want := rows[rowIndex : rowIndex+10]; !reflect.DeepEqual(want, result) {
+			t.Errorf("rows mismatch at index %d:\nwant: %+v\ngot:  %+v", rowIndex, want, result)
+		}
+	}
+}
+
+func TestGenericReaderFilterRows(t *testing.T) {
+	rows := makeFilterRows(1000)
+	input, err := writeFilterRows(rows, 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewGenericReader[filterRow](input, parquet.FilterRows(
+		parquet.And(
+			parquet.Gt([]string{"id"}, parquet.ValueOf(int64(600))),
+			parquet.IsNull([]string{"score"}),
+		),
+	))
+
+	want := []filterRow{}
+	for _, row := range rows {
+		if row.ID > 600 && row.Score == nil {
+			want = append(want, row)
+		}
+	}
+
+	got := make([]filterRow, len(want)+1)
+	n, err := reader.Read(got)
+	if err != io.EOF {
+		t.Errorf("expected io.EOF after reading all rows, got %v", err)
+	}
+	if !reflect.DeepEqual(got[:n], want) {
+		t.Errorf("rows mismatch: want=%d rows got=%d rows", len(want), n)
+	}
+}
+
+func makeGenericRows[Row any](n int) []Row {
+	prng := rand.New(rand.NewSource(0))
+	rows := make([]Row, n)
+	for i := range rows {
+		v, ok := quick.Value(reflect.TypeOf(rows[i]), prng)
+		if !ok {
+			panic("cannot generate random value for test")
+		}
+		rows[i] = v.Interface().(Row)
+	}
+	return rows
+}
+
+func BenchmarkGenericReader(b *testing.B) {
+	rows := makeGenericRows[contact](benchmarkReaderNumRows)
+
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[contact](buffer)
+	if _, err := writer.Write(rows); err != nil {
+		b.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		b.Fatal(err)
+	}
+
+	reader := parquet.NewGenericReader[contact](bytes.NewReader(buffer.Bytes()))
+	result := make([]contact, 100)
+
+	for i := 0; i < b.N; i++ {
+		if _, err := reader.Read(result); err != nil {
+			if err != io.EOF {
+				b.Fatal(err)
+			}
+			reader.Reset()
+		}
+	}
+}
+
+func TestGenericReaderSchema(t *testing.T) {
+	type Row struct {
+		Name  string `parquet:"name"`
+		Value int64  `parquet:"value"`
+	}
+
+	rows := []Row{{Name: "A", Value: 1}, {Name: "B", Value: 2}}
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[Row](buffer)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("compatible schema", func(t *testing.T) {
+		schema, err := parquet.ParseSchema(`message Row {
+	required binary name (STRING);
+	required int64 value;
+}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader := parquet.NewGenericReader[Row](bytes.NewReader(buffer.Bytes()), schema)
+		result := make([]Row, len(rows))
+		if n, err := reader.Read(result); n != len(rows) || (err != nil && err != io.EOF) {
+			t.Fatalf("reading rows: n=%d err=%v", n, err)
+		}
+		if !reflect.DeepEqual(rows, result) {
+			t.Errorf("rows mismatch:\nwant: %+v\ngot:  %+v", rows, result)
+		}
+	})
+
+	t.Run("incompatible schema", func(t *testing.T) {
+		type Other struct {
+			Name  string `parquet:"name"`
+			Value int64  `parquet:"value"`
+			Extra int64  `parquet:"extra"`
+		}
+		reader := parquet.NewGenericReader[Row](bytes.NewReader(buffer.Bytes()), parquet.SchemaOf(Other{}))
+		result := make([]Row, len(rows))
+		if n, err := reader.Read(result); n != 0 || err == nil || err == io.EOF {
+			t.Errorf("expected an error reading rows with an incompatible schema: n=%d err=%v", n, err)
+		}
+	})
+}
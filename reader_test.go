@@ -461,3 +461,102 @@ func TestReaderSeekToRow(t *testing.T) {
 		}
 	}
 }
+
+func TestReaderConcurrency(t *testing.T) {
+	type rowType struct {
+		ID    int64             `parquet:"id"`
+		Name  utf8string        `parquet:"name,dict"`
+		Score *float64          `parquet:"score,optional"`
+		Tags  []utf8string      `parquet:"tags"`
+		Attrs map[string]string `parquet:"attrs"`
+	}
+
+	rows := make([]rowType, 5000)
+	for i := range rows {
+		rows[i].ID = int64(i)
+		rows[i].Name = utf8string(fmt.Sprintf("name-%d", i%17))
+		if i%3 != 0 {
+			score := float64(i) / 10
+			rows[i].Score = &score
+		}
+		for j := 0; j < i%4; j++ {
+			rows[i].Tags = append(rows[i].Tags, utf8string(fmt.Sprintf("tag-%d", j)))
+		}
+		rows[i].Attrs = map[string]string{"key": fmt.Sprint(i)}
+		if rows[i].Tags == nil {
+			rows[i].Tags = []utf8string{}
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	writer := parquet.NewWriter(buf, parquet.PageBufferSize(1024), parquet.Compression(&parquet.Snappy))
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+		if (i+1)%1000 == 0 {
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	input := bytes.NewReader(buf.Bytes())
+
+	t.Run("read", func(t *testing.T) {
+		reader := parquet.NewReader(input, parquet.ReadConcurrency(4))
+		defer reader.Close()
+
+		for i := range rows {
+			row := rowType{}
+			if err := reader.Read(&row); err != nil {
+				t.Fatalf("reading row %d: %v", i, err)
+			}
+			if !reflect.DeepEqual(row, rows[i]) {
+				t.Fatalf("row %d mismatch: got=%+v want=%+v", i, row, rows[i])
+			}
+		}
+		if err := reader.Read(new(rowType)); err != io.EOF {
+			t.Fatalf("expected io.EOF after reading all rows, got %v", err)
+		}
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		reader := parquet.NewGenericReader[rowType](input, parquet.ReadConcurrency(2))
+		defer reader.Close()
+
+		for _, rowIndex := range []int64{4321, 10, 2999, 3000, 0} {
+			if err := reader.SeekToRow(rowIndex); err != nil {
+				t.Fatal(err)
+			}
+			values := make([]rowType, 3)
+			n, err := reader.Read(values)
+			if err != nil {
+				t.Fatalf("reading rows at index %d: %v", rowIndex, err)
+			}
+			if !reflect.DeepEqual(values[:n], rows[rowIndex:rowIndex+int64(n)]) {
+				t.Fatalf("rows mismatch at index %d", rowIndex)
+			}
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		reader := parquet.NewReader(input, parquet.ReadConcurrency(8))
+		for i := 0; i < 10; i++ {
+			if _, err := reader.ReadRow(nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := reader.Close(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestReaderConfigConcurrency(t *testing.T) {
+	if _, err := parquet.NewReaderConfig(parquet.ReadConcurrency(-1)); err == nil {
+		t.Error("expected an error for a negative concurrency level")
+	}
+}
@@ -0,0 +1,313 @@
+package parquet
+
+import (
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/segmentio/parquet-go/format"
+)
+
+// tailReader is an implementation of io.ReaderAt serving reads of the end of a
+// file from memory. Files are opened by reading their footer, then the page
+// index which is usually located right before the footer; reading the end of
+// the file speculatively allows both to be loaded with a single request.
+type tailReader struct {
+	reader io.ReaderAt
+	offset int64
+	tail   []byte
+}
+
+func (r *tailReader) ReadAt(b []byte, off int64) (int, error) {
+	if i := off - r.offset; i >= 0 && i+int64(len(b)) <= int64(len(r.tail)) {
+		return copy(b, r.tail[i:]), nil
+	}
+	return r.reader.ReadAt(b, off)
+}
+
+// rangeReader is an implementation of io.ReaderAt which serves reads of the
+// column chunks of a file from ranges of bytes loaded in memory.
+//
+// The byte ranges of the column chunks of each row group are merged when they
+// are close to each other, then all the ranges of a row group are fetched
+// concurrently the first time one of its column chunks is read. Row groups
+// that are never read (e.g. because a filter eliminated them) are never
+// fetched. The memory of ranges is released when all the column chunks that
+// they contain have been read, and fetched again if needed.
+//
+// Bloom filters are planned as a separate group, which is fetched when the
+// file is opened, and retained in memory.
+type rangeReader struct {
+	reader      io.ReaderAt
+	concurrency int
+	groups      [][]*readRange
+	// Ranges of column chunks and bloom filters, sorted by offset. The ranges
+	// of each slice are disjoint, but merged bloom filters ranges may overlap
+	// with column chunk ranges.
+	columnChunks []*readRange
+	bloomFilters []*readRange
+}
+
+type readRange struct {
+	group  int
+	offset int64
+	length int64
+	// Column chunks contained in the range. Once the last byte of each chunk
+	// was read, the memory of the range is released. Ranges of bloom filters
+	// have no chunks and are never released.
+	chunks []byteRange
+
+	mutex       sync.Mutex
+	fetched     bool
+	finished    []bool
+	numFinished int
+	data        []byte
+	done        chan struct{}
+	err         error
+}
+
+// consume records that the bytes at [off:end) of the range were read, and
+// returns true if all its column chunks were read to their end. Tracking the
+// ends of column chunks instead of counting bytes prevents reading the same
+// bytes multiple times from releasing the memory early, and accounts for
+// pages skipped by seeking in a column chunk.
+func (rng *readRange) consume(off, end int64) bool {
+	if rng.finished == nil {
+		rng.finished = make([]bool, len(rng.chunks))
+	}
+	for i, chunk := range rng.chunks {
+		if chunkEnd := chunk.end(); !rng.finished[i] && off < chunkEnd && chunkEnd <= end {
+			rng.finished[i] = true
+			rng.numFinished++
+		}
+	}
+	return len(rng.chunks) > 0 && rng.numFinished == len(rng.chunks)
+}
+
+func (r *rangeReader) ReadAt(b []byte, off int64) (int, error) {
+	rng := findRange(r.bloomFilters, off, int64(len(b)))
+	if rng == nil {
+		rng = findRange(r.columnChunks, off, int64(len(b)))
+	}
+	if rng == nil {
+		return r.reader.ReadAt(b, off)
+	}
+
+	for {
+		rng.mutex.Lock()
+		if rng.err != nil {
+			err := rng.err
+			rng.mutex.Unlock()
+			return 0, err
+		}
+		if rng.data != nil {
+			n := copy(b, rng.data[off-rng.offset:])
+			if rng.consume(off, off+int64(n)) {
+				rng.data, rng.done = nil, nil
+				rng.finished, rng.numFinished = nil, 0
+			}
+			rng.mutex.Unlock()
+			return n, nil
+		}
+		done, fetched := rng.done, rng.fetched
+		rng.mutex.Unlock()
+
+		switch {
+		case done != nil:
+			<-done
+		case fetched:
+			// The range was released after being read entirely, only this
+			// range needs to be loaded again.
+			r.fetch([]*readRange{rng})
+		default:
+			r.fetch(r.groups[rng.group])
+		}
+	}
+}
+
+// findRange returns the range containing the n bytes at offset off, or nil if
+// there are none.
+func findRange(ranges []*readRange, off, n int64) *readRange {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].offset > off }) - 1
+	if i < 0 || off+n > ranges[i].offset+ranges[i].length {
+		return nil
+	}
+	return ranges[i]
+}
+
+// fetch starts loading the ranges which are not in memory, with at most
+// r.concurrency reads in flight.
+func (r *rangeReader) fetch(group []*readRange) {
+	ranges := make([]*readRange, 0, len(group))
+
+	for _, rng := range group {
+		rng.mutex.Lock()
+		if rng.data == nil && rng.done == nil {
+			rng.done = make(chan struct{})
+			rng.fetched = true
+			ranges = append(ranges, rng)
+		}
+		rng.mutex.Unlock()
+	}
+
+	if len(ranges) == 0 {
+		return
+	}
+
+	queue := make(chan *readRange, len(ranges))
+	for _, rng := range ranges {
+		queue <- rng
+	}
+	close(queue)
+
+	concurrency := r.concurrency
+	if concurrency > len(ranges) {
+		concurrency = len(ranges)
+	}
+
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			for rng := range queue {
+				data := make([]byte, rng.length)
+				n, err := r.reader.ReadAt(data, rng.offset)
+				if n == len(data) {
+					err = nil
+				} else if err == nil {
+					err = io.ErrUnexpectedEOF
+				}
+				rng.mutex.Lock()
+				if err != nil {
+					rng.err = err
+				} else {
+					rng.data = data
+				}
+				close(rng.done)
+				rng.mutex.Unlock()
+			}
+		}()
+	}
+}
+
+// byteRange is a section of a file that a reader needs to read.
+type byteRange struct {
+	offset int64
+	length int64
+}
+
+func (r byteRange) end() int64 { return r.offset + r.length }
+
+// coalesceRanges merges byte ranges separated by at most gap bytes, as long as
+// the merged ranges do not exceed size bytes (ranges larger than size on their
+// own are never split). The merged ranges record the ranges that they contain.
+func coalesceRanges(group int, ranges []byteRange, gap, size int64) []*readRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].offset < ranges[j].offset })
+	merged := make([]*readRange, 0, len(ranges))
+
+	for _, rng := range ranges {
+		if n := len(merged); n > 0 {
+			last := merged[n-1]
+			lastEnd := last.offset + last.length
+			if rng.offset-lastEnd <= gap && rng.end()-last.offset <= size {
+				if end := rng.end(); end > lastEnd {
+					last.length = end - last.offset
+				}
+				last.chunks = append(last.chunks, rng)
+				continue
+			}
+		}
+		merged = append(merged, &readRange{
+			group:  group,
+			offset: rng.offset,
+			length: rng.length,
+			chunks: []byteRange{rng},
+		})
+	}
+
+	return merged
+}
+
+// newRangeReader plans the reads of the column chunks and bloom filters of the
+// given file from reader. Only the column chunks at indexes where selected is
+// true are planned, and bloom filters only if loadBloomFilters is true.
+func newRangeReader(f *File, reader io.ReaderAt, config *FileConfig, selected []bool, loadBloomFilters bool) *rangeReader {
+	r := &rangeReader{
+		reader:      reader,
+		concurrency: config.ReadRangeConcurrency,
+	}
+
+	// Bloom filters do not have a length in the file metadata. Their ends are
+	// approximated with the next offset of a known section of the file,
+	// which is exact for files where filters are written contiguously before
+	// the column chunks of their row group, as done by parquet.Writer.
+	var boundaries []int64
+	var bloomFilters []byteRange
+
+	for i := range f.metadata.RowGroups {
+		rowGroup := &f.metadata.RowGroups[i]
+		chunks := make([]byteRange, 0, len(rowGroup.Columns))
+
+		for j := range rowGroup.Columns {
+			c := &rowGroup.Columns[j]
+			chunk := columnChunkRange(c)
+			boundaries = append(boundaries, chunk.offset)
+			if c.ColumnIndexOffset > 0 {
+				boundaries = append(boundaries, c.ColumnIndexOffset)
+			}
+			if c.OffsetIndexOffset > 0 {
+				boundaries = append(boundaries, c.OffsetIndexOffset)
+			}
+			if c.MetaData.BloomFilterOffset > 0 {
+				boundaries = append(boundaries, c.MetaData.BloomFilterOffset)
+			}
+			if j < len(selected) && selected[j] {
+				chunks = append(chunks, chunk)
+				if loadBloomFilters && c.MetaData.BloomFilterOffset > 0 {
+					bloomFilters = append(bloomFilters, byteRange{offset: c.MetaData.BloomFilterOffset})
+				}
+			}
+		}
+
+		r.groups = append(r.groups, coalesceRanges(i, chunks, config.ReadRangeGap, config.ReadRangeSize))
+	}
+
+	if len(bloomFilters) > 0 {
+		// The footer is located at the end of the file, its size is unknown
+		// at this stage but the last 8 bytes are always the footer length and
+		// magic number.
+		boundaries = append(boundaries, f.size-8)
+		sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+
+		for i := range bloomFilters {
+			offset := bloomFilters[i].offset
+			if j := sort.Search(len(boundaries), func(j int) bool { return boundaries[j] > offset }); j < len(boundaries) {
+				bloomFilters[i].length = boundaries[j] - offset
+			}
+		}
+
+		group := len(r.groups)
+		ranges := coalesceRanges(group, bloomFilters, config.ReadRangeGap, config.ReadRangeSize)
+		for _, rng := range ranges {
+			// Bloom filters are checked at random positions, there is no
+			// point in tracking which bytes were read.
+			rng.chunks = nil
+		}
+		r.groups = append(r.groups, ranges)
+		r.bloomFilters = ranges
+	}
+
+	// Row groups are written contiguously, so the ranges of distinct row
+	// groups never overlap.
+	for _, group := range r.groups[:len(f.metadata.RowGroups)] {
+		r.columnChunks = append(r.columnChunks, group...)
+	}
+	sort.Slice(r.columnChunks, func(i, j int) bool { return r.columnChunks[i].offset < r.columnChunks[j].offset })
+	return r
+}
+
+func columnChunkRange(c *format.ColumnChunk) byteRange {
+	offset := c.MetaData.DataPageOffset
+	if dictOffset := c.MetaData.DictionaryPageOffset; dictOffset > 0 && dictOffset < offset {
+		offset = dictOffset
+	}
+	return byteRange{offset: offset, length: c.MetaData.TotalCompressedSize}
+}
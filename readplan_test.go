@@ -0,0 +1,80 @@
+package parquet
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+)
+
+type countingReaderAt struct {
+	reader *bytes.Reader
+	reads  int64
+}
+
+func (r *countingReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	atomic.AddInt64(&r.reads, 1)
+	return r.reader.ReadAt(b, off)
+}
+
+func TestRangeReaderRelease(t *testing.T) {
+	data := make([]byte, 32)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	file := &countingReaderAt{reader: bytes.NewReader(data)}
+
+	// Two column chunks separated by a gap of 2 bytes, merged in one range.
+	group := coalesceRanges(0, []byteRange{{offset: 0, length: 10}, {offset: 12, length: 8}}, 4, 1024)
+	if len(group) != 1 {
+		t.Fatalf("wrong number of ranges: want=1 got=%d", len(group))
+	}
+	r := &rangeReader{
+		reader:       file,
+		concurrency:  1,
+		groups:       [][]*readRange{group},
+		columnChunks: group,
+	}
+	rng := group[0]
+
+	read := func(off, n int64) {
+		t.Helper()
+		b := make([]byte, n)
+		if _, err := r.ReadAt(b, off); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, data[off:off+n]) {
+			t.Fatalf("wrong data read at offset %d: %v", off, b)
+		}
+	}
+	released := func() bool {
+		rng.mutex.Lock()
+		defer rng.mutex.Unlock()
+		return rng.data == nil
+	}
+
+	// Reading the first chunk twice reads more bytes than there are in the
+	// column chunks, but the second chunk was not read yet.
+	read(0, 10)
+	read(0, 10)
+	if released() {
+		t.Fatal("range released before all its column chunks were read")
+	}
+
+	// Pages may be skipped, reading the end of a column chunk is enough.
+	read(16, 4)
+	if !released() {
+		t.Fatal("range not released after all its column chunks were read")
+	}
+	if reads := atomic.LoadInt64(&file.reads); reads != 1 {
+		t.Errorf("wrong number of reads: want=1 got=%d", reads)
+	}
+
+	// Once released, the range is fetched again if needed.
+	read(12, 8)
+	if reads := atomic.LoadInt64(&file.reads); reads != 2 {
+		t.Errorf("wrong number of reads: want=2 got=%d", reads)
+	}
+	if released() {
+		t.Error("range fetched again was released before all its column chunks were read")
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/segmentio/parquet-go/internal/bits"
 )
 
@@ -320,9 +324,18 @@ func deconstructFuncOfOptional(columnIndex int16, node Node) (int16, deconstruct
 	columnIndex, deconstruct := deconstructFuncOf(columnIndex, Required(node))
 	return columnIndex, func(row Row, levels levels, value reflect.Value) Row {
 		if value.IsValid() {
-			if value.IsZero() {
+			switch {
+			case value.Kind() == reflect.Interface:
+				// Dynamic values are only null when the interface is nil, zero
+				// values like 0 or "" are valid values of optional columns.
+				if dynamicValueOf(value).IsValid() {
+					levels.definitionLevel++
+				} else {
+					value = reflect.Value{}
+				}
+			case value.IsZero():
 				value = reflect.Value{}
-			} else {
+			default:
 				if value.Kind() == reflect.Ptr {
 					value = value.Elem()
 				}
@@ -333,10 +346,27 @@ func deconstructFuncOfOptional(columnIndex int16, node Node) (int16, deconstruct
 	}
 }
 
-//go:noinline
 func deconstructFuncOfRepeated(columnIndex int16, node Node) (int16, deconstructFunc) {
-	columnIndex, deconstruct := deconstructFuncOf(columnIndex, Required(node))
-	return columnIndex, func(row Row, levels levels, value reflect.Value) Row {
+	return deconstructFuncOfElements(columnIndex, Required(node))
+}
+
+// deconstructFuncOfElements returns a function deconstructing the elements of
+// slices or arrays into a repeated column. The element node is passed instead
+// of the repeated node so lists can retain the optionality of their elements.
+//
+//go:noinline
+func deconstructFuncOfElements(columnIndex int16, elem Node) (int16, deconstructFunc) {
+	nextColumnIndex, deconstruct := deconstructFuncOf(columnIndex, elem)
+	return nextColumnIndex, func(row Row, levels levels, value reflect.Value) Row {
+		if value.Kind() == reflect.Interface {
+			value = dynamicValueOf(value)
+			switch value.Kind() {
+			case reflect.Invalid, reflect.Slice, reflect.Array:
+			default:
+				panic(&deconstructError{columnIndex, fmt.Errorf("cannot deconstruct go value of type %s into repeated column", value.Type())})
+			}
+		}
+
 		if !value.IsValid() || value.Len() == 0 {
 			return deconstruct(row, levels, reflect.Value{})
 		}
@@ -363,7 +393,7 @@ func deconstructFuncOfRequired(columnIndex int16, node Node) (int16, deconstruct
 }
 
 func deconstructFuncOfList(columnIndex int16, node Node) (int16, deconstructFunc) {
-	return deconstructFuncOf(columnIndex, Repeated(listElementOf(node)))
+	return deconstructFuncOfElements(columnIndex, listElementOf(node))
 }
 
 //go:noinline
@@ -373,12 +403,41 @@ func deconstructFuncOfMap(columnIndex int16, node Node) (int16, deconstructFunc)
 	keyValueElem := keyValueType.Elem()
 	keyType := keyValueElem.Field(0).Type
 	valueType := keyValueElem.Field(1).Type
-	columnIndex, deconstruct := deconstructFuncOf(columnIndex, schemaOf(keyValueElem))
-	return columnIndex, func(row Row, levels levels, mapValue reflect.Value) Row {
+	nextColumnIndex, deconstruct := deconstructFuncOf(columnIndex, schemaOf(keyValueElem))
+	// Maps holding dynamic keys or values cannot be converted to the key and
+	// value types of the key_value struct, their entries are deconstructed by
+	// functions built for the key and value fields instead.
+	keyValueFields := keyValue.Fields()
+	valueColumnIndex, deconstructKey := deconstructFuncOf(columnIndex, keyValueFields[0])
+	_, deconstructValue := deconstructFuncOf(valueColumnIndex, keyValueFields[1])
+	return nextColumnIndex, func(row Row, levels levels, mapValue reflect.Value) Row {
+		if mapValue.Kind() == reflect.Interface {
+			mapValue = dynamicValueOf(mapValue)
+			switch mapValue.Kind() {
+			case reflect.Invalid, reflect.Map:
+			default:
+				panic(&deconstructError{columnIndex, fmt.Errorf("cannot deconstruct go value of type %s into map column", mapValue.Type())})
+			}
+		}
+
 		if !mapValue.IsValid() || mapValue.Len() == 0 {
 			return deconstruct(row, levels, reflect.Value{})
 		}
 
+		if t := mapValue.Type(); t.Key().Kind() == reflect.Interface || t.Elem().Kind() == reflect.Interface {
+			levels.repetitionDepth++
+			levels.definitionLevel++
+
+			iter := mapValue.MapRange()
+			for iter.Next() {
+				row = deconstructKey(row, levels, iter.Key())
+				row = deconstructValue(row, levels, iter.Value())
+				levels.repetitionLevel = levels.repetitionDepth
+			}
+
+			return row
+		}
+
 		levels.repetitionDepth++
 		levels.definitionLevel++
 
@@ -401,18 +460,46 @@ func deconstructFuncOfMap(columnIndex int16, node Node) (int16, deconstructFunc)
 func deconstructFuncOfGroup(columnIndex int16, node Node) (int16, deconstructFunc) {
 	fields := node.Fields()
 	funcs := make([]deconstructFunc, len(fields))
+	names := make([]reflect.Value, len(fields))
 	for i, field := range fields {
 		columnIndex, funcs[i] = deconstructFuncOf(columnIndex, field)
+		names[i] = reflect.ValueOf(field.Name())
 	}
 	return columnIndex, func(row Row, levels levels, value reflect.Value) Row {
-		if value.IsValid() {
-			for i, f := range funcs {
-				row = f(row, levels, fields[i].Value(value))
+		if value.Kind() == reflect.Interface {
+			value = dynamicValueOf(value)
+			switch value.Kind() {
+			case reflect.Invalid, reflect.Map, reflect.Struct:
+			default:
+				panic(&deconstructError{-1, fmt.Errorf("cannot deconstruct go value of type %s into parquet group", value.Type())})
 			}
-		} else {
+		}
+
+		switch value.Kind() {
+		case reflect.Invalid:
 			for _, f := range funcs {
 				row = f(row, levels, value)
 			}
+		case reflect.Map:
+			// Maps like map[string]interface{} represent groups by holding
+			// the values of fields at the keys matching their names, keys
+			// which do not match any fields are ignored.
+			keyType := value.Type().Key()
+			for i, f := range funcs {
+				name := names[i]
+				if name.Type() != keyType {
+					name = name.Convert(keyType)
+				}
+				fieldValue := value.MapIndex(name)
+				if fields[i].Required() && !dynamicValueOf(fieldValue).IsValid() {
+					panic(&deconstructError{-1, fmt.Errorf("missing value of required field %q", fields[i].Name())})
+				}
+				row = f(row, levels, fieldValue)
+			}
+		default:
+			for i, f := range funcs {
+				row = f(row, levels, fields[i].Value(value))
+			}
 		}
 		return row
 	}
@@ -423,13 +510,31 @@ func deconstructFuncOfLeaf(columnIndex int16, node Node) (int16, deconstructFunc
 	if columnIndex > MaxColumnIndex {
 		panic("row cannot be deconstructed because it has more than 127 columns")
 	}
-	kind := node.Type().Kind()
+	typ := node.Type()
+	kind := typ.Kind()
+	makeValueOf := func(v reflect.Value) Value { return makeValue(kind, v) }
+	// Some logical types support converting from Go types that do not map
+	// directly to their physical type (e.g. time.Time values of TIMESTAMP
+	// columns).
+	if t, ok := typ.(interface{ makeValue(reflect.Value) Value }); ok {
+		makeValueOf = t.makeValue
+	}
+	goType := goTypeOfLeaf(node)
 	valueColumnIndex := ^columnIndex
 	return columnIndex + 1, func(row Row, levels levels, value reflect.Value) Row {
 		v := Value{}
 
+		if value.Kind() == reflect.Interface {
+			if value = dynamicValueOf(value); value.IsValid() {
+				var err error
+				if value, err = convertDynamicValue(typ, goType, value); err != nil {
+					panic(&deconstructError{columnIndex, err})
+				}
+			}
+		}
+
 		if value.IsValid() {
-			v = makeValue(kind, value)
+			v = makeValueOf(value)
 		}
 
 		v.repetitionLevel = levels.repetitionLevel
@@ -442,17 +547,40 @@ func deconstructFuncOfLeaf(columnIndex int16, node Node) (int16, deconstructFunc
 type reconstructFunc func(reflect.Value, levels, Row) (Row, error)
 
 func reconstructFuncOf(columnIndex int16, node Node) (int16, reconstructFunc) {
+	var reconstruct reconstructFunc
 	switch {
 	case node.Optional():
 		return reconstructFuncOfOptional(columnIndex, node)
 	case node.Repeated():
-		return reconstructFuncOfRepeated(columnIndex, node)
+		columnIndex, reconstruct = reconstructFuncOfRepeated(columnIndex, node)
 	case isList(node):
-		return reconstructFuncOfList(columnIndex, node)
+		columnIndex, reconstruct = reconstructFuncOfList(columnIndex, node)
 	case isMap(node):
-		return reconstructFuncOfMap(columnIndex, node)
+		columnIndex, reconstruct = reconstructFuncOfMap(columnIndex, node)
 	default:
-		return reconstructFuncOfRequired(columnIndex, node)
+		columnIndex, reconstruct = reconstructFuncOfRequired(columnIndex, node)
+	}
+	return columnIndex, reconstructFuncOfInterface(node, reconstruct)
+}
+
+// reconstructFuncOfInterface wraps reconstruct to support reconstructing rows
+// into interface values (e.g. the values of a map[string]interface{}). The
+// interfaces are set to Go values of the types returned by dynamicTypeOf.
+//
+//go:noinline
+func reconstructFuncOfInterface(node Node, reconstruct reconstructFunc) reconstructFunc {
+	dynamicType := dynamicTypeOf(node)
+	return func(value reflect.Value, levels levels, row Row) (Row, error) {
+		if value.Kind() != reflect.Interface {
+			return reconstruct(value, levels, row)
+		}
+		if !dynamicType.AssignableTo(value.Type()) {
+			return row, fmt.Errorf("cannot reconstruct go value of type %s into %s", dynamicType, value.Type())
+		}
+		elem := reflect.New(dynamicType).Elem()
+		row, err := reconstruct(elem, levels, row)
+		value.Set(elem)
+		return row, err
 	}
 }
 
@@ -486,9 +614,16 @@ func reconstructFuncOfOptional(columnIndex int16, node Node) (int16, reconstruct
 	}
 }
 
-//go:noinline
 func reconstructFuncOfRepeated(columnIndex int16, node Node) (int16, reconstructFunc) {
-	nextColumnIndex, reconstruct := reconstructFuncOf(columnIndex, Required(node))
+	return reconstructFuncOfElements(columnIndex, Required(node))
+}
+
+// reconstructFuncOfElements is the counterpart of deconstructFuncOfElements,
+// reconstructing the values of a repeated column into a slice.
+//
+//go:noinline
+func reconstructFuncOfElements(columnIndex int16, elem Node) (int16, reconstructFunc) {
+	nextColumnIndex, reconstruct := reconstructFuncOf(columnIndex, elem)
 	rowLength := nextColumnIndex - columnIndex
 	return nextColumnIndex, func(value reflect.Value, lvls levels, row Row) (Row, error) {
 		t := value.Type()
@@ -554,7 +689,7 @@ func reconstructFuncOfRequired(columnIndex int16, node Node) (int16, reconstruct
 }
 
 func reconstructFuncOfList(columnIndex int16, node Node) (int16, reconstructFunc) {
-	return reconstructFuncOf(columnIndex, Repeated(listElementOf(node)))
+	return reconstructFuncOfElements(columnIndex, listElementOf(node))
 }
 
 //go:noinline
@@ -565,6 +700,9 @@ func reconstructFuncOfMap(columnIndex int16, node Node) (int16, reconstructFunc)
 	keyValueZero := reflect.Zero(keyValueElem)
 	nextColumnIndex, reconstruct := reconstructFuncOf(columnIndex, schemaOf(keyValueElem))
 	rowLength := nextColumnIndex - columnIndex
+	keyValueFields := keyValue.Fields()
+	valueColumnIndex, reconstructKey := reconstructFuncOf(columnIndex, keyValueFields[0])
+	_, reconstructValue := reconstructFuncOf(valueColumnIndex, keyValueFields[1])
 	return nextColumnIndex, func(mapValue reflect.Value, lvls levels, row Row) (Row, error) {
 		t := mapValue.Type()
 		k := t.Key()
@@ -574,6 +712,23 @@ func reconstructFuncOfMap(columnIndex int16, node Node) (int16, reconstructFunc)
 			mapValue.Set(reflect.MakeMap(t))
 		}
 
+		if k.Kind() == reflect.Interface || v.Kind() == reflect.Interface {
+			key := reflect.New(k).Elem()
+			value := reflect.New(v).Elem()
+			return reconstructRepeated(columnIndex, rowLength, lvls, row, func(levels levels, row Row) (Row, error) {
+				row, err := reconstructKey(key, levels, row)
+				if err == nil {
+					row, err = reconstructValue(value, levels, row)
+				}
+				if err == nil {
+					mapValue.SetMapIndex(key, value)
+					key.Set(reflect.Zero(k))
+					value.Set(reflect.Zero(v))
+				}
+				return row, err
+			})
+		}
+
 		elem := reflect.New(keyValueElem).Elem()
 		return reconstructRepeated(columnIndex, rowLength, lvls, row, func(levels levels, row Row) (Row, error) {
 			row, err := reconstruct(elem, levels, row)
@@ -600,6 +755,26 @@ func reconstructFuncOfGroup(columnIndex int16, node Node) (int16, reconstructFun
 	return columnIndex, func(value reflect.Value, levels levels, row Row) (Row, error) {
 		var err error
 
+		if value.Kind() == reflect.Map {
+			t := value.Type()
+			if value.IsNil() {
+				value.Set(reflect.MakeMap(t))
+			}
+			for i, f := range funcs {
+				name := reflect.ValueOf(fields[i].Name())
+				if name.Type() != t.Key() {
+					name = name.Convert(t.Key())
+				}
+				elem := reflect.New(t.Elem()).Elem()
+				if row, err = f(elem, levels, row); err != nil {
+					err = fmt.Errorf("%s → %w", fields[i].Name(), err)
+					break
+				}
+				value.SetMapIndex(name, elem)
+			}
+			return row, err
+		}
+
 		for i, f := range funcs {
 			if row, err = f(fields[i].Value(value), levels, row); err != nil {
 				err = fmt.Errorf("%s → %w", fields[i].Name(), err)
@@ -613,10 +788,231 @@ func reconstructFuncOfGroup(columnIndex int16, node Node) (int16, reconstructFun
 
 //go:noinline
 func reconstructFuncOfLeaf(columnIndex int16, node Node) (int16, reconstructFunc) {
+	assignValueOf := assignValue
+	if t, ok := node.Type().(interface {
+		assignValue(reflect.Value, Value) error
+	}); ok {
+		assignValueOf = t.assignValue
+	}
 	return columnIndex + 1, func(value reflect.Value, _ levels, row Row) (Row, error) {
 		if !row.startsWith(columnIndex) {
 			return row, fmt.Errorf("no values found in parquet row for column %d", columnIndex)
 		}
-		return row[1:], assignValue(value, row[0])
+		return row[1:], assignValueOf(value, row[0])
+	}
+}
+
+// =============================================================================
+// Dynamic values
+//
+// The functions below support deconstructing and reconstructing rows from Go
+// values which are not structs, but values like map[string]interface{} or
+// []interface{}, whose layout is driven by the schema instead of their type.
+// =============================================================================
+
+var (
+	goInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+	goSliceType     = reflect.SliceOf(goInterfaceType)
+	goMapType       = reflect.MapOf(reflect.TypeOf(""), goInterfaceType)
+)
+
+// deconstructError is the type of panics raised when dynamic values cannot be
+// deconstructed because they do not match the schema. The column index is -1
+// when the error is not specific to a leaf column.
+type deconstructError struct {
+	columnIndex int16
+	err         error
+}
+
+func (e *deconstructError) Error() string { return e.err.Error() }
+
+func (e *deconstructError) Unwrap() error { return e.err }
+
+// dynamicValueOf returns the value held in v when it is an interface, and
+// dereferences pointers. The returned value is invalid if v was nil.
+func dynamicValueOf(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// dynamicTypeOf returns the Go type that rows are reconstructed into when the
+// destination is an interface: []interface{} for lists and repeated nodes,
+// maps for map and group nodes, and the Go type of leaf nodes.
+func dynamicTypeOf(node Node) reflect.Type {
+	switch {
+	case node.Repeated(), isList(node):
+		return goSliceType
+	case isMap(node):
+		keyType := dynamicTypeOf(mapKeyValueOf(node).Fields()[0])
+		if !keyType.Comparable() {
+			keyType = reflect.TypeOf("")
+		}
+		return reflect.MapOf(keyType, goInterfaceType)
+	case !node.Leaf():
+		return goMapType
+	}
+	if _, ok := node.Type().(*timestampType); ok {
+		return goTimeType
+	}
+	return goTypeOfLeaf(node)
+}
+
+// convertDynamicValue converts v to a Go value that can be written to columns
+// of type t, which have the given Go type. Values held in dynamic structures
+// often do not have the exact type of the column, for example all numbers
+// decoded from JSON are float64, the function performs the conversions that
+// do not lose information and returns an error for the others.
+func convertDynamicValue(t Type, goType reflect.Type, v reflect.Value) (reflect.Value, error) {
+	if v.Type() == goType {
+		return v, nil
+	}
+
+	switch typ := t.(type) {
+	case *timestampType:
+		switch {
+		case v.Type() == goTimeType:
+			return v, nil
+		case v.Kind() == reflect.String:
+			tm, err := time.Parse(time.RFC3339Nano, v.String())
+			if err != nil {
+				return v, fmt.Errorf("cannot convert %q to parquet column of type %s: %w", v.String(), typ, err)
+			}
+			return reflect.ValueOf(tm), nil
+		}
+	case *timeType:
+		if v.Type() == goDurationType {
+			return v, nil
+		}
+	case *uuidType:
+		if v.Kind() == reflect.String && v.Len() != 16 {
+			u, err := uuid.Parse(v.String())
+			if err != nil {
+				return v, fmt.Errorf("cannot convert %q to parquet column of type %s: %w", v.String(), typ, err)
+			}
+			return reflect.ValueOf(u), nil
+		}
+	case *float16Type:
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return reflect.ValueOf(Float32ToFloat16(float32(v.Float()))), nil
+		}
+	}
+
+	switch t.Kind() {
+	case ByteArray:
+		// Strings and byte slices are both supported when creating values
+		// of BYTE_ARRAY columns, there is no need to convert between them.
+		if v.Kind() == reflect.String || (v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8) {
+			return v, nil
+		}
+	case Int96:
+		if v.Type() == goTimeType {
+			return v, nil
+		}
+	}
+
+	out := reflect.New(goType).Elem()
+	ok := false
+
+	switch goType.Kind() {
+	case reflect.Bool:
+		switch v.Kind() {
+		case reflect.Bool:
+			out.SetBool(v.Bool())
+			ok = true
+		case reflect.String:
+			b, err := strconv.ParseBool(v.String())
+			out.SetBool(b)
+			ok = err == nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i := v.Int()
+			out.SetInt(i)
+			ok = !out.OverflowInt(i)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u := v.Uint()
+			out.SetInt(int64(u))
+			ok = u <= math.MaxInt64 && !out.OverflowInt(int64(u))
+		case reflect.Float32, reflect.Float64:
+			f := v.Float()
+			out.SetInt(int64(f))
+			ok = f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !out.OverflowInt(int64(f))
+		case reflect.String:
+			i, err := strconv.ParseInt(v.String(), 10, goType.Bits())
+			out.SetInt(i)
+			ok = err == nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i := v.Int()
+			out.SetUint(uint64(i))
+			ok = i >= 0 && !out.OverflowUint(uint64(i))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u := v.Uint()
+			out.SetUint(u)
+			ok = !out.OverflowUint(u)
+		case reflect.Float32, reflect.Float64:
+			f := v.Float()
+			out.SetUint(uint64(f))
+			ok = f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 && !out.OverflowUint(uint64(f))
+		case reflect.String:
+			u, err := strconv.ParseUint(v.String(), 10, goType.Bits())
+			out.SetUint(u)
+			ok = err == nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			out.SetFloat(float64(v.Int()))
+			ok = true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			out.SetFloat(float64(v.Uint()))
+			ok = true
+		case reflect.Float32, reflect.Float64:
+			f := v.Float()
+			out.SetFloat(f)
+			ok = !out.OverflowFloat(f)
+		case reflect.String:
+			f, err := strconv.ParseFloat(v.String(), goType.Bits())
+			out.SetFloat(f)
+			ok = err == nil
+		}
+
+	case reflect.String:
+		switch {
+		case v.Kind() == reflect.String:
+			out.SetString(v.String())
+			ok = true
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			out.SetString(string(v.Bytes()))
+			ok = true
+		}
+
+	case reflect.Array:
+		if goType.Elem().Kind() == reflect.Uint8 {
+			switch v.Kind() {
+			case reflect.String, reflect.Array, reflect.Slice:
+				if v.Kind() == reflect.String || v.Type().Elem().Kind() == reflect.Uint8 {
+					reflect.Copy(out, v)
+					ok = v.Len() == goType.Len()
+				}
+			}
+		}
+	}
+
+	if !ok {
+		return v, fmt.Errorf("cannot convert go value %v of type %s to parquet column of type %s", v, v.Type(), t)
 	}
+	return out, nil
 }
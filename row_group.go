@@ -205,25 +205,39 @@ func MergeRowGroups(rowGroups []RowGroup, options ...RowGroupOption) (RowGroup,
 		}
 	}
 
-	m.sortFuncs = make([]columnSortFunc, len(m.sorting))
+	m.sortFuncs = sortFuncsOf(schema, m.sorting)
+	return m, nil
+}
+
+// sortFuncsOf returns the functions comparing values of the columns of schema
+// that rows are sorted by, in the order of the sorting columns.
+func sortFuncsOf(schema *Schema, sorting []SortingColumn) []columnSortFunc {
+	sortFuncs := make([]columnSortFunc, len(sorting))
 	forEachLeafColumnOf(schema, func(leaf leafColumn) {
-		if sortingIndex := searchSortingColumn(m.sorting, leaf.path); sortingIndex < len(m.sorting) {
-			m.sortFuncs[sortingIndex] = columnSortFunc{
+		if sortingIndex := searchSortingColumn(sorting, leaf.path); sortingIndex < len(sorting) {
+			sortFuncs[sortingIndex] = columnSortFunc{
 				columnIndex: leaf.columnIndex,
 				compare: sortFuncOf(
 					leaf.node.Type(),
 					&SortConfig{
 						MaxRepetitionLevel: int(leaf.maxRepetitionLevel),
 						MaxDefinitionLevel: int(leaf.maxDefinitionLevel),
-						Descending:         m.sorting[sortingIndex].Descending(),
-						NullsFirst:         m.sorting[sortingIndex].NullsFirst(),
+						Descending:         sorting[sortingIndex].Descending(),
+						NullsFirst:         sorting[sortingIndex].NullsFirst(),
 					},
 				),
 			}
 		}
 	})
-
-	return m, nil
+	// Sorting columns which do not exist in the schema have no values to be
+	// compared, they are dropped from the list.
+	compareFuncs := sortFuncs[:0]
+	for _, f := range sortFuncs {
+		if f.compare != nil {
+			compareFuncs = append(compareFuncs, f)
+		}
+	}
+	return compareFuncs
 }
 
 type rowGroup struct {
@@ -303,15 +317,27 @@ func (r *rowGroupRowReader) ReadRow(row Row) (Row, error) {
 		return row, io.EOF
 	}
 	n := len(row)
-	row, err := r.schema.readRow(row, 0, r.columns)
+	row, err := r.schema.readRowFunc()(row, 0, r.columns)
 	if err == nil && len(row) == n {
 		err = io.EOF
 	}
 	return row, err
 }
 
+// Close releases the page readers of the column chunks, which stops the
+// goroutines prefetching pages when the row group was configured to read
+// pages concurrently.
+func (r *rowGroupRowReader) Close() (err error) {
+	for i := range r.columns {
+		if e := r.columns[i].close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
 func (r *rowGroupRowReader) WriteRowsTo(w RowWriter) (int64, error) {
-	if r.rowGroup == nil {
+	if r.rowGroup == nil || hasRowGroupLimits(w) {
 		return CopyRows(w, struct{ RowReaderWithSchema }{r})
 	}
 	defer func() { r.rowGroup, r.seek = nil, 0 }()
@@ -488,6 +514,7 @@ func (emptyPages) SeekToRow(int64) error   { return nil }
 var (
 	_ RowReaderWithSchema = (*rowGroupRowReader)(nil)
 	_ RowWriterTo         = (*rowGroupRowReader)(nil)
+	_ io.Closer           = (*rowGroupRowReader)(nil)
 
 	_ RowReaderWithSchema = emptyRowReader{}
 	_ RowWriterTo         = emptyRowReader{}
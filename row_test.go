@@ -1,8 +1,11 @@
 package parquet_test
 
 import (
+	"bytes"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/segmentio/parquet-go"
@@ -565,3 +568,200 @@ func BenchmarkReconstruct(b *testing.B) {
 		}
 	}
 }
+
+func TestDeconstructionReconstructionDynamic(t *testing.T) {
+	schema, err := parquet.ParseSchema(`message Event {
+	required int64 id (INT(64,true));
+	required binary name (STRING);
+	optional double score;
+	required int32 count (INT(32,true));
+	required int64 time (TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS));
+	optional group tags (LIST) {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+	optional group attributes (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			optional int64 value (INT(64,true));
+		}
+	}
+	optional group location {
+		required double lat;
+		required double lng;
+	}
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The values are represented as if they had been decoded from JSON.
+	value := map[string]interface{}{
+		"id":    float64(42),
+		"name":  "hello",
+		"score": nil,
+		"count": "7",
+		"time":  "2022-06-30T12:34:56.789Z",
+		"tags":  []interface{}{"a", "b"},
+		"attributes": map[string]interface{}{
+			"answer": float64(42),
+			"none":   nil,
+		},
+		"ignored": true,
+	}
+
+	row := schema.Deconstruct(nil, value)
+
+	result := map[string]interface{}{}
+	if err := schema.Reconstruct(&result, row); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"id":    int64(42),
+		"name":  "hello",
+		"score": nil,
+		"count": int32(7),
+		"time":  time.Date(2022, 6, 30, 12, 34, 56, 789e6, time.UTC),
+		"tags":  []interface{}{"a", "b"},
+		"attributes": map[string]interface{}{
+			"answer": int64(42),
+			"none":   nil,
+		},
+		"location": nil,
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("wrong reconstructed value:\nwant = %#v\ngot  = %#v", want, result)
+	}
+
+	var dynamic interface{}
+	if err := schema.Reconstruct(&dynamic, row); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dynamic, want) {
+		t.Errorf("wrong value reconstructed into interface:\nwant = %#v\ngot  = %#v", want, dynamic)
+	}
+
+	value["location"] = map[string]interface{}{"lat": 48.85, "lng": 2.35}
+	want["location"] = map[string]interface{}{"lat": 48.85, "lng": 2.35}
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output, schema)
+	if err := writer.Write(value); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(output.Bytes()))
+	result = map[string]interface{}{}
+	if err := reader.Read(&result); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("wrong value read from parquet file:\nwant = %#v\ngot  = %#v", want, result)
+	}
+}
+
+func TestDeconstructionReconstructionListOptionalElements(t *testing.T) {
+	schema, err := parquet.ParseSchema(`message List {
+	optional group values (LIST) {
+		repeated group list {
+			optional int64 element;
+		}
+	}
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	one, three := int64(1), int64(3)
+	row := schema.Deconstruct(nil, map[string]interface{}{
+		"values": []interface{}{one, nil, three},
+	})
+
+	// The definition level of null elements must be the one of the repeated
+	// list group, which is one less than the level of the present elements.
+	want := parquet.Row{
+		parquet.ValueOf(one).Level(0, 3, 0),
+		parquet.ValueOf(nil).Level(1, 2, 0),
+		parquet.ValueOf(three).Level(1, 3, 0),
+	}
+	if !row.Equal(want) {
+		t.Fatalf("wrong deconstructed row:\nwant = %+v\ngot  = %+v", want, row)
+	}
+
+	var dynamic map[string]interface{}
+	if err := schema.Reconstruct(&dynamic, row); err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{one, nil, three}; !reflect.DeepEqual(dynamic["values"], want) {
+		t.Errorf("wrong reconstructed list:\nwant = %#v\ngot  = %#v", want, dynamic["values"])
+	}
+
+	var value struct {
+		Values []*int64 `parquet:"values"`
+	}
+	if err := schema.Reconstruct(&value, row); err != nil {
+		t.Fatal(err)
+	}
+	if want := []*int64{&one, nil, &three}; !reflect.DeepEqual(value.Values, want) {
+		t.Errorf("wrong reconstructed slice of pointers:\nwant = %v\ngot  = %v", want, value.Values)
+	}
+}
+
+func TestDeconstructDynamicErrors(t *testing.T) {
+	schema, err := parquet.ParseSchema(`message Event {
+	required int32 id;
+	optional group tags (LIST) {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scenario string
+		value    map[string]interface{}
+		error    string
+	}{
+		{
+			scenario: "missing required field",
+			value:    map[string]interface{}{},
+			error:    `missing value of required field "id"`,
+		},
+		{
+			scenario: "fractional number in integer column",
+			value:    map[string]interface{}{"id": 1.5},
+			error:    "id: cannot convert go value 1.5 of type float64 to parquet column of type INT32",
+		},
+		{
+			scenario: "integer overflow",
+			value:    map[string]interface{}{"id": int64(1) << 40},
+			error:    "id: cannot convert go value 1099511627776 of type int64 to parquet column of type INT32",
+		},
+		{
+			scenario: "list holding a string",
+			value:    map[string]interface{}{"id": 1, "tags": "a"},
+			error:    "tags.list.element: cannot deconstruct go value of type string into repeated column",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			writer := parquet.NewWriter(new(bytes.Buffer), schema)
+			err := writer.Write(test.value)
+			if err == nil {
+				t.Fatal("expected an error but got <nil>")
+			}
+			if !strings.Contains(err.Error(), test.error) {
+				t.Errorf("wrong error:\nwant = %s\ngot  = %s", test.error, err)
+			}
+		})
+	}
+}
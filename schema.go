@@ -21,6 +21,7 @@ import (
 type Schema struct {
 	name        string
 	root        Node
+	once        sync.Once
 	deconstruct deconstructFunc
 	reconstruct reconstructFunc
 	readRow     columnReadRowFunc
@@ -55,8 +56,12 @@ type Schema struct {
 //	enum      | for string types, use the parquet ENUM logical type
 //	uuid      | for string and [16]byte types, use the parquet UUID logical type
 //	decimal   | for int32, int64 and [n]byte types, use the parquet DECIMAL logical type
+//	float16   | for [2]byte types, use the parquet FLOAT16 logical type
+//	interval  | for [12]byte types, use the parquet INTERVAL converted type
 //	date      | for int32 types use the DATE logical type
-//	timestamp | for int64 types use the TIMESTAMP logical type with millisecond precision
+//	timestamp | for int64 and time.Time types use the TIMESTAMP logical type
+//	time      | for int32, int64 and time.Duration types use the TIME logical type
+//	id        | sets the field id of the parquet column
 //
 // The date logical type is an int32 value of the number of days since the unix epoch
 //
@@ -67,6 +72,25 @@ type Schema struct {
 //		Cost int64 `parquet:"cost,decimal(0:3)"`
 //	}
 //
+// The id tag must be followed by a positive integer parameter; for example:
+//
+//	type Item struct {
+//		Name string `parquet:"name,id(12)"`
+//	}
+//
+// The timestamp and time tags may be followed by the time unit of the column
+// (millisecond, microsecond or nanosecond, defaulting to millisecond), and
+// optionally by "local" to mark the values as not adjusted to UTC; for example:
+//
+//	type Event struct {
+//		Time    time.Time     `parquet:"time,timestamp(microsecond)"`
+//		Local   time.Time     `parquet:"local,timestamp(nanosecond,local)"`
+//		Elapsed time.Duration `parquet:"elapsed,time(millisecond)"`
+//	}
+//
+// Fields of type time.Time and time.Duration with no such tags use the
+// TIMESTAMP and TIME logical types with nanosecond precision.
+//
 // Invalid combination of struct tags and Go types, or repeating options will
 // cause the function to panic.
 //
@@ -100,20 +124,53 @@ func schemaOf(model reflect.Type) *Schema {
 func NewSchema(name string, root Node) *Schema {
 	mapping, columns := columnMappingOf(root)
 	return &Schema{
-		name: name,
-		root: root,
-		// deconstruct: makeDeconstructFunc(root),
-		// reconstruct: makeReconstructFunc(root),
-		// readRow: makeColumnReadRowFunc(root, nil),
-		mapping:     mapping,
-		columns:     columns,
+		name:    name,
+		root:    root,
+		mapping: mapping,
+		columns: columns,
 	}
 }
 
+// MakeColumnReadRowFunc restricts the set of top-level fields that rows read
+// with the schema are made of to those named in usedFields.
+//
+// By default, all the fields of the schema are read.
+//
+// Deprecated: the function only supports selecting top-level fields and
+// mutates the schema; use ProjectSchema, ProjectRowGroup, or the SelectColumns
+// option instead.
 func (s *Schema) MakeColumnReadRowFunc(usedFields []string) {
+	s.init()
 	s.readRow = makeColumnReadRowFunc(s.root, usedFields)
 }
 
+// init lazily compiles the functions used to deconstruct, reconstruct, and read
+// rows of the schema. Building them is deferred until first use so that the
+// cost is only paid by programs that need them, and that schemas that cannot
+// be mapped to Go values may still be used to describe parquet files.
+func (s *Schema) init() {
+	s.once.Do(func() {
+		s.deconstruct = makeDeconstructFunc(s.root)
+		s.reconstruct = makeReconstructFunc(s.root)
+		_, s.readRow = columnReadRowFuncOf(s.root, 0, 0)
+	})
+}
+
+func (s *Schema) deconstructFunc() deconstructFunc {
+	s.init()
+	return s.deconstruct
+}
+
+func (s *Schema) reconstructFunc() reconstructFunc {
+	s.init()
+	return s.reconstruct
+}
+
+func (s *Schema) readRowFunc() columnReadRowFunc {
+	s.init()
+	return s.readRow
+}
+
 func dereference(t reflect.Type) reflect.Type {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -123,7 +180,7 @@ func dereference(t reflect.Type) reflect.Type {
 
 func makeDeconstructFunc(node Node) (deconstruct deconstructFunc) {
 	if schema, _ := node.(*Schema); schema != nil {
-		return schema.deconstruct
+		return schema.deconstructFunc()
 	}
 	if !node.Leaf() {
 		_, deconstruct = deconstructFuncOf(0, node)
@@ -133,7 +190,7 @@ func makeDeconstructFunc(node Node) (deconstruct deconstructFunc) {
 
 func makeReconstructFunc(node Node) (reconstruct reconstructFunc) {
 	if schema, _ := node.(*Schema); schema != nil {
-		return schema.reconstruct
+		return schema.reconstructFunc()
 	}
 	if !node.Leaf() {
 		_, reconstruct = reconstructFuncOf(0, node)
@@ -193,6 +250,11 @@ func (s *Schema) ConfigureReader(config *ReaderConfig) { config.Schema = s }
 // output parquet file.
 func (s *Schema) ConfigureWriter(config *WriterConfig) { config.Schema = s }
 
+// ConfigureDataset satisfies the DatasetOption interface, allowing Schema
+// instances to be passed to OpenDataset to declare the schema that the files of
+// the dataset are converted to.
+func (s *Schema) ConfigureDataset(config *DatasetConfig) { config.Schema = s }
+
 // String returns a parquet schema representation of s.
 func (s *Schema) String() string { return sprint(s.name, s.root) }
 
@@ -227,11 +289,33 @@ func (s *Schema) Compression() compress.Codec { return s.root.Compression() }
 // GoType returns the Go type that best represents the schema.
 func (s *Schema) GoType() reflect.Type { return s.root.GoType() }
 
+// FieldID returns the field id of the root node of the parquet schema.
+func (s *Schema) FieldID() int { return fieldIDOf(s.root) }
+
 // Deconstruct deconstructs a Go value and appends it to a row.
 //
+// The value may be a Go struct, or a dynamic value like map[string]interface{}
+// holding the values of fields at the keys matching their names. Lists and
+// repeated fields are represented by slices (e.g. []interface{}), and maps by Go
+// maps. Values held in interfaces are converted to the types of the columns
+// they are written to when it does not lose information; for example, float64
+// values decoded from JSON may be written to INT64 columns if they have no
+// fractional part. Keys of maps that do not match any fields are ignored.
+//
 // The method panics is the structure of the go value does not match the
 // parquet schema.
 func (s *Schema) Deconstruct(row Row, value interface{}) Row {
+	row, err := s.deconstructValue(row, value)
+	if err != nil {
+		panic(err)
+	}
+	return row
+}
+
+// deconstructValue is like Deconstruct but returns an error when a dynamic
+// value does not match the schema, which is used by writers to report errors
+// on rows built from data that the application does not control.
+func (s *Schema) deconstructValue(row Row, value interface{}) (_ Row, err error) {
 	v := reflect.ValueOf(value)
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
@@ -240,10 +324,24 @@ func (s *Schema) Deconstruct(row Row, value interface{}) Row {
 			v = v.Elem()
 		}
 	}
-	if s.deconstruct != nil {
-		row = s.deconstruct(row, levels{}, v)
+	deconstruct := s.deconstructFunc()
+	if deconstruct == nil {
+		return row, nil
 	}
-	return row
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*deconstructError)
+			if !ok {
+				panic(r)
+			}
+			if e.columnIndex >= 0 && int(e.columnIndex) < len(s.columns) {
+				err = fmt.Errorf("cannot deconstruct go value of type %T: %s: %w", value, columnPath(s.columns[e.columnIndex]), e)
+			} else {
+				err = fmt.Errorf("cannot deconstruct go value of type %T: %w", value, e)
+			}
+		}
+	}()
+	return deconstruct(row, levels{}, v), nil
 }
 
 // Reconstruct reconstructs a Go value from a row.
@@ -251,6 +349,13 @@ func (s *Schema) Deconstruct(row Row, value interface{}) Row {
 // The go value passed as first argument must be a non-nil pointer for the
 // row to be decoded into.
 //
+// Rows may be reconstructed into dynamic values like map[string]interface{}.
+// Interfaces receive values of Go types chosen from the schema: groups are
+// reconstructed as map[string]interface{}, lists and repeated fields as
+// []interface{}, maps as Go maps with interface{} values, timestamps as
+// time.Time, and other leaf columns as values of their Go type (e.g. int64 or
+// string). Null values are represented by nil interfaces.
+//
 // The method panics if the structure of the go value and parquet row do not
 // match.
 func (s *Schema) Reconstruct(value interface{}, row Row) error {
@@ -265,8 +370,8 @@ func (s *Schema) Reconstruct(value interface{}, row Row) error {
 		panic("cannot reconstruct row into nil pointer of type " + v.Type().String())
 	}
 	var err error
-	if s.reconstruct != nil {
-		row, err = s.reconstruct(v.Elem(), levels{}, row)
+	if reconstruct := s.reconstructFunc(); reconstruct != nil {
+		row, err = reconstruct(v.Elem(), levels{}, row)
 		if len(row) > 0 && err == nil {
 			err = fmt.Errorf("%d values remain unused after reconstructing go value of type %s from parquet row", len(row), v.Type())
 		}
@@ -408,6 +513,8 @@ type structField struct {
 
 func (f *structField) Name() string { return f.name }
 
+func (f *structField) FieldID() int { return fieldIDOf(f.Node) }
+
 func (f *structField) Value(base reflect.Value) reflect.Value {
 	switch base.Kind() {
 	case reflect.Map:
@@ -419,7 +526,6 @@ func (f *structField) Value(base reflect.Value) reflect.Value {
 			return fieldByIndex(base, f.index)
 		}
 	}
-	return reflect.Value{}
 }
 
 func structFieldString(f reflect.StructField) string {
@@ -443,6 +549,7 @@ func makeStructField(f reflect.StructField) structField {
 		field      = structField{name: f.Name, index: f.Index}
 		optional   bool
 		list       bool
+		fieldID    int
 		encoded    encoding.Encoding
 		compressed compress.Codec
 	)
@@ -468,6 +575,13 @@ func makeStructField(f reflect.StructField) structField {
 		list = true
 	}
 
+	setFieldID := func(id int) {
+		if fieldID != 0 {
+			throwInvalidStructField("struct field has field id declared multiple times", f)
+		}
+		fieldID = id
+	}
+
 	setEncoding := func(e encoding.Encoding) {
 		if encoded != nil {
 			throwInvalidStructField("struct field has encoding declared multiple times", f)
@@ -569,6 +683,27 @@ func makeStructField(f reflect.StructField) structField {
 					throwInvalidFieldTag(f, option)
 				}
 
+			case "float16":
+				switch f.Type.Kind() {
+				case reflect.Array:
+					if f.Type.Elem().Kind() != reflect.Uint8 || f.Type.Len() != 2 {
+						throwInvalidFieldTag(f, option)
+					}
+					setNode(Float16())
+				default:
+					throwInvalidFieldTag(f, option)
+				}
+
+			case "interval":
+				switch {
+				case f.Type == goIntervalType:
+					setNode(Interval())
+				case f.Type.Kind() == reflect.Array && f.Type.Elem().Kind() == reflect.Uint8 && f.Type.Len() == 12:
+					setNode(Interval())
+				default:
+					throwInvalidFieldTag(f, option)
+				}
+
 			case "decimal":
 				scale, precision, err := parseDecimalArgs(args)
 				if err != nil {
@@ -595,12 +730,37 @@ func makeStructField(f reflect.StructField) structField {
 					throwInvalidFieldTag(f, option)
 				}
 			case "timestamp":
-				switch f.Type.Kind() {
-				case reflect.Int64:
-					setNode(Timestamp(Millisecond))
+				unit, isAdjustedToUTC, err := parseTimestampArgs(args)
+				if err != nil {
+					throwInvalidFieldTag(f, option+args)
+				}
+				switch {
+				case f.Type == goTimeType, f.Type.Kind() == reflect.Int64:
+					setNode(TimestampAdjusted(unit, isAdjustedToUTC))
 				default:
 					throwInvalidFieldTag(f, option)
 				}
+			case "time":
+				unit, isAdjustedToUTC, err := parseTimestampArgs(args)
+				if err != nil {
+					throwInvalidFieldTag(f, option+args)
+				}
+				// Times in milliseconds are stored as INT32 values, other
+				// units use INT64 values.
+				switch {
+				case f.Type == goDurationType,
+					f.Type.Kind() == reflect.Int32 && unit == Millisecond,
+					f.Type.Kind() == reflect.Int64 && unit != Millisecond:
+					setNode(TimeAdjusted(unit, isAdjustedToUTC))
+				default:
+					throwInvalidFieldTag(f, option+args)
+				}
+			case "id":
+				id, err := parseFieldIDArgs(args)
+				if err != nil {
+					throwInvalidFieldTag(f, option+args)
+				}
+				setFieldID(id)
 			default:
 				throwUnknownFieldTag(f, option)
 			}
@@ -627,6 +787,10 @@ func makeStructField(f reflect.StructField) structField {
 		field.Node = Optional(field.Node)
 	}
 
+	if fieldID != 0 {
+		field.Node = FieldID(field.Node, fieldID)
+	}
+
 	return field
 }
 
@@ -636,6 +800,12 @@ func nodeOf(t reflect.Type) Node {
 		return Leaf(Int96Type)
 	case reflect.TypeOf(uuid.UUID{}):
 		return UUID()
+	case goTimeType:
+		return &goNode{Node: Timestamp(Nanosecond), gotype: t}
+	case goDurationType:
+		return &goNode{Node: Time(Nanosecond), gotype: t}
+	case goIntervalType:
+		return Interval()
 	}
 
 	var n Node
@@ -694,12 +864,22 @@ func nodeOf(t reflect.Type) Node {
 }
 
 func split(s string) (head, tail string) {
-	if i := strings.IndexByte(s, ','); i < 0 {
-		head = s
-	} else {
-		head, tail = s[:i], s[i+1:]
+	// Commas within parenthesis separate the arguments of an option, they do
+	// not delimit the options of the tag.
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth <= 0 {
+				return s[:i], s[i+1:]
+			}
+		}
 	}
-	return
+	return s, ""
 }
 
 func splitOptionArgs(s string) (option, args string) {
@@ -710,6 +890,59 @@ func splitOptionArgs(s string) (option, args string) {
 	}
 }
 
+func parseFieldIDArgs(args string) (int, error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return 0, fmt.Errorf("malformed field id args: %s", args)
+	}
+	args = strings.TrimPrefix(args, "(")
+	args = strings.TrimSuffix(args, ")")
+	id, err := strconv.ParseInt(args, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if id <= 0 {
+		return 0, fmt.Errorf("invalid field id: %d", id)
+	}
+	return int(id), nil
+}
+
+func parseTimestampArgs(args string) (unit TimeUnit, isAdjustedToUTC bool, err error) {
+	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return nil, false, fmt.Errorf("malformed time args: %s", args)
+	}
+	args = strings.TrimPrefix(args, "(")
+	args = strings.TrimSuffix(args, ")")
+	unit, isAdjustedToUTC = Millisecond, true
+	if args == "" {
+		return unit, isAdjustedToUTC, nil
+	}
+	parts := strings.Split(args, ",")
+	if len(parts) > 2 {
+		return nil, false, fmt.Errorf("malformed time args: (%s)", args)
+	}
+	switch strings.TrimSpace(parts[0]) {
+	case "millisecond":
+		unit = Millisecond
+	case "microsecond":
+		unit = Microsecond
+	case "nanosecond":
+		unit = Nanosecond
+	default:
+		return nil, false, fmt.Errorf("invalid time unit: %s", parts[0])
+	}
+	if len(parts) == 2 {
+		switch strings.TrimSpace(parts[1]) {
+		case "utc":
+			isAdjustedToUTC = true
+		case "local":
+			isAdjustedToUTC = false
+		default:
+			return nil, false, fmt.Errorf("invalid time adjustment: %s", parts[1])
+		}
+	}
+	return unit, isAdjustedToUTC, nil
+}
+
 func parseDecimalArgs(args string) (scale, precision int, err error) {
 	if !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
 		return 0, 0, fmt.Errorf("malformed decimal args: %s", args)
@@ -738,6 +971,8 @@ type goNode struct {
 
 func (n *goNode) GoType() reflect.Type { return n.gotype }
 
+func (n *goNode) FieldID() int { return fieldIDOf(n.Node) }
+
 var (
 	_ RowGroupOption = (*Schema)(nil)
 	_ ReaderOption   = (*Schema)(nil)
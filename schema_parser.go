@@ -0,0 +1,470 @@
+package parquet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseSchema parses a parquet schema from its textual representation, which
+// is the message type format produced by PrintSchema and other parquet tools:
+//
+//	message Event {
+//		required int64 id (INT(64,true)) = 1;
+//		optional binary name (STRING);
+//		required int64 time (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+//		optional group tags (LIST) {
+//			repeated group list {
+//				required binary element (STRING);
+//			}
+//		}
+//	}
+//
+// Fields may be annotated with logical types (e.g. STRING, DECIMAL, TIMESTAMP)
+// or with the names of the deprecated converted types (e.g. UTF8, INT_32,
+// TIMESTAMP_MILLIS). The parameters of the TIME and TIMESTAMP annotations may
+// be passed by name as printed by PrintSchema, or by position, for example
+// TIMESTAMP(MICROS,true). Since the scale of decimals cannot be greater than
+// their precision, the two parameters of DECIMAL annotations may be written in
+// any order.
+//
+// The groups and fields of the returned schema retain the order in which they
+// were declared, which allows the schema to round-trip with PrintSchema.
+func ParseSchema(text string) (*Schema, error) {
+	p := &schemaParser{text: text, line: 1}
+	schema, err := p.parseMessage()
+	if err != nil {
+		return nil, fmt.Errorf("parsing parquet schema: line %d: %w", p.line, err)
+	}
+	return schema, nil
+}
+
+type schemaParser struct {
+	text string
+	line int
+}
+
+// next returns the next token of the input, which is either a punctuation
+// character or a sequence of characters delimited by spaces or punctuation.
+// The function returns an empty string when the end of the input is reached.
+func (p *schemaParser) next() string {
+	for len(p.text) > 0 {
+		switch c := p.text[0]; c {
+		case '\n':
+			p.line++
+			fallthrough
+		case ' ', '\t', '\r':
+			p.text = p.text[1:]
+			continue
+		case '(', ')', '{', '}', ';', ',', '=':
+			token := p.text[:1]
+			p.text = p.text[1:]
+			return token
+		}
+		i := strings.IndexAny(p.text, " \t\r\n(){};,=")
+		if i < 0 {
+			i = len(p.text)
+		}
+		token := p.text[:i]
+		p.text = p.text[i:]
+		return token
+	}
+	return ""
+}
+
+// peek returns the next token without consuming it.
+func (p *schemaParser) peek() string {
+	text, line := p.text, p.line
+	token := p.next()
+	p.text, p.line = text, line
+	return token
+}
+
+func (p *schemaParser) expect(want string) error {
+	if token := p.next(); token != want {
+		return unexpectedToken(token, want)
+	}
+	return nil
+}
+
+func unexpectedToken(token, want string) error {
+	if token == "" {
+		return fmt.Errorf("unexpected end of input, expected %q", want)
+	}
+	return fmt.Errorf("unexpected %q, expected %q", token, want)
+}
+
+func (p *schemaParser) parseMessage() (*Schema, error) {
+	if err := p.expect("message"); err != nil {
+		return nil, err
+	}
+	name := ""
+	if p.peek() != "{" {
+		name = p.next()
+	}
+	fields, err := p.parseFields()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.next(); token != "" {
+		return nil, fmt.Errorf("unexpected %q after the end of the message", token)
+	}
+	return NewSchema(name, &projectedGroup{Node: Group{}, fields: fields}), nil
+}
+
+func (p *schemaParser) parseFields() ([]Field, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	fields := []Field{}
+	names := make(map[string]struct{})
+	for p.peek() != "}" {
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := names[field.Name()]; exists {
+			return nil, fmt.Errorf("duplicate field name %q", field.Name())
+		}
+		names[field.Name()] = struct{}{}
+		fields = append(fields, field)
+	}
+	p.next()
+	return fields, nil
+}
+
+func (p *schemaParser) parseField() (Field, error) {
+	var repetition func(Node) Node
+	switch token := p.next(); strings.ToLower(token) {
+	case "required":
+		repetition = Required
+	case "optional":
+		repetition = Optional
+	case "repeated":
+		repetition = Repeated
+	default:
+		return nil, unexpectedToken(token, "required")
+	}
+
+	isGroup := strings.EqualFold(p.peek(), "group")
+	physicalType, length := "", 0
+	if isGroup {
+		p.next()
+	} else {
+		var err error
+		if physicalType, length, err = p.parsePhysicalType(); err != nil {
+			return nil, err
+		}
+	}
+
+	name := p.next()
+	if !isName(name) {
+		return nil, unexpectedToken(name, "field name")
+	}
+
+	annotation, err := p.parseAnnotation()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	id := 0
+	if p.peek() == "=" {
+		p.next()
+		token := p.next()
+		if id, err = strconv.Atoi(token); err != nil || id <= 0 {
+			return nil, fmt.Errorf("%s: invalid field id: %q", name, token)
+		}
+	}
+
+	var node Node
+	if isGroup {
+		fields, err := p.parseFields()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() == ";" {
+			p.next()
+		}
+		node, err = makeParsedGroup(annotation, fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	} else {
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+		node, err = makeParsedLeaf(physicalType, length, annotation)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	node = repetition(node)
+	if id != 0 {
+		node = FieldID(node, id)
+	}
+	return &projectedField{Node: node, name: name}, nil
+}
+
+func (p *schemaParser) parsePhysicalType() (physicalType string, length int, err error) {
+	physicalType = strings.ToLower(p.next())
+	switch physicalType {
+	case "boolean", "int32", "int64", "int96", "float", "double", "binary":
+	case "fixed_len_byte_array":
+		if err := p.expect("("); err != nil {
+			return "", 0, err
+		}
+		token := p.next()
+		if length, err = strconv.Atoi(token); err != nil || length <= 0 {
+			return "", 0, fmt.Errorf("invalid length of fixed_len_byte_array: %q", token)
+		}
+		if err := p.expect(")"); err != nil {
+			return "", 0, err
+		}
+	case "":
+		return "", 0, unexpectedToken("", "type")
+	default:
+		return "", 0, fmt.Errorf("unsupported physical type: %q", physicalType)
+	}
+	return physicalType, length, nil
+}
+
+// schemaAnnotation is the representation of logical or converted type
+// annotations, for example TIMESTAMP(isAdjustedToUTC=true,unit=MICROS) has the
+// name "TIMESTAMP" and two arguments.
+type schemaAnnotation struct {
+	name string
+	args []schemaAnnotationArg
+}
+
+type schemaAnnotationArg struct {
+	key   string // empty for positional arguments
+	value string
+}
+
+func (p *schemaParser) parseAnnotation() (a schemaAnnotation, err error) {
+	if p.peek() != "(" {
+		return a, nil
+	}
+	p.next()
+	if a.name = strings.ToUpper(p.next()); !isName(a.name) {
+		return a, unexpectedToken(a.name, "annotation")
+	}
+	if p.peek() == "(" {
+		p.next()
+		for {
+			arg := schemaAnnotationArg{value: p.next()}
+			if p.peek() == "=" {
+				p.next()
+				arg.key, arg.value = arg.value, p.next()
+			}
+			if !isName(arg.value) {
+				return a, unexpectedToken(arg.value, "annotation argument")
+			}
+			a.args = append(a.args, arg)
+			token := p.next()
+			if token == ")" {
+				break
+			}
+			if token != "," {
+				return a, unexpectedToken(token, ")")
+			}
+		}
+	}
+	return a, p.expect(")")
+}
+
+// arg returns the value of the argument at the given position, or with the
+// given name, or an empty string if the annotation had no such argument.
+func (a *schemaAnnotation) arg(index int, key string) string {
+	for i, arg := range a.args {
+		if strings.EqualFold(arg.key, key) || (arg.key == "" && i == index) {
+			return arg.value
+		}
+	}
+	return ""
+}
+
+func (a *schemaAnnotation) intArgs() ([]int, error) {
+	values := make([]int, len(a.args))
+	for i, arg := range a.args {
+		v, err := strconv.Atoi(arg.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation argument: %q", a.name, arg.value)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func isName(token string) bool {
+	switch token {
+	case "", "(", ")", "{", "}", ";", ",", "=":
+		return false
+	default:
+		return true
+	}
+}
+
+func makeParsedGroup(annotation schemaAnnotation, fields []Field) (Node, error) {
+	// Groups of parsed schemas are represented by the same type as projected
+	// groups, which retain the order of their fields.
+	group := &projectedGroup{fields: fields}
+	switch annotation.name {
+	case "":
+		group.Node = Group{}
+	case "LIST":
+		group.Node = listNode{}
+	case "MAP":
+		group.Node = mapNode{}
+	case "MAP_KEY_VALUE":
+		// This legacy annotation has no equivalent logical type, the layout
+		// of the group is sufficient to read maps.
+		group.Node = Group{}
+	default:
+		return nil, fmt.Errorf("invalid annotation of group: %s", annotation.name)
+	}
+	return group, nil
+}
+
+func makeParsedLeaf(physicalType string, length int, annotation schemaAnnotation) (Node, error) {
+	var typ Type
+	switch physicalType {
+	case "boolean":
+		typ = BooleanType
+	case "int32":
+		typ = Int32Type
+	case "int64":
+		typ = Int64Type
+	case "int96":
+		typ = Int96Type
+	case "float":
+		typ = FloatType
+	case "double":
+		typ = DoubleType
+	case "binary":
+		typ = ByteArrayType
+	case "fixed_len_byte_array":
+		typ = FixedLenByteArrayType(length)
+	}
+
+	if annotation.name == "" {
+		return Leaf(typ), nil
+	}
+
+	node, err := makeAnnotatedLeaf(typ, annotation)
+	if err != nil {
+		return nil, err
+	}
+	if t := node.Type(); t.Kind() != typ.Kind() || (typ.Kind() == FixedLenByteArray && t.Length() != typ.Length()) {
+		return nil, fmt.Errorf("invalid annotation of %s column: %s", physicalType, annotation.name)
+	}
+	return node, nil
+}
+
+func makeAnnotatedLeaf(typ Type, annotation schemaAnnotation) (Node, error) {
+	switch annotation.name {
+	case "STRING", "UTF8":
+		return String(), nil
+	case "ENUM":
+		return Enum(), nil
+	case "JSON":
+		return JSON(), nil
+	case "BSON":
+		return BSON(), nil
+	case "UUID":
+		return UUID(), nil
+	case "DATE":
+		return Date(), nil
+	case "FLOAT16":
+		return Float16(), nil
+	case "INTERVAL":
+		return Interval(), nil
+
+	case "INT", "INTEGER":
+		if len(annotation.args) != 2 {
+			return nil, fmt.Errorf("%s annotation must have two arguments", annotation.name)
+		}
+		bitWidth, err1 := strconv.Atoi(annotation.args[0].value)
+		isSigned, err2 := strconv.ParseBool(annotation.args[1].value)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid %s annotation arguments: %q, %q", annotation.name, annotation.args[0].value, annotation.args[1].value)
+		}
+		switch bitWidth {
+		case 8, 16, 32, 64:
+		default:
+			return nil, fmt.Errorf("invalid bit width of %s annotation: %d", annotation.name, bitWidth)
+		}
+		if isSigned {
+			return Int(bitWidth), nil
+		}
+		return Uint(bitWidth), nil
+
+	case "INT_8", "INT_16", "INT_32", "INT_64":
+		bitWidth, _ := strconv.Atoi(strings.TrimPrefix(annotation.name, "INT_"))
+		return Int(bitWidth), nil
+	case "UINT_8", "UINT_16", "UINT_32", "UINT_64":
+		bitWidth, _ := strconv.Atoi(strings.TrimPrefix(annotation.name, "UINT_"))
+		return Uint(bitWidth), nil
+
+	case "DECIMAL":
+		args, err := annotation.intArgs()
+		if err == nil && len(args) != 2 {
+			err = fmt.Errorf("DECIMAL annotation must have two arguments")
+		}
+		if err != nil {
+			return nil, err
+		}
+		precision, scale := args[0], args[1]
+		if scale > precision {
+			return nil, fmt.Errorf("DECIMAL annotation scale must not exceed the precision: DECIMAL(%d,%d)", precision, scale)
+		}
+		switch typ.Kind() {
+		case Int32, Int64, FixedLenByteArray:
+		default:
+			return nil, fmt.Errorf("invalid annotation of %s column: DECIMAL", typ)
+		}
+		return Decimal(scale, precision, typ), nil
+
+	case "TIME", "TIMESTAMP":
+		unit, err := parseAnnotationTimeUnit(annotation.arg(0, "unit"))
+		if err != nil {
+			return nil, err
+		}
+		isAdjustedToUTC := true
+		if arg := annotation.arg(1, "isAdjustedToUTC"); arg != "" {
+			if isAdjustedToUTC, err = strconv.ParseBool(arg); err != nil {
+				return nil, fmt.Errorf("invalid %s annotation argument: %q", annotation.name, arg)
+			}
+		}
+		if annotation.name == "TIME" {
+			return TimeAdjusted(unit, isAdjustedToUTC), nil
+		}
+		return TimestampAdjusted(unit, isAdjustedToUTC), nil
+
+	case "TIME_MILLIS":
+		return Time(Millisecond), nil
+	case "TIME_MICROS":
+		return Time(Microsecond), nil
+	case "TIMESTAMP_MILLIS":
+		return Timestamp(Millisecond), nil
+	case "TIMESTAMP_MICROS":
+		return Timestamp(Microsecond), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported annotation: %s", annotation.name)
+	}
+}
+
+func parseAnnotationTimeUnit(unit string) (TimeUnit, error) {
+	switch strings.ToUpper(unit) {
+	case "MILLIS":
+		return Millisecond, nil
+	case "MICROS":
+		return Microsecond, nil
+	case "NANOS":
+		return Nanosecond, nil
+	default:
+		return nil, fmt.Errorf("invalid time unit: %q", unit)
+	}
+}
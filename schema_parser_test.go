@@ -0,0 +1,177 @@
+package parquet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    string
+		print    string
+	}{
+		{
+			scenario: "round trip",
+			input: `message Event {
+	required int64 id (INT(64,true)) = 1;
+	optional binary name (STRING) = 2;
+	required int64 time (TIMESTAMP(isAdjustedToUTC=false,unit=NANOS));
+	required int32 day (DATE);
+	required int64 elapsed (TIME(isAdjustedToUTC=true,unit=MICROS));
+	required fixed_len_byte_array(16) uuid (UUID);
+	required fixed_len_byte_array(2) weight (FLOAT16);
+	required fixed_len_byte_array(12) period (INTERVAL);
+	required fixed_len_byte_array(8) amount (DECIMAL(18,2));
+	optional binary kind (ENUM);
+	optional binary doc (JSON);
+	optional binary raw;
+	required int96 legacy;
+	required boolean flag;
+	required float ratio;
+	required double score;
+	optional group tags (LIST) = 3 {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+	optional group attributes (MAP) {
+		repeated group key_value {
+			required binary key (STRING);
+			optional int32 value (INT(16,false));
+		}
+	}
+	repeated group zebra {
+		required int32 b;
+		required int32 a;
+	}
+}`,
+		},
+
+		{
+			scenario: "alternative syntax",
+			input: `
+message
+Record {
+  REQUIRED INT64 id (INTEGER(64, true)) = 7;
+  optional BINARY name (UTF8);
+  required int64 time (TIMESTAMP(MICROS,true));
+  required int64 millis (TIMESTAMP_MILLIS);
+  required int32 time_of_day (TIME_MILLIS);
+  required int32 small (INT_8);
+  required int64 large (UINT_64);
+  required FIXED_LEN_BYTE_ARRAY(16) price (DECIMAL(38,9));
+  required int32 cost (decimal(9,2));
+  optional group map (MAP_KEY_VALUE) {
+    repeated group map { required binary key (UTF8); };
+  };
+}`,
+			print: `message Record {
+	required int64 id (INT(64,true)) = 7;
+	optional binary name (STRING);
+	required int64 time (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	required int64 millis (TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS));
+	required int32 time_of_day (TIME(isAdjustedToUTC=true,unit=MILLIS));
+	required int32 small (INT(8,true));
+	required int64 large (INT(64,false));
+	required fixed_len_byte_array(16) price (DECIMAL(38,9));
+	required int32 cost (DECIMAL(9,2));
+	optional group map {
+		repeated group map {
+			required binary key (STRING);
+		}
+	}
+}`,
+		},
+
+		{
+			scenario: "empty message",
+			input:    `message {}`,
+			print:    `message {` + "\n" + `}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			schema, err := parquet.ParseSchema(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := test.print
+			if want == "" {
+				want = test.input
+			}
+			if s := schema.String(); s != want {
+				t.Errorf("\nexpected:\n\n%s\n\nfound:\n\n%s\n", want, s)
+			}
+		})
+	}
+}
+
+func TestParseSchemaOf(t *testing.T) {
+	type Row struct {
+		ID    int64             `parquet:"id,id(1)"`
+		Name  string            `parquet:"name,optional"`
+		Tags  []string          `parquet:"tags,list"`
+		Attrs map[string]string `parquet:"attrs"`
+		Inner struct {
+			Value float64 `parquet:"value"`
+		} `parquet:"inner"`
+	}
+
+	schema := parquet.SchemaOf(new(Row))
+	parsed, err := parquet.ParseSchema(schema.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.String() != schema.String() {
+		t.Errorf("parsed schema mismatch:\n%s\n%s", schema, parsed)
+	}
+	if parsed.Name() != "Row" {
+		t.Errorf("wrong schema name: %q", parsed.Name())
+	}
+	if columns := parsed.Columns(); len(columns) != len(schema.Columns()) {
+		t.Errorf("wrong number of columns: %d", len(columns))
+	}
+	leaf, ok := parsed.Lookup("attrs", "key_value", "value")
+	if !ok || leaf.MaxRepetitionLevel != 1 || leaf.MaxDefinitionLevel != 1 {
+		t.Errorf("wrong map value column: %+v", leaf)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		error string
+	}{
+		{`messages {}`, `line 1: unexpected "messages", expected "message"`},
+		{`message {`, `line 1: unexpected end of input, expected "required"`},
+		{`message { required int64 a; } x`, `unexpected "x" after the end of the message`},
+		{"message {\n\toptional int33 a;\n}", `line 2: unsupported physical type: "int33"`},
+		{`message { required int64 a }`, `unexpected "}", expected ";"`},
+		{`message { required int64 a; required int32 a; }`, `duplicate field name "a"`},
+		{`message { required int64 a (DATE); }`, `a: invalid annotation of int64 column: DATE`},
+		{`message { required binary a (UUID); }`, `a: invalid annotation of binary column: UUID`},
+		{`message { required int32 a (INT(7,true)); }`, `a: invalid bit width of INT annotation: 7`},
+		{`message { required int64 a (TIMESTAMP(SECONDS,true)); }`, `a: invalid time unit: "SECONDS"`},
+		{`message { required int64 a (WHATEVER); }`, `a: unsupported annotation: WHATEVER`},
+		{`message { required int64 a = 0; }`, `a: invalid field id: "0"`},
+		{`message { required group a (STRING) {} }`, `a: invalid annotation of group: STRING`},
+		{`message { required fixed_len_byte_array(x) a; }`, `invalid length of fixed_len_byte_array: "x"`},
+		{`message { required int32 a (DECIMAL(2,5)); }`, `a: DECIMAL annotation scale must not exceed the precision: DECIMAL(2,5)`},
+	}
+
+	for _, test := range tests {
+		t.Run("", func(t *testing.T) {
+			_, err := parquet.ParseSchema(test.input)
+			if err == nil {
+				t.Fatal("expected an error but parsing succeeded")
+			}
+			if !strings.Contains(err.Error(), test.error) {
+				t.Errorf("wrong error:\nwant = %s\ngot  = %s", test.error, err)
+			}
+		})
+	}
+}
@@ -2,10 +2,13 @@ package parquet_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/segmentio/parquet-go"
 )
 
+type float16 [2]byte
+
 func TestSchemaOf(t *testing.T) {
 	tests := []struct {
 		value interface{}
@@ -52,6 +55,59 @@ func TestSchemaOf(t *testing.T) {
 		required binary first_name (STRING);
 		required binary last_name (STRING);
 	}
+}`,
+		},
+
+		{
+			value: new(struct {
+				ID    int64    `parquet:"id,id(1)"`
+				Tags  []string `parquet:"tags,list,id(2)"`
+				Inner struct {
+					Name string `parquet:"name,id(4)"`
+				} `parquet:"inner,optional,id(3)"`
+			}),
+			print: `message {
+	required int64 id (INT(64,true)) = 1;
+	required group tags (LIST) = 2 {
+		repeated group list {
+			required binary element (STRING);
+		}
+	}
+	optional group inner = 3 {
+		required binary name (STRING) = 4;
+	}
+}`,
+		},
+
+		{
+			value: new(struct {
+				Weight float16 `parquet:"weight,float16"`
+				Bias   [2]byte `parquet:"bias,float16,optional"`
+			}),
+			print: `message {
+	required fixed_len_byte_array(2) weight (FLOAT16);
+	optional fixed_len_byte_array(2) bias (FLOAT16);
+}`,
+		},
+
+		{
+			value: new(struct {
+				Time      time.Time     `parquet:"time"`
+				Micros    time.Time     `parquet:"micros,timestamp(microsecond)"`
+				Local     time.Time     `parquet:"local,timestamp(nanosecond,local),optional"`
+				Millis    int64         `parquet:"millis,timestamp"`
+				Elapsed   time.Duration `parquet:"elapsed"`
+				TimeOfDay int32         `parquet:"time_of_day,time(millisecond)"`
+				Duration  time.Duration `parquet:"duration,time(microsecond,local)"`
+			}),
+			print: `message {
+	required int64 time (TIMESTAMP(isAdjustedToUTC=true,unit=NANOS));
+	required int64 micros (TIMESTAMP(isAdjustedToUTC=true,unit=MICROS));
+	optional int64 local (TIMESTAMP(isAdjustedToUTC=false,unit=NANOS));
+	required int64 millis (TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS));
+	required int64 elapsed (TIME(isAdjustedToUTC=true,unit=NANOS));
+	required int32 time_of_day (TIME(isAdjustedToUTC=true,unit=MILLIS));
+	required int64 duration (TIME(isAdjustedToUTC=false,unit=MICROS));
 }`,
 		},
 	}
@@ -66,3 +122,36 @@ func TestSchemaOf(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldIDOfWrappedNodes(t *testing.T) {
+	fieldIDOf := func(node parquet.Node) int {
+		if n, ok := node.(interface{ FieldID() int }); ok {
+			return n.FieldID()
+		}
+		return 0
+	}
+
+	leaf := parquet.FieldID(parquet.Int(64), 42)
+	for _, test := range []struct {
+		scenario string
+		node     parquet.Node
+	}{
+		{scenario: "field id", node: leaf},
+		{scenario: "optional", node: parquet.Optional(leaf)},
+		{scenario: "repeated", node: parquet.Repeated(leaf)},
+		{scenario: "required", node: parquet.Required(leaf)},
+		{scenario: "encoded", node: parquet.Encoded(leaf, &parquet.Plain)},
+		{scenario: "compressed", node: parquet.Compressed(leaf, &parquet.Snappy)},
+		{scenario: "group field", node: parquet.Group{"value": leaf}.Fields()[0]},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			if id := fieldIDOf(test.node); id != 42 {
+				t.Errorf("wrong field id: want 42 but got %d", id)
+			}
+		})
+	}
+
+	if id := fieldIDOf(parquet.Optional(parquet.Int(64))); id != 0 {
+		t.Errorf("node without field id has id %d", id)
+	}
+}
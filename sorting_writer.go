@@ -0,0 +1,321 @@
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// SortingWriter is a parquet writer which produces row groups sorted by the
+// sorting columns configured on the writer, regardless of the order in which
+// rows were written.
+//
+// The writer buffers rows in memory until the number of rows given when
+// constructing the writer is reached; the buffered rows are then sorted and
+// written to a temporary row group acquired from the SortingBuffers pool. When
+// the writer is flushed or closed, the sorted runs of rows are merged into a
+// row group of the output file.
+//
+// Because the temporary row groups are encoded and compressed, they hold a lot
+// less memory than the rows they contain. Using a pool of on-disk buffers
+// allows the writer to produce sorted files much larger than the memory
+// available to the program:
+//
+//	writer := parquet.NewSortingWriter(output, 1e6,
+//		parquet.SortingColumns(
+//			parquet.Ascending("timestamp"),
+//		),
+//		parquet.SortingBuffers(
+//			parquet.NewFileBufferPool("", "sorting-*"),
+//		),
+//	)
+//
+// The MaxRowsPerRowGroup and RowGroupTargetSize options may be used to split
+// the merged rows into multiple row groups, each of them being sorted and
+// following the rows of the previous row group in the sort order.
+type SortingWriter struct {
+	output  *Writer
+	config  *WriterConfig
+	schema  *Schema
+	sorting sortingBuffer
+	maxRows int
+	values  []Value
+	// Sorted runs of rows are written as row groups of a temporary parquet
+	// file held in buffer.
+	runs    *Writer
+	buffer  io.ReadWriter
+	offset  offsetTrackingWriter
+	numRuns int
+}
+
+// NewSortingWriter constructs a parquet writer producing sorted row groups to
+// the given io.Writer, buffering at most sortRowCount rows in memory before
+// writing them to a temporary row group.
+//
+// The function panics if the writer configuration is invalid, or if
+// sortRowCount is not positive.
+func NewSortingWriter(output io.Writer, sortRowCount int64, options ...WriterOption) *SortingWriter {
+	config, err := NewWriterConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+	if sortRowCount <= 0 {
+		panic(errorInvalidOptionValue("sortRowCount", sortRowCount))
+	}
+	w := &SortingWriter{
+		output:  NewWriter(output, config),
+		config:  config,
+		maxRows: int(sortRowCount),
+	}
+	if config.Schema != nil {
+		w.configure(config.Schema)
+	}
+	return w
+}
+
+func (w *SortingWriter) configure(schema *Schema) {
+	w.schema = schema
+	w.sorting.sortFuncs = sortFuncsOf(schema, w.config.SortingColumns)
+	// The runs are temporary files which are only read back by the writer,
+	// options which apply to the output are left to their default values.
+	w.runs = NewWriter(nil, &WriterConfig{
+		ColumnPageBuffers: w.config.ColumnPageBuffers,
+		PageBufferSize:    w.config.PageBufferSize,
+		Schema:            schema,
+		SortingColumns:    w.config.SortingColumns,
+		Compression:       w.config.Compression,
+		SortingBuffers:    w.config.SortingBuffers,
+	})
+	if w.output.schema == nil {
+		w.output.configure(schema)
+	}
+}
+
+// Close flushes the buffered rows and writes the parquet footer to the output.
+func (w *SortingWriter) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return w.output.Close()
+}
+
+// Flush merges all the rows written since the last call to Flush and writes
+// them sorted to the output.
+//
+// Unless the MaxRowsPerRowGroup or RowGroupTargetSize options were set, the
+// rows are written to a single row group.
+func (w *SortingWriter) Flush() error {
+	defer w.releaseBuffer()
+
+	if err := w.sortAndWriteBufferedRows(); err != nil {
+		return err
+	}
+	if w.numRuns == 0 {
+		return nil
+	}
+	if err := w.runs.Close(); err != nil {
+		return err
+	}
+
+	r, err := w.readerAt()
+	if err != nil {
+		return err
+	}
+
+	f, err := OpenFile(r, w.offset.offset,
+		SkipPageIndex(true),
+		SkipBloomFilters(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	merged, err := MergeRowGroups(f.RowGroups(),
+		w.schema,
+		SortingColumns(w.config.SortingColumns...),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := CopyRows(w.output, merged.Rows()); err != nil {
+		return err
+	}
+	return w.output.Flush()
+}
+
+// Reset clears the state of the writer without flushing any of the buffers,
+// and setting the output to the io.Writer passed as argument, allowing the
+// writer to be reused to produce another parquet file.
+func (w *SortingWriter) Reset(output io.Writer) {
+	w.output.Reset(output)
+	w.sorting.reset()
+	w.releaseBuffer()
+}
+
+// Write is called to write another row to the sorting writer.
+//
+// If no schema was configured on the writer, it is deducted from the Go type
+// of the row, which then has to be a struct or pointer to struct.
+func (w *SortingWriter) Write(row interface{}) error {
+	if w.schema == nil {
+		w.configure(SchemaOf(row))
+	}
+	defer func() {
+		clearValues(w.values)
+	}()
+	var err error
+	if w.values, err = w.schema.deconstructValue(w.values[:0], row); err != nil {
+		return err
+	}
+	return w.WriteRow(w.values)
+}
+
+// WriteRow is called to write another row to the sorting writer.
+//
+// The row values are copied, the application may reuse the row after the
+// method returned.
+func (w *SortingWriter) WriteRow(row Row) error {
+	if w.sorting.Len() >= w.maxRows {
+		if err := w.sortAndWriteBufferedRows(); err != nil {
+			return err
+		}
+	}
+	w.sorting.append(row)
+	return nil
+}
+
+// ReadRowsFrom reads rows from the reader passed as arguments and writes them
+// to w.
+func (w *SortingWriter) ReadRowsFrom(rows RowReader) (int64, error) {
+	if w.schema == nil {
+		if r, ok := rows.(RowReaderWithSchema); ok {
+			w.configure(r.Schema())
+		}
+	}
+	written, values, err := copyRows(w, rows, w.values[:0])
+	w.values = values
+	return written, err
+}
+
+// Schema returns the schema of rows written by w.
+//
+// The returned value will be nil if no schema has yet been configured on w.
+func (w *SortingWriter) Schema() *Schema { return w.schema }
+
+func (w *SortingWriter) sortAndWriteBufferedRows() error {
+	if w.sorting.Len() == 0 {
+		return nil
+	}
+	defer w.sorting.reset()
+	sort.Sort(&w.sorting)
+
+	if w.buffer == nil {
+		w.buffer = w.config.SortingBuffers.GetPageBuffer()
+		w.offset.Reset(w.buffer)
+		w.runs.Reset(&w.offset)
+	}
+
+	for i := 0; i < w.sorting.Len(); i++ {
+		if err := w.runs.WriteRow(w.sorting.row(i)); err != nil {
+			return err
+		}
+	}
+	if err := w.runs.Flush(); err != nil {
+		return err
+	}
+	w.numRuns++
+	return nil
+}
+
+func (w *SortingWriter) releaseBuffer() {
+	if w.buffer != nil {
+		w.config.SortingBuffers.PutPageBuffer(w.buffer)
+		w.buffer = nil
+	}
+	w.offset.Reset(nil)
+	w.numRuns = 0
+}
+
+// readerAt returns an io.ReaderAt exposing the content of the temporary
+// buffer where sorted runs were written.
+func (w *SortingWriter) readerAt() (io.ReaderAt, error) {
+	switch b := w.buffer.(type) {
+	case io.ReaderAt:
+		return b, nil
+	case *bytes.Buffer:
+		return bytes.NewReader(b.Bytes()), nil
+	default:
+		return nil, fmt.Errorf("sorting buffers of type %T do not implement io.ReaderAt", b)
+	}
+}
+
+// sortingBuffer holds copies of rows written to a SortingWriter, and implements
+// sort.Interface to order them using the sort functions of the sorting columns.
+type sortingBuffer struct {
+	sortFuncs []columnSortFunc
+	values    []Value
+	rows      [][2]int
+}
+
+func (buf *sortingBuffer) append(row Row) {
+	i := len(buf.values)
+	for _, v := range row {
+		buf.values = append(buf.values, v.Clone())
+	}
+	buf.rows = append(buf.rows, [2]int{i, len(buf.values)})
+}
+
+func (buf *sortingBuffer) reset() {
+	clearValues(buf.values)
+	buf.values = buf.values[:0]
+	buf.rows = buf.rows[:0]
+}
+
+func (buf *sortingBuffer) row(i int) Row {
+	r := buf.rows[i]
+	return buf.values[r[0]:r[1]:r[1]]
+}
+
+func (buf *sortingBuffer) Len() int { return len(buf.rows) }
+
+func (buf *sortingBuffer) Less(i, j int) bool {
+	row1 := buf.row(i)
+	row2 := buf.row(j)
+
+	for _, sorting := range buf.sortFuncs {
+		values1 := columnValuesOf(row1, sorting.columnIndex)
+		values2 := columnValuesOf(row2, sorting.columnIndex)
+		comp := sorting.compare(values1, values2)
+		switch {
+		case comp < 0:
+			return true
+		case comp > 0:
+			return false
+		}
+	}
+
+	return false
+}
+
+func (buf *sortingBuffer) Swap(i, j int) {
+	buf.rows[i], buf.rows[j] = buf.rows[j], buf.rows[i]
+}
+
+// columnValuesOf returns the values of row which belong to the column at the
+// given index. Rows hold values ordered by column index, so the values can be
+// located with a binary search.
+func columnValuesOf(row Row, columnIndex int16) []Value {
+	i := sort.Search(len(row), func(i int) bool { return row[i].Column() >= int(columnIndex) })
+	j := i
+	for j < len(row) && row[j].Column() == int(columnIndex) {
+		j++
+	}
+	return row[i:j]
+}
+
+var (
+	_ RowWriterWithSchema = (*SortingWriter)(nil)
+	_ RowReaderFrom       = (*SortingWriter)(nil)
+)
@@ -0,0 +1,219 @@
+package parquet_test
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestSortingWriter(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testSortingWriter(t, parquet.NewPageBufferPool(), "")
+	})
+	t.Run("file", func(t *testing.T) {
+		tmpdir := t.TempDir()
+		testSortingWriter(t, parquet.NewFileBufferPool(tmpdir, "sorting-*"), tmpdir)
+	})
+}
+
+func testSortingWriter(t *testing.T, buffers parquet.PageBufferPool, tmpdir string) {
+	type Row struct {
+		Value int64  `parquet:"value"`
+		Name  string `parquet:"name"`
+	}
+
+	const numRows = 1000
+
+	prng := rand.New(rand.NewSource(0))
+	rows := make([]Row, numRows)
+	for i := range rows {
+		rows[i] = Row{
+			Value: prng.Int63n(100),
+			Name:  string(rune('A' + prng.Intn(26))),
+		}
+	}
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewSortingWriter(output, 99,
+		parquet.SortingColumns(
+			parquet.Descending("value"),
+			parquet.Ascending("name"),
+		),
+		parquet.SortingBuffers(buffers),
+		parquet.MaxRowsPerRowGroup(300),
+	)
+
+	// Flushing in the middle produces two independently sorted sequences of
+	// row groups.
+	for i := range rows {
+		if i == numRows/2 {
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if tmpdir != "" {
+		entries, err := os.ReadDir(tmpdir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("temporary files were not removed: %d", len(entries))
+		}
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, rowGroup := range f.Metadata().RowGroups {
+		sortingColumns := rowGroup.SortingColumns
+		if len(sortingColumns) != 2 ||
+			sortingColumns[0].ColumnIdx != 0 || !sortingColumns[0].Descending ||
+			sortingColumns[1].ColumnIdx != 1 || sortingColumns[1].Descending {
+			t.Errorf("row group %d: wrong sorting columns: %+v", i, sortingColumns)
+		}
+	}
+
+	want := make([]Row, numRows)
+	copy(want, rows)
+	for _, part := range [][]Row{want[:numRows/2], want[numRows/2:]} {
+		sort.SliceStable(part, func(i, j int) bool {
+			if part[i].Value != part[j].Value {
+				return part[i].Value > part[j].Value
+			}
+			return part[i].Name < part[j].Name
+		})
+	}
+
+	reader := parquet.NewReader(f)
+	for i := range want {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatalf("reading row %d: %v", i, err)
+		}
+		if row != want[i] {
+			t.Fatalf("row %d mismatch: want %+v but got %+v", i, want[i], row)
+		}
+	}
+	if err := reader.Read(new(Row)); err != io.EOF {
+		t.Errorf("expected io.EOF after the last row but got %v", err)
+	}
+}
+
+func TestSortingWriterOutputOptions(t *testing.T) {
+	type Row struct {
+		Time  time.Time `parquet:"time"`
+		Value int64     `parquet:"value"`
+	}
+
+	const numRows = 100
+
+	prng := rand.New(rand.NewSource(0))
+	rows := make([]Row, numRows)
+	for i := range rows {
+		rows[i] = Row{
+			Time:  time.Unix(prng.Int63n(1e9), 0).UTC(),
+			Value: int64(i),
+		}
+	}
+
+	// The options applying to the output only must not prevent the sorted
+	// runs from being read back.
+	keyring := &parquet.Keyring{Footer: testFooterKey}
+	output := new(bytes.Buffer)
+	writer := parquet.NewSortingWriter(output, 10,
+		parquet.SortingColumns(parquet.Ascending("time")),
+		parquet.Int96Timestamps(true),
+		&parquet.EncryptionConfig{Keys: keyring},
+		parquet.BloomFilters(parquet.SplitBlockFilter("value")),
+		parquet.MaxRowsPerRowGroup(numRows/2),
+		parquet.KeyValueMetadata("key", "value"),
+		parquet.WriteConcurrency(2),
+	)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()),
+		&parquet.DecryptionConfig{Keys: keyring},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.RowGroups()); n != 2 {
+		t.Errorf("wrong number of row groups: want=2 got=%d", n)
+	}
+	if leaf, _ := f.Schema().Lookup("time"); leaf.Node.Type().Kind() != parquet.Int96 {
+		t.Errorf("wrong physical type of the time column: %s", leaf.Node.Type().Kind())
+	}
+	if f.RowGroups()[0].ColumnChunks()[1].BloomFilter() == nil {
+		t.Error("missing bloom filter of the value column")
+	}
+	if value, ok := f.Lookup("key"); !ok || value != "value" {
+		t.Errorf("wrong key/value metadata: want=value got=%q", value)
+	}
+
+	want := make([]Row, numRows)
+	copy(want, rows)
+	sort.SliceStable(want, func(i, j int) bool { return want[i].Time.Before(want[j].Time) })
+
+	reader := parquet.NewReader(f, parquet.SchemaOf(Row{}))
+	for i := range want {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatalf("reading row %d: %v", i, err)
+		}
+		if !row.Time.Equal(want[i].Time) || row.Value != want[i].Value {
+			t.Fatalf("row %d mismatch: want %+v but got %+v", i, want[i], row)
+		}
+	}
+}
+
+// readWriterBufferPool is a PageBufferPool producing buffers which do not
+// support random access.
+type readWriterBufferPool struct{}
+
+func (readWriterBufferPool) GetPageBuffer() io.ReadWriter {
+	return struct{ io.ReadWriter }{new(bytes.Buffer)}
+}
+
+func (readWriterBufferPool) PutPageBuffer(io.ReadWriter) {}
+
+func TestSortingWriterBuffersWithoutReaderAt(t *testing.T) {
+	type Row struct {
+		Value int64 `parquet:"value"`
+	}
+
+	writer := parquet.NewSortingWriter(new(bytes.Buffer), 10,
+		parquet.SortingColumns(parquet.Ascending("value")),
+		parquet.SortingBuffers(readWriterBufferPool{}),
+	)
+	for i := 0; i < 100; i++ {
+		if err := writer.Write(&Row{Value: int64(100 - i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err == nil {
+		t.Error("closing a sorting writer with buffers which do not implement io.ReaderAt did not return an error")
+	}
+}
@@ -2,6 +2,7 @@ package parquet
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"reflect"
@@ -456,6 +457,160 @@ func (t *uuidType) GoType() reflect.Type {
 	return reflect.TypeOf(uuid.UUID{})
 }
 
+// Float16 constructs a leaf node of FLOAT16 logical type, representing IEEE 754
+// half-precision floating point numbers stored in little-endian byte order.
+//
+// The Float32ToFloat16 and Float16ToFloat32 functions may be used to convert
+// values to and from the representation of the column.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#float16
+func Float16() Node { return Leaf(&float16Type{}) }
+
+type float16Type format.Float16Type
+
+func (t *float16Type) String() string { return (*format.Float16Type)(t).String() }
+
+func (t *float16Type) Kind() Kind { return FixedLenByteArray }
+
+func (t *float16Type) Length() int { return 2 }
+
+func (t *float16Type) Compare(a, b Value) int {
+	return compareFloat16(a.ByteArray(), b.ByteArray())
+}
+
+func (t *float16Type) ColumnOrder() *format.ColumnOrder {
+	return &typeDefinedColumnOrder
+}
+
+func (t *float16Type) PhysicalType() *format.Type {
+	return &physicalTypes[FixedLenByteArray]
+}
+
+func (t *float16Type) LogicalType() *format.LogicalType {
+	return &format.LogicalType{Float16: (*format.Float16Type)(t)}
+}
+
+func (t *float16Type) ConvertedType() *deprecated.ConvertedType { return nil }
+
+func (t *float16Type) NewColumnIndexer(sizeLimit int) ColumnIndexer {
+	return newFloat16ColumnIndexer()
+}
+
+func (t *float16Type) NewDictionary(columnIndex, numValues int, data []byte) Dictionary {
+	return float16Dictionary{newFixedLenByteArrayDictionary(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)}
+}
+
+func (t *float16Type) NewColumnBuffer(columnIndex, bufferSize int) ColumnBuffer {
+	return float16ColumnBuffer{newFixedLenByteArrayColumnBuffer(t, makeColumnIndex(columnIndex), bufferSize)}
+}
+
+func (t *float16Type) NewPage(columnIndex, numValues int, data []byte) Page {
+	return float16Page{newFixedLenByteArrayPage(makeColumnIndex(columnIndex), makeNumValues(numValues), data, 2)}
+}
+
+func (t *float16Type) GoType() reflect.Type {
+	return reflect.TypeOf([2]byte{})
+}
+
+// Float32ToFloat16 converts f to the representation of the nearest IEEE 754
+// half-precision floating point number, as stored in columns of FLOAT16
+// logical type. Values too large to be represented are converted to infinity.
+func Float32ToFloat16(f float32) [2]byte {
+	h := float32ToFloat16Bits(f)
+	return [2]byte{byte(h), byte(h >> 8)}
+}
+
+// Float16ToFloat32 converts the half-precision floating point number stored in
+// b, as read from a column of FLOAT16 logical type, to a float32.
+func Float16ToFloat32(b [2]byte) float32 {
+	return float16BitsToFloat32(uint16(b[0]) | uint16(b[1])<<8)
+}
+
+func float32ToFloat16Bits(f float32) uint16 {
+	u := math.Float32bits(f)
+	sign := uint16(u>>16) & 0x8000
+	exp := int((u>>23)&0xFF) - 127 + 15
+	mant := u & 0x7FFFFF
+
+	switch {
+	case (u>>23)&0xFF == 0xFF: // infinity or NaN
+		h := sign | 0x7C00
+		if mant != 0 {
+			h |= 0x200 | uint16(mant>>13)
+		}
+		return h
+	case exp >= 0x1F: // overflow
+		return sign | 0x7C00
+	case exp <= 0: // subnormal or zero
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint(14 - exp)
+		h := sign | uint16(mant>>shift)
+		// Round half to even.
+		rem, half := mant&(1<<shift-1), uint32(1)<<(shift-1)
+		if rem > half || (rem == half && h&1 != 0) {
+			h++
+		}
+		return h
+	default:
+		h := sign | uint16(exp)<<10 | uint16(mant>>13)
+		// Round half to even, the carry may propagate to the exponent which
+		// correctly rounds the largest values up to infinity.
+		if rem := mant & 0x1FFF; rem > 0x1000 || (rem == 0x1000 && h&1 != 0) {
+			h++
+		}
+		return h
+	}
+}
+
+func float16BitsToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1F
+	mant := uint32(h & 0x3FF)
+
+	switch exp {
+	case 0x1F: // infinity or NaN
+		return math.Float32frombits(sign | 0x7F800000 | mant<<13)
+	case 0: // subnormal or zero
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		exp = 127 - 15 + 1
+		for mant&0x400 == 0 {
+			mant <<= 1
+			exp--
+		}
+		return math.Float32frombits(sign | exp<<23 | (mant&0x3FF)<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
+
+func float16ToFloat32(b []byte) float32 {
+	return float16BitsToFloat32(uint16(b[0]) | uint16(b[1])<<8)
+}
+
+func isFloat16NaN(b []byte) bool {
+	h := uint16(b[0]) | uint16(b[1])<<8
+	return (h&0x7C00) == 0x7C00 && (h&0x3FF) != 0
+}
+
+// compareFloat16 orders NaN values after all other values so FLOAT16 values
+// have a total order, which is required to sort them and match them in filters.
+func compareFloat16(a, b []byte) int {
+	switch aNaN, bNaN := isFloat16NaN(a), isFloat16NaN(b); {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return +1
+	case bNaN:
+		return -1
+	}
+	return compareFloat32(float16ToFloat32(a), float16ToFloat32(b))
+}
+
 // Enum constructs a leaf node with a logical type representing enumerations.
 //
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#enum
@@ -672,11 +827,25 @@ func (u *nanosecond) TimeUnit() format.TimeUnit {
 	return format.TimeUnit{Nanos: (*format.NanoSeconds)(u)}
 }
 
+var (
+	goTimeType     = reflect.TypeOf(time.Time{})
+	goDurationType = reflect.TypeOf(time.Duration(0))
+)
+
 // Time constructs a leaf node of TIME logical type.
 //
+// Values of Go type time.Duration are converted to the time unit of the column
+// when written to, and read from, columns of this type.
+//
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#time
-func Time(unit TimeUnit) Node {
-	return Leaf(&timeType{IsAdjustedToUTC: true, Unit: unit.TimeUnit()})
+func Time(unit TimeUnit) Node { return TimeAdjusted(unit, true) }
+
+// TimeAdjusted constructs a leaf node of TIME logical type, with the
+// isAdjustedToUTC property of the logical type set to the given value.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#time
+func TimeAdjusted(unit TimeUnit, isAdjustedToUTC bool) Node {
+	return Leaf(&timeType{IsAdjustedToUTC: isAdjustedToUTC, Unit: unit.TimeUnit()})
 }
 
 type timeType format.TimeType
@@ -744,11 +913,49 @@ func (t *timeType) ConvertedType() *deprecated.ConvertedType {
 	}
 }
 
+func (t *timeType) makeValue(v reflect.Value) Value {
+	if v.Type() != goDurationType {
+		return makeValue(t.Kind(), v)
+	}
+	d := time.Duration(v.Int()) / t.Unit.Duration()
+	if t.useInt32() {
+		return makeValueInt32(int32(d))
+	}
+	return makeValueInt64(int64(d))
+}
+
+func (t *timeType) assignValue(dst reflect.Value, src Value) error {
+	if dst.Type() != goDurationType || src.IsNull() {
+		return assignValue(dst, src)
+	}
+	var d time.Duration
+	if t.useInt32() {
+		d = time.Duration(src.Int32())
+	} else {
+		d = time.Duration(src.Int64())
+	}
+	dst.SetInt(int64(d * t.Unit.Duration()))
+	return nil
+}
+
 // Timestamp constructs of leaf node of TIMESTAMP logical type.
 //
+// Values of Go type time.Time are converted to the time unit of the column
+// when written to, and read from, columns of this type.
+//
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#timestamp
-func Timestamp(unit TimeUnit) Node {
-	return Leaf(&timestampType{IsAdjustedToUTC: true, Unit: unit.TimeUnit()})
+func Timestamp(unit TimeUnit) Node { return TimestampAdjusted(unit, true) }
+
+// TimestampAdjusted constructs a leaf node of TIMESTAMP logical type, with the
+// isAdjustedToUTC property of the logical type set to the given value.
+//
+// When isAdjustedToUTC is false, the column holds local date-times: values of
+// Go type time.Time are stored using the wall clock of their location, and
+// are read back as times in the time.Local location with the same wall clock.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#timestamp
+func TimestampAdjusted(unit TimeUnit, isAdjustedToUTC bool) Node {
+	return Leaf(&timestampType{IsAdjustedToUTC: isAdjustedToUTC, Unit: unit.TimeUnit()})
 }
 
 type timestampType format.TimestampType
@@ -780,6 +987,216 @@ func (t *timestampType) ConvertedType() *deprecated.ConvertedType {
 	}
 }
 
+func (t *timestampType) makeValue(v reflect.Value) Value {
+	if v.Type() != goTimeType {
+		return makeValue(Int64, v)
+	}
+	return makeValueInt64(t.timestamp(v.Interface().(time.Time)))
+}
+
+func (t *timestampType) assignValue(dst reflect.Value, src Value) error {
+	if dst.Type() != goTimeType || src.IsNull() {
+		return assignValue(dst, src)
+	}
+	dst.Set(reflect.ValueOf(t.time(src.Int64())))
+	return nil
+}
+
+func (t *timestampType) timestamp(tm time.Time) int64 {
+	if !t.IsAdjustedToUTC {
+		year, month, day := tm.Date()
+		hour, min, sec := tm.Clock()
+		tm = time.Date(year, month, day, hour, min, sec, tm.Nanosecond(), time.UTC)
+	}
+	switch {
+	case t.Unit.Millis != nil:
+		return tm.UnixMilli()
+	case t.Unit.Micros != nil:
+		return tm.UnixMicro()
+	default:
+		return tm.UnixNano()
+	}
+}
+
+// Legacy applications like Hive, Impala, or older versions of Spark store
+// timestamps as INT96 values, made of the number of nanoseconds since midnight
+// in the first 8 bytes, followed by the Julian day number in the last 4 bytes.
+const julianDayOfUnixEpoch = 2440588
+
+func int96ToTime(i96 deprecated.Int96) time.Time {
+	nanos := int64(i96[1])<<32 | int64(i96[0])
+	days := int64(i96[2]) - julianDayOfUnixEpoch
+	return time.Unix(days*secondsPerDay, nanos).UTC()
+}
+
+func timeToInt96(t time.Time) deprecated.Int96 {
+	secs := t.Unix()
+	days := secs / secondsPerDay
+	if secs%secondsPerDay < 0 {
+		days--
+	}
+	nanos := (secs-days*secondsPerDay)*int64(time.Second) + int64(t.Nanosecond())
+	return deprecated.Int96{uint32(nanos), uint32(nanos >> 32), uint32(days + julianDayOfUnixEpoch)}
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// timestampTypeOf returns the TIMESTAMP logical type of t, or nil if t is not
+// a timestamp type.
+func timestampTypeOf(t Type) *timestampType {
+	if lt := t.LogicalType(); lt != nil && lt.Timestamp != nil && t.Kind() == Int64 {
+		return (*timestampType)(lt.Timestamp)
+	}
+	return nil
+}
+
+// utc returns a copy of t which interprets timestamps as UTC instants. INT96
+// values carry no information about time zones, so timestamps are converted
+// from and to INT96 values in UTC regardless of their isAdjustedToUTC property,
+// preserving the wall clock time of local timestamps.
+func (t *timestampType) utc() *timestampType {
+	u := *t
+	u.IsAdjustedToUTC = true
+	return &u
+}
+
+func (t *timestampType) time(timestamp int64) time.Time {
+	var tm time.Time
+	switch {
+	case t.Unit.Millis != nil:
+		tm = time.UnixMilli(timestamp).UTC()
+	case t.Unit.Micros != nil:
+		tm = time.UnixMicro(timestamp).UTC()
+	default:
+		tm = time.Unix(0, timestamp).UTC()
+	}
+	if !t.IsAdjustedToUTC {
+		year, month, day := tm.Date()
+		hour, min, sec := tm.Clock()
+		tm = time.Date(year, month, day, hour, min, sec, tm.Nanosecond(), time.Local)
+	}
+	return tm
+}
+
+// Interval constructs a leaf node of the INTERVAL converted type, representing
+// durations made of a number of months, days, and milliseconds stored as three
+// little-endian unsigned 32 bits integers in a 12 bytes fixed length byte array.
+//
+// The sort order of INTERVAL values is undefined, no min and max values are
+// recorded in the statistics of INTERVAL columns. Values are compared by their
+// months, days, and milliseconds, which is only meaningful to sort rows
+// deterministically.
+//
+// Values of Go type IntervalValue or [12]byte may be written to, and read from,
+// columns of this type.
+//
+// https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#interval
+func Interval() Node { return Leaf(&intervalType{}) }
+
+// IntervalValue is the Go representation of values of the INTERVAL converted
+// type.
+type IntervalValue struct {
+	Months       uint32
+	Days         uint32
+	Milliseconds uint32
+}
+
+// AddTo returns the time t shifted by the interval.
+func (v IntervalValue) AddTo(t time.Time) time.Time {
+	return t.AddDate(0, int(v.Months), int(v.Days)).Add(time.Duration(v.Milliseconds) * time.Millisecond)
+}
+
+// String returns a human-readable representation of v.
+func (v IntervalValue) String() string {
+	return fmt.Sprintf("%d months %d days %s", v.Months, v.Days, time.Duration(v.Milliseconds)*time.Millisecond)
+}
+
+func makeIntervalValue(b []byte) IntervalValue {
+	return IntervalValue{
+		Months:       binary.LittleEndian.Uint32(b[0:]),
+		Days:         binary.LittleEndian.Uint32(b[4:]),
+		Milliseconds: binary.LittleEndian.Uint32(b[8:]),
+	}
+}
+
+func (v IntervalValue) bytes() (b [12]byte) {
+	binary.LittleEndian.PutUint32(b[0:], v.Months)
+	binary.LittleEndian.PutUint32(b[4:], v.Days)
+	binary.LittleEndian.PutUint32(b[8:], v.Milliseconds)
+	return b
+}
+
+var goIntervalType = reflect.TypeOf(IntervalValue{})
+
+type intervalType struct{}
+
+func (t *intervalType) String() string { return "INTERVAL" }
+
+func (t *intervalType) Kind() Kind { return FixedLenByteArray }
+
+func (t *intervalType) Length() int { return 12 }
+
+func (t *intervalType) Compare(a, b Value) int {
+	v1, v2 := makeIntervalValue(a.ByteArray()), makeIntervalValue(b.ByteArray())
+	switch {
+	case v1.Months != v2.Months:
+		return compareUint32(v1.Months, v2.Months)
+	case v1.Days != v2.Days:
+		return compareUint32(v1.Days, v2.Days)
+	default:
+		return compareUint32(v1.Milliseconds, v2.Milliseconds)
+	}
+}
+
+// The sort order of INTERVAL values is undefined.
+func (t *intervalType) ColumnOrder() *format.ColumnOrder { return nil }
+
+func (t *intervalType) PhysicalType() *format.Type { return &physicalTypes[FixedLenByteArray] }
+
+func (t *intervalType) LogicalType() *format.LogicalType { return nil }
+
+func (t *intervalType) ConvertedType() *deprecated.ConvertedType {
+	return &convertedTypes[deprecated.Interval]
+}
+
+func (t *intervalType) NewColumnIndexer(sizeLimit int) ColumnIndexer {
+	return new(undefinedOrderColumnIndexer)
+}
+
+func (t *intervalType) NewDictionary(columnIndex, numValues int, data []byte) Dictionary {
+	return newFixedLenByteArrayDictionary(t, makeColumnIndex(columnIndex), makeNumValues(numValues), data)
+}
+
+func (t *intervalType) NewColumnBuffer(columnIndex, bufferSize int) ColumnBuffer {
+	return newFixedLenByteArrayColumnBuffer(t, makeColumnIndex(columnIndex), bufferSize)
+}
+
+func (t *intervalType) NewPage(columnIndex, numValues int, data []byte) Page {
+	return newFixedLenByteArrayPage(makeColumnIndex(columnIndex), makeNumValues(numValues), data, 12)
+}
+
+func (t *intervalType) GoType() reflect.Type { return goIntervalType }
+
+func (t *intervalType) makeValue(v reflect.Value) Value {
+	if v.Type() != goIntervalType {
+		return makeValue(FixedLenByteArray, v)
+	}
+	b := v.Interface().(IntervalValue).bytes()
+	return makeValueBytes(FixedLenByteArray, b[:])
+}
+
+func (t *intervalType) assignValue(dst reflect.Value, src Value) error {
+	if dst.Type() != goIntervalType || src.IsNull() {
+		return assignValue(dst, src)
+	}
+	dst.Set(reflect.ValueOf(makeIntervalValue(src.ByteArray())))
+	return nil
+}
+
+func (t *intervalType) printValue(v Value) string {
+	return makeIntervalValue(v.ByteArray()).String()
+}
+
 // List constructs a node of LIST logical type.
 //
 // https://github.com/apache/parquet-format/blob/master/LogicalTypes.md#lists
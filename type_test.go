@@ -0,0 +1,394 @@
+package parquet_test
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestFloat16Conversion(t *testing.T) {
+	tests := []struct {
+		value float32
+		bits  uint16
+	}{
+		{value: 0, bits: 0x0000},
+		{value: float32(math.Copysign(0, -1)), bits: 0x8000},
+		{value: 1, bits: 0x3C00},
+		{value: -2, bits: 0xC000},
+		{value: 0.5, bits: 0x3800},
+		{value: 65504, bits: 0x7BFF},                 // largest finite value
+		{value: 0x1p-14, bits: 0x0400},               // smallest normal value
+		{value: 0x1p-24, bits: 0x0001},               // smallest subnormal value
+		{value: float32(math.Inf(+1)), bits: 0x7C00}, // positive infinity
+		{value: float32(math.Inf(-1)), bits: 0xFC00}, // negative infinity
+		{value: 1 + 0x1p-11, bits: 0x3C00},           // rounded to even
+		{value: 1 + 0x1p-11 + 0x1p-23, bits: 0x3C01}, // rounded up
+		{value: 65520, bits: 0x7C00},                 // overflow
+		{value: 0x1p-26, bits: 0x0000},               // underflow
+		{value: -0x1p-24 - 0x1p-26, bits: 0x8001},    // rounded subnormal
+		{value: float32(math.NaN()), bits: 0x7E00},   // quiet NaN
+	}
+
+	for _, test := range tests {
+		b := parquet.Float32ToFloat16(test.value)
+		if bits := uint16(b[0]) | uint16(b[1])<<8; bits != test.bits {
+			t.Errorf("Float32ToFloat16(%g): want %#04x but got %#04x", test.value, test.bits, bits)
+		}
+		v := parquet.Float16ToFloat32(b)
+		switch {
+		case math.IsNaN(float64(test.value)):
+			if !math.IsNaN(float64(v)) {
+				t.Errorf("Float16ToFloat32(%#04x): want NaN but got %g", test.bits, v)
+			}
+		case parquet.Float32ToFloat16(v) != b:
+			t.Errorf("Float16ToFloat32(%#04x): value does not round trip: %g", test.bits, v)
+		}
+	}
+
+	// All finite half-precision values are representable as float32.
+	for i := 0; i < 0x10000; i++ {
+		b := [2]byte{byte(i), byte(i >> 8)}
+		v := parquet.Float16ToFloat32(b)
+		if math.IsNaN(float64(v)) {
+			continue
+		}
+		if parquet.Float32ToFloat16(v) != b {
+			t.Fatalf("%#04x: value does not round trip: %g", i, v)
+		}
+	}
+}
+
+func TestFloat16Type(t *testing.T) {
+	type Row struct {
+		Value [2]byte `parquet:"value,float16"`
+		Index [2]byte `parquet:"index,float16,dict"`
+	}
+
+	values := []float32{-100, -2.5, -1, -0.25, 0, 0.5, 1, 3, 42, 1000}
+	rows := make([]Row, 100)
+	for i := range rows {
+		v := parquet.Float32ToFloat16(values[i%len(values)])
+		rows[i] = Row{Value: v, Index: v}
+	}
+	prng := rand.New(rand.NewSource(0))
+	prng.Shuffle(len(rows), func(i, j int) { rows[i], rows[j] = rows[j], rows[i] })
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output)
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	minValue := parquet.Float32ToFloat16(-100)
+	maxValue := parquet.Float32ToFloat16(1000)
+
+	for i, columnChunk := range f.RowGroups()[0].ColumnChunks() {
+		columnType := columnChunk.Type()
+		if s := columnType.String(); s != "FLOAT16" {
+			t.Errorf("column %d: wrong type: %s", i, s)
+		}
+		columnIndex := columnChunk.ColumnIndex()
+		if min := columnIndex.MinValue(0); !bytes.Equal(min.ByteArray(), minValue[:]) {
+			t.Errorf("column %d: wrong min value in column index: %x", i, min.ByteArray())
+		}
+		if max := columnIndex.MaxValue(0); !bytes.Equal(max.ByteArray(), maxValue[:]) {
+			t.Errorf("column %d: wrong max value in column index: %x", i, max.ByteArray())
+		}
+	}
+
+	buffer := parquet.NewBuffer(parquet.SortingColumns(parquet.Ascending("value")))
+	for i := range rows {
+		if err := buffer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sort.Sort(buffer)
+
+	reader := parquet.NewRowGroupReader(buffer)
+	prev := float32(math.Inf(-1))
+	for i := range rows {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		v := parquet.Float16ToFloat32(row.Value)
+		if v < prev {
+			t.Fatalf("row %d is not sorted: %g < %g", i, v, prev)
+		}
+		prev = v
+	}
+
+	nan := float32(math.NaN())
+	negativeZero := float32(math.Copysign(0, -1))
+	for _, test := range []struct {
+		scenario string
+		values   []float32
+		min, max [2]byte
+		noBounds bool
+	}{
+		{scenario: "NaN values are ignored", values: []float32{nan, 2, nan, -3, nan}, min: parquet.Float32ToFloat16(-3), max: parquet.Float32ToFloat16(2)},
+		{scenario: "zero minimum is negative", values: []float32{0, 1, 2}, min: parquet.Float32ToFloat16(negativeZero), max: parquet.Float32ToFloat16(2)},
+		{scenario: "zero maximum is positive", values: []float32{-2, negativeZero, -1}, min: parquet.Float32ToFloat16(-2), max: parquet.Float32ToFloat16(0)},
+		{scenario: "only zeros", values: []float32{0, negativeZero, nan}, min: parquet.Float32ToFloat16(negativeZero), max: parquet.Float32ToFloat16(0)},
+		{scenario: "only NaN values", values: []float32{nan, nan}, noBounds: true},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			rows := make([]Row, len(test.values))
+			for i, v := range test.values {
+				rows[i].Value = parquet.Float32ToFloat16(v)
+				rows[i].Index = rows[i].Value
+			}
+
+			output := new(bytes.Buffer)
+			writer := parquet.NewWriter(output)
+			for i := range rows {
+				if err := writer.Write(&rows[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+			f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, columnChunk := range f.RowGroups()[0].ColumnChunks() {
+				page, err := columnChunk.Pages().ReadPage()
+				if err != nil {
+					t.Fatal(err)
+				}
+				min, max, ok := page.Bounds()
+				if test.noBounds {
+					if ok {
+						t.Errorf("column %d: page of NaN values has bounds: min=%x max=%x", i, min.ByteArray(), max.ByteArray())
+					}
+					continue
+				}
+				if !ok {
+					t.Errorf("column %d: page has no bounds", i)
+				}
+				if !bytes.Equal(min.ByteArray(), test.min[:]) {
+					t.Errorf("column %d: wrong min value of page: want=%x got=%x", i, test.min, min.ByteArray())
+				}
+				if !bytes.Equal(max.ByteArray(), test.max[:]) {
+					t.Errorf("column %d: wrong max value of page: want=%x got=%x", i, test.max, max.ByteArray())
+				}
+			}
+
+			for i, columnIndex := range f.ColumnIndexes() {
+				min, max := columnIndex.MinValues[0], columnIndex.MaxValues[0]
+				if test.noBounds {
+					if !math.IsNaN(float64(parquet.Float16ToFloat32([2]byte{min[0], min[1]}))) {
+						t.Errorf("column %d: page of NaN values has min value %x in the column index", i, min)
+					}
+					continue
+				}
+				if !bytes.Equal(min, test.min[:]) {
+					t.Errorf("column %d: wrong min value in column index: want=%x got=%x", i, test.min, min)
+				}
+				if !bytes.Equal(max, test.max[:]) {
+					t.Errorf("column %d: wrong max value in column index: want=%x got=%x", i, test.max, max)
+				}
+			}
+
+			buffer := parquet.NewBuffer()
+			for i := range rows {
+				if err := buffer.Write(&rows[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, rowGroup := range []parquet.RowGroup{buffer, f.RowGroups()[0]} {
+				found, err := readAllRows(parquet.NewRowGroupReader(rowGroup, parquet.FilterRows(
+					parquet.Eq([]string{"value"}, parquet.ValueOf(parquet.Float32ToFloat16(1))),
+				)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := 0
+				for _, v := range test.values {
+					if v == 1 {
+						want++
+					}
+				}
+				if len(found) != want {
+					t.Errorf("wrong number of rows matching the filter: want=%d got=%d", want, len(found))
+				}
+			}
+		})
+	}
+
+	typ := parquet.Float16().Type()
+	a := parquet.ValueOf(parquet.Float32ToFloat16(-1))
+	b := parquet.ValueOf(parquet.Float32ToFloat16(0.5))
+	if typ.Compare(a, b) >= 0 || typ.Compare(b, a) <= 0 || typ.Compare(a, a) != 0 {
+		t.Error("float16 values are not compared by their numeric value")
+	}
+}
+
+func TestTimeTypes(t *testing.T) {
+	type Row struct {
+		Nanos   time.Time     `parquet:"nanos"`
+		Micros  time.Time     `parquet:"micros,timestamp(microsecond)"`
+		Millis  time.Time     `parquet:"millis,timestamp(millisecond),optional"`
+		Local   time.Time     `parquet:"local,timestamp(microsecond,local)"`
+		Elapsed time.Duration `parquet:"elapsed"`
+		Short   time.Duration `parquet:"short,time(millisecond)"`
+		Long    time.Duration `parquet:"long,time(microsecond)"`
+	}
+
+	location := time.FixedZone("UTC+2", 2*3600)
+	now := time.Date(2022, 6, 30, 12, 34, 56, 123456789, location)
+
+	row := Row{
+		Nanos:   now,
+		Micros:  now,
+		Millis:  now,
+		Local:   now,
+		Elapsed: 1234567891 * time.Nanosecond,
+		Short:   1234567891 * time.Nanosecond,
+		Long:    1234567891 * time.Nanosecond,
+	}
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output)
+	for _, r := range []Row{row, {}} {
+		if err := writer.Write(&r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := make([]Row, 2)
+	reader := parquet.NewReader(f)
+	for i := range rows {
+		if err := reader.Read(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := Row{
+		Nanos:   now.UTC(),
+		Micros:  now.Truncate(time.Microsecond).UTC(),
+		Millis:  now.Truncate(time.Millisecond).UTC(),
+		Local:   time.Date(2022, 6, 30, 12, 34, 56, 123456000, time.Local),
+		Elapsed: 1234567891 * time.Nanosecond,
+		Short:   1234 * time.Millisecond,
+		Long:    1234567 * time.Microsecond,
+	}
+	if !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("wrong row values:\nwant = %+v\ngot  = %+v", want, rows[0])
+	}
+	if !rows[1].Millis.IsZero() {
+		t.Errorf("null timestamp was not read as a zero time: %v", rows[1].Millis)
+	}
+
+	g, _ := f.Schema().Lookup("micros")
+	if g.Node.Type().LogicalType().Timestamp.Unit.Micros == nil {
+		t.Errorf("wrong unit for timestamp column: %s", g.Node.Type())
+	}
+}
+
+func TestIntervalType(t *testing.T) {
+	type Row struct {
+		Value parquet.IntervalValue `parquet:"value"`
+		Bytes [12]byte              `parquet:"bytes,interval"`
+	}
+
+	rows := []Row{
+		{Value: parquet.IntervalValue{Months: 1, Days: 2, Milliseconds: 3004}},
+		{Value: parquet.IntervalValue{Months: 0, Days: 30, Milliseconds: 0}},
+		{Value: parquet.IntervalValue{Months: 12, Days: 0, Milliseconds: 1}},
+	}
+	for i := range rows {
+		rows[i].Bytes[0] = byte(i)
+	}
+
+	schema := parquet.SchemaOf(new(Row))
+	if s, want := schema.String(), `message Row {
+	required fixed_len_byte_array(12) value (INTERVAL);
+	required fixed_len_byte_array(12) bytes (INTERVAL);
+}`; s != want {
+		t.Errorf("wrong schema:\nwant = %s\ngot  = %s", want, s)
+	}
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output, parquet.DataPageStatistics(true))
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, columnOrder := range f.Metadata().ColumnOrders {
+		if columnOrder.TypeOrder != nil {
+			t.Errorf("column %d: the column order must be undefined", i)
+		}
+	}
+	for i, columnChunk := range f.RowGroups()[0].ColumnChunks() {
+		if s := columnChunk.Type().String(); s != "INTERVAL" {
+			t.Errorf("column %d: wrong type: %s", i, s)
+		}
+		columnIndex := columnChunk.ColumnIndex()
+		if min, max := columnIndex.MinValue(0), columnIndex.MaxValue(0); len(min.ByteArray()) != 0 || len(max.ByteArray()) != 0 {
+			t.Errorf("column %d: min and max values must not be recorded: %q %q", i, min.ByteArray(), max.ByteArray())
+		}
+	}
+
+	reader := parquet.NewReader(f)
+	for i := range rows {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if row != rows[i] {
+			t.Errorf("row %d mismatch: want %+v but got %+v", i, rows[i], row)
+		}
+	}
+
+	buffer := new(strings.Builder)
+	if err := parquet.PrintRowGroup(buffer, f.RowGroups()[0]); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buffer.String(), "1 months 2 days 3.004s") {
+		t.Errorf("interval values are not printed in a readable form:\n%s", buffer)
+	}
+
+	v := parquet.IntervalValue{Months: 1, Days: 2, Milliseconds: 3004}
+	tm := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	if got, want := v.AddTo(tm), time.Date(2022, 3, 5, 0, 0, 3, 4e6, time.UTC); !got.Equal(want) {
+		t.Errorf("wrong result of adding %s to %s: want %s but got %s", v, tm, want, got)
+	}
+}
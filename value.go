@@ -8,6 +8,7 @@ import (
 	"math"
 	"reflect"
 	"strconv"
+	"time"
 	"unsafe"
 
 	"github.com/google/uuid"
@@ -222,6 +223,8 @@ func makeValue(k Kind, v reflect.Value) Value {
 		switch v.Type() {
 		case reflect.TypeOf(deprecated.Int96{}):
 			return makeValueInt96(v.Interface().(deprecated.Int96))
+		case goTimeType:
+			return makeValueInt96(timeToInt96(v.Interface().(time.Time)))
 		}
 
 	case Float:
@@ -609,7 +612,11 @@ func assignValue(dst reflect.Value, src Value) error {
 		}
 
 	case Int96:
-		val = reflect.ValueOf(src.Int96())
+		if dst.Type() == goTimeType {
+			val = reflect.ValueOf(int96ToTime(src.Int96()))
+		} else {
+			val = reflect.ValueOf(src.Int96())
+		}
 
 	case Float:
 		v := src.Float()
@@ -8,13 +8,16 @@ import (
 	"hash/crc32"
 	"io"
 	"sort"
+	"sync"
 
 	"github.com/segmentio/encoding/thrift"
+	"github.com/segmentio/parquet-go/bloom"
 	"github.com/segmentio/parquet-go/compress"
 	"github.com/segmentio/parquet-go/encoding"
 	"github.com/segmentio/parquet-go/encoding/plain"
 	"github.com/segmentio/parquet-go/format"
 	"github.com/segmentio/parquet-go/internal/bits"
+	"github.com/segmentio/parquet-go/internal/hyperloglog"
 )
 
 // A Writer uses a parquet schema and sequence of Go values to produce a parquet
@@ -46,6 +49,11 @@ type Writer struct {
 	schema *Schema
 	writer *writer
 	values []Value
+	// When the schema of the file differs from the schema of rows written to
+	// the writer (e.g. to write timestamps as INT96 values), conv converts the
+	// rows to the file schema.
+	conv Conversion
+	row  Row
 }
 
 // NewWriter constructs a parquet writer writing a file to the given io.Writer.
@@ -84,7 +92,21 @@ func (w *Writer) configure(schema *Schema) {
 	if schema != nil {
 		w.config.Schema = schema
 		w.schema = schema
-		w.writer = newWriter(w.output, w.config)
+		config := w.config
+
+		if config.Int96Timestamps {
+			if fileSchema := int96TimestampsOf(schema); fileSchema != nil {
+				conv, err := Convert(fileSchema, schema)
+				if err != nil {
+					panic(err)
+				}
+				c := *config
+				c.Schema = fileSchema
+				config, w.conv = &c, conv
+			}
+		}
+
+		w.writer = newWriter(w.output, config)
 	}
 }
 
@@ -101,7 +123,8 @@ func (w *Writer) Close() error {
 //
 // Flush is called automatically on Close, it is only useful to call explicitly
 // if the application needs to limit the size of row groups or wants to produce
-// multiple row groups per file.
+// multiple row groups per file. The MaxRowsPerRowGroup and RowGroupTargetSize
+// options can also be used to have the writer flush row groups automatically.
 func (w *Writer) Flush() error {
 	if w.writer != nil {
 		return w.writer.flush()
@@ -126,6 +149,10 @@ func (w *Writer) Reset(output io.Writer) {
 // and decompose it into a set of columns and values. If no schema were passed
 // to NewWriter, it is deducted from the Go type of the row, which then have to
 // be a struct or pointer to struct.
+//
+// Rows may also be dynamic values like map[string]interface{} when a schema was
+// configured on the writer (see Schema.Deconstruct), in which case an error is
+// returned if the value does not match the schema.
 func (w *Writer) Write(row interface{}) error {
 	if w.schema == nil {
 		w.configure(SchemaOf(row))
@@ -133,7 +160,10 @@ func (w *Writer) Write(row interface{}) error {
 	defer func() {
 		clearValues(w.values)
 	}()
-	w.values = w.schema.Deconstruct(w.values[:0], row)
+	var err error
+	if w.values, err = w.schema.deconstructValue(w.values[:0], row); err != nil {
+		return err
+	}
 	return w.WriteRow(w.values)
 }
 
@@ -144,7 +174,19 @@ func (w *Writer) Write(row interface{}) error {
 //
 // The row is expected to contain values for each column of the writer's schema,
 // in the order produced by the parquet.(*Schema).Deconstruct method.
-func (w *Writer) WriteRow(row Row) error { return w.writer.WriteRow(row) }
+func (w *Writer) WriteRow(row Row) error {
+	if w.conv != nil {
+		defer func() {
+			clearValues(w.row)
+		}()
+		var err error
+		if w.row, err = w.conv.Convert(w.row[:0], row); err != nil {
+			return err
+		}
+		row = w.row
+	}
+	return w.writer.WriteRow(row)
+}
 
 // WriteRowGroup writes a row group to the parquet file.
 //
@@ -153,6 +195,9 @@ func (w *Writer) WriteRow(row Row) error { return w.writer.WriteRow(row) }
 //
 // The content of the row group is flushed to the writer; after the method
 // returns successfully, the row group will be empty and in ready to be reused.
+//
+// The rows of the group are always written to a single row group of the file,
+// the MaxRowsPerRowGroup and RowGroupTargetSize options do not apply.
 func (w *Writer) WriteRowGroup(rowGroup RowGroup) (int64, error) {
 	rowGroupSchema := rowGroup.Schema()
 	switch {
@@ -163,11 +208,19 @@ func (w *Writer) WriteRowGroup(rowGroup RowGroup) (int64, error) {
 	case !nodesAreEqual(w.schema, rowGroupSchema):
 		return 0, ErrRowGroupSchemaMismatch
 	}
+	if w.conv != nil {
+		rowGroup = ConvertRowGroup(rowGroup, w.conv)
+	}
 	if err := w.writer.flush(); err != nil {
 		return 0, err
 	}
-	w.writer.configureBloomFilters(rowGroup.ColumnChunks())
+	if err := w.writer.configureBloomFilters(rowGroup.ColumnChunks()); err != nil {
+		return 0, err
+	}
+	autoFlush := w.writer.autoFlush
+	w.writer.autoFlush = false
 	n, err := CopyRows(w.writer, rowGroup.Rows())
+	w.writer.autoFlush = autoFlush
 	if err != nil {
 		return n, err
 	}
@@ -185,15 +238,90 @@ func (w *Writer) ReadRowsFrom(rows RowReader) (written int64, err error) {
 			w.configure(r.Schema())
 		}
 	}
-	written, w.values, err = copyRows(w.writer, rows, w.values[:0])
+	if w.conv != nil {
+		rows = ConvertRowReader(rows, w.conv)
+	}
+	var dst RowWriter = w.writer
+	if w.hasRowGroupLimits() {
+		// Readers optimizing the copy with WritePage write the values one
+		// column at a time, which does not allow splitting the rows into row
+		// groups. Hiding the method has them write rows one by one instead.
+		dst = struct{ RowWriter }{w.writer}
+	}
+	written, w.values, err = copyRows(dst, rows, w.values[:0])
 	return written, err
 }
 
+// hasRowGroupLimits returns true if w was configured to flush row groups
+// automatically, in which case rows must not be copied with WriteRowGroup.
+func (w *Writer) hasRowGroupLimits() bool {
+	return w.config.MaxRowsPerRowGroup > 0 || w.config.RowGroupTargetSize > 0
+}
+
+// hasRowGroupLimits returns true if w is a parquet writer configured to split
+// the rows written to it into row groups.
+func hasRowGroupLimits(w RowWriter) bool {
+	l, ok := w.(interface{ hasRowGroupLimits() bool })
+	return ok && l.hasRowGroupLimits()
+}
+
 // Schema returns the schema of rows written by w.
 //
 // The returned value will be nil if no schema has yet been configured on w.
 func (w *Writer) Schema() *Schema { return w.schema }
 
+// int96TimestampsOf returns a copy of schema where the columns of TIMESTAMP
+// logical type are replaced by INT96 columns, or nil if the schema had no
+// timestamp columns.
+func int96TimestampsOf(schema *Schema) *Schema {
+	root, changed := int96TimestampsOfNode(schema.root)
+	if !changed {
+		return nil
+	}
+	return NewSchema(schema.Name(), root)
+}
+
+func int96TimestampsOfNode(node Node) (Node, bool) {
+	if node.Leaf() {
+		if timestampTypeOf(node.Type()) == nil {
+			return node, false
+		}
+		return &int96Node{node}, true
+	}
+
+	fields := node.Fields()
+	group := &projectedGroup{
+		Node:   node,
+		fields: make([]Field, len(fields)),
+	}
+	changed := false
+
+	for i, field := range fields {
+		n, c := int96TimestampsOfNode(field)
+		group.fields[i] = &projectedField{Node: n, name: field.Name()}
+		changed = changed || c
+	}
+
+	return group, changed
+}
+
+// int96Node exposes a leaf node of TIMESTAMP logical type as an INT96 column,
+// retaining its repetition, compression, and field id.
+type int96Node struct{ Node }
+
+func (n *int96Node) Type() Type { return Int96Type }
+
+func (n *int96Node) FieldID() int { return fieldIDOf(n.Node) }
+
+func (n *int96Node) Encoding() encoding.Encoding {
+	// Encodings like DELTA_BINARY_PACKED that were valid for the INT64 values
+	// of the timestamp column do not support INT96 values.
+	if e := n.Node.Encoding(); e != nil && canEncode(e, Int96) {
+		return e
+	}
+	return nil
+}
+
 type writer struct {
 	buffer *bufio.Writer
 	writer offsetTrackingWriter
@@ -213,6 +341,21 @@ type writer struct {
 	columnIndexes  [][]format.ColumnIndex
 	offsetIndexes  [][]format.OffsetIndex
 	sortingColumns []format.SortingColumn
+
+	// Limits of the row groups produced by the writer, the buffered rows are
+	// flushed automatically when one of them is reached and autoFlush is true.
+	maxRowsPerRowGroup int64
+	rowGroupTargetSize int64
+	numRows            int64
+	autoFlush          bool
+	// Running total of the uncompressed size of the pages written to the
+	// buffers of the columns, updated when the columns flush their pages.
+	rowGroupSize int64
+
+	encryption *fileEncryptor
+	// Semaphore bounding the number of columns encoding pages concurrently,
+	// nil when the writer encodes all the columns on the calling goroutine.
+	workers chan struct{}
 }
 
 func newWriter(output io.Writer, config *WriterConfig) *writer {
@@ -230,6 +373,21 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	}
 	sortKeyValueMetadata(w.metadata)
 	w.sortingColumns = make([]format.SortingColumn, len(config.SortingColumns))
+	w.maxRowsPerRowGroup = config.MaxRowsPerRowGroup
+	w.rowGroupTargetSize = config.RowGroupTargetSize
+	w.autoFlush = w.maxRowsPerRowGroup > 0 || w.rowGroupTargetSize > 0
+
+	if config.Encryption != nil {
+		e, err := newFileEncryptor(config.Encryption)
+		if err != nil {
+			panic(err)
+		}
+		w.encryption = e
+	}
+
+	if config.Concurrency > 1 {
+		w.workers = make(chan struct{}, config.Concurrency)
+	}
 
 	config.Schema.forEachNode(func(name string, node Node) {
 		nodeType := node.Type()
@@ -264,6 +422,7 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 			Scale:          scale,
 			Precision:      precision,
 			LogicalType:    logicalType,
+			FieldID:        int32(fieldIDOf(node)),
 		})
 	})
 
@@ -280,10 +439,16 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	// Those buffers are scratch space used to generate the page header and
 	// content, they are shared by all column chunks because they are only
 	// used during calls to writeDictionaryPage or writeDataPage, which are
-	// not done concurrently.
+	// not done concurrently, unless the writer was configured to encode the
+	// columns concurrently, in which case each column gets its own buffers.
 	buffers := new(writerBuffers)
 
 	forEachLeafColumnOf(config.Schema, func(leaf leafColumn) {
+		buffers := buffers
+		if w.workers != nil {
+			buffers = new(writerBuffers)
+		}
+
 		encoding := encodingOf(leaf.node)
 		dictionary := Dictionary(nil)
 		columnType := leaf.node.Type()
@@ -295,6 +460,11 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 		}
 
 		if isDictionaryEncoding(encoding) {
+			// The deprecated PLAIN_DICTIONARY encoding is found on the columns
+			// of files written by legacy applications; data pages referencing
+			// the dictionary always use the RLE encoding for the indexes, which
+			// is what RLE_DICTIONARY represents.
+			encoding = &RLEDictionary
 			dictionary = columnType.NewDictionary(columnIndex, 0, make([]byte, 0, defaultDictBufferSize))
 			columnType = dictionary.Type()
 		}
@@ -308,22 +478,30 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 			columnFilter:       searchBloomFilterColumn(config.BloomFilters, leaf.path),
 			compression:        compression,
 			dictionary:         dictionary,
+			fallbackType:       leaf.node.Type(),
 			dataPageType:       dataPageType,
 			maxRepetitionLevel: leaf.maxRepetitionLevel,
 			maxDefinitionLevel: leaf.maxDefinitionLevel,
 			bufferIndex:        int32(leaf.columnIndex),
 			bufferSize:         int32(config.PageBufferSize),
 			writePageStats:     config.DataPageStatistics,
+			maxRowsPerPage:     config.MaxRowsPerPage,
+			maxDictionarySize:  int64(config.MaxDictionaryPageSize),
 			encodings:          make([]format.Encoding, 0, 3),
-			// Data pages in version 2 can omit compression when dictionary
-			// encoding is employed; only the dictionary page needs to be
-			// compressed, the data pages are encoded with the hybrid
-			// RLE/Bit-Pack encoding which doesn't benefit from an extra
-			// compression layer.
-			isCompressed: isCompressed(compression) && (dataPageType != format.DataPageV2 || dictionary == nil),
+			rowGroupSize:       &w.rowGroupSize,
 		}
+		c.isCompressed = c.dataPagesAreCompressed()
 
 		c.header.encoder.Reset(c.header.protocol.NewWriter(&buffers.header))
+		c.async.workers = w.workers
+
+		if w.encryption != nil {
+			e, err := w.encryption.column(leaf.path, columnIndex)
+			if err != nil {
+				panic(err)
+			}
+			c.encryption = e
+		}
 
 		if leaf.maxRepetitionLevel > 0 {
 			c.insert = (*writerColumn).insertRepeated
@@ -345,6 +523,7 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 		c.page.encoding = encoding
 		c.encodings = addEncoding(c.encodings, c.page.encoding.Encoding())
 		sortPageEncodings(c.encodings)
+		c.resetBloomFilter()
 
 		w.columns = append(w.columns, c)
 
@@ -380,7 +559,11 @@ func newWriter(output io.Writer, config *WriterConfig) *writer {
 	}
 
 	for i, c := range w.columns {
-		w.columnOrders[i] = *c.columnType.ColumnOrder()
+		// Types with an undefined sort order have no column order, which is
+		// represented by an empty union.
+		if columnOrder := c.columnType.ColumnOrder(); columnOrder != nil {
+			w.columnOrders[i] = *columnOrder
+		}
 	}
 
 	return w
@@ -406,8 +589,13 @@ func (w *writer) reset(writer io.Writer) {
 		w.offsetIndexes[i] = nil
 	}
 	w.rowGroups = w.rowGroups[:0]
+	w.numRows = 0
+	w.rowGroupSize = 0
 	w.columnIndexes = w.columnIndexes[:0]
 	w.offsetIndexes = w.offsetIndexes[:0]
+	if w.encryption != nil {
+		w.encryption.reset()
+	}
 }
 
 func (w *writer) close() error {
@@ -436,18 +624,46 @@ func (w *writer) writeFileHeader() error {
 		return io.ErrClosedPipe
 	}
 	if w.writer.offset == 0 {
-		_, err := w.writer.WriteString("PAR1")
+		_, err := w.writer.WriteString(w.magic())
 		return err
 	}
 	return nil
 }
 
-func (w *writer) configureBloomFilters(columnChunks []ColumnChunk) {
+func (w *writer) magic() string {
+	if w.encryption != nil {
+		return w.encryption.magic()
+	}
+	return "PAR1"
+}
+
+func (w *writer) configureBloomFilters(columnChunks []ColumnChunk) error {
 	for i, c := range w.columns {
-		if c.columnFilter != nil {
+		if c.columnFilter == nil {
+			continue
+		}
+		// When the column chunk already has a split block filter (e.g. it was
+		// read from a file or built by a buffer), the filter is copied instead
+		// of hashing the values again.
+		if filter := columnChunks[i].BloomFilter(); filter != nil && filter.Size() > 0 {
+			bits, ok, err := splitBlockFilterBits(filter)
+			if err != nil {
+				return fmt.Errorf("reading bloom filter of column %q: %w", c.columnPath, err)
+			}
+			if ok {
+				c.filter.bits = append(c.filter.bits[:0], bits...)
+				c.filter.copied = true
+				continue
+			}
+		}
+		// Filters sized from the number of distinct values are not sized from
+		// the number of values, which would often make them much larger than
+		// they need to be.
+		if _, isDistinctValuesFilter := c.columnFilter.(distinctValuesFilter); !isDistinctValuesFilter {
 			c.resizeBloomFilter(columnChunks[i].NumValues())
 		}
 	}
+	return nil
 }
 
 func (w *writer) writeFileFooter() error {
@@ -471,7 +687,7 @@ func (w *writer) writeFileFooter() error {
 		for j := range columnIndexes {
 			column := &rowGroup.Columns[j]
 			column.ColumnIndexOffset = w.writer.offset
-			if err := encoder.Encode(&columnIndexes[j]); err != nil {
+			if err := w.writePageIndex(encoder, &columnIndexes[j], columnIndexModule, i, j); err != nil {
 				return err
 			}
 			column.ColumnIndexLength = int32(w.writer.offset - column.ColumnIndexOffset)
@@ -483,7 +699,7 @@ func (w *writer) writeFileFooter() error {
 		for j := range offsetIndexes {
 			column := &rowGroup.Columns[j]
 			column.OffsetIndexOffset = w.writer.offset
-			if err := encoder.Encode(&offsetIndexes[j]); err != nil {
+			if err := w.writePageIndex(encoder, &offsetIndexes[j], offsetIndexModule, i, j); err != nil {
 				return err
 			}
 			column.OffsetIndexLength = int32(w.writer.offset - column.OffsetIndexOffset)
@@ -495,7 +711,7 @@ func (w *writer) writeFileFooter() error {
 		numRows += w.rowGroups[rowGroupIndex].NumRows
 	}
 
-	footer, err := thrift.Marshal(new(thrift.CompactProtocol), &format.FileMetaData{
+	metadata := &format.FileMetaData{
 		Version:          1,
 		Schema:           w.schemaElements,
 		NumRows:          numRows,
@@ -503,27 +719,67 @@ func (w *writer) writeFileFooter() error {
 		KeyValueMetadata: w.metadata,
 		CreatedBy:        w.createdBy,
 		ColumnOrders:     w.columnOrders,
-	})
+	}
+
+	e := w.encryption
+	if e != nil {
+		if err := e.encryptColumnMetaData(w.rowGroups, w.columns); err != nil {
+			return err
+		}
+		if e.config.PlaintextFooter {
+			metadata.EncryptionAlgorithm = e.algorithm
+			metadata.FooterSigningKeyMetadata = e.footerKey.Metadata
+		}
+	}
+
+	footer, err := thrift.Marshal(new(thrift.CompactProtocol), metadata)
 	if err != nil {
 		return err
 	}
 
+	if e != nil {
+		if e.config.PlaintextFooter {
+			footer = e.signFooter(footer)
+		} else if footer, err = e.encryptFooter(footer); err != nil {
+			return err
+		}
+	}
+
 	length := len(footer)
 	footer = append(footer, 0, 0, 0, 0)
-	footer = append(footer, "PAR1"...)
+	footer = append(footer, w.magic()...)
 	binary.LittleEndian.PutUint32(footer[length:], uint32(length))
 
 	_, err = w.writer.Write(footer)
 	return err
 }
 
+func (w *writer) writePageIndex(encoder *thrift.Encoder, index interface{}, moduleType int8, rowGroup, column int) error {
+	e := w.columns[column].encryption
+	if e == nil {
+		return encoder.Encode(index)
+	}
+	data, err := thrift.Marshal(new(thrift.CompactProtocol), index)
+	if err != nil {
+		return err
+	}
+	_, err = w.writer.Write(e.encrypt(nil, data, moduleType, rowGroup))
+	return err
+}
+
 func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []SortingColumn) (int64, error) {
+	if err := w.wait(); err != nil {
+		return 0, err
+	}
+
 	numRows := w.columns[0].totalRowCount()
 	if numRows == 0 {
 		return 0, nil
 	}
 
 	defer func() {
+		w.numRows = 0
+		w.rowGroupSize = 0
 		for _, c := range w.columns {
 			c.reset()
 		}
@@ -532,13 +788,8 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 		}
 	}()
 
-	for _, c := range w.columns {
-		if err := c.flush(); err != nil {
-			return 0, err
-		}
-		if err := c.flushFilterPages(); err != nil {
-			return 0, err
-		}
+	if err := w.flushColumns(); err != nil {
+		return 0, err
 	}
 
 	if err := w.writeFileHeader(); err != nil {
@@ -560,7 +811,14 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 
 		if c.dictionary != nil {
 			c.columnChunk.MetaData.DictionaryPageOffset = w.writer.offset
-			if err := c.writeDictionaryPage(&w.writer, c.dictionary); err != nil {
+			// When columns are encoded concurrently, the dictionary pages were
+			// already encoded in the column buffers by flushColumns.
+			if w.workers == nil {
+				if err := c.encodeDictionaryPage(c.dictionary); err != nil {
+					return 0, fmt.Errorf("encoding dictionary page of row group column %d: %w", i, err)
+				}
+			}
+			if err := c.writeDictionaryPage(&w.writer); err != nil {
 				return 0, fmt.Errorf("writing dictionary page of row group colum %d: %w", i, err)
 			}
 		}
@@ -623,9 +881,77 @@ func (w *writer) writeRowGroup(rowGroupSchema *Schema, rowGroupSortingColumns []
 
 	w.columnIndexes = append(w.columnIndexes, columnIndex)
 	w.offsetIndexes = append(w.offsetIndexes, offsetIndex)
+
+	if w.encryption != nil {
+		w.encryption.rowGroup = len(w.rowGroups)
+	}
 	return numRows, nil
 }
 
+// flushColumns flushes the buffered values of all columns and generates their
+// bloom filters. When the writer encodes columns concurrently, the dictionary
+// pages are also encoded, each column being processed on its own goroutine.
+func (w *writer) flushColumns() error {
+	if w.workers == nil {
+		for _, c := range w.columns {
+			if err := c.flush(); err != nil {
+				return err
+			}
+			if err := c.flushFilterPages(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, c := range w.columns {
+		if err := c.flush(); err != nil {
+			return err
+		}
+	}
+
+	errs := make([]error, len(w.columns))
+	wg := sync.WaitGroup{}
+
+	for i, c := range w.columns {
+		wg.Add(1)
+		go func(c *writerColumn, err *error) {
+			defer wg.Done()
+			if *err = c.wait(); *err != nil {
+				return
+			}
+			w.workers <- struct{}{}
+			defer func() { <-w.workers }()
+			if *err = c.flushFilterPages(); *err != nil {
+				return
+			}
+			if c.dictionary != nil {
+				*err = c.encodeDictionaryPage(c.dictionary)
+			}
+		}(c, &errs[i])
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wait blocks until all the pages being flushed in the background have been
+// written to the page buffers of their columns.
+func (w *writer) wait() (err error) {
+	for _, c := range w.columns {
+		if e := c.wait(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
 func (w *writer) WriteRow(row Row) error {
 	for i := range row {
 		c := w.columns[row[i].Column()]
@@ -638,9 +964,25 @@ func (w *writer) WriteRow(row Row) error {
 			return err
 		}
 	}
+	if w.numRows++; w.autoFlush && w.rowGroupIsFull() {
+		return w.flush()
+	}
 	return nil
 }
 
+// rowGroupIsFull returns true if the row group buffered by the writer reached
+// one of the configured limits.
+//
+// The size of the row group only accounts for the pages that the columns have
+// flushed, the values still held in the column buffers are not counted, which
+// avoids walking all the columns after each row.
+func (w *writer) rowGroupIsFull() bool {
+	if w.maxRowsPerRowGroup > 0 && w.numRows >= w.maxRowsPerRowGroup {
+		return true
+	}
+	return w.rowGroupTargetSize > 0 && w.rowGroupSize >= w.rowGroupTargetSize
+}
+
 // The WriteValues method is intended to work in pair with WritePage to allow
 // programs to target writing values to specific columns of of the writer.
 func (w *writer) WriteValues(values []Value) (numValues int, err error) {
@@ -743,6 +1085,17 @@ func (wb *writerBuffers) compress(codec compress.Codec) (err error) {
 	return err
 }
 
+// encrypt replaces the levels and data of the page with the encrypted module
+// of the page.
+func (wb *writerBuffers) encrypt(e *columnEncryptor, moduleType int8, page int) {
+	wb.scratch = append(wb.scratch[:0], wb.repetitions...)
+	wb.scratch = append(wb.scratch, wb.definitions...)
+	wb.scratch = append(wb.scratch, wb.page...)
+	wb.repetitions = wb.repetitions[:0]
+	wb.definitions = wb.definitions[:0]
+	wb.page = e.encryptPage(wb.page[:0], wb.scratch, moduleType, page)
+}
+
 func (wb *writerBuffers) swapPageAndScratchBuffers() {
 	wb.page, wb.scratch = wb.scratch, wb.page[:0]
 }
@@ -762,6 +1115,14 @@ type writerColumn struct {
 	columnFilter BloomFilterColumn
 	compression  compress.Codec
 	dictionary   Dictionary
+	encryption   *columnEncryptor
+
+	// When the dictionary grows larger than maxDictionarySize, the column
+	// falls back to writing values of fallbackType for the rest of the row
+	// group; the dictionary is then only used to write the dictionary page
+	// referenced by the data pages written before the fallback.
+	fallback     bool
+	fallbackType Type
 
 	dataPageType       format.PageType
 	maxRepetitionLevel int8
@@ -769,6 +1130,17 @@ type writerColumn struct {
 
 	buffers *writerBuffers
 
+	// When the writer encodes columns concurrently, pages are flushed on a
+	// background goroutine while the next page is being buffered; done is
+	// closed when the flush completes, after which the column buffer that it
+	// used becomes the spare buffer of the column.
+	async struct {
+		workers chan struct{}
+		done    chan struct{}
+		spare   ColumnBuffer
+		err     error
+	}
+
 	header struct {
 		protocol thrift.CompactProtocol
 		encoder  thrift.Encoder
@@ -779,11 +1151,20 @@ type writerColumn struct {
 	}
 
 	filter struct {
-		bits  []byte
-		pages []BufferedPage
+		bits []byte
+		// Hashes of the values written while the size of the filter is not
+		// known, they are inserted in the filter at the end of the row group.
+		hashes filterHashes
+		// Set when the filter was copied from the row group being written,
+		// the pages do not need to be written to the filter in this case.
+		copied bool
 	}
 
+	dictionaryBytes int64  // size of the dictionary last added to rowGroupSize
+	rowGroupSize    *int64 // running total of the writer, see writer.rowGroupSize
+
 	numRows        int64
+	maxRowsPerPage int64
 	maxValues      int32
 	numValues      int32
 	bufferIndex    int32
@@ -792,11 +1173,15 @@ type writerColumn struct {
 	isCompressed   bool
 	encodings      []format.Encoding
 
+	maxDictionarySize int64
+
 	columnChunk *format.ColumnChunk
 	offsetIndex *format.OffsetIndex
 }
 
 func (c *writerColumn) reset() {
+	c.wait()
+	c.async.err = nil
 	if c.columnBuffer != nil {
 		c.columnBuffer.Reset()
 	}
@@ -812,16 +1197,19 @@ func (c *writerColumn) reset() {
 	for i := range c.pages {
 		c.pages[i] = nil
 	}
-	for i := range c.filter.pages {
-		c.filter.pages[i] = nil
-	}
 	c.pages = c.pages[:0]
+	if c.fallback {
+		c.restoreDictionaryEncoding()
+	}
 	// Bloom filters may change in size between row groups, but we retain the
 	// buffer to avoid reallocating large memory blocks.
 	c.filter.bits = c.filter.bits[:0]
-	c.filter.pages = c.filter.pages[:0]
+	c.filter.hashes.values = c.filter.hashes.values[:0]
+	c.filter.copied = false
+	c.resetBloomFilter()
 	c.numRows = 0
 	c.numValues = 0
+	c.dictionaryBytes = 0
 	// Reset the fields of column chunks that change between row groups,
 	// but keep the ones that remain unchanged.
 	c.columnChunk.MetaData.NumValues = 0
@@ -832,6 +1220,7 @@ func (c *writerColumn) reset() {
 	c.columnChunk.MetaData.Statistics = format.Statistics{}
 	c.columnChunk.MetaData.EncodingStats = make([]format.PageEncodingStats, 0, cap(c.columnChunk.MetaData.EncodingStats))
 	c.columnChunk.MetaData.BloomFilterOffset = 0
+	c.columnChunk.MetaData.Encoding = c.encodings
 	// Retain the previous capacity in the new page locations array, assuming
 	// the number of pages should be roughly the same between row groups written
 	// by the writer.
@@ -846,12 +1235,33 @@ func (c *writerColumn) totalRowCount() int64 {
 	return n
 }
 
+// addPageSize adds the uncompressed size of a page flushed by the column, and
+// the growth of its dictionary, to the running total of the row group.
+//
+// The size is computed from the pages before they are encoded so it does not
+// depend on whether pages are flushed in the background or not.
+func (c *writerColumn) addPageSize(size int64) {
+	if c.dictionary != nil {
+		dictionaryBytes := c.dictionary.Page().Size()
+		size += dictionaryBytes - c.dictionaryBytes
+		c.dictionaryBytes = dictionaryBytes
+	}
+	*c.rowGroupSize += size
+}
+
 func (c *writerColumn) canFlush() bool {
 	return c.columnBuffer.Size() >= int64(c.bufferSize/2)
 }
 
 func (c *writerColumn) flush() (err error) {
 	if c.numValues != 0 {
+		c.addPageSize(c.columnBuffer.Size())
+		// Pages of dictionary-encoded columns reference the dictionary, which
+		// is modified when writing more values to the column, so they cannot
+		// be flushed in the background.
+		if c.async.workers != nil && (c.dictionary == nil || c.fallback) {
+			return c.flushAsync()
+		}
 		c.numValues = 0
 		defer c.columnBuffer.Reset()
 		_, err = c.writeBufferedPage(c.columnBuffer.Page())
@@ -859,33 +1269,98 @@ func (c *writerColumn) flush() (err error) {
 	return err
 }
 
+func (c *writerColumn) flushAsync() error {
+	// Waiting for the previous flush guarantees that pages are written in
+	// order, and makes its column buffer available to receive the next values.
+	if err := c.wait(); err != nil {
+		return err
+	}
+
+	columnBuffer := c.columnBuffer
+	if c.async.spare == nil {
+		c.async.spare = c.newColumnBuffer()
+	}
+	c.columnBuffer, c.async.spare = c.async.spare, nil
+	c.numValues = 0
+
+	done := make(chan struct{})
+	c.async.done = done
+
+	go func() {
+		defer close(done)
+		c.async.workers <- struct{}{}
+		_, err := c.writeBufferedPage(columnBuffer.Page())
+		<-c.async.workers
+		columnBuffer.Reset()
+		c.async.spare, c.async.err = columnBuffer, err
+	}()
+	return nil
+}
+
+func (c *writerColumn) wait() error {
+	if c.async.done != nil {
+		<-c.async.done
+		c.async.done = nil
+	}
+	return c.async.err
+}
+
 func (c *writerColumn) flushFilterPages() (err error) {
-	if c.columnFilter != nil {
-		// If there is a dictionary, it contains all the values that we need to
-		// write to the filter.
-		if dict := c.dictionary; dict != nil {
-			if c.filter.bits == nil {
-				c.resizeBloomFilter(int64(dict.Len()))
-			}
-			return c.writePageToFilter(dict.Page())
+	if c.columnFilter == nil || c.filter.copied {
+		return nil
+	}
+
+	// If there is a dictionary, it contains all the values that we need to
+	// write to the filter, unless the column fell back to another encoding in
+	// which case the values of pages written after the fallback were hashed
+	// (or written to the filter if it was already allocated).
+	if dict := c.dictionary; dict != nil && !c.fallback {
+		if len(c.filter.bits) == 0 {
+			c.resizeBloomFilter(int64(dict.Len()))
 		}
+		return c.writePageToFilter(dict.Page())
+	}
 
-		if len(c.filter.pages) > 0 {
-			numValues := int64(0)
-			for _, page := range c.filter.pages {
-				numValues += page.NumValues()
-			}
-			c.resizeBloomFilter(numValues)
-			for _, page := range c.filter.pages {
-				if err := c.writePageToFilter(page); err != nil {
-					return err
-				}
-			}
+	if len(c.filter.bits) > 0 {
+		if c.fallback {
+			return c.writePageToFilter(c.dictionary.Page())
+		}
+		return nil
+	}
+
+	if c.fallback {
+		if err := c.writePageHashes(c.dictionary.Page()); err != nil {
+			return err
 		}
 	}
+
+	numValues := int64(len(c.filter.hashes.values))
+	if sketch := c.filter.hashes.sketch; sketch != nil {
+		// Filters configured with a false positive probability are sized from
+		// the number of distinct values rather than the number of values.
+		numValues = sketch.Estimate()
+	}
+	c.resizeBloomFilter(numValues)
+	bloom.MakeSplitBlockFilter(c.filter.bits).InsertBulk(c.filter.hashes.values)
 	return nil
 }
 
+// resetBloomFilter is called when the column starts a new row group. Filters
+// configured with the number of distinct values of column chunks are allocated
+// immediately, which allows pages to be written to the filter instead of being
+// buffered until the end of the row group.
+func (c *writerColumn) resetBloomFilter() {
+	if f, ok := c.columnFilter.(distinctValuesFilter); ok {
+		if ndv, estimate := f.distinctValues(); !estimate {
+			c.resizeBloomFilter(ndv)
+		} else if c.filter.hashes.sketch == nil {
+			c.filter.hashes.sketch = new(hyperloglog.Sketch)
+		} else {
+			c.filter.hashes.sketch.Reset()
+		}
+	}
+}
+
 func (c *writerColumn) resizeBloomFilter(numValues int64) {
 	const bitsPerValue = 10 // TODO: make this configurable
 	filterSize := c.columnFilter.Size(numValues, bitsPerValue)
@@ -941,9 +1416,72 @@ func (c *writerColumn) writeRow(row []Value) error {
 		return err
 	}
 	c.numValues += int32(len(row))
+	return c.checkLimits()
+}
+
+// checkLimits is called after writing values to the column buffer, it switches
+// the column to its fallback encoding if the dictionary became too large, and
+// flushes the buffered page if it reached the maximum number of rows.
+func (c *writerColumn) checkLimits() error {
+	if c.maxDictionarySize > 0 && c.dictionary != nil && !c.fallback {
+		if c.dictionary.Page().Size() > c.maxDictionarySize {
+			return c.fallbackToPlainEncoding()
+		}
+	}
+	if c.maxRowsPerPage > 0 && c.columnBuffer != nil {
+		if int64(c.columnBuffer.Len()) >= c.maxRowsPerPage {
+			return c.flush()
+		}
+	}
 	return nil
 }
 
+// fallbackToPlainEncoding flushes the values buffered with the dictionary
+// encoding and configures the column to write the values of the remaining
+// pages of the row group with the PLAIN encoding.
+func (c *writerColumn) fallbackToPlainEncoding() error {
+	if err := c.flush(); err != nil {
+		return err
+	}
+	c.fallback = true
+	c.columnType = c.fallbackType
+	c.columnBuffer = nil
+	c.async.spare = nil
+	// Like parquet-mr and Arrow, the writer falls back to the PLAIN encoding,
+	// which all readers support in both versions of data pages.
+	c.page.encoding = &Plain
+	c.isCompressed = c.dataPagesAreCompressed()
+	// The encodings of the column chunk are copied because the slice is
+	// shared with the metadata of row groups that were already written.
+	encodings := make([]format.Encoding, len(c.encodings), len(c.encodings)+1)
+	copy(encodings, c.encodings)
+	encodings = addEncoding(encodings, c.page.encoding.Encoding())
+	sortPageEncodings(encodings)
+	c.columnChunk.MetaData.Encoding = encodings
+	return nil
+}
+
+// restoreDictionaryEncoding reverts the changes made by fallbackToPlainEncoding
+// so the next row group starts with the dictionary encoding again.
+func (c *writerColumn) restoreDictionaryEncoding() {
+	c.fallback = false
+	c.columnType = c.dictionary.Type()
+	c.columnBuffer = nil
+	c.async.spare = nil
+	c.page.encoding = &RLEDictionary
+	c.isCompressed = c.dataPagesAreCompressed()
+}
+
+// dataPagesAreCompressed returns whether data pages of the column need to be
+// compressed. Data pages in version 2 can omit compression when dictionary
+// encoding is employed; only the dictionary page needs to be compressed, the
+// data pages are encoded with the hybrid RLE/Bit-Pack encoding which doesn't
+// benefit from an extra compression layer.
+func (c *writerColumn) dataPagesAreCompressed() bool {
+	dictionaryEncoded := c.dictionary != nil && !c.fallback
+	return isCompressed(c.compression) && (c.dataPageType != format.DataPageV2 || !dictionaryEncoded)
+}
+
 func (c *writerColumn) WriteValues(values []Value) (numValues int, err error) {
 	if c.columnBuffer == nil {
 		c.columnBuffer = c.newColumnBuffer()
@@ -955,10 +1493,18 @@ func (c *writerColumn) WriteValues(values []Value) (numValues int, err error) {
 }
 
 func (c *writerColumn) WritePage(page Page) (numValues int64, err error) {
+	if err := c.wait(); err != nil {
+		return 0, err
+	}
+
 	// Page write optimizations are only available the column is not reindexing
 	// the values. If a dictionary is present, the column needs to see each
 	// individual value in order to re-index them in the dictionary.
-	if c.dictionary == nil || c.dictionary == page.Dictionary() {
+	dictionary := c.dictionary
+	if c.fallback {
+		dictionary = nil
+	}
+	if dictionary == nil || dictionary == page.Dictionary() {
 		// If the column had buffered values, we continue writing values from
 		// the page into the column buffer if it would have caused producing a
 		// page less than half the size of the target; if there were enough
@@ -979,7 +1525,8 @@ func (c *writerColumn) WritePage(page Page) (numValues int64, err error) {
 				// Buffered pages may be larger than the target page size on the
 				// column, in which case multiple pages get written by slicing
 				// the original page into sub-pages.
-				err = forEachPageSlice(p, int64(c.bufferSize), func(p BufferedPage) error {
+				err = forEachPageSlice(p, int64(c.bufferSize), c.maxRowsPerPage, func(p BufferedPage) error {
+					c.addPageSize(p.Size())
 					n, err := c.writeBufferedPage(p)
 					numValues += n
 					return err
@@ -992,6 +1539,7 @@ func (c *writerColumn) WritePage(page Page) (numValues int64, err error) {
 				// are being copied into a new file, they are simply copied to
 				// amortize the cost of decoding and re-encoding the pages, which
 				// often includes costly compression steps.
+				c.addPageSize(p.Size())
 				return c.writeCompressedPage(p)
 			}
 		}
@@ -1006,7 +1554,10 @@ func (c *writerColumn) WritePage(page Page) (numValues int64, err error) {
 
 func (c *writerColumn) writePageValues(page ValueReader) (numValues int64, err error) {
 	numValues, err = CopyValues(c, page)
-	if err == nil && c.canFlush() {
+	if err == nil {
+		err = c.checkLimits()
+	}
+	if err == nil && c.numValues > 0 && c.canFlush() {
 		// Always attempt to flush after writing a full page if we have enough
 		// buffered values; the intent is to leave the column clean so that
 		// subsequent calls to the WritePage method can use optimized write path
@@ -1017,13 +1568,30 @@ func (c *writerColumn) writePageValues(page ValueReader) (numValues int64, err e
 }
 
 func (c *writerColumn) writeBloomFilter(w io.Writer) error {
-	e := thrift.NewEncoder(c.header.protocol.NewWriter(w))
 	h := bloomFilterHeader(c.columnFilter)
 	h.NumBytes = int32(len(c.filter.bits))
-	if err := e.Encode(&h); err != nil {
+
+	if c.encryption == nil {
+		e := thrift.NewEncoder(c.header.protocol.NewWriter(w))
+		if err := e.Encode(&h); err != nil {
+			return err
+		}
+		_, err := w.Write(c.filter.bits)
 		return err
 	}
-	_, err := w.Write(c.filter.bits)
+
+	// The header and bitset of bloom filters are encrypted as separate
+	// modules. The header is not encoded in the column buffers, which may
+	// be holding the dictionary page.
+	header := new(bytes.Buffer)
+	if err := thrift.NewEncoder(c.header.protocol.NewWriter(header)).Encode(&h); err != nil {
+		return err
+	}
+	c.encryption.encryptHeader(header, bloomFilterHeaderModule, noPageOrdinal)
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return err
+	}
+	_, err := w.Write(c.encryption.encrypt(nil, c.filter.bits, bloomFilterBitsetModule, c.encryption.file.rowGroup))
 	return err
 }
 
@@ -1057,6 +1625,10 @@ func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 	}
 
 	switch {
+	case c.columnFilter == nil || c.filter.copied || (c.dictionary != nil && !c.fallback):
+		// If the column uses a dictionary encoding, all possible values exist
+		// in the dictionary and there is no need to write the pages to the
+		// filter, which would only contain the dictionary indexes anyways.
 	case len(c.filter.bits) > 0:
 		// When the writer knows the number of values in advance (e.g. when
 		// writing a full row group), the filter encoding is set and the page
@@ -1066,13 +1638,13 @@ func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 		if err := c.writePageToFilter(page); err != nil {
 			return 0, err
 		}
-	case c.columnFilter != nil && c.dictionary == nil:
-		// If the column uses a dictionary encoding, all possible values exist
-		// in the dictionary and there is no need to buffer the pages, but if
-		// the column is supposed to generate a filter and the number of values
-		// wasn't known, we must buffer all the pages in order to properly size
-		// the filter.
-		c.filter.pages = append(c.filter.pages, page.Clone())
+	default:
+		// If the column is supposed to generate a filter and the number of
+		// values wasn't known, the hashes of values are retained in order to
+		// properly size the filter.
+		if err := c.writePageHashes(page); err != nil {
+			return 0, err
+		}
 	}
 
 	statistics := format.Statistics{}
@@ -1111,10 +1683,22 @@ func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 		}
 	}
 
+	if c.encryption != nil {
+		if err := c.encryption.checkPage(len(c.pages)); err != nil {
+			return 0, err
+		}
+		buf.encrypt(c.encryption, dataPageModule, len(c.pages))
+		pageHeader.CompressedPageSize = int32(buf.size())
+		pageHeader.CRC = int32(buf.crc32())
+	}
+
 	buf.header.Reset()
 	if err := c.header.encoder.Encode(pageHeader); err != nil {
 		return 0, err
 	}
+	if c.encryption != nil {
+		c.encryption.encryptHeader(&buf.header, dataPageHeaderModule, len(c.pages))
+	}
 
 	size := int64(buf.header.Len()) +
 		int64(len(buf.repetitions)) +
@@ -1146,6 +1730,7 @@ func (c *writerColumn) writeBufferedPage(page BufferedPage) (int64, error) {
 
 func (c *writerColumn) writeCompressedPage(page CompressedPage) (int64, error) {
 	switch {
+	case c.filter.copied:
 	case len(c.filter.bits) > 0:
 		// TODO: modify the Buffer method to accept some kind of buffer pool as
 		// argument so we can use a pre-allocated page buffer to load the page
@@ -1161,9 +1746,11 @@ func (c *writerColumn) writeCompressedPage(page CompressedPage) (int64, error) {
 		}
 	case c.columnFilter != nil && c.dictionary == nil:
 		// When a column filter is configured but no page filter was allocated,
-		// we need to buffer the page in order to have access to the number of
-		// values and properly size the bloom filter when writing the row group.
-		c.filter.pages = append(c.filter.pages, page.Buffer())
+		// we need to retain the hashes of values in order to properly size the
+		// bloom filter when writing the row group.
+		if err := c.writePageHashes(page.Buffer()); err != nil {
+			return 0, err
+		}
 	}
 
 	pageHeader := &format.PageHeader{
@@ -1181,11 +1768,30 @@ func (c *writerColumn) writeCompressedPage(page CompressedPage) (int64, error) {
 		return 0, fmt.Errorf("writing compressed page type of unknown type: %s", h.PageType())
 	}
 
+	pageData := page.PageData()
+	if c.encryption != nil {
+		if err := c.encryption.checkPage(len(c.pages)); err != nil {
+			return 0, err
+		}
+		data, err := io.ReadAll(pageData)
+		if err != nil {
+			return 0, err
+		}
+		buf := c.buffers
+		buf.page = c.encryption.encryptPage(buf.page[:0], data, dataPageModule, len(c.pages))
+		pageHeader.CompressedPageSize = int32(len(buf.page))
+		pageHeader.CRC = int32(crc32.ChecksumIEEE(buf.page))
+		pageData = bytes.NewReader(buf.page)
+	}
+
 	header := &c.buffers.header
 	header.Reset()
 	if err := c.header.encoder.Encode(pageHeader); err != nil {
 		return 0, err
 	}
+	if c.encryption != nil {
+		c.encryption.encryptHeader(header, dataPageHeaderModule, len(c.pages))
+	}
 	headerSize := int32(header.Len())
 	compressedSize := int64(headerSize + pageHeader.CompressedPageSize)
 
@@ -1194,7 +1800,7 @@ func (c *writerColumn) writeCompressedPage(page CompressedPage) (int64, error) {
 		if err != nil {
 			return headerSize, err
 		}
-		dataSize, err := io.Copy(output, page.PageData())
+		dataSize, err := io.Copy(output, pageData)
 		return headerSize + dataSize, err
 	})
 	if err != nil {
@@ -1204,7 +1810,9 @@ func (c *writerColumn) writeCompressedPage(page CompressedPage) (int64, error) {
 	return page.NumValues(), nil
 }
 
-func (c *writerColumn) writeDictionaryPage(output io.Writer, dict Dictionary) (err error) {
+// encodeDictionaryPage encodes the header and content of the dictionary page
+// in the column buffers, where writeDictionaryPage reads them from.
+func (c *writerColumn) encodeDictionaryPage(dict Dictionary) (err error) {
 	buf := c.buffers
 	buf.reset()
 
@@ -1219,6 +1827,13 @@ func (c *writerColumn) writeDictionaryPage(output io.Writer, dict Dictionary) (e
 		}
 	}
 
+	if c.encryption != nil {
+		if err := c.encryption.checkPage(noPageOrdinal); err != nil {
+			return err
+		}
+		buf.encrypt(c.encryption, dictionaryPageModule, noPageOrdinal)
+	}
+
 	pageHeader := &format.PageHeader{
 		Type:                 format.DictionaryPage,
 		UncompressedPageSize: int32(uncompressedPageSize),
@@ -1236,21 +1851,47 @@ func (c *writerColumn) writeDictionaryPage(output io.Writer, dict Dictionary) (e
 	if err := c.header.encoder.Encode(pageHeader); err != nil {
 		return err
 	}
-	if _, err := output.Write(header.Bytes()); err != nil {
-		return err
-	}
-	if _, err := output.Write(buf.page); err != nil {
-		return err
+	if c.encryption != nil {
+		c.encryption.encryptHeader(header, dictionaryPageHeaderModule, noPageOrdinal)
 	}
 	c.recordPageStats(int32(header.Len()), pageHeader, nil)
 	return nil
 }
 
+func (c *writerColumn) writeDictionaryPage(output io.Writer) error {
+	buf := c.buffers
+	if _, err := output.Write(buf.header.Bytes()); err != nil {
+		return err
+	}
+	_, err := output.Write(buf.page)
+	return err
+}
+
 func (w *writerColumn) writePageToFilter(page BufferedPage) (err error) {
 	w.filter.bits, err = page.Encode(w.filter.bits, w.columnFilter.Encoding())
 	return err
 }
 
+func (w *writerColumn) writePageHashes(page BufferedPage) error {
+	_, err := page.Encode(nil, hashEncoding{hashes: &w.filter.hashes})
+	return err
+}
+
+// filterHashes retains the hashes of values written to a column until its bloom
+// filter can be sized. When the filter is sized from the number of distinct
+// values, the hashes are also inserted in a sketch which estimates it.
+type filterHashes struct {
+	values []uint64
+	sketch *hyperloglog.Sketch
+}
+
+func (h *filterHashes) InsertBulk(hashes []uint64) {
+	h.values = append(h.values, hashes...)
+	if h.sketch != nil {
+		h.sketch.InsertBulk(hashes)
+	}
+}
+
 func (c *writerColumn) writePage(size int64, writeTo func(io.Writer) (int64, error)) error {
 	buffer := c.pool.GetPageBuffer()
 	defer func() {
@@ -1271,6 +1912,9 @@ func (c *writerColumn) writePage(size int64, writeTo func(io.Writer) (int64, err
 
 func (c *writerColumn) makePageStatistics(page Page) format.Statistics {
 	numNulls := page.NumNulls()
+	if c.columnType.ColumnOrder() == nil {
+		return format.Statistics{NullCount: numNulls}
+	}
 	minValue, maxValue, _ := page.Bounds()
 	minValueBytes := minValue.Bytes()
 	maxValueBytes := maxValue.Bytes()
@@ -0,0 +1,127 @@
+//go:build go1.18
+
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// GenericWriter is similar to a Writer but uses a type parameter to define the
+// Go type representing the schema of rows being written.
+//
+// This example showcases a typical use of generic parquet writers:
+//
+//	writer := parquet.NewGenericWriter[RowType](output)
+//
+//	if _, err := writer.Write(rows); err != nil {
+//		...
+//	}
+//
+//	if err := writer.Close(); err != nil {
+//		...
+//	}
+//
+// The parquet schema of the type parameter is compiled once when the writer is
+// constructed, and rows are deconstructed directly from the slice passed to
+// Write, which avoids the dynamic type checks and allocations of calling Write
+// on a Writer with values boxed in interfaces.
+type GenericWriter[T any] struct {
+	base Writer
+}
+
+// NewGenericWriter is like NewWriter but returns a GenericWriter[T] suited to
+// write rows of Go type T.
+//
+// The type parameter T must be a struct type, the function panics otherwise.
+// When the options contain a schema, it is used to deconstruct the rows instead
+// of the schema derived from T.
+func NewGenericWriter[T any](output io.Writer, options ...WriterOption) *GenericWriter[T] {
+	config, err := NewWriterConfig(options...)
+	if err != nil {
+		panic(err)
+	}
+
+	if config.Schema == nil {
+		config.Schema = schemaOf(typeOf[T]())
+	}
+
+	w := &GenericWriter[T]{
+		base: Writer{
+			output: output,
+			config: config,
+		},
+	}
+
+	w.base.configure(config.Schema)
+	return w
+}
+
+// Close must be called after all values were produced to the writer in order to
+// flush all buffers and write the parquet footer.
+func (w *GenericWriter[T]) Close() error { return w.base.Close() }
+
+// Flush flushes all buffers into a row group to the underlying io.Writer.
+func (w *GenericWriter[T]) Flush() error { return w.base.Flush() }
+
+// Reset clears the state of the writer without flushing any of the buffers,
+// and setting the output to the io.Writer passed as argument, allowing the
+// writer to be reused to produce another parquet file.
+func (w *GenericWriter[T]) Reset(output io.Writer) { w.base.Reset(output) }
+
+// Write writes the rows passed as argument to the parquet file, returning the
+// number of rows that were written.
+func (w *GenericWriter[T]) Write(rows []T) (int, error) {
+	if w.base.schema.deconstructFunc() == nil {
+		return 0, fmt.Errorf("cannot write go values of type %s to parquet rows", reflect.TypeOf(rows).Elem())
+	}
+
+	defer func() {
+		clearValues(w.base.values)
+	}()
+
+	for i := range rows {
+		var err error
+		// Values which do not match the schema (e.g. dynamic values held in
+		// maps) are reported as errors rather than panics.
+		if w.base.values, err = w.base.schema.deconstructValue(w.base.values[:0], &rows[i]); err != nil {
+			return i, err
+		}
+		if err := w.base.WriteRow(w.base.values); err != nil {
+			return i, err
+		}
+	}
+
+	return len(rows), nil
+}
+
+// WriteRow is called to write another row to the parquet file.
+//
+// The row is expected to contain values for each column of the writer's schema,
+// in the order produced by the parquet.(*Schema).Deconstruct method.
+func (w *GenericWriter[T]) WriteRow(row Row) error { return w.base.WriteRow(row) }
+
+// WriteRowGroup writes a row group to the parquet file.
+//
+// See (*Writer).WriteRowGroup for details.
+func (w *GenericWriter[T]) WriteRowGroup(rowGroup RowGroup) (int64, error) {
+	return w.base.WriteRowGroup(rowGroup)
+}
+
+// ReadRowsFrom reads rows from the reader passed as arguments and writes them
+// to w.
+func (w *GenericWriter[T]) ReadRowsFrom(rows RowReader) (int64, error) {
+	return w.base.ReadRowsFrom(rows)
+}
+
+// Schema returns the schema of rows written by w.
+func (w *GenericWriter[T]) Schema() *Schema { return w.base.Schema() }
+
+func (w *GenericWriter[T]) hasRowGroupLimits() bool { return w.base.hasRowGroupLimits() }
+
+var (
+	_ RowWriterWithSchema = (*GenericWriter[struct{}])(nil)
+	_ RowReaderFrom       = (*GenericWriter[struct{}])(nil)
+	_ RowGroupWriter      = (*GenericWriter[struct{}])(nil)
+)
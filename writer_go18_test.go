@@ -0,0 +1,162 @@
+//go:build go1.18
+
+package parquet_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/parquet-go"
+)
+
+func TestGenericWriter(t *testing.T) {
+	t.Run("BOOLEAN", testGenericWriter[booleanColumn])
+	t.Run("INT32", testGenericWriter[int32Column])
+	t.Run("INT64", testGenericWriter[int64Column])
+	t.Run("INT96", testGenericWriter[int96Column])
+	t.Run("FLOAT", testGenericWriter[floatColumn])
+	t.Run("DOUBLE", testGenericWriter[doubleColumn])
+	t.Run("BYTE_ARRAY", testGenericWriter[byteArrayColumn])
+	t.Run("FIXED_LEN_BYTE_ARRAY", testGenericWriter[fixedLenByteArrayColumn])
+	t.Run("STRING", testGenericWriter[stringColumn])
+	t.Run("STRING (dict)", testGenericWriter[indexedStringColumn])
+	t.Run("UUID", testGenericWriter[uuidColumn])
+	t.Run("DECIMAL", testGenericWriter[decimalColumn])
+	t.Run("AddressBook", testGenericWriter[addressBook])
+	t.Run("Contact", testGenericWriter[contact])
+	t.Run("NestedListColumn", testGenericWriter[nestedListColumn])
+}
+
+func TestGenericWriterInt96Timestamps(t *testing.T) {
+	type Row struct {
+		Nanos  time.Time `parquet:"nanos"`
+		Micros time.Time `parquet:"micros,timestamp(microsecond)"`
+		Value  int64     `parquet:"value"`
+	}
+
+	rows := []Row{
+		{Nanos: time.Unix(0, 1), Micros: time.Unix(1, 0), Value: 1},
+		{Nanos: time.Unix(86400, 0), Micros: time.Unix(2, 1000), Value: 2},
+	}
+
+	want := new(bytes.Buffer)
+	writer := parquet.NewWriter(want, parquet.SchemaOf(rows[0]), parquet.Int96Timestamps(true))
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := new(bytes.Buffer)
+	genericWriter := parquet.NewGenericWriter[Row](got, parquet.Int96Timestamps(true))
+	if _, err := genericWriter.Write(rows); err != nil {
+		t.Fatal(err)
+	}
+	if err := genericWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(want.Bytes(), got.Bytes()) {
+		t.Error("generic writer produced a different parquet file than the writer")
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(got.Bytes()), int64(got.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"nanos", "micros"} {
+		leaf, _ := f.Schema().Lookup(path)
+		if kind := leaf.Node.Type().Kind(); kind != parquet.Int96 {
+			t.Errorf("column %q: wrong physical type: %s", path, kind)
+		}
+	}
+}
+
+func TestGenericWriterMapErrors(t *testing.T) {
+	schema, err := parquet.ParseSchema(`message Row {
+	required int32 id;
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := parquet.NewGenericWriter[map[string]interface{}](new(bytes.Buffer), schema)
+	n, err := writer.Write([]map[string]interface{}{{"id": 1}, {"id": 1.5}})
+	if err == nil {
+		t.Fatal("expected an error but got <nil>")
+	}
+	if n != 1 {
+		t.Errorf("wrong number of rows written: want=1 got=%d", n)
+	}
+	const want = "id: cannot convert go value 1.5 of type float64 to parquet column of type INT32"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("wrong error:\nwant = %s\ngot  = %s", want, err)
+	}
+}
+
+func testGenericWriter[Row any](t *testing.T) {
+	rows := makeGenericRows[Row](42)
+
+	want := new(bytes.Buffer)
+	writer := parquet.NewWriter(want, parquet.SchemaOf(rows[0]))
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := new(bytes.Buffer)
+	genericWriter := parquet.NewGenericWriter[Row](got)
+	n, err := genericWriter.Write(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(rows) {
+		t.Fatalf("wrong number of rows written: want=%d got=%d", len(rows), n)
+	}
+	if err := genericWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(want.Bytes(), got.Bytes()) {
+		t.Error("generic writer produced a different parquet file than the writer")
+	}
+
+	reader := parquet.NewReader(bytes.NewReader(got.Bytes()))
+	for i := range rows {
+		row := new(Row)
+		if err := reader.Read(row); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*row, rows[i]) {
+			t.Errorf("row mismatch at index %d\nwant = %+v\ngot  = %+v", i, rows[i], *row)
+		}
+	}
+}
+
+func BenchmarkGenericWriter(b *testing.B) {
+	rows := makeGenericRows[contact](benchmarkReaderNumRows)
+	buffer := new(bytes.Buffer)
+	writer := parquet.NewGenericWriter[contact](buffer)
+
+	for i := 0; i < b.N; i++ {
+		buffer.Reset()
+		writer.Reset(buffer)
+
+		if _, err := writer.Write(rows); err != nil {
+			b.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
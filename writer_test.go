@@ -3,18 +3,26 @@ package parquet_test
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"os/exec"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"testing/quick"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hexops/gotextdiff"
 	"github.com/hexops/gotextdiff/myers"
 	"github.com/hexops/gotextdiff/span"
 	"github.com/segmentio/parquet-go"
+	"github.com/segmentio/parquet-go/bloom"
 	"github.com/segmentio/parquet-go/compress"
+	"github.com/segmentio/parquet-go/deprecated"
+	"github.com/segmentio/parquet-go/format"
 )
 
 const (
@@ -574,6 +582,173 @@ func TestBloomFilterForDict(t *testing.T) {
 	}
 }
 
+func TestWriterBloomFilterFPP(t *testing.T) {
+	type Row struct {
+		Low  int64  `parquet:"low"`
+		High int64  `parquet:"high"`
+		Dict string `parquet:"dict,dict"`
+		Hint int64  `parquet:"hint"`
+	}
+
+	const numRows = 10e3
+	const fpp = 0.01
+
+	rows := make([]Row, numRows)
+	for i := range rows {
+		rows[i] = Row{
+			Low:  int64(i % 10),
+			High: int64(i),
+			Dict: strconv.Itoa(i % 100),
+			Hint: int64(i),
+		}
+	}
+
+	filters := parquet.BloomFilters(
+		parquet.SplitBlockFilterFPP(fpp, "low"),
+		parquet.SplitBlockFilterFPP(fpp, "high"),
+		parquet.SplitBlockFilterFPP(fpp, "dict"),
+		parquet.SplitBlockFilterNDV(fpp, 1000, "hint"),
+	)
+
+	for _, test := range []struct {
+		scenario string
+		write    func(*parquet.Writer) error
+	}{
+		{
+			scenario: "Write",
+			write: func(w *parquet.Writer) error {
+				for i := range rows {
+					if err := w.Write(&rows[i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			scenario: "WriteRowGroup",
+			write: func(w *parquet.Writer) error {
+				buffer := parquet.NewBuffer(w.Schema())
+				for i := range rows {
+					if err := buffer.Write(&rows[i]); err != nil {
+						return err
+					}
+				}
+				_, err := w.WriteRowGroup(buffer)
+				return err
+			},
+		},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			w := parquet.NewWriter(b, parquet.SchemaOf(Row{}), filters)
+			if err := test.write(w); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			columns := f.RowGroups()[0].ColumnChunks()
+
+			for _, column := range []struct {
+				index int
+				name  string
+				ndv   int64
+				value func(int) parquet.Value
+			}{
+				{0, "low", 10, func(i int) parquet.Value { return parquet.ValueOf(int64(i % 10)) }},
+				{1, "high", numRows, func(i int) parquet.Value { return parquet.ValueOf(int64(i)) }},
+				{2, "dict", 100, func(i int) parquet.Value { return parquet.ValueOf(strconv.Itoa(i % 100)) }},
+				{3, "hint", 1000, func(i int) parquet.Value { return parquet.ValueOf(int64(i)) }},
+			} {
+				filter := columns[column.index].BloomFilter()
+				if filter == nil {
+					t.Fatalf("%s: missing bloom filter", column.name)
+				}
+
+				// The number of distinct values of the "high" column is
+				// estimated, the size of the filter may be slightly off.
+				size := int64(bloom.BlockSize * bloom.NumSplitBlocksOfFPP(column.ndv, fpp))
+				if delta := math.Abs(float64(filter.Size()-size)) / float64(size); delta > 0.05 {
+					t.Errorf("%s: wrong bloom filter size: want=%d got=%d", column.name, size, filter.Size())
+				}
+
+				for i := 0; i < numRows; i++ {
+					if ok, err := filter.Check(column.value(i)); err != nil {
+						t.Fatal(err)
+					} else if !ok {
+						t.Fatalf("%s: bloom filter does not contain value %v of row %d", column.name, column.value(i), i)
+					}
+				}
+			}
+
+			falsePositives := 0
+			for i := numRows; i < 2*numRows; i++ {
+				if ok, _ := columns[1].BloomFilter().Check(parquet.ValueOf(int64(i))); ok {
+					falsePositives++
+				}
+			}
+			if r := float64(falsePositives) / numRows; r > 2*fpp {
+				t.Errorf("bloom filter triggered too many false positives: %g", r)
+			}
+		})
+	}
+}
+
+func TestWriterBloomFilterDictionaryFallback(t *testing.T) {
+	type Row struct {
+		Name string `parquet:"name,dict"`
+	}
+
+	const numRows = 10e3
+	const fpp = 0.01
+
+	b := new(bytes.Buffer)
+	w := parquet.NewWriter(b,
+		parquet.PageBufferSize(1024),
+		parquet.MaxDictionaryPageSize(1024),
+		parquet.BloomFilters(parquet.SplitBlockFilterFPP(fpp, "name")),
+	)
+	for i := 0; i < numRows; i++ {
+		if err := w.Write(&Row{Name: strconv.Itoa(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := f.RowGroups()[0].ColumnChunks()[0].BloomFilter()
+	if filter == nil {
+		t.Fatal("missing bloom filter")
+	}
+
+	// The values written to the dictionary before the fallback and to the
+	// pages after it are all in the filter, which is sized from the estimated
+	// number of distinct values.
+	size := int64(bloom.BlockSize * bloom.NumSplitBlocksOfFPP(numRows, fpp))
+	if delta := math.Abs(float64(filter.Size()-size)) / float64(size); delta > 0.05 {
+		t.Errorf("wrong bloom filter size: want=%d got=%d", size, filter.Size())
+	}
+	for i := 0; i < numRows; i++ {
+		value := parquet.ValueOf(strconv.Itoa(i))
+		if ok, err := filter.Check(value); err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			t.Fatalf("bloom filter does not contain value %v", value)
+		}
+	}
+}
+
 func TestWriterRepeatedUUIDDict(t *testing.T) {
 	inputID := uuid.MustParse("123456ab-0000-0000-0000-000000000000")
 	records := []struct {
@@ -610,3 +785,486 @@ func TestWriterRepeatedUUIDDict(t *testing.T) {
 		t.Errorf("expected to get UUID %q back out, got %q", inputID, row[0].Bytes())
 	}
 }
+
+func TestWriterPlainDictionary(t *testing.T) {
+	// PLAIN_DICTIONARY is found on the columns of files written by legacy
+	// applications, writing with their schema must produce RLE encoded
+	// dictionary indexes.
+	schema := parquet.NewSchema("test", parquet.Group{
+		"name": parquet.Encoded(parquet.String(), &parquet.PlainDictionary),
+	})
+	names := []string{"a", "b", "c", "b", "a", "a", "c", "b", "a", "c"}
+
+	b := new(bytes.Buffer)
+	w := parquet.NewWriter(b, schema)
+	for _, name := range names {
+		if err := w.Write(map[string]interface{}{"name": name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rleDictionary := false
+	encodings := f.Metadata().RowGroups[0].Columns[0].MetaData.Encoding
+	for _, encoding := range encodings {
+		switch encoding {
+		case format.PlainDictionary:
+			t.Errorf("column chunk lists the PLAIN_DICTIONARY encoding: %v", encodings)
+		case format.RLEDictionary:
+			rleDictionary = true
+		}
+	}
+	if !rleDictionary {
+		t.Errorf("column chunk does not list the RLE_DICTIONARY encoding: %v", encodings)
+	}
+
+	rows, err := readAllRows(parquet.NewReader(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(names) {
+		t.Fatalf("wrong number of rows: want=%d got=%d", len(names), len(rows))
+	}
+	for i, row := range rows {
+		if got := row[0].String(); got != names[i] {
+			t.Errorf("wrong value at row %d: want=%q got=%q", i, names[i], got)
+		}
+	}
+}
+
+func TestWriterConcurrency(t *testing.T) {
+	type rowType struct {
+		ID       int64             `parquet:"id"`
+		Name     string            `parquet:"name,dict"`
+		Email    string            `parquet:"email,zstd"`
+		Score    *float64          `parquet:"score,optional"`
+		Tags     []string          `parquet:"tags"`
+		Attrs    map[string]string `parquet:"attrs"`
+		Comments []string          `parquet:"comments,list"`
+	}
+
+	rows := make([]rowType, 10000)
+	for i := range rows {
+		rows[i] = rowType{
+			ID:    int64(i),
+			Name:  fmt.Sprintf("name-%d", i%23),
+			Email: fmt.Sprintf("user-%d@example.com", i),
+			Attrs: map[string]string{"key": fmt.Sprint(i % 7)},
+		}
+		if i%5 != 0 {
+			score := float64(i) / 3
+			rows[i].Score = &score
+		}
+		for j := 0; j < i%3; j++ {
+			rows[i].Tags = append(rows[i].Tags, fmt.Sprintf("tag-%d", j))
+			rows[i].Comments = append(rows[i].Comments, fmt.Sprintf("comment-%d-%d", i, j))
+		}
+	}
+
+	write := func(options ...parquet.WriterOption) []byte {
+		buffer := new(bytes.Buffer)
+		options = append([]parquet.WriterOption{
+			parquet.PageBufferSize(2048),
+			parquet.Compression(&parquet.Gzip),
+			parquet.BloomFilters(
+				parquet.SplitBlockFilter("email"),
+				parquet.SplitBlockFilter("name"),
+			),
+		}, options...)
+		writer := parquet.NewWriter(buffer, options...)
+		for i := range rows {
+			if err := writer.Write(&rows[i]); err != nil {
+				t.Fatal(err)
+			}
+			if (i+1)%3000 == 0 {
+				if err := writer.Flush(); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buffer.Bytes()
+	}
+
+	for _, concurrency := range []int{2, 4, 16} {
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			want := write()
+			got := write(parquet.WriteConcurrency(concurrency))
+			if !bytes.Equal(want, got) {
+				t.Fatal("files written with and without concurrency differ")
+			}
+			want = write(parquet.RowGroupTargetSize(64 * 1024))
+			got = write(parquet.RowGroupTargetSize(64*1024), parquet.WriteConcurrency(concurrency))
+			if !bytes.Equal(want, got) {
+				t.Fatal("files written with a target row group size and concurrency differ")
+			}
+		})
+	}
+}
+
+func TestWriterRowGroupLimits(t *testing.T) {
+	type rowType struct {
+		ID    int64  `parquet:"id"`
+		Name  string `parquet:"name,dict"`
+		Email string `parquet:"email"`
+	}
+
+	rows := make([]rowType, 1000)
+	for i := range rows {
+		rows[i] = rowType{
+			ID:    int64(i),
+			Name:  fmt.Sprintf("name-%d", i%10),
+			Email: fmt.Sprintf("user-%d@example.com", i),
+		}
+	}
+
+	write := func(options ...parquet.WriterOption) *parquet.File {
+		t.Helper()
+		f, err := createParquetFile(makeRows(rows), append(options, parquet.PageBufferSize(1024))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := f.NumRows(); n != int64(len(rows)) {
+			t.Fatalf("wrong number of rows: want %d but got %d", len(rows), n)
+		}
+		return f
+	}
+
+	t.Run("max rows", func(t *testing.T) {
+		f := write(parquet.MaxRowsPerRowGroup(300))
+		want := []int64{300, 300, 300, 100}
+		rowGroups := f.RowGroups()
+		if len(rowGroups) != len(want) {
+			t.Fatalf("wrong number of row groups: want %d but got %d", len(want), len(rowGroups))
+		}
+		for i, rowGroup := range rowGroups {
+			if n := rowGroup.NumRows(); n != want[i] {
+				t.Errorf("row group %d: wrong number of rows: want %d but got %d", i, want[i], n)
+			}
+		}
+	})
+
+	t.Run("target size", func(t *testing.T) {
+		const targetSize = 8 * 1024
+		f := write(parquet.RowGroupTargetSize(targetSize))
+		rowGroups := f.Metadata().RowGroups
+		if len(rowGroups) < 2 {
+			t.Fatalf("expected multiple row groups but got %d", len(rowGroups))
+		}
+		for i, rowGroup := range rowGroups {
+			// Levels and page headers are not accounted for in the size of
+			// the buffered values, allow some slack for them.
+			if size := rowGroup.TotalByteSize; size > targetSize+1024 {
+				t.Errorf("row group %d: size exceeds the target: %d > %d", i, size, targetSize)
+			}
+		}
+	})
+
+	t.Run("copy rows", func(t *testing.T) {
+		buffer := parquet.NewBuffer()
+		for i := range rows {
+			if err := buffer.Write(&rows[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		input := write()
+
+		for _, rowGroup := range []parquet.RowGroup{buffer, input.RowGroups()[0]} {
+			output := new(bytes.Buffer)
+			writer := parquet.NewWriter(output, parquet.MaxRowsPerRowGroup(300))
+			if _, err := parquet.CopyRows(writer, rowGroup.Rows()); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+			f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []int64{300, 300, 300, 100}
+			rowGroups := f.RowGroups()
+			if len(rowGroups) != len(want) {
+				t.Fatalf("wrong number of row groups: want %d but got %d", len(want), len(rowGroups))
+			}
+			for i, rowGroup := range rowGroups {
+				if n := rowGroup.NumRows(); n != want[i] {
+					t.Errorf("row group %d: wrong number of rows: want %d but got %d", i, want[i], n)
+				}
+			}
+		}
+	})
+
+	t.Run("write row group", func(t *testing.T) {
+		buffer := parquet.NewBuffer()
+		for i := range rows {
+			if err := buffer.Write(&rows[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		output := new(bytes.Buffer)
+		writer := parquet.NewWriter(output, parquet.MaxRowsPerRowGroup(300))
+		if _, err := writer.WriteRowGroup(buffer); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(f.RowGroups()); n != 1 {
+			t.Errorf("row groups written explicitly must not be split: got %d row groups", n)
+		}
+	})
+}
+
+func TestWriterPageLimits(t *testing.T) {
+	type rowType struct {
+		ID   int64  `parquet:"id,dict"`
+		Name string `parquet:"name,dict"`
+	}
+
+	rows := make([]rowType, 1000)
+	for i := range rows {
+		rows[i] = rowType{
+			ID:   int64(i),
+			Name: fmt.Sprintf("name-%d", i),
+		}
+	}
+
+	write := func(t *testing.T, options ...parquet.WriterOption) *parquet.File {
+		t.Helper()
+		f, err := createParquetFile(makeRows(rows), options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader := parquet.NewReader(f)
+		for i := range rows {
+			row := rowType{}
+			if err := reader.Read(&row); err != nil {
+				t.Fatalf("reading row %d: %v", i, err)
+			}
+			if row != rows[i] {
+				t.Fatalf("row %d mismatch: want %+v but got %+v", i, rows[i], row)
+			}
+		}
+		return f
+	}
+
+	for _, test := range []struct {
+		scenario string
+		options  []parquet.WriterOption
+	}{
+		{scenario: "data page v1", options: []parquet.WriterOption{parquet.DataPageVersion(1)}},
+		{scenario: "data page v2", options: []parquet.WriterOption{parquet.DataPageVersion(2)}},
+		{scenario: "compressed", options: []parquet.WriterOption{parquet.Compression(&parquet.Snappy)}},
+		{scenario: "concurrency", options: []parquet.WriterOption{parquet.WriteConcurrency(4)}},
+	} {
+		t.Run(test.scenario, func(t *testing.T) {
+			t.Run("max dictionary page size", func(t *testing.T) {
+				const maxDictionaryPageSize = 1024
+				f := write(t, append(test.options,
+					parquet.MaxDictionaryPageSize(maxDictionaryPageSize),
+					parquet.MaxRowsPerRowGroup(600),
+					parquet.PageBufferSize(256),
+					parquet.BloomFilters(parquet.SplitBlockFilter("name")),
+				)...)
+
+				wantEncodings := [][]format.Encoding{
+					{format.Plain, format.RLEDictionary},
+					{format.Plain, format.RLEDictionary},
+				}
+
+				for i, rowGroup := range f.Metadata().RowGroups {
+					for j, columnChunk := range rowGroup.Columns {
+						want := wantEncodings[j]
+						if got := columnChunk.MetaData.Encoding; !reflect.DeepEqual(got, want) {
+							t.Errorf("row group %d, column %d: wrong encodings: want %v but got %v", i, j, want, got)
+						}
+						if size := columnChunk.MetaData.DictionaryPageOffset; size == 0 {
+							t.Errorf("row group %d, column %d: missing dictionary page", i, j)
+						}
+					}
+				}
+
+				for i, rowGroup := range f.RowGroups() {
+					columnChunk := rowGroup.ColumnChunks()[1]
+					pages := columnChunk.Pages()
+					dictionaryPages, fallbackPages := 0, 0
+					for {
+						p, err := pages.ReadPage()
+						if err != nil {
+							if err != io.EOF {
+								t.Fatal(err)
+							}
+							break
+						}
+						if dict := p.Dictionary(); dict != nil {
+							dictionaryPages++
+							if size := dict.Page().Size(); size > 2*maxDictionaryPageSize {
+								t.Errorf("row group %d: dictionary is too large: %d", i, size)
+							}
+						} else {
+							fallbackPages++
+						}
+					}
+					if dictionaryPages == 0 {
+						t.Errorf("row group %d: no pages were dictionary encoded", i)
+					}
+					if fallbackPages == 0 {
+						t.Errorf("row group %d: no pages were written after the dictionary fallback", i)
+					}
+
+					filter := columnChunk.BloomFilter()
+					if filter == nil {
+						t.Fatalf("row group %d: missing bloom filter", i)
+					}
+					n := rowGroup.NumRows()
+					for _, row := range rows[600*i : 600*i+int(n)] {
+						if ok, err := filter.Check(parquet.ValueOf(row.Name)); err != nil {
+							t.Fatal(err)
+						} else if !ok {
+							t.Errorf("row group %d: value missing from the bloom filter: %q", i, row.Name)
+						}
+					}
+				}
+			})
+
+			t.Run("max rows per page", func(t *testing.T) {
+				const maxRowsPerPage = 64
+				f := write(t, append(test.options, parquet.MaxRowsPerPage(maxRowsPerPage))...)
+
+				for _, rowGroup := range f.RowGroups() {
+					for j, columnChunk := range rowGroup.ColumnChunks() {
+						offsetIndex := columnChunk.OffsetIndex()
+						numPages := offsetIndex.NumPages()
+						if want := (int(rowGroup.NumRows()) + maxRowsPerPage - 1) / maxRowsPerPage; numPages != want {
+							t.Errorf("column %d: wrong number of pages: want %d but got %d", j, want, numPages)
+						}
+						for k := 1; k < numPages; k++ {
+							if n := offsetIndex.FirstRowIndex(k) - offsetIndex.FirstRowIndex(k-1); n > maxRowsPerPage {
+								t.Errorf("column %d: page %d has too many rows: %d", j, k-1, n)
+							}
+						}
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestWriterInt96Timestamps(t *testing.T) {
+	type Row struct {
+		Nanos  time.Time `parquet:"nanos"`
+		Micros time.Time `parquet:"micros,timestamp(microsecond),dict"`
+		Millis time.Time `parquet:"millis,timestamp(millisecond),optional"`
+		Int64  int64     `parquet:"int64,timestamp,delta"`
+		Value  int64     `parquet:"value"`
+	}
+
+	times := []time.Time{
+		time.Date(1970, 1, 2, 0, 0, 1, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 500000000, time.UTC),
+		time.Date(2022, 7, 14, 10, 11, 12, 123456789, time.UTC),
+		time.Date(1900, 1, 1, 0, 0, 0, 1, time.UTC),
+	}
+	rows := make([]Row, len(times))
+	for i, tm := range times {
+		rows[i] = Row{
+			Nanos:  tm,
+			Micros: tm,
+			Int64:  tm.UnixMilli(),
+			Value:  int64(i),
+		}
+		if i%2 == 0 {
+			rows[i].Millis = tm
+		}
+	}
+
+	output := new(bytes.Buffer)
+	writer := parquet.NewWriter(output, parquet.Int96Timestamps(true))
+	for i := range rows {
+		if err := writer.Write(&rows[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"nanos", "micros", "millis", "int64"} {
+		leaf, _ := f.Schema().Lookup(path)
+		if kind := leaf.Node.Type().Kind(); kind != parquet.Int96 {
+			t.Errorf("column %q: wrong physical type: %s", path, kind)
+		}
+	}
+	if leaf, _ := f.Schema().Lookup("value"); leaf.Node.Type().Kind() != parquet.Int64 {
+		t.Errorf("column %q: wrong physical type: %s", "value", leaf.Node.Type().Kind())
+	}
+
+	// The first value is one day and one second after the unix epoch, which
+	// is Julian day 2440589.
+	values := make([]parquet.Value, 1)
+	pages := f.RowGroups()[0].ColumnChunks()[0].Pages()
+	page, err := pages.ReadPage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := page.Values().ReadValues(values); err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	if i96 := values[0].Int96(); i96 != (deprecated.Int96{1e9, 0, 2440589}) {
+		t.Errorf("wrong INT96 representation of %s: %v", times[0], [3]uint32(i96))
+	}
+
+	// Reading the file converts the INT96 columns back to timestamps.
+	reader := parquet.NewReader(f, parquet.SchemaOf(new(Row)))
+	for i, tm := range times {
+		row := Row{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		want := Row{
+			Nanos:  tm,
+			Micros: tm.Truncate(time.Microsecond),
+			Int64:  tm.UnixMilli(),
+			Value:  int64(i),
+		}
+		if i%2 == 0 {
+			want.Millis = tm.Truncate(time.Millisecond)
+		}
+		if !reflect.DeepEqual(row, want) {
+			t.Errorf("row %d mismatch:\nwant = %+v\ngot  = %+v", i, want, row)
+		}
+	}
+
+	// INT96 values may also be read into time.Time fields directly.
+	type Int96Row struct {
+		Nanos time.Time `parquet:"nanos"`
+	}
+	reader = parquet.NewReader(f)
+	for i, tm := range times {
+		row := Int96Row{}
+		if err := reader.Read(&row); err != nil {
+			t.Fatal(err)
+		}
+		if !row.Nanos.Equal(tm) {
+			t.Errorf("row %d: wrong time: want %s but got %s", i, tm, row.Nanos)
+		}
+	}
+}